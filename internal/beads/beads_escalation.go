@@ -27,6 +27,7 @@ type EscalationFields struct {
 	ReescalationCount  int    // Number of times this has been re-escalated
 	LastReescalatedAt  string // When last re-escalated (empty if never)
 	LastReescalatedBy  string // Who last re-escalated (empty if never)
+	Deliveries         string // Outcome of external deliveries (email, SMS, Slack)
 }
 
 
@@ -96,6 +97,11 @@ func FormatEscalationDescription(title string, fields *EscalationFields) string
 	} else {
 		lines = append(lines, "last_reescalated_by: null")
 	}
+	if fields.Deliveries != "" {
+		lines = append(lines, fmt.Sprintf("deliveries: %s", fields.Deliveries))
+	} else {
+		lines = append(lines, "deliveries: null")
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -152,6 +158,8 @@ func ParseEscalationFields(description string) *EscalationFields {
 			fields.LastReescalatedAt = value
 		case "last_reescalated_by":
 			fields.LastReescalatedBy = value
+		case "deliveries":
+			fields.Deliveries = value
 		}
 	}
 
@@ -229,6 +237,31 @@ func (b *Beads) AckEscalation(id, ackedBy string) error {
 	})
 }
 
+// RecordEscalationDeliveries records the outcome of external deliveries
+// (one line, e.g. "email:human sent to ...; slack failed ...") on an
+// escalation bead. The "delivery-failed" label marks escalations where
+// some delivery failed, so they can be found and retried by hand.
+func (b *Beads) RecordEscalationDeliveries(id, deliveries string, failed bool) error {
+	issue, fields, err := b.GetEscalationBead(id)
+	if err != nil {
+		return err
+	}
+	if issue == nil {
+		return fmt.Errorf("escalation not found: %s", id)
+	}
+
+	fields.Deliveries = deliveries
+	description := FormatEscalationDescription(issue.Title, fields)
+
+	opts := UpdateOptions{Description: &description}
+	if failed {
+		opts.AddLabels = []string{"delivery-failed"}
+	} else {
+		opts.RemoveLabels = []string{"delivery-failed"}
+	}
+	return b.Update(id, opts)
+}
+
 // CloseEscalation closes an escalation bead with a resolution reason.
 // Sets closed_by and closed_reason fields, closes the issue.
 func (b *Beads) CloseEscalation(id, closedBy, reason string) error {
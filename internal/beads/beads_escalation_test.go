@@ -88,6 +88,7 @@ func TestFormatEscalationDescription(t *testing.T) {
 				"closed_reason: null",
 				"related_bead: null",
 				"original_severity: null",
+				"deliveries: null",
 			},
 		},
 		{
@@ -257,6 +258,7 @@ func TestEscalationFieldsRoundTrip(t *testing.T) {
 		ReescalationCount: 1,
 		LastReescalatedAt: "2024-06-15T11:30:00Z",
 		LastReescalatedBy: "deacon",
+		Deliveries:        "email:human sent to oncall@example.com; slack failed after 3 attempt(s) (Slack webhook returned 500)",
 	}
 
 	formatted := FormatEscalationDescription("Escalation: Agent stuck", original)
@@ -298,6 +300,9 @@ func TestEscalationFieldsRoundTrip(t *testing.T) {
 	if parsed.LastReescalatedBy != original.LastReescalatedBy {
 		t.Errorf("LastReescalatedBy: got %q, want %q", parsed.LastReescalatedBy, original.LastReescalatedBy)
 	}
+	if parsed.Deliveries != original.Deliveries {
+		t.Errorf("Deliveries: got %q, want %q", parsed.Deliveries, original.Deliveries)
+	}
 }
 
 func TestBumpSeverity(t *testing.T) {
@@ -647,6 +647,10 @@ func TestMRFieldsRoundTrip(t *testing.T) {
 		Rig:         "gastown",
 		MergeCommit: "abc123def789",
 		CloseReason: "merged",
+		PullRequest: "https://github.com/acme/widgets/pull/42",
+		GateRun:     "20261016T120000.000000000-1",
+		Phase:       "merged",
+		TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
 	}
 
 	// Format to string
@@ -22,6 +22,7 @@ type AttachmentFields struct {
 	ConvoyID         string // Convoy bead ID tracking this issue (e.g., "hq-cv-abc")
 	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local", or "" (default = mr)
 	ConvoyOwned      bool   // If true, convoy has gt:owned label (caller-managed lifecycle)
+	TraceParent      string // W3C traceparent of the sling that dispatched this work
 }
 
 // ParseAttachmentFields extracts attachment fields from an issue's description.
@@ -84,6 +85,9 @@ func ParseAttachmentFields(issue *Issue) *AttachmentFields {
 		case "convoy_owned", "convoy-owned", "convoyowned":
 			fields.ConvoyOwned = strings.ToLower(value) == "true"
 			hasFields = true
+		case "trace_parent", "trace-parent", "traceparent":
+			fields.TraceParent = value
+			hasFields = true
 		}
 	}
 
@@ -132,6 +136,9 @@ func FormatAttachmentFields(fields *AttachmentFields) string {
 	if fields.ConvoyOwned {
 		lines = append(lines, "convoy_owned: true")
 	}
+	if fields.TraceParent != "" {
+		lines = append(lines, "trace_parent: "+fields.TraceParent)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -171,6 +178,9 @@ func SetAttachmentFields(issue *Issue, fields *AttachmentFields) string {
 		"convoy_owned":      true,
 		"convoy-owned":      true,
 		"convoyowned":       true,
+		"trace_parent":      true,
+		"trace-parent":      true,
+		"traceparent":       true,
 	}
 
 	// Collect non-attachment lines from existing description
@@ -391,6 +401,10 @@ type MRFields struct {
 	MergeCommit string // SHA of merge commit (set on close)
 	CloseReason string // Reason for closing: merged, rejected, conflict, superseded
 	AgentBead   string // Agent bead ID that created this MR (for traceability)
+	PullRequest string // Forge pull request URL when landed through a pull request
+	GateRun     string // ID of the last persisted gate run (see gt mq show)
+	Phase       string // Landing phase recorded by the refinery: merging, merged or failed
+	TraceParent string // W3C traceparent of the gt done that submitted the MR
 
 	// Conflict resolution fields (for priority scoring)
 	RetryCount      int    // Number of conflict-resolution cycles
@@ -457,6 +471,18 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "agent_bead", "agent-bead", "agentbead":
 			fields.AgentBead = value
 			hasFields = true
+		case "pull_request", "pull-request", "pullrequest":
+			fields.PullRequest = value
+			hasFields = true
+		case "gate_run", "gate-run", "gaterun":
+			fields.GateRun = value
+			hasFields = true
+		case "mr_phase", "mr-phase", "mrphase":
+			fields.Phase = value
+			hasFields = true
+		case "trace_parent", "trace-parent", "traceparent":
+			fields.TraceParent = value
+			hasFields = true
 		case "retry_count", "retry-count", "retrycount":
 			if n, err := parseIntField(value); err == nil {
 				fields.RetryCount = n
@@ -523,6 +549,18 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.AgentBead != "" {
 		lines = append(lines, "agent_bead: "+fields.AgentBead)
 	}
+	if fields.PullRequest != "" {
+		lines = append(lines, "pull_request: "+fields.PullRequest)
+	}
+	if fields.GateRun != "" {
+		lines = append(lines, "gate_run: "+fields.GateRun)
+	}
+	if fields.Phase != "" {
+		lines = append(lines, "mr_phase: "+fields.Phase)
+	}
+	if fields.TraceParent != "" {
+		lines = append(lines, "trace_parent: "+fields.TraceParent)
+	}
 	if fields.RetryCount > 0 {
 		lines = append(lines, fmt.Sprintf("retry_count: %d", fields.RetryCount))
 	}
@@ -568,6 +606,18 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"agent_bead":         true,
 		"agent-bead":         true,
 		"agentbead":          true,
+		"pull_request":       true,
+		"pull-request":       true,
+		"pullrequest":        true,
+		"gate_run":           true,
+		"gate-run":           true,
+		"gaterun":            true,
+		"mr_phase":           true,
+		"mr-phase":           true,
+		"mrphase":            true,
+		"trace_parent":       true,
+		"trace-parent":       true,
+		"traceparent":        true,
 		"retry_count":        true,
 		"retry-count":        true,
 		"retrycount":         true,
@@ -0,0 +1,361 @@
+// Package budget tracks agent spend against configured budgets: daily per
+// rig, total per convoy and monthly for the town.
+//
+// Spend is charged by the cost pipeline (`gt costs record`) and kept in
+// <townRoot>/.runtime/budget-state.json. Crossing a budget's warning level
+// or limit is reported once per period so callers can escalate; while a
+// budget is exceeded, the configured action holds scheduler dispatch or
+// refuses new polecats for the scope. The impure parts (escalation,
+// scheduler and sling wiring) live in cmd.
+package budget
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Scope kinds.
+const (
+	ScopeTown   = "town"
+	ScopeRig    = "rig"
+	ScopeConvoy = "convoy"
+)
+
+// Actions taken while a budget is exceeded. Each includes the ones before it.
+const (
+	// ActionEscalate only escalates.
+	ActionEscalate = "escalate"
+	// ActionPause also holds queued beads for the scope in the scheduler.
+	ActionPause = "pause"
+	// ActionStop also refuses to sling new polecats for the scope.
+	ActionStop = "stop"
+)
+
+// Levels reported in Status.
+const (
+	LevelOK       = "ok"
+	LevelWarn     = "warn"
+	LevelExceeded = "exceeded"
+)
+
+// AllKey is the map key applying a budget to every rig or convoy not listed.
+const AllKey = "*"
+
+// DefaultWarnPercent is the share of a budget at which a warning escalates.
+const DefaultWarnPercent = 80
+
+// Config declares spend budgets in USD. Zero or absent budgets are unlimited.
+type Config struct {
+	// TownMonthlyUSD caps the whole town's spend per calendar month.
+	TownMonthlyUSD float64 `json:"town_monthly_usd,omitempty"`
+
+	// RigDailyUSD caps each rig's spend per calendar day, keyed by rig name.
+	// "*" applies to every rig not listed.
+	RigDailyUSD map[string]float64 `json:"rig_daily_usd,omitempty"`
+
+	// ConvoyUSD caps each convoy's total spend, keyed by convoy ID.
+	// "*" applies to every convoy not listed.
+	ConvoyUSD map[string]float64 `json:"convoy_usd,omitempty"`
+
+	// WarnPercent is the share of a budget (1-99) at which a warning is
+	// escalated before the limit is reached. Default 80; negative disables.
+	WarnPercent int `json:"warn_percent,omitempty"`
+
+	// Action is what happens while a budget is exceeded: "escalate",
+	// "pause" (default; also hold scheduled work) or "stop" (also refuse
+	// gt sling). Escalation happens in every case.
+	Action string `json:"action,omitempty"`
+}
+
+// GetAction returns the configured action or the default (pause).
+func (c *Config) GetAction() string {
+	if c == nil || c.Action == "" {
+		return ActionPause
+	}
+	return c.Action
+}
+
+// GetWarnPercent returns the warning level, or 0 when warnings are disabled.
+func (c *Config) GetWarnPercent() int {
+	if c == nil || c.WarnPercent == 0 {
+		return DefaultWarnPercent
+	}
+	if c.WarnPercent < 0 {
+		return 0
+	}
+	return c.WarnPercent
+}
+
+// Enabled reports whether any budget is configured.
+func (c *Config) Enabled() bool {
+	return c != nil && (c.TownMonthlyUSD > 0 || len(c.RigDailyUSD) > 0 || len(c.ConvoyUSD) > 0)
+}
+
+// Validate checks the action, warning level and budget amounts.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.GetAction() {
+	case ActionEscalate, ActionPause, ActionStop:
+	default:
+		return fmt.Errorf("invalid budget action %q (want escalate, pause or stop)", c.Action)
+	}
+	if c.WarnPercent >= 100 {
+		return fmt.Errorf("invalid budget warn_percent %d (want 1-99, or negative to disable)", c.WarnPercent)
+	}
+	if c.TownMonthlyUSD < 0 {
+		return fmt.Errorf("invalid town_monthly_usd %.2f", c.TownMonthlyUSD)
+	}
+	for _, m := range []map[string]float64{c.RigDailyUSD, c.ConvoyUSD} {
+		for key, v := range m {
+			if v < 0 {
+				return fmt.Errorf("invalid budget %.2f for %s", v, key)
+			}
+		}
+	}
+	return nil
+}
+
+// Limit returns the budget for a scope, or 0 when it is unlimited.
+func (c *Config) Limit(kind, key string) float64 {
+	if c == nil {
+		return 0
+	}
+	var m map[string]float64
+	switch kind {
+	case ScopeTown:
+		return c.TownMonthlyUSD
+	case ScopeRig:
+		m = c.RigDailyUSD
+	case ScopeConvoy:
+		m = c.ConvoyUSD
+	}
+	if v, ok := m[key]; ok {
+		return v
+	}
+	return m[AllKey]
+}
+
+// Period returns the budget period containing t: the month for the town,
+// the day for rigs, and "" for convoys (whose budget is a lifetime total).
+func Period(kind string, t time.Time) string {
+	switch kind {
+	case ScopeTown:
+		return t.Format("2006-01")
+	case ScopeRig:
+		return t.Format("2006-01-02")
+	}
+	return ""
+}
+
+// Status is a budget's spend in its current period.
+type Status struct {
+	Kind     string  `json:"kind"`
+	Key      string  `json:"key,omitempty"`
+	Period   string  `json:"period,omitempty"`
+	SpentUSD float64 `json:"spent_usd"`
+	LimitUSD float64 `json:"limit_usd"`
+	Level    string  `json:"level"`
+}
+
+// Percent returns spend as a percentage of the budget.
+func (s Status) Percent() float64 {
+	if s.LimitUSD <= 0 {
+		return 0
+	}
+	return s.SpentUSD / s.LimitUSD * 100
+}
+
+// Name returns a human label for the scope, e.g. "rig gastown".
+func (s Status) Name() string {
+	if s.Key == "" {
+		return s.Kind
+	}
+	return s.Kind + " " + s.Key
+}
+
+func level(spent, limit float64, warnPercent int) string {
+	switch {
+	case limit <= 0:
+		return LevelOK
+	case spent >= limit:
+		return LevelExceeded
+	case warnPercent > 0 && spent >= limit*float64(warnPercent)/100:
+		return LevelWarn
+	}
+	return LevelOK
+}
+
+// Charge is one recorded session cost.
+type Charge struct {
+	// Session identifies the agent session by its transcript or runtime
+	// session ID, not the tmux session name, which the next run reuses.
+	// CostUSD is the session's running total (each `gt costs record`
+	// re-reads the whole session log), so only the increase since the
+	// session's last charge is spent.
+	Session string
+	CostUSD float64
+	Rig     string
+	Convoy  string
+	At      time.Time
+}
+
+// Crossing is a budget level newly reached by a charge.
+type Crossing struct {
+	Status
+	// Action is the configured action, for crossings into LevelExceeded.
+	Action string
+}
+
+// sessionTTL is how long a session's last charge is remembered.
+const sessionTTL = 7 * 24 * time.Hour
+
+// Apply charges c to the town, its rig and its convoy, and returns the
+// warning and exceeded levels reached for the first time this period.
+func (s *State) Apply(cfg *Config, c Charge) []Crossing {
+	if s.Scopes == nil {
+		s.Scopes = make(map[string]*Spend)
+	}
+	if s.Sessions == nil {
+		s.Sessions = make(map[string]*SessionCharge)
+	}
+
+	delta := c.CostUSD
+	if prev, ok := s.Sessions[c.Session]; ok && c.CostUSD >= prev.CostUSD {
+		delta = c.CostUSD - prev.CostUSD
+	}
+	if c.Session != "" {
+		s.Sessions[c.Session] = &SessionCharge{CostUSD: c.CostUSD, At: c.At}
+	}
+	for id, sc := range s.Sessions {
+		if c.At.Sub(sc.At) > sessionTTL {
+			delete(s.Sessions, id)
+		}
+	}
+	if delta <= 0 {
+		return nil
+	}
+
+	var crossings []Crossing
+	for _, scope := range []struct{ kind, key string }{{ScopeTown, ""}, {ScopeRig, c.Rig}, {ScopeConvoy, c.Convoy}} {
+		if scope.kind != ScopeTown && scope.key == "" {
+			continue
+		}
+		sp := s.spend(scope.kind, scope.key, c.At)
+		sp.SpentUSD += delta
+
+		limit := cfg.Limit(scope.kind, scope.key)
+		st := Status{Kind: scope.kind, Key: scope.key, Period: sp.Period, SpentUSD: sp.SpentUSD, LimitUSD: limit,
+			Level: level(sp.SpentUSD, limit, cfg.GetWarnPercent())}
+		switch {
+		case st.Level == LevelExceeded && !sp.Exceeded:
+			sp.Exceeded, sp.Warned = true, true
+			crossings = append(crossings, Crossing{Status: st, Action: cfg.GetAction()})
+		case st.Level == LevelWarn && !sp.Warned:
+			sp.Warned = true
+			crossings = append(crossings, Crossing{Status: st})
+		}
+	}
+	return crossings
+}
+
+// spend returns the scope's spend for the period containing t, starting a
+// new period when the stored one has ended.
+func (s *State) spend(kind, key string, t time.Time) *Spend {
+	id := kind + "/" + key
+	period := Period(kind, t)
+	sp, ok := s.Scopes[id]
+	if !ok || sp.Period != period {
+		sp = &Spend{Kind: kind, Key: key, Period: period}
+		s.Scopes[id] = sp
+	}
+	return sp
+}
+
+// Statuses reports every configured budget at now: the town, every rig and
+// convoy with an explicit budget, and those covered by "*" that have spend.
+func (s *State) Statuses(cfg *Config, now time.Time) []Status {
+	if !cfg.Enabled() {
+		return nil
+	}
+	type scope struct{ kind, key string }
+	scopes := make(map[scope]bool)
+	if cfg.TownMonthlyUSD > 0 {
+		scopes[scope{ScopeTown, ""}] = true
+	}
+	for kind, m := range map[string]map[string]float64{ScopeRig: cfg.RigDailyUSD, ScopeConvoy: cfg.ConvoyUSD} {
+		for key := range m {
+			if key != AllKey {
+				scopes[scope{kind, key}] = true
+			}
+		}
+	}
+	if s != nil {
+		for _, sp := range s.Scopes {
+			if sp.Kind != ScopeTown && cfg.Limit(sp.Kind, sp.Key) > 0 {
+				scopes[scope{sp.Kind, sp.Key}] = true
+			}
+		}
+	}
+
+	statuses := make([]Status, 0, len(scopes))
+	for sc := range scopes {
+		limit := cfg.Limit(sc.kind, sc.key)
+		if limit <= 0 {
+			continue
+		}
+		st := Status{Kind: sc.kind, Key: sc.key, Period: Period(sc.kind, now), LimitUSD: limit}
+		if s != nil {
+			if sp, ok := s.Scopes[sc.kind+"/"+sc.key]; ok && sp.Period == st.Period {
+				st.SpentUSD = sp.SpentUSD
+			}
+		}
+		st.Level = level(st.SpentUSD, limit, cfg.GetWarnPercent())
+		statuses = append(statuses, st)
+	}
+	order := map[string]int{ScopeTown: 0, ScopeRig: 1, ScopeConvoy: 2}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Kind != statuses[j].Kind {
+			return order[statuses[i].Kind] < order[statuses[j].Kind]
+		}
+		return statuses[i].Key < statuses[j].Key
+	})
+	return statuses
+}
+
+// Exceeded returns the first exceeded budget (town, then rig, then convoy)
+// covering new work for rig and convoy at now. Either may be empty.
+func (s *State) Exceeded(cfg *Config, rig, convoy string, now time.Time) (Status, bool) {
+	if s == nil || !cfg.Enabled() {
+		return Status{}, false
+	}
+	for _, scope := range []struct{ kind, key string }{{ScopeTown, ""}, {ScopeRig, rig}, {ScopeConvoy, convoy}} {
+		if scope.kind != ScopeTown && scope.key == "" {
+			continue
+		}
+		limit := cfg.Limit(scope.kind, scope.key)
+		sp, ok := s.Scopes[scope.kind+"/"+scope.key]
+		if limit <= 0 || !ok || sp.Period != Period(scope.kind, now) || sp.SpentUSD < limit {
+			continue
+		}
+		return Status{Kind: scope.kind, Key: scope.key, Period: sp.Period, SpentUSD: sp.SpentUSD, LimitUSD: limit, Level: LevelExceeded}, true
+	}
+	return Status{}, false
+}
+
+// Hold reports whether the configured action holds new work for rig and
+// convoy in the scheduler (pause or stop) and, with sling set, in gt sling
+// (stop only). The reason names the exceeded budget.
+func (s *State) Hold(cfg *Config, rig, convoy string, now time.Time, sling bool) (string, bool) {
+	action := cfg.GetAction()
+	if action == ActionEscalate || (sling && action != ActionStop) {
+		return "", false
+	}
+	st, exceeded := s.Exceeded(cfg, rig, convoy, now)
+	if !exceeded {
+		return "", false
+	}
+	return fmt.Sprintf("%s budget exceeded ($%.2f of $%.2f)", st.Name(), st.SpentUSD, st.LimitUSD), true
+}
@@ -0,0 +1,203 @@
+package budget
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var day1 = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+func levels(crossings []Crossing) []string {
+	var out []string
+	for _, c := range crossings {
+		out = append(out, c.Name()+":"+c.Level)
+	}
+	return out
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestApply_ChargesSessionDeltas(t *testing.T) {
+	cfg := &Config{RigDailyUSD: map[string]float64{"gastown": 10}}
+	s := &State{}
+
+	// The Stop hook records the session's running total after every turn.
+	for _, total := range []float64{1, 3, 3, 6} {
+		s.Apply(cfg, Charge{Session: "toast.jsonl", CostUSD: total, Rig: "gastown", At: day1})
+	}
+	s.Apply(cfg, Charge{Session: "nux.jsonl", CostUSD: 2, Rig: "gastown", At: day1})
+
+	got := s.Scopes["rig/gastown"].SpentUSD
+	if math.Abs(got-8) > 1e-9 {
+		t.Errorf("rig spend = %v, want 8", got)
+	}
+	if got := s.Scopes["town/"].SpentUSD; math.Abs(got-8) > 1e-9 {
+		t.Errorf("town spend = %v, want 8", got)
+	}
+
+	// A restarted session (running total drops) charges its new total.
+	s.Apply(cfg, Charge{Session: "toast.jsonl", CostUSD: 1, Rig: "gastown", At: day1})
+	if got := s.Scopes["rig/gastown"].SpentUSD; math.Abs(got-9) > 1e-9 {
+		t.Errorf("rig spend after restart = %v, want 9", got)
+	}
+}
+
+func TestApply_CrossingsReportedOncePerPeriod(t *testing.T) {
+	cfg := &Config{
+		TownMonthlyUSD: 100,
+		RigDailyUSD:    map[string]float64{AllKey: 10},
+		ConvoyUSD:      map[string]float64{"hq-cv-1": 5},
+		Action:         ActionStop,
+	}
+	s := &State{}
+	charge := func(session string, cost float64, at time.Time) []string {
+		return levels(s.Apply(cfg, Charge{Session: session, CostUSD: cost, Rig: "gastown", Convoy: "hq-cv-1", At: at}))
+	}
+
+	if got, want := charge("a", 4, day1), []string{"convoy hq-cv-1:warn"}; !equal(got, want) {
+		t.Errorf("first charge crossings = %v, want %v", got, want)
+	}
+	if got, want := charge("a", 8.5, day1), []string{"rig gastown:warn", "convoy hq-cv-1:exceeded"}; !equal(got, want) {
+		t.Errorf("second charge crossings = %v, want %v", got, want)
+	}
+	if got, want := charge("b", 2, day1), []string{"rig gastown:exceeded"}; !equal(got, want) {
+		t.Errorf("third charge crossings = %v, want %v", got, want)
+	}
+	if got := charge("b", 3, day1); len(got) != 0 {
+		t.Errorf("already-reported levels crossed again: %v", got)
+	}
+
+	// Next day: the rig budget starts over, the convoy total doesn't.
+	day2 := day1.Add(24 * time.Hour)
+	if got := charge("c", 1, day2); len(got) != 0 {
+		t.Errorf("next-day charge crossings = %v, want none", got)
+	}
+	if sp := s.Scopes["rig/gastown"]; sp.Period != "2026-10-17" || sp.SpentUSD != 1 {
+		t.Errorf("rig spend after rollover = %+v, want 1 in 2026-10-17", sp)
+	}
+	if got := s.Scopes["convoy/hq-cv-1"].SpentUSD; math.Abs(got-12.5) > 1e-9 {
+		t.Errorf("convoy spend = %v, want 12.5", got)
+	}
+}
+
+func TestApply_ExpiresOldSessions(t *testing.T) {
+	s := &State{}
+	s.Apply(&Config{}, Charge{Session: "old", CostUSD: 1, At: day1})
+	s.Apply(&Config{}, Charge{Session: "new", CostUSD: 1, At: day1.Add(8 * 24 * time.Hour)})
+	if _, ok := s.Sessions["old"]; ok {
+		t.Error("expected session charged 8 days ago to be forgotten")
+	}
+}
+
+func TestHold(t *testing.T) {
+	s := &State{}
+	cfg := &Config{RigDailyUSD: map[string]float64{"gastown": 5}}
+	s.Apply(cfg, Charge{Session: "a", CostUSD: 6, Rig: "gastown", At: day1})
+
+	tests := []struct {
+		action   string
+		rig      string
+		sling    bool
+		wantHeld bool
+	}{
+		{ActionEscalate, "gastown", false, false},
+		{ActionPause, "gastown", false, true},
+		{ActionPause, "gastown", true, false},
+		{ActionStop, "gastown", true, true},
+		{ActionStop, "beads", true, false},
+	}
+	for _, tt := range tests {
+		cfg.Action = tt.action
+		reason, held := s.Hold(cfg, tt.rig, "", day1, tt.sling)
+		if held != tt.wantHeld {
+			t.Errorf("Hold(%s, %s, sling=%v) = %v, want %v", tt.action, tt.rig, tt.sling, held, tt.wantHeld)
+		}
+		if held && reason != "rig gastown budget exceeded ($6.00 of $5.00)" {
+			t.Errorf("Hold reason = %q", reason)
+		}
+	}
+
+	// The hold lifts when the day's budget resets.
+	cfg.Action = ActionStop
+	if _, held := s.Hold(cfg, "gastown", "", day1.Add(24*time.Hour), true); held {
+		t.Error("expected hold to lift the next day")
+	}
+}
+
+func TestStatuses(t *testing.T) {
+	cfg := &Config{
+		TownMonthlyUSD: 100,
+		RigDailyUSD:    map[string]float64{"beads": 20, AllKey: 10},
+	}
+	s := &State{}
+	s.Apply(cfg, Charge{Session: "a", CostUSD: 9, Rig: "gastown", At: day1})
+
+	got := s.Statuses(cfg, day1)
+	want := []Status{
+		{Kind: ScopeTown, Period: "2026-10", SpentUSD: 9, LimitUSD: 100, Level: LevelOK},
+		{Kind: ScopeRig, Key: "beads", Period: "2026-10-16", LimitUSD: 20, Level: LevelOK},
+		{Kind: ScopeRig, Key: "gastown", Period: "2026-10-16", SpentUSD: 9, LimitUSD: 10, Level: LevelWarn},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Statuses = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("status[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if got := (&State{}).Statuses(&Config{}, day1); got != nil {
+		t.Errorf("Statuses without budgets = %+v, want nil", got)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		cfg     Config
+		wantErr bool
+	}{
+		{Config{}, false},
+		{Config{Action: ActionStop, WarnPercent: -1}, false},
+		{Config{Action: "explode"}, true},
+		{Config{WarnPercent: 100}, true},
+		{Config{RigDailyUSD: map[string]float64{"gastown": -1}}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) = %v, wantErr %v", tt.cfg, err, tt.wantErr)
+		}
+	}
+}
+
+func TestUpdatePersistsState(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg := &Config{TownMonthlyUSD: 10}
+	for _, cost := range []float64{2, 5} {
+		err := Update(townRoot, func(s *State) error {
+			s.Apply(cfg, Charge{Session: "a", CostUSD: cost, At: day1})
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+	s, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if got := s.Scopes["town/"].SpentUSD; got != 5 {
+		t.Errorf("town spend = %v, want 5", got)
+	}
+}
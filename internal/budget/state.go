@@ -0,0 +1,108 @@
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// State is the spend tracked against budgets.
+// Stored at <townRoot>/.runtime/budget-state.json.
+type State struct {
+	// Scopes holds the current period's spend, keyed by "kind/key".
+	Scopes map[string]*Spend `json:"scopes,omitempty"`
+
+	// Sessions remembers each session's last charged running total.
+	Sessions map[string]*SessionCharge `json:"sessions,omitempty"`
+}
+
+// Spend is one scope's spend in a period.
+type Spend struct {
+	Kind     string  `json:"kind"`
+	Key      string  `json:"key,omitempty"`
+	Period   string  `json:"period,omitempty"`
+	SpentUSD float64 `json:"spent_usd"`
+
+	// Warned and Exceeded record that the level was already reported
+	// this period, so each is escalated once.
+	Warned   bool `json:"warned,omitempty"`
+	Exceeded bool `json:"exceeded,omitempty"`
+}
+
+// SessionCharge is the last running total charged for a session.
+type SessionCharge struct {
+	CostUSD float64   `json:"cost_usd"`
+	At      time.Time `json:"at"`
+}
+
+func statePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "budget-state.json")
+}
+
+// LoadState loads budget state, returning an empty state if there is none yet.
+func LoadState(townRoot string) (*State, error) {
+	data, err := os.ReadFile(statePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{}, nil
+		}
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing budget state: %w", err)
+	}
+	return &s, nil
+}
+
+// Update loads the state, applies fn and saves the result, holding a file
+// lock so concurrent `gt costs record` calls don't lose charges.
+func Update(townRoot string, fn func(*State) error) error {
+	path := statePath(townRoot)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking budget state: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	s, err := LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := fn(s); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Atomic write: temp file + rename
+	tmp, err := os.CreateTemp(dir, ".budget-state-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -8,13 +8,18 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/budget"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/quota"
 	"github.com/steveyegge/gastown/internal/scheduler/capacity"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 )
 
 // maxDispatchFailures is the maximum number of consecutive dispatch failures
@@ -38,6 +43,11 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 	}
 	defer func() { _ = fileLock.Unlock() }()
 
+	// Deadlines are checked even while paused — that's when they get missed.
+	if !dryRun {
+		escalateOverdueContexts(townRoot)
+	}
+
 	// Load scheduler state
 	state, err := capacity.LoadState(townRoot)
 	if err != nil {
@@ -84,6 +94,10 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 		batchSize = batchOverride
 	}
 	spawnDelay := schedulerCfg.GetSpawnDelay()
+	policy, err := capacity.PolicyFromConfig(schedulerCfg)
+	if err != nil {
+		return 0, err
+	}
 
 	townBeads := beads.NewWithBeadsDir(townRoot, filepath.Join(townRoot, ".beads"))
 
@@ -99,7 +113,7 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 	polecatNames := make(map[string]string)
 	cycle := &capacity.DispatchCycle{
 		AvailableCapacity: func() (int, error) {
-			active := countActivePolecats()
+			active := countActivePolecats(townRoot)
 			cap := maxPolecats - active
 			if cap <= 0 {
 				return 0, nil // No free slots — PlanDispatch treats <= 0 as no capacity
@@ -121,6 +135,12 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 			if b.TargetRig != "" {
 				successfulRigs[b.TargetRig] = true
 			}
+			if b.Context != nil && b.Context.Recurrence != "" {
+				if err := enqueueNextOccurrence(townRoot, actor, b); err != nil {
+					fmt.Fprintf(os.Stderr, "%s Could not re-enqueue recurring %s: %v\n",
+						style.Warning.Render("⚠"), b.WorkBeadID, err)
+				}
+			}
 			_ = events.LogFeed(events.TypeSchedulerDispatch, actor,
 				events.SchedulerDispatchPayload(b.WorkBeadID, b.TargetRig, polecatNames[b.ID]))
 			return nil
@@ -157,6 +177,19 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 		},
 		BatchSize:  batchSize,
 		SpawnDelay: spawnDelay,
+		Policy:     policy,
+		PolicyEnv: func() capacity.PolicyEnv {
+			env := capacity.PolicyEnv{
+				ActiveByRig: countActivePolecatsByRig(townRoot),
+				Limits:      buildSchedulerLimiter(townRoot, schedulerCfg, settings),
+			}
+			// Matching polecats to convoys costs a bd query per rig; only
+			// the convoy cap needs it.
+			if schedulerCfg.MaxPerConvoy > 0 {
+				env.ActiveByConvoy = countActivePolecatsByConvoy(townRoot)
+			}
+			return env
+		},
 	}
 
 	if dryRun {
@@ -164,7 +197,7 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 		if planErr != nil {
 			return 0, fmt.Errorf("planning dispatch: %w", planErr)
 		}
-		printDryRunPlan(plan, maxPolecats, batchSize)
+		printDryRunPlan(townRoot, plan, maxPolecats, batchSize, policy.Name())
 		return 0, nil
 	}
 
@@ -199,14 +232,59 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 	return report.Dispatched, nil
 }
 
+// buildSchedulerLimiter builds the per-rig/account/agent limiter for a cycle
+// from the running polecats and quota state. Rate-limited accounts come from
+// quota.Manager so beads pinned to them wait instead of failing, and beads
+// under an exceeded spend budget wait until it resets.
+func buildSchedulerLimiter(townRoot string, cfg *capacity.SchedulerConfig, settings *config.TownSettings) *capacity.Limiter {
+	accounts, _ := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+	defaultAccount := ""
+	if accounts != nil {
+		defaultAccount = accounts.Default
+	}
+	defaultAgent := settings.DefaultAgent
+	if defaultAgent == "" {
+		defaultAgent = "claude"
+	}
+
+	rateLimited, err := quota.NewManager(townRoot).RateLimited()
+	if err != nil {
+		style.PrintWarning("could not read quota state: %v", err)
+	}
+
+	// Reading each session's account/agent costs backend calls; skip it unless
+	// a limit depends on it.
+	withRuntime := len(cfg.AccountMaxPolecats) > 0 || len(cfg.AgentMaxPolecats) > 0
+	running := listActivePolecats(townRoot, accounts, withRuntime)
+
+	limiter := capacity.NewLimiter(cfg, running, rateLimited, defaultAccount, defaultAgent)
+	if settings.Budgets.Enabled() {
+		// Exceeded spend budgets (action pause or stop) hold work for their rig
+		// or convoy; state is read once per cycle.
+		if state, err := budget.LoadState(townRoot); err != nil {
+			style.PrintWarning("could not read budget state: %v", err)
+		} else {
+			now := time.Now()
+			limiter.SetHold(func(b capacity.PendingBead) (string, bool) {
+				var convoy string
+				if b.Context != nil {
+					convoy = b.Context.Convoy
+				}
+				return state.Hold(settings.Budgets, b.TargetRig, convoy, now, false)
+			})
+		}
+	}
+	return limiter
+}
+
 // printDryRunPlan displays a dry-run dispatch plan.
-func printDryRunPlan(plan capacity.DispatchPlan, maxPolecats, batchSize int) {
+func printDryRunPlan(townRoot string, plan capacity.DispatchPlan, maxPolecats, batchSize int, policyName string) {
 	if plan.Reason == "none" {
 		fmt.Println("No ready beads scheduled for dispatch")
 		return
 	}
 
-	activePolecats := countActivePolecats()
+	activePolecats := countActivePolecats(townRoot)
 	capStr := "unlimited"
 	if maxPolecats > 0 {
 		cap := maxPolecats - activePolecats
@@ -217,15 +295,25 @@ func printDryRunPlan(plan capacity.DispatchPlan, maxPolecats, batchSize int) {
 	}
 
 	totalReady := len(plan.ToDispatch) + plan.Skipped
-	if len(plan.ToDispatch) == 0 {
+	if len(plan.ToDispatch) == 0 && plan.Reason == "capacity" {
 		fmt.Printf("No capacity: %s, %d ready bead(s) waiting\n", capStr, totalReady)
 		return
 	}
 
-	fmt.Printf("%s Would dispatch %d bead(s) (capacity: %s, batch: %d, ready: %d, reason: %s)\n",
-		style.Bold.Render("📋"), len(plan.ToDispatch), capStr, batchSize, totalReady, plan.Reason)
-	for _, b := range plan.ToDispatch {
-		fmt.Printf("  Would dispatch: %s → %s\n", b.WorkBeadID, b.TargetRig)
+	fmt.Printf("%s Would dispatch %d bead(s) (capacity: %s, batch: %d, ready: %d, policy: %s, reason: %s)\n",
+		style.Bold.Render("📋"), len(plan.ToDispatch), capStr, batchSize, totalReady, policyName, plan.Reason)
+	if len(plan.Decisions) == 0 {
+		for _, b := range plan.ToDispatch {
+			fmt.Printf("  Would dispatch: %s → %s\n", b.WorkBeadID, b.TargetRig)
+		}
+		return
+	}
+	for _, d := range plan.Decisions {
+		if d.Selected {
+			fmt.Printf("  Would dispatch: %s → %s  %s\n", d.Bead.WorkBeadID, d.Bead.TargetRig, style.Dim.Render("("+d.Reason+")"))
+		} else {
+			fmt.Printf("  Would skip:     %s → %s  %s\n", d.Bead.WorkBeadID, d.Bead.TargetRig, style.Dim.Render("("+d.Reason+")"))
+		}
 	}
 }
 
@@ -342,9 +430,9 @@ func getReadySlingContexts(townRoot string) ([]capacity.PendingBead, error) {
 		return nil, nil
 	}
 
-	// 2. Build readyWorkIDs set from bd ready across all dirs
+	// 2. Build ready work bead → priority map from bd ready across all dirs
 	// (work beads live in rig-local DBs, so we need to check all dirs)
-	readyWorkIDs, readyErr := listReadyWorkBeadIDsWithError(townRoot)
+	readyWork, readyErr := listReadyWorkBeadsWithError(townRoot)
 	if readyErr != nil {
 		return nil, readyErr
 	}
@@ -379,7 +467,8 @@ func getReadySlingContexts(townRoot string) ([]capacity.PendingBead, error) {
 		}
 
 		// Only include if work bead is ready (unblocked)
-		if !readyWorkIDs[fields.WorkBeadID] {
+		priority, ready := readyWork[fields.WorkBeadID]
+		if !ready {
 			continue
 		}
 
@@ -396,11 +485,13 @@ func getReadySlingContexts(townRoot string) ([]capacity.PendingBead, error) {
 			TargetRig:   fields.TargetRig,
 			Description: ctx.Description,
 			Labels:      ctx.Labels,
+			Priority:    priority,
 			Context:     fields,
 		})
 	}
 
-	return result, nil
+	// Hold beads whose not_before time hasn't arrived.
+	return capacity.TimeWindow(time.Now())(result), nil
 }
 
 // dispatchSingleBead dispatches one scheduled bead via executeSling.
@@ -433,6 +524,12 @@ func dispatchSingleBead(b capacity.PendingBead, townRoot, _ string) (*SlingResul
 		BeadsDir:         filepath.Join(townRoot, ".beads"),
 	}
 
+	// Dispatch under the trace of the gt sling that scheduled the bead, so
+	// the polecat and its work join it rather than this scheduler run's.
+	if b.Context.TraceParent != "" {
+		defer telemetry.SwapProcessTraceParent(b.Context.TraceParent)()
+	}
+
 	fmt.Printf("  Dispatching %s → %s...\n", b.WorkBeadID, b.TargetRig)
 	result, err := executeSling(params)
 	if err != nil {
@@ -485,7 +582,23 @@ func listAllSlingContexts(townRoot string) ([]*beads.Issue, error) {
 // listReadyWorkBeadIDsWithError returns a set of work bead IDs that are unblocked.
 // Returns an error only when ALL dirs fail (partial success is acceptable).
 func listReadyWorkBeadIDsWithError(townRoot string) (map[string]bool, error) {
-	readyIDs := make(map[string]bool)
+	priorities, err := listReadyWorkBeadsWithError(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	readyIDs := make(map[string]bool, len(priorities))
+	for id := range priorities {
+		readyIDs[id] = true
+	}
+	return readyIDs, nil
+}
+
+// listReadyWorkBeadsWithError returns unblocked work bead IDs mapped to their
+// priority (used by dispatch policies). Beads without a reported priority get
+// capacity.DefaultBeadPriority.
+// Returns an error only when ALL dirs fail (partial success is acceptable).
+func listReadyWorkBeadsWithError(townRoot string) (map[string]int, error) {
+	ready := make(map[string]int)
 	dirs := beadsSearchDirs(townRoot)
 	failCount := 0
 	var lastErr error
@@ -501,18 +614,23 @@ func listReadyWorkBeadIDsWithError(townRoot string) (map[string]bool, error) {
 			continue
 		}
 		var readyBeads []struct {
-			ID string `json:"id"`
+			ID       string `json:"id"`
+			Priority *int   `json:"priority"`
 		}
 		if err := json.Unmarshal(readyOut, &readyBeads); err == nil {
 			for _, b := range readyBeads {
-				readyIDs[b.ID] = true
+				priority := capacity.DefaultBeadPriority
+				if b.Priority != nil {
+					priority = *b.Priority
+				}
+				ready[b.ID] = priority
 			}
 		}
 	}
 	if failCount == len(dirs) && failCount > 0 {
 		return nil, fmt.Errorf("all %d bd ready queries failed (last: %w)", failCount, lastErr)
 	}
-	return readyIDs, nil
+	return ready, nil
 }
 
 // listReadyWorkBeadIDs returns a set of work bead IDs that are unblocked.
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/budget"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/scheduler/capacity"
@@ -610,6 +611,21 @@ Supported keys:
   scheduler.max_polecats      Dispatch mode: -1 = direct (default), N > 0 = deferred
   scheduler.batch_size        Beads per heartbeat (default: 1)
   scheduler.spawn_delay       Delay between spawns (default: 0s)
+  scheduler.rig_max_polecats.<rig>        Max concurrent polecats in one rig (0 = no limit)
+  scheduler.account_max_polecats.<handle> Max concurrent polecats on one account
+  scheduler.agent_max_polecats.<agent>    Max concurrent polecats per agent preset
+  scheduler.policy            Dispatch order: fifo (default), priority, or fair
+  scheduler.rig_weight.<rig>  Rig's share under the fair policy (default: 1)
+  scheduler.max_per_convoy    Max polecats per convoy at once (default: 0 = unlimited)
+  scheduler.aging_interval    Raise waiting beads one priority level per interval
+                              (e.g. 1h; default: off)
+  budget.town_monthly_usd     Town spend cap per calendar month (0 = none)
+  budget.rig_daily_usd.<rig>  Rig spend cap per day; <rig> "*" = every rig
+  budget.convoy_usd.<convoy>  Convoy total spend cap; <convoy> "*" = every convoy
+  budget.warn_percent         Escalate a warning at this share of a budget
+                              (default: 80; -1 = no warning)
+  budget.action               When exceeded: escalate, pause (default; hold
+                              scheduled work), or stop (also refuse gt sling)
   maintenance.window          Maintenance window start time in HH:MM (e.g., "03:00")
   maintenance.interval        How often: "daily", "weekly", "monthly", or duration
   maintenance.threshold       Commit count threshold (default: 1000)
@@ -631,6 +647,11 @@ Examples:
   gt config set cli_theme dark
   gt config set default_agent claude
   gt config set scheduler.max_polecats 5
+  gt config set scheduler.agent_max_polecats.codex 3
+  gt config set scheduler.policy fair
+  gt config set scheduler.rig_weight.gastown 2
+  gt config set budget.rig_daily_usd.gastown 40
+  gt config set budget.action stop
   gt config set maintenance.window 03:00
   gt config set maintenance.interval daily
   gt config set lifecycle.reaper.delete_age 336h
@@ -653,6 +674,18 @@ Supported keys:
   scheduler.max_polecats      Dispatch mode (-1 = direct, N > 0 = deferred)
   scheduler.batch_size        Beads per heartbeat
   scheduler.spawn_delay       Delay between spawns
+  scheduler.rig_max_polecats.<rig>        Per-rig concurrency limit
+  scheduler.account_max_polecats.<handle> Per-account concurrency limit
+  scheduler.agent_max_polecats.<agent>    Per-agent-preset concurrency limit
+  scheduler.policy            Dispatch order (fifo, priority, fair)
+  scheduler.rig_weight.<rig>  Rig's share under the fair policy
+  scheduler.max_per_convoy    Max polecats per convoy at once
+  scheduler.aging_interval    Priority aging interval
+  budget.town_monthly_usd     Town spend cap per calendar month
+  budget.rig_daily_usd.<rig>  Rig spend cap per day
+  budget.convoy_usd.<convoy>  Convoy total spend cap
+  budget.warn_percent         Warning level as a share of a budget
+  budget.action               Action when a budget is exceeded
   maintenance.window          Maintenance window start time (HH:MM)
   maintenance.interval        How often: daily, weekly, monthly, or duration
   maintenance.threshold       Commit count threshold
@@ -749,6 +782,37 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		}
 		townSettings.Scheduler.SpawnDelay = value
 
+	case "scheduler.policy":
+		if !capacity.IsValidPolicy(value) {
+			return fmt.Errorf("invalid value for %s: %q (expected %s, %s, or %s)", key, value,
+				capacity.PolicyFIFO, capacity.PolicyPriority, capacity.PolicyFair)
+		}
+		if townSettings.Scheduler == nil {
+			townSettings.Scheduler = capacity.DefaultSchedulerConfig()
+		}
+		townSettings.Scheduler.Policy = value
+
+	case "scheduler.max_per_convoy":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid value for %s: expected non-negative integer (0 = unlimited)", key)
+		}
+		if townSettings.Scheduler == nil {
+			townSettings.Scheduler = capacity.DefaultSchedulerConfig()
+		}
+		townSettings.Scheduler.MaxPerConvoy = n
+
+	case "scheduler.aging_interval":
+		// Validate it parses as a positive duration ("0" disables aging)
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid value for %s: expected Go duration, e.g. 30m, 2h (0 = off)", key)
+		}
+		if townSettings.Scheduler == nil {
+			townSettings.Scheduler = capacity.DefaultSchedulerConfig()
+		}
+		townSettings.Scheduler.AgingInterval = value
+
 	case "maintenance.window", "maintenance.interval", "maintenance.threshold":
 		return setMaintenanceConfig(townRoot, key, value)
 
@@ -756,7 +820,19 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		if strings.HasPrefix(key, "lifecycle.") {
 			return setLifecycleConfig(townRoot, key, value)
 		}
-		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay\n  maintenance.window\n  maintenance.interval\n  maintenance.threshold\n  lifecycle.reaper.*\n  lifecycle.compactor.*\n  lifecycle.doctor.*\n  lifecycle.backup.*", key)
+		if strings.HasPrefix(key, "budget.") {
+			if err := setBudgetConfig(townSettings, key, value); err != nil {
+				return err
+			}
+			break
+		}
+		if handled, err := setSchedulerMapKey(townSettings, key, value); handled {
+			if err != nil {
+				return err
+			}
+			break
+		}
+		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay\n  scheduler.rig_max_polecats.<rig>\n  scheduler.account_max_polecats.<handle>\n  scheduler.agent_max_polecats.<agent>\n  scheduler.policy\n  scheduler.rig_weight.<rig>\n  scheduler.max_per_convoy\n  scheduler.aging_interval\n  budget.town_monthly_usd\n  budget.rig_daily_usd.<rig>\n  budget.convoy_usd.<convoy>\n  budget.warn_percent\n  budget.action\n  maintenance.window\n  maintenance.interval\n  maintenance.threshold\n  lifecycle.reaper.*\n  lifecycle.compactor.*\n  lifecycle.doctor.*\n  lifecycle.backup.*", key)
 	}
 
 	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
@@ -823,6 +899,18 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 		}
 		value = scfg.GetSpawnDelay().String()
 
+	case "scheduler.policy":
+		value = townSettings.Scheduler.GetPolicy()
+
+	case "scheduler.max_per_convoy":
+		value = "0"
+		if townSettings.Scheduler != nil {
+			value = strconv.Itoa(townSettings.Scheduler.MaxPerConvoy)
+		}
+
+	case "scheduler.aging_interval":
+		value = townSettings.Scheduler.GetAgingInterval().String()
+
 	case "maintenance.window", "maintenance.interval", "maintenance.threshold":
 		return getMaintenanceConfig(townRoot, key)
 
@@ -830,13 +918,163 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 		if strings.HasPrefix(key, "lifecycle.") {
 			return getLifecycleConfig(townRoot, key)
 		}
-		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay\n  maintenance.window\n  maintenance.interval\n  maintenance.threshold\n  lifecycle.reaper.*\n  lifecycle.compactor.*\n  lifecycle.doctor.*\n  lifecycle.backup.*", key)
+		if v, ok := getBudgetConfig(townSettings.Budgets, key); ok {
+			value = v
+			break
+		}
+		if v, ok := getSchedulerMapKey(townSettings.Scheduler, key); ok {
+			value = v
+			break
+		}
+		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay\n  scheduler.rig_max_polecats.<rig>\n  scheduler.account_max_polecats.<handle>\n  scheduler.agent_max_polecats.<agent>\n  scheduler.policy\n  scheduler.rig_weight.<rig>\n  scheduler.max_per_convoy\n  scheduler.aging_interval\n  budget.town_monthly_usd\n  budget.rig_daily_usd.<rig>\n  budget.convoy_usd.<convoy>\n  budget.warn_percent\n  budget.action\n  maintenance.window\n  maintenance.interval\n  maintenance.threshold\n  lifecycle.reaper.*\n  lifecycle.compactor.*\n  lifecycle.doctor.*\n  lifecycle.backup.*", key)
 	}
 
 	fmt.Println(value)
 	return nil
 }
 
+// schedulerMapKeys are the per-name scheduler settings, set as
+// "<prefix><name>" (e.g. scheduler.rig_weight.gastown). Each maps to a
+// map[string]int field of SchedulerConfig; fallback is reported by get when
+// the name isn't set.
+var schedulerMapKeys = []struct {
+	prefix   string
+	fallback string
+	field    func(*capacity.SchedulerConfig) *map[string]int
+}{
+	{"scheduler.rig_weight.", "1", func(c *capacity.SchedulerConfig) *map[string]int { return &c.RigWeights }},
+	{"scheduler.rig_max_polecats.", "0", func(c *capacity.SchedulerConfig) *map[string]int { return &c.RigMaxPolecats }},
+	{"scheduler.account_max_polecats.", "0", func(c *capacity.SchedulerConfig) *map[string]int { return &c.AccountMaxPolecats }},
+	{"scheduler.agent_max_polecats.", "0", func(c *capacity.SchedulerConfig) *map[string]int { return &c.AgentMaxPolecats }},
+}
+
+// setSchedulerMapKey sets a per-name scheduler setting. A value of 0 removes
+// the entry (default weight / no limit). Returns handled=false for other keys.
+func setSchedulerMapKey(townSettings *config.TownSettings, key, value string) (handled bool, err error) {
+	for _, mk := range schedulerMapKeys {
+		name, ok := strings.CutPrefix(key, mk.prefix)
+		if !ok || name == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return true, fmt.Errorf("invalid value for %s: expected non-negative integer (0 = unset)", key)
+		}
+		if townSettings.Scheduler == nil {
+			townSettings.Scheduler = capacity.DefaultSchedulerConfig()
+		}
+		m := mk.field(townSettings.Scheduler)
+		if n == 0 {
+			delete(*m, name)
+			return true, nil
+		}
+		if *m == nil {
+			*m = make(map[string]int)
+		}
+		(*m)[name] = n
+		return true, nil
+	}
+	return false, nil
+}
+
+// getSchedulerMapKey reads a per-name scheduler setting.
+func getSchedulerMapKey(scfg *capacity.SchedulerConfig, key string) (string, bool) {
+	for _, mk := range schedulerMapKeys {
+		name, ok := strings.CutPrefix(key, mk.prefix)
+		if !ok || name == "" {
+			continue
+		}
+		if scfg != nil {
+			if n := (*mk.field(scfg))[name]; n > 0 {
+				return strconv.Itoa(n), true
+			}
+		}
+		return mk.fallback, true
+	}
+	return "", false
+}
+
+// setBudgetConfig sets a budget.* key in town settings. A budget of 0
+// removes it.
+func setBudgetConfig(townSettings *config.TownSettings, key, value string) error {
+	cfg := townSettings.Budgets
+	if cfg == nil {
+		cfg = &budget.Config{}
+	}
+
+	switch key {
+	case "budget.town_monthly_usd":
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil || v < 0 {
+			return fmt.Errorf("invalid value for %s: expected non-negative amount in USD (0 = none)", key)
+		}
+		cfg.TownMonthlyUSD = v
+
+	case "budget.warn_percent":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w (expected integer)", key, err)
+		}
+		cfg.WarnPercent = n
+
+	case "budget.action":
+		cfg.Action = value
+
+	default:
+		var m *map[string]float64
+		name, ok := strings.CutPrefix(key, "budget.rig_daily_usd.")
+		if ok {
+			m = &cfg.RigDailyUSD
+		} else if name, ok = strings.CutPrefix(key, "budget.convoy_usd."); ok {
+			m = &cfg.ConvoyUSD
+		}
+		if m == nil || name == "" {
+			return fmt.Errorf("unknown config key: %q", key)
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil || v < 0 {
+			return fmt.Errorf("invalid value for %s: expected non-negative amount in USD (0 = none)", key)
+		}
+		if v == 0 {
+			delete(*m, name)
+			break
+		}
+		if *m == nil {
+			*m = make(map[string]float64)
+		}
+		(*m)[name] = v
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	townSettings.Budgets = cfg
+	return nil
+}
+
+// getBudgetConfig reads a budget.* key.
+func getBudgetConfig(cfg *budget.Config, key string) (string, bool) {
+	formatUSD := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	switch key {
+	case "budget.town_monthly_usd":
+		if cfg == nil {
+			return "0", true
+		}
+		return formatUSD(cfg.TownMonthlyUSD), true
+	case "budget.warn_percent":
+		return strconv.Itoa(cfg.GetWarnPercent()), true
+	case "budget.action":
+		return cfg.GetAction(), true
+	}
+	if name, ok := strings.CutPrefix(key, "budget.rig_daily_usd."); ok && name != "" {
+		return formatUSD(cfg.Limit(budget.ScopeRig, name)), true
+	}
+	if name, ok := strings.CutPrefix(key, "budget.convoy_usd."); ok && name != "" {
+		return formatUSD(cfg.Limit(budget.ScopeConvoy, name)), true
+	}
+	return "", false
+}
+
 // setMaintenanceConfig sets a maintenance.* key in daemon.json (patrol config).
 func setMaintenanceConfig(townRoot, key, value string) error {
 	patrolConfig := daemon.LoadPatrolConfig(townRoot)
@@ -200,7 +200,8 @@ var convoyCreateCmd = &cobra.Command{
 	Long: `Create a new convoy that tracks the specified issues.
 
 The convoy is created in town-level beads (hq-* prefix) and can track
-issues across any rig.
+issues across any rig. Beads in peer towns can be tracked by hop:// URI
+(see 'gt remote'); they are tracked through a local mirror bead.
 
 The --owner flag specifies who requested the convoy (receives completion
 notification by default). If not specified, defaults to created_by.
@@ -257,7 +258,8 @@ If the convoy is closed, it will be automatically reopened.
 
 Examples:
   gt convoy add hq-cv-abc gt-new-issue
-  gt convoy add hq-cv-abc gt-issue1 gt-issue2 gt-issue3`,
+  gt convoy add hq-cv-abc gt-issue1 gt-issue2 gt-issue3
+  gt convoy add hq-cv-abc hop://ops@acme.com/main-town/backend/be-x1`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: runConvoyAdd,
 }
@@ -410,6 +412,12 @@ func getTownBeadsDir() (string, error) {
 }
 
 func runConvoyCreate(cmd *cobra.Command, args []string) error {
+	// Remote (hop://) beads are tracked through town-level mirrors
+	args, err := resolveHopRefs(args)
+	if err != nil {
+		return err
+	}
+
 	name := args[0]
 	trackedIssues := args[1:]
 
@@ -555,7 +563,10 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 
 func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	convoyID := args[0]
-	issuesToAdd := args[1:]
+	issuesToAdd, err := resolveHopRefs(args[1:])
+	if err != nil {
+		return err
+	}
 
 	townBeads, err := getTownBeadsDir()
 	if err != nil {
@@ -647,6 +658,12 @@ func runConvoyCheck(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Pick up remote (hop://) beads closed in peer towns before checking
+	// completion; their mirrors are what convoys actually track.
+	if err := refreshHopMirrors(filepath.Dir(townBeads), convoyCheckDryRun); err != nil {
+		style.PrintWarning("couldn't refresh remote mirrors: %v", err)
+	}
+
 	// If a specific convoy ID is provided, check only that convoy
 	if len(args) == 1 {
 		convoyID := args[0]
@@ -1615,6 +1632,16 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Recorded agent spend (see gt costs --by-convoy)
+	trackedIDs := make([]string, len(tracked))
+	for i, t := range tracked {
+		trackedIDs[i] = t.ID
+	}
+	cost, beadCosts := convoyCosts(townBeads, convoyID, trackedIDs)
+	for i := range tracked {
+		tracked[i].CostUSD = beadCosts[tracked[i].ID]
+	}
+
 	if convoyStatusJSON {
 		lifecycle := "system-managed"
 		if isOwned {
@@ -1630,6 +1657,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Tracked       []trackedIssueInfo `json:"tracked"`
 			Completed     int                `json:"completed"`
 			Total         int                `json:"total"`
+			CostUSD       float64            `json:"cost_usd"`
 		}
 		out := jsonStatus{
 			ID:            convoy.ID,
@@ -1641,6 +1669,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Tracked:       tracked,
 			Completed:     completed,
 			Total:         len(tracked),
+			CostUSD:       cost,
 		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -1661,6 +1690,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Merge:     %s\n", merge)
 	}
 	fmt.Printf("  Progress:  %d/%d completed\n", completed, len(tracked))
+	fmt.Printf("  Cost:      $%.2f\n", cost)
 	fmt.Printf("  Created:   %s\n", convoy.CreatedAt)
 	if convoy.ClosedAt != "" {
 		fmt.Printf("  Closed:    %s\n", convoy.ClosedAt)
@@ -1695,6 +1725,9 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 				}
 				line += fmt.Sprintf("  %s", style.Dim.Render(workerDisplay))
 			}
+			if t.CostUSD > 0 {
+				line += fmt.Sprintf("  %s", style.Dim.Render(fmt.Sprintf("$%.2f", t.CostUSD)))
+			}
 			fmt.Println(line)
 		}
 	}
@@ -1974,6 +2007,7 @@ type trackedIssueInfo struct {
 	Labels    []string `json:"labels,omitempty"`     // Bead labels (propagated from trackedDependency)
 	Worker    string   `json:"worker,omitempty"`     // Worker currently assigned (e.g., gastown/nux)
 	WorkerAge string   `json:"worker_age,omitempty"` // How long worker has been on this issue
+	CostUSD   float64  `json:"cost_usd,omitempty"`   // Recorded agent spend attributed to this issue
 }
 
 // trackedDependency is dep-list data enriched with fresh issue details.
@@ -2,14 +2,13 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
-	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -20,16 +19,21 @@ import (
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/usage"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
-	costsJSON    bool
-	costsToday   bool
-	costsWeek    bool
-	costsByRole  bool
-	costsByRig   bool
-	costsVerbose bool
+	costsJSON      bool
+	costsToday     bool
+	costsWeek      bool
+	costsByRole    bool
+	costsByRig     bool
+	costsByAgent   bool
+	costsByConvoy  bool
+	costsByBead    bool
+	costsByFormula bool
+	costsVerbose   bool
 
 	// Record subcommand flags
 	recordSession  string
@@ -47,11 +51,18 @@ var (
 var costsCmd = &cobra.Command{
 	Use:     "costs",
 	GroupID: GroupDiag,
-	Short:   "Show costs for running Claude sessions",
-	Long: `Display costs for Claude Code sessions in Gas Town.
+	Short:   "Show costs for running agent sessions",
+	Long: `Display costs for agent sessions in Gas Town.
 
-Costs are calculated from Claude Code transcript files at ~/.claude/projects/
-by summing token usage from assistant messages and applying model-specific pricing.
+Costs are calculated from each agent runtime's session log (Claude Code
+transcripts, Codex rollouts, Gemini chats, OpenCode and Pi sessions) by summing
+token usage and applying model-specific pricing. The session's runtime is read
+from GT_AGENT. Cursor, Amp, Copilot and Auggie sessions are not metered:
+their usage isn't readable, so they show "n/a" and are left out of totals
+and the cost log. Override or extend the pricing table with "pricing" in
+settings/config.json:
+
+  "pricing": {"gpt-5-codex": {"input_per_million": 1.25, "output_per_million": 10}}
 
 Examples:
   gt costs              # Live costs from running sessions
@@ -59,6 +70,10 @@ Examples:
   gt costs --week       # This week's costs from digest beads + today's log
   gt costs --by-role    # Breakdown by role (polecat, witness, etc.)
   gt costs --by-rig     # Breakdown by rig
+  gt costs --by-agent   # Breakdown by agent runtime (claude, codex, ...)
+  gt costs --by-convoy  # Breakdown by convoy
+  gt costs --by-bead    # Breakdown by work bead
+  gt costs --by-formula # Breakdown by formula (compare kinds of work)
   gt costs --json       # Output as JSON
   gt costs -v           # Show debug output for failures
 
@@ -73,15 +88,26 @@ var costsRecordCmd = &cobra.Command{
 	Short: "Record session cost to local log file (called by Stop hook)",
 	Long: `Record the final cost of a session to a local log file.
 
-This command is intended to be called from a Claude Code Stop hook.
-It reads token usage from the Claude Code transcript file (~/.claude/projects/...)
-and calculates the cost based on model pricing, then appends it to
+This command is intended to be called from an agent's Stop hook (or from
+'gt done' for runtimes without hooks). It reads token usage from the session
+log of the agent named by GT_AGENT (Claude Code transcripts by default) and
+calculates the cost based on model pricing, then appends it to
 ~/.gt/costs.jsonl. This is a simple append operation that never fails
 due to database availability.
 
+Each cost is linked to the session's work: the --work-item bead, or else the
+bead hooked by the session's agent, plus the convoy and formula/molecule
+recorded on that bead when it was slung. See gt costs --by-convoy,
+--by-bead and --by-formula.
+
 Session costs are aggregated daily by 'gt costs digest' into a single
 permanent "Cost Report YYYY-MM-DD" bead for audit purposes.
 
+When budgets are configured (gt config set budget.*), each recorded cost is
+also charged against the town, rig and convoy budgets. Reaching a budget's
+warning level or limit escalates; an exceeded budget can hold scheduled
+work (action "pause") or also refuse gt sling (action "stop").
+
 Examples:
   gt costs record --session gt-gastown-toast
   gt costs record --session gt-gastown-toast --work-item gt-abc123`,
@@ -135,12 +161,16 @@ func init() {
 	costsCmd.Flags().BoolVar(&costsWeek, "week", false, "Show this week's total from session events")
 	costsCmd.Flags().BoolVar(&costsByRole, "by-role", false, "Show breakdown by role")
 	costsCmd.Flags().BoolVar(&costsByRig, "by-rig", false, "Show breakdown by rig")
+	costsCmd.Flags().BoolVar(&costsByAgent, "by-agent", false, "Show breakdown by agent runtime")
+	costsCmd.Flags().BoolVar(&costsByConvoy, "by-convoy", false, "Show breakdown by convoy")
+	costsCmd.Flags().BoolVar(&costsByBead, "by-bead", false, "Show breakdown by work bead")
+	costsCmd.Flags().BoolVar(&costsByFormula, "by-formula", false, "Show breakdown by formula")
 	costsCmd.Flags().BoolVarP(&costsVerbose, "verbose", "v", false, "Show debug output for failures")
 
 	// Add record subcommand
 	costsCmd.AddCommand(costsRecordCmd)
 	costsRecordCmd.Flags().StringVar(&recordSession, "session", "", "Tmux session name to record")
-	costsRecordCmd.Flags().StringVar(&recordWorkItem, "work-item", "", "Work item ID (bead) for attribution")
+	costsRecordCmd.Flags().StringVar(&recordWorkItem, "work-item", "", "Work item ID (bead) for attribution (default: the hooked bead)")
 
 	// Add digest subcommand
 	costsCmd.AddCommand(costsDigestCmd)
@@ -159,8 +189,12 @@ type SessionCost struct {
 	Role    string  `json:"role"`
 	Rig     string  `json:"rig,omitempty"`
 	Worker  string  `json:"worker,omitempty"`
+	Agent   string  `json:"agent,omitempty"`
 	Cost    float64 `json:"cost_usd"`
 	Running bool    `json:"running"`
+	// Unmetered is set for runtimes without a usage provider (Cursor, Amp,
+	// Copilot, Auggie): their spend is unknown, not zero.
+	Unmetered bool `json:"unmetered,omitempty"`
 }
 
 // CostEntry is a ledger entry for historical cost tracking.
@@ -169,77 +203,35 @@ type CostEntry struct {
 	Role      string    `json:"role"`
 	Rig       string    `json:"rig,omitempty"`
 	Worker    string    `json:"worker,omitempty"`
+	Agent     string    `json:"agent,omitempty"`
 	CostUSD   float64   `json:"cost_usd"`
 	StartedAt time.Time `json:"started_at"`
 	EndedAt   time.Time `json:"ended_at"`
 	WorkItem  string    `json:"work_item,omitempty"`
+	Convoy    string    `json:"convoy,omitempty"`
+	Formula   string    `json:"formula,omitempty"`
+	Molecule  string    `json:"molecule,omitempty"`
 }
 
 // CostsOutput is the JSON output structure.
 type CostsOutput struct {
-	Sessions []SessionCost      `json:"sessions,omitempty"`
-	Total    float64            `json:"total_usd"`
-	ByRole   map[string]float64 `json:"by_role,omitempty"`
-	ByRig    map[string]float64 `json:"by_rig,omitempty"`
-	Period   string             `json:"period,omitempty"`
+	Sessions  []SessionCost      `json:"sessions,omitempty"`
+	Total     float64            `json:"total_usd"`
+	ByRole    map[string]float64 `json:"by_role,omitempty"`
+	ByRig     map[string]float64 `json:"by_rig,omitempty"`
+	ByAgent   map[string]float64 `json:"by_agent,omitempty"`
+	ByConvoy  map[string]float64 `json:"by_convoy,omitempty"`
+	ByBead    map[string]float64 `json:"by_bead,omitempty"`
+	ByFormula map[string]float64 `json:"by_formula,omitempty"`
+	Period    string             `json:"period,omitempty"`
 }
 
 // costRegex matches cost patterns like "$1.23" or "$12.34"
 var costRegex = regexp.MustCompile(`\$(\d+\.\d{2})`)
 
-// TranscriptMessage represents a message from a Claude Code transcript file.
-type TranscriptMessage struct {
-	Type      string                 `json:"type"`
-	SessionID string                 `json:"sessionId"`
-	CWD       string                 `json:"cwd"`
-	Message   *TranscriptMessageBody `json:"message,omitempty"`
-}
-
-// TranscriptMessageBody contains the message content and usage info.
-type TranscriptMessageBody struct {
-	Model string          `json:"model"`
-	Role  string          `json:"role"`
-	Usage *TranscriptUsage `json:"usage,omitempty"`
-}
-
-// TranscriptUsage contains token usage information.
-type TranscriptUsage struct {
-	InputTokens              int `json:"input_tokens"`
-	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
-	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
-	OutputTokens             int `json:"output_tokens"`
-}
-
-// TokenUsage aggregates token usage across a session.
-type TokenUsage struct {
-	Model                    string
-	InputTokens              int
-	CacheCreationInputTokens int
-	CacheReadInputTokens     int
-	OutputTokens             int
-}
-
-// Model pricing per million tokens (as of Jan 2025).
-// See: https://www.anthropic.com/pricing
-var modelPricing = map[string]struct {
-	InputPerMillion       float64
-	OutputPerMillion      float64
-	CacheReadPerMillion   float64 // 90% discount on input price
-	CacheCreatePerMillion float64 // 25% premium on input price
-}{
-	// Claude Opus 4.5
-	"claude-opus-4-5-20251101": {15.0, 75.0, 1.5, 18.75},
-	// Claude Sonnet 4
-	"claude-sonnet-4-20250514": {3.0, 15.0, 0.3, 3.75},
-	// Claude Haiku 3.5
-	"claude-3-5-haiku-20241022": {1.0, 5.0, 0.1, 1.25},
-	// Fallback for unknown models (use Sonnet pricing)
-	"default": {3.0, 15.0, 0.3, 3.75},
-}
-
 func runCosts(cmd *cobra.Command, args []string) error {
 	// If querying ledger, use ledger functions
-	if costsToday || costsWeek || costsByRole || costsByRig {
+	if costsToday || costsWeek || costsBreakdownRequested() {
 		return runCostsFromLedger()
 	}
 
@@ -247,6 +239,11 @@ func runCosts(cmd *cobra.Command, args []string) error {
 	return runLiveCosts()
 }
 
+// costsBreakdownRequested reports whether any --by-* flag is set.
+func costsBreakdownRequested() bool {
+	return costsByRole || costsByRig || costsByAgent || costsByConvoy || costsByBead || costsByFormula
+}
+
 func runLiveCosts() error {
 	t := tmux.NewTmux()
 
@@ -258,6 +255,7 @@ func runLiveCosts() error {
 
 	var costs []SessionCost
 	var total float64
+	settings := loadCostsTownSettings()
 
 	for _, sess := range sessions {
 		// Only process Gas Town sessions
@@ -265,9 +263,6 @@ func runLiveCosts() error {
 			continue
 		}
 
-		// Parse session name to get role/rig/worker
-		role, rig, worker := parseSessionName(sess)
-
 		// Get working directory of the session
 		workDir, err := getTmuxSessionWorkDir(sess)
 		if err != nil {
@@ -277,28 +272,31 @@ func runLiveCosts() error {
 			continue
 		}
 
-		// Extract cost from Claude transcript
-		cost, err := extractCostFromWorkDir(workDir)
-		if err != nil {
-			if costsVerbose {
-				fmt.Fprintf(os.Stderr, "[costs] could not extract cost for %s: %v\n", sess, err)
+		c := liveSessionCost(t, sess, workDir, t.IsAgentRunning(sess), settings)
+		costs = append(costs, c)
+		total += c.Cost
+	}
+
+	// Polecats run headless under the PTY supervisor when the town selects
+	// it; their sessions aren't in tmux.
+	townRoot, _ := workspace.FindFromCwd()
+	if backend := session.PolecatBackend(townRoot, t); session.AsTmux(backend) == nil {
+		ptySessions, err := backend.ListSessions()
+		if err != nil && costsVerbose {
+			fmt.Fprintf(os.Stderr, "[costs] could not list headless sessions: %v\n", err)
+		}
+		for _, sess := range ptySessions {
+			workDir, _ := backend.GetEnvironment(sess, "GT_POLECAT_PATH")
+			if workDir == "" {
+				if costsVerbose {
+					fmt.Fprintf(os.Stderr, "[costs] could not get workdir for %s\n", sess)
+				}
+				continue
 			}
-			// Still include the session with zero cost
-			cost = 0.0
+			c := liveSessionCost(backend, sess, workDir, backend.IsAgentAlive(sess), settings)
+			costs = append(costs, c)
+			total += c.Cost
 		}
-
-		// Check if an agent appears to be running
-		running := t.IsAgentRunning(sess)
-
-		costs = append(costs, SessionCost{
-			Session: sess,
-			Role:    role,
-			Rig:     rig,
-			Worker:  worker,
-			Cost:    cost,
-			Running: running,
-		})
-		total += cost
 	}
 
 	// Sort by session name
@@ -316,9 +314,40 @@ func runLiveCosts() error {
 	return outputCostsHuman(costs, total)
 }
 
+// liveSessionCost reads the running cost of a session's agent from its
+// session log. Sessions whose cost can't be read are reported at zero.
+func liveSessionCost(backend session.SessionBackend, sess, workDir string, running bool, settings *config.TownSettings) SessionCost {
+	// Parse session name to get role/rig/worker
+	role, rig, worker := parseSessionName(sess)
+
+	// Extract cost from the agent's session log
+	agent, _ := backend.GetEnvironment(sess, "GT_AGENT")
+	unmetered := costsUsageProvider(agent, settings) == ""
+	var cost float64
+	if !unmetered {
+		var err error
+		cost, _, err = extractSessionCost(agent, workDir, settings)
+		if err != nil && costsVerbose {
+			fmt.Fprintf(os.Stderr, "[costs] could not extract cost for %s: %v\n", sess, err)
+		}
+	}
+
+	return SessionCost{
+		Session:   sess,
+		Role:      role,
+		Rig:       rig,
+		Worker:    worker,
+		Agent:     costsAgentName(agent),
+		Cost:      cost,
+		Running:   running,
+		Unmetered: unmetered,
+	}
+}
+
 func runCostsFromLedger() error {
 	now := time.Now()
 	var entries []CostEntry
+	var digests []CostDigest
 	var err error
 
 	if costsToday {
@@ -331,7 +360,7 @@ func runCostsFromLedger() error {
 	} else if costsWeek {
 		// For week: query digest beads (costs.digest events)
 		// These are the aggregated daily reports
-		entries, err = queryDigestBeads(7)
+		digests, err = queryCostDigests("", 7)
 		if err != nil {
 			return fmt.Errorf("querying digest beads: %w", err)
 		}
@@ -339,8 +368,8 @@ func runCostsFromLedger() error {
 		// Also include today's wisps (not yet digested)
 		todayEntries, _ := querySessionCostEntries(now)
 		entries = append(entries, todayEntries...)
-	} else if costsByRole || costsByRig {
-		// When using --by-role or --by-rig without time filter, default to today
+	} else if costsBreakdownRequested() {
+		// When using a --by-* breakdown without time filter, default to today
 		// (querying all historical events would be expensive and likely empty)
 		entries, err = querySessionCostEntries(now)
 		if err != nil {
@@ -352,34 +381,42 @@ func runCostsFromLedger() error {
 		entries = querySessionEvents()
 	}
 
-	if len(entries) == 0 {
+	if len(entries) == 0 && len(digests) == 0 {
 		fmt.Println(style.Dim.Render("No cost data found. Costs are recorded when sessions end."))
 		return nil
 	}
 
 	// Calculate totals
-	var total float64
-	byRole := make(map[string]float64)
-	byRig := make(map[string]float64)
-
+	breakdown := newCostBreakdown()
+	for _, digest := range digests {
+		breakdown.addDigest(digest)
+	}
 	for _, entry := range entries {
-		total += entry.CostUSD
-		byRole[entry.Role] += entry.CostUSD
-		if entry.Rig != "" {
-			byRig[entry.Rig] += entry.CostUSD
-		}
+		breakdown.addEntry(entry)
 	}
 
 	// Build output
 	output := CostsOutput{
-		Total: total,
+		Total: breakdown.Total,
 	}
 
 	if costsByRole {
-		output.ByRole = byRole
+		output.ByRole = breakdown.ByRole
 	}
 	if costsByRig {
-		output.ByRig = byRig
+		output.ByRig = breakdown.ByRig
+	}
+	if costsByAgent {
+		output.ByAgent = breakdown.ByAgent
+	}
+	if costsByConvoy {
+		output.ByConvoy = breakdown.ByConvoy
+	}
+	if costsByBead {
+		output.ByBead = breakdown.ByBead
+	}
+	if costsByFormula {
+		output.ByFormula = breakdown.ByFormula
 	}
 
 	// Set period label
@@ -393,7 +430,7 @@ func runCostsFromLedger() error {
 		return outputCostsJSON(output)
 	}
 
-	return outputLedgerHuman(output, entries)
+	return outputLedgerHuman(output, breakdown.Sessions)
 }
 
 // SessionEvent represents a session.ended event from beads.
@@ -562,8 +599,10 @@ func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
 	return entries, nil
 }
 
-// queryDigestBeads queries costs.digest events from the past N days and extracts session entries.
-func queryDigestBeads(days int) ([]CostEntry, error) {
+// queryCostDigests queries costs.digest events from the past N days (all
+// digests when days <= 0). bd runs in dir, or the current directory when
+// dir is empty.
+func queryCostDigests(dir string, days int) ([]CostDigest, error) {
 	// Get list of event IDs
 	listArgs := []string{
 		"list",
@@ -574,6 +613,7 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	}
 
 	listCmd := exec.Command("bd", listArgs...)
+	listCmd.Dir = dir
 	listOutput, err := listCmd.Output()
 	if err != nil {
 		return nil, nil
@@ -595,6 +635,7 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	}
 
 	showCmd := exec.Command("bd", showArgs...)
+	showCmd.Dir = dir
 	showOutput, err := showCmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("showing events: %w", err)
@@ -606,10 +647,12 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	}
 
 	// Calculate date range
-	now := time.Now()
-	cutoff := now.AddDate(0, 0, -days)
+	var cutoff time.Time
+	if days > 0 {
+		cutoff = time.Now().AddDate(0, 0, -days)
+	}
 
-	var entries []CostEntry
+	var digests []CostDigest
 	for _, event := range events {
 		// Filter for costs.digest events only
 		if event.EventKind != "costs.digest" {
@@ -633,23 +676,10 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 			continue
 		}
 
-		// If the digest has per-session data (old format), use it directly.
-		// Otherwise, synthesize entries from the aggregate ByRole data.
-		if len(digest.Sessions) > 0 {
-			entries = append(entries, digest.Sessions...)
-		} else {
-			for role, cost := range digest.ByRole {
-				entries = append(entries, CostEntry{
-					SessionID: fmt.Sprintf("digest-%s-%s", digest.Date, role),
-					Role:      role,
-					CostUSD:   cost,
-					EndedAt:   digestDate,
-				})
-			}
-		}
+		digests = append(digests, digest)
 	}
 
-	return entries, nil
+	return digests, nil
 }
 
 // parseSessionName extracts role, rig, and worker from a session name.
@@ -699,144 +729,58 @@ func extractCost(content string) float64 {
 	return cost
 }
 
-// getClaudeProjectDir returns the Claude Code project directory for a working directory.
-// Claude Code stores transcripts in ~/.claude/projects/<path-with-dashes-instead-of-slashes>/
-func getClaudeProjectDir(workDir string) (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-
-	// Convert path to Claude's directory naming: replace / with -
-	// Keep leading slash - it becomes a leading dash in Claude's encoding
-	projectName := strings.ReplaceAll(workDir, "/", "-")
-	return filepath.Join(home, ".claude", "projects", projectName), nil
-}
-
-// findLatestTranscript finds the most recently modified .jsonl file in a directory.
-func findLatestTranscript(projectDir string) (string, error) {
-	var latestPath string
-	var latestTime time.Time
-
-	err := filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if d.IsDir() && path != projectDir {
-			return fs.SkipDir // Don't recurse into subdirectories
-		}
-		if !d.IsDir() && strings.HasSuffix(path, ".jsonl") {
-			info, err := d.Info()
-			if err != nil {
-				return nil // Skip files we can't stat
-			}
-			if info.ModTime().After(latestTime) {
-				latestTime = info.ModTime()
-				latestPath = path
-			}
-		}
-		return nil
-	})
-
+// extractSessionCost computes the cost of the latest session of agent in
+// workDir, using the usage extractor for the agent's runtime and the town's
+// pricing table. It also returns the agent session the cost was read from.
+func extractSessionCost(agent, workDir string, settings *config.TownSettings) (float64, string, error) {
+	provider := costsUsageProvider(agent, settings)
+	usages, err := usage.Extract(provider, workDir)
 	if err != nil {
-		return "", err
+		return 0, "", fmt.Errorf("reading %s usage: %w", costsAgentName(agent), err)
 	}
-	if latestPath == "" {
-		return "", fmt.Errorf("no transcript files found in %s", projectDir)
+	var pricing map[string]*config.ModelPricing
+	if settings != nil {
+		pricing = settings.Pricing
 	}
-	return latestPath, nil
+	return usage.Cost(usages, pricing), usage.SessionOf(usages), nil
 }
 
-// parseTranscriptUsage reads a transcript file and sums token usage from assistant messages.
-func parseTranscriptUsage(transcriptPath string) (*TokenUsage, error) {
-	file, err := os.Open(transcriptPath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	usage := &TokenUsage{}
-	scanner := bufio.NewScanner(file)
-	// Increase buffer for potentially large JSON lines
-	buf := make([]byte, 0, 256*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-
-		var msg TranscriptMessage
-		if err := json.Unmarshal(line, &msg); err != nil {
-			continue // Skip malformed lines
-		}
-
-		// Only process assistant messages with usage info
-		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
-			continue
-		}
-
-		// Capture the model (use first one found, they should all be the same)
-		if usage.Model == "" && msg.Message.Model != "" {
-			usage.Model = msg.Message.Model
+// costsUsageProvider resolves the usage log format for an agent name,
+// following custom agents in town settings to the runtime they launch.
+func costsUsageProvider(agent string, settings *config.TownSettings) string {
+	var command string
+	if settings != nil {
+		if rc := settings.Agents[agent]; rc != nil {
+			if rc.Provider != "" && config.GetAgentPresetByName(rc.Provider) != nil {
+				return config.GetUsageProvider(rc.Provider, "")
+			}
+			command = rc.Command
 		}
-
-		// Sum token usage
-		u := msg.Message.Usage
-		usage.InputTokens += u.InputTokens
-		usage.CacheCreationInputTokens += u.CacheCreationInputTokens
-		usage.CacheReadInputTokens += u.CacheReadInputTokens
-		usage.OutputTokens += u.OutputTokens
 	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-
-	return usage, nil
+	return config.GetUsageProvider(agent, command)
 }
 
-// calculateCost converts token usage to USD cost based on model pricing.
-func calculateCost(usage *TokenUsage) float64 {
-	if usage == nil {
-		return 0.0
-	}
-
-	// Look up pricing for the model
-	pricing, ok := modelPricing[usage.Model]
-	if !ok {
-		pricing = modelPricing["default"]
+// costsAgentName returns the agent name costs are attributed to. Sessions
+// and log entries without GT_AGENT predate non-Claude runtimes.
+func costsAgentName(agent string) string {
+	if agent == "" {
+		return string(config.AgentClaude)
 	}
-
-	// Calculate cost (prices are per million tokens)
-	inputCost := float64(usage.InputTokens) / 1_000_000 * pricing.InputPerMillion
-	cacheReadCost := float64(usage.CacheReadInputTokens) / 1_000_000 * pricing.CacheReadPerMillion
-	cacheCreateCost := float64(usage.CacheCreationInputTokens) / 1_000_000 * pricing.CacheCreatePerMillion
-	outputCost := float64(usage.OutputTokens) / 1_000_000 * pricing.OutputPerMillion
-
-	return inputCost + cacheReadCost + cacheCreateCost + outputCost
+	return agent
 }
 
-// extractCostFromWorkDir extracts cost from Claude Code transcript for a working directory.
-// This reads the most recent transcript file and sums all token usage.
-func extractCostFromWorkDir(workDir string) (float64, error) {
-	projectDir, err := getClaudeProjectDir(workDir)
-	if err != nil {
-		return 0, fmt.Errorf("getting project dir: %w", err)
-	}
-
-	transcriptPath, err := findLatestTranscript(projectDir)
-	if err != nil {
-		return 0, fmt.Errorf("finding transcript: %w", err)
+// loadCostsTownSettings loads town settings for custom agents and pricing
+// overrides. Returns nil outside a town or when settings can't be read.
+func loadCostsTownSettings() *config.TownSettings {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
 	}
-
-	usage, err := parseTranscriptUsage(transcriptPath)
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
 	if err != nil {
-		return 0, fmt.Errorf("parsing transcript: %w", err)
+		return nil
 	}
-
-	return calculateCost(usage), nil
+	return settings
 }
 
 // getTmuxSessionWorkDir gets the current working directory of a tmux session.
@@ -864,11 +808,12 @@ func outputCostsHuman(costs []SessionCost, total float64) error {
 	fmt.Printf("\n%s Live Session Costs\n\n", style.Bold.Render("💰"))
 
 	// Print table header
-	fmt.Printf("%-25s %-10s %-15s %10s %8s\n",
-		"Session", "Role", "Rig/Worker", "Cost", "Status")
-	fmt.Println(strings.Repeat("─", 75))
+	fmt.Printf("%-25s %-10s %-15s %-10s %10s %8s\n",
+		"Session", "Role", "Rig/Worker", "Agent", "Cost", "Status")
+	fmt.Println(strings.Repeat("─", 86))
 
 	// Print each session
+	var unmetered []string
 	for _, c := range costs {
 		statusIcon := style.Success.Render("●")
 		if !c.Running {
@@ -884,22 +829,36 @@ func outputCostsHuman(costs []SessionCost, total float64) error {
 			}
 		}
 
-		fmt.Printf("%-25s %-10s %-15s %10s %8s\n",
+		cost := fmt.Sprintf("$%.2f", c.Cost)
+		if c.Unmetered {
+			cost = "n/a"
+			if !slices.Contains(unmetered, c.Agent) {
+				unmetered = append(unmetered, c.Agent)
+			}
+		}
+
+		fmt.Printf("%-25s %-10s %-15s %-10s %10s %8s\n",
 			c.Session,
 			c.Role,
 			rigWorker,
-			fmt.Sprintf("$%.2f", c.Cost),
+			c.Agent,
+			cost,
 			statusIcon)
 	}
 
 	// Print total
-	fmt.Println(strings.Repeat("─", 75))
+	fmt.Println(strings.Repeat("─", 86))
 	fmt.Printf("%s %s\n", style.Bold.Render("Total:"), fmt.Sprintf("$%.2f", total))
+	if len(unmetered) > 0 {
+		sort.Strings(unmetered)
+		fmt.Println(style.Dim.Render(fmt.Sprintf("Not metered (no usage log format): %s — excluded from total",
+			strings.Join(unmetered, ", "))))
+	}
 
 	return nil
 }
 
-func outputLedgerHuman(output CostsOutput, entries []CostEntry) error {
+func outputLedgerHuman(output CostsOutput, sessions int) error {
 	periodStr := ""
 	if output.Period != "" {
 		periodStr = fmt.Sprintf(" (%s)", output.Period)
@@ -927,21 +886,53 @@ func outputLedgerHuman(output CostsOutput, entries []CostEntry) error {
 		}
 	}
 
+	// By agent breakdown
+	if len(output.ByAgent) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("By Agent:"))
+		for agent, cost := range output.ByAgent {
+			fmt.Printf("  %-15s $%.2f\n", agent, cost)
+		}
+	}
+
+	printCostsByCost("By Convoy:", output.ByConvoy)
+	printCostsByCost("By Bead:", output.ByBead)
+	printCostsByCost("By Formula:", output.ByFormula)
+
 	// Session count
-	fmt.Printf("\n%s %d sessions\n", style.Dim.Render("Entries:"), len(entries))
+	fmt.Printf("\n%s %d sessions\n", style.Dim.Render("Entries:"), sessions)
 
 	return nil
 }
 
+// printCostsByCost prints a breakdown section, most expensive first.
+func printCostsByCost(title string, costs map[string]float64) {
+	if len(costs) == 0 {
+		return
+	}
+	fmt.Printf("\n%s\n", style.Bold.Render(title))
+	for _, key := range sortedCostKeys(costs) {
+		fmt.Printf("  %-20s $%.2f\n", key, costs[key])
+	}
+}
+
 // CostLogEntry represents a single entry in the costs.jsonl log file.
 type CostLogEntry struct {
 	SessionID string    `json:"session_id"`
 	Role      string    `json:"role"`
 	Rig       string    `json:"rig,omitempty"`
 	Worker    string    `json:"worker,omitempty"`
+	Agent     string    `json:"agent,omitempty"`
 	CostUSD   float64   `json:"cost_usd"`
 	EndedAt   time.Time `json:"ended_at"`
 	WorkItem  string    `json:"work_item,omitempty"`
+	Convoy    string    `json:"convoy,omitempty"`
+	Formula   string    `json:"formula,omitempty"`
+	Molecule  string    `json:"molecule,omitempty"`
+
+	// AgentSession is the agent's own session (transcript path or runtime
+	// session ID) the cost was read from. Unlike SessionID, the tmux session
+	// name, it is not reused by a later run.
+	AgentSession string `json:"agent_session,omitempty"`
 }
 
 // getCostsLogPath returns the path to the costs log file.
@@ -951,7 +942,7 @@ func getCostsLogPath() string {
 }
 
 // runCostsRecord captures the final cost from a session and appends it to a local log file.
-// This is called by the Claude Code Stop hook. It's designed to never fail due to
+// This is called by the agent's Stop hook. It's designed to never fail due to
 // database availability - it's a simple file append operation.
 func runCostsRecord(cmd *cobra.Command, args []string) error {
 	// Get session from flag or try to detect from environment
@@ -976,6 +967,26 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	cost, err := recordSessionCost(session, recordWorkItem)
+	if err != nil {
+		return err
+	}
+
+	// Output confirmation (silent if cost is zero and no work item)
+	if cost > 0 || recordWorkItem != "" {
+		fmt.Printf("%s Recorded $%.2f for %s", style.Success.Render("✓"), cost, session)
+		if recordWorkItem != "" {
+			fmt.Printf(" (work: %s)", recordWorkItem)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// recordSessionCost extracts the cost of session's latest agent run and
+// appends it to the costs log, returning the recorded cost.
+func recordSessionCost(session, workItem string) (float64, error) {
 	// Get working directory from environment or tmux session
 	workDir := os.Getenv("GT_CWD")
 	if workDir == "" {
@@ -989,14 +1000,22 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Extract cost from Claude transcript
+	// Resolve the agent runtime from the environment or tmux session
+	agent := os.Getenv("GT_AGENT")
+	if agent == "" {
+		agent, _ = tmux.NewTmux().GetEnvironment(session, "GT_AGENT")
+	}
+
+	// Extract cost from the agent's session log
+	settings := loadCostsTownSettings()
 	var cost float64
+	var agentSession string
 	if workDir != "" {
 		var err error
-		cost, err = extractCostFromWorkDir(workDir)
+		cost, agentSession, err = extractSessionCost(agent, workDir, settings)
 		if err != nil {
 			if costsVerbose {
-				fmt.Fprintf(os.Stderr, "[costs] could not extract cost from transcript: %v\n", err)
+				fmt.Fprintf(os.Stderr, "[costs] could not extract cost from session log: %v\n", err)
 			}
 			cost = 0.0
 		}
@@ -1005,21 +1024,29 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	// Parse session name
 	role, rig, worker := parseSessionName(session)
 
+	// Link the cost to the session's bead, convoy and formula
+	work := attributeSessionWork(session, workDir, workItem)
+
 	// Build log entry
 	entry := CostLogEntry{
-		SessionID: session,
-		Role:      role,
-		Rig:       rig,
-		Worker:    worker,
-		CostUSD:   cost,
-		EndedAt:   time.Now(),
-		WorkItem:  recordWorkItem,
+		SessionID:    session,
+		AgentSession: agentSession,
+		Role:         role,
+		Rig:          rig,
+		Worker:       worker,
+		Agent:        costsAgentName(agent),
+		CostUSD:      cost,
+		EndedAt:      time.Now(),
+		WorkItem:     work.Bead,
+		Convoy:       work.Convoy,
+		Formula:      work.Formula,
+		Molecule:     work.Molecule,
 	}
 
 	// Marshal to JSON
 	entryJSON, err := json.Marshal(entry)
 	if err != nil {
-		return fmt.Errorf("marshaling cost entry: %w", err)
+		return 0, fmt.Errorf("marshaling cost entry: %w", err)
 	}
 
 	// Append to log file
@@ -1028,7 +1055,7 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	// Ensure directory exists
 	logDir := filepath.Dir(logPath)
 	if err := os.MkdirAll(logDir, 0755); err != nil {
-		return fmt.Errorf("creating log directory: %w", err)
+		return 0, fmt.Errorf("creating log directory: %w", err)
 	}
 
 	// Open file for append (create if doesn't exist).
@@ -1036,25 +1063,42 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	// A JSON log entry is ~200 bytes, so concurrent appends are safe.
 	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
-		return fmt.Errorf("opening costs log: %w", err)
+		return 0, fmt.Errorf("opening costs log: %w", err)
 	}
 	defer f.Close()
 
 	// Write entry with newline
 	if _, err := f.Write(append(entryJSON, '\n')); err != nil {
-		return fmt.Errorf("writing to costs log: %w", err)
+		return 0, fmt.Errorf("writing to costs log: %w", err)
 	}
 
-	// Output confirmation (silent if cost is zero and no work item)
-	if cost > 0 || recordWorkItem != "" {
-		fmt.Printf("%s Recorded $%.2f for %s", style.Success.Render("✓"), cost, session)
-		if recordWorkItem != "" {
-			fmt.Printf(" (work: %s)", recordWorkItem)
-		}
-		fmt.Println()
-	}
+	trackBudgetSpend(settings, entry)
 
-	return nil
+	return cost, nil
+}
+
+// recordCostOnDone records the session cost from gt done for runtimes
+// without lifecycle hooks (e.g., Codex), since no Stop hook runs
+// `gt costs record` for them. Custom agents are skipped: whether they run a
+// hook can't be told from the registry, and recording twice double-counts.
+// Best effort.
+func recordCostOnDone(workItem string) {
+	info := config.GetAgentPresetByName(os.Getenv("GT_AGENT"))
+	if info == nil || info.SupportsHooks || info.UsageProvider == "" {
+		return
+	}
+	session := os.Getenv("GT_SESSION")
+	if session == "" {
+		session = deriveSessionName()
+	}
+	if session == "" {
+		return
+	}
+	if cost, err := recordSessionCost(session, workItem); err != nil {
+		style.PrintWarning("could not record session cost: %v", err)
+	} else if cost > 0 {
+		fmt.Printf("%s Recorded session cost $%.2f\n", style.Bold.Render("✓"), cost)
+	}
 }
 
 // deriveSessionName derives the tmux session name from GT_* environment variables.
@@ -1139,6 +1183,10 @@ type CostDigest struct {
 	Sessions     []CostEntry        `json:"sessions,omitempty"`
 	ByRole       map[string]float64 `json:"by_role"`
 	ByRig        map[string]float64 `json:"by_rig,omitempty"`
+	ByAgent      map[string]float64 `json:"by_agent,omitempty"`
+	ByConvoy     map[string]float64 `json:"by_convoy,omitempty"`
+	ByBead       map[string]float64 `json:"by_bead,omitempty"`
+	ByFormula    map[string]float64 `json:"by_formula,omitempty"`
 }
 
 // CostDigestPayload is the compact payload stored in the bead.
@@ -1149,6 +1197,10 @@ type CostDigestPayload struct {
 	SessionCount int                `json:"session_count"`
 	ByRole       map[string]float64 `json:"by_role"`
 	ByRig        map[string]float64 `json:"by_rig,omitempty"`
+	ByAgent      map[string]float64 `json:"by_agent,omitempty"`
+	ByConvoy     map[string]float64 `json:"by_convoy,omitempty"`
+	ByBead       map[string]float64 `json:"by_bead,omitempty"`
+	ByFormula    map[string]float64 `json:"by_formula,omitempty"`
 }
 
 // runCostsDigest aggregates session cost entries into a daily digest bead.
@@ -1182,20 +1234,21 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 	}
 
 	// Build digest
-	digest := CostDigest{
-		Date:     dateStr,
-		Sessions: costEntries,
-		ByRole:   make(map[string]float64),
-		ByRig:    make(map[string]float64),
-	}
-
+	breakdown := newCostBreakdown()
 	for _, e := range costEntries {
-		digest.TotalUSD += e.CostUSD
-		digest.SessionCount++
-		digest.ByRole[e.Role] += e.CostUSD
-		if e.Rig != "" {
-			digest.ByRig[e.Rig] += e.CostUSD
-		}
+		breakdown.addEntry(e)
+	}
+	digest := CostDigest{
+		Date:         dateStr,
+		TotalUSD:     breakdown.Total,
+		SessionCount: breakdown.Sessions,
+		Sessions:     costEntries,
+		ByRole:       breakdown.ByRole,
+		ByRig:        breakdown.ByRig,
+		ByAgent:      breakdown.ByAgent,
+		ByConvoy:     breakdown.ByConvoy,
+		ByBead:       capCostKeys(breakdown.ByBead, maxDigestBeads),
+		ByFormula:    breakdown.ByFormula,
 	}
 
 	if digestDryRun {
@@ -1212,6 +1265,25 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 				fmt.Printf("    %s: $%.2f\n", rig, cost)
 			}
 		}
+		fmt.Printf("  By Agent:\n")
+		for agent, cost := range digest.ByAgent {
+			fmt.Printf("    %s: $%.2f\n", agent, cost)
+		}
+		if len(digest.ByConvoy) > 0 {
+			fmt.Printf("  By Convoy:\n")
+			for _, convoy := range sortedCostKeys(digest.ByConvoy) {
+				fmt.Printf("    %s: $%.2f\n", convoy, digest.ByConvoy[convoy])
+			}
+		}
+		if len(digest.ByFormula) > 0 {
+			fmt.Printf("  By Formula:\n")
+			for _, formula := range sortedCostKeys(digest.ByFormula) {
+				fmt.Printf("    %s: $%.2f\n", formula, digest.ByFormula[formula])
+			}
+		}
+		if len(digest.ByBead) > 0 {
+			fmt.Printf("  Beads: %d attributed\n", len(digest.ByBead))
+		}
 		return nil
 	}
 
@@ -1238,6 +1310,26 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 
 // querySessionCostEntries reads session cost entries from the local log file for a target date.
 func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
+	all, err := readCostLogEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter by target date
+	targetDay := targetDate.Format("2006-01-02")
+	var entries []CostEntry
+	for _, entry := range all {
+		if entry.EndedAt.Format("2006-01-02") == targetDay {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// readCostLogEntries reads every entry in the local costs log (entries not
+// yet digested).
+func readCostLogEntries() ([]CostEntry, error) {
 	logPath := getCostsLogPath()
 
 	// Read log file
@@ -1249,7 +1341,6 @@ func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
 		return nil, fmt.Errorf("reading costs log: %w", err)
 	}
 
-	targetDay := targetDate.Format("2006-01-02")
 	var entries []CostEntry
 
 	// Parse each line as a CostLogEntry
@@ -1268,19 +1359,18 @@ func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
 			continue
 		}
 
-		// Filter by target date
-		if logEntry.EndedAt.Format("2006-01-02") != targetDay {
-			continue
-		}
-
 		entries = append(entries, CostEntry{
 			SessionID: logEntry.SessionID,
 			Role:      logEntry.Role,
 			Rig:       logEntry.Rig,
 			Worker:    logEntry.Worker,
+			Agent:     logEntry.Agent,
 			CostUSD:   logEntry.CostUSD,
 			EndedAt:   logEntry.EndedAt,
 			WorkItem:  logEntry.WorkItem,
+			Convoy:    logEntry.Convoy,
+			Formula:   logEntry.Formula,
+			Molecule:  logEntry.Molecule,
 		})
 	}
 
@@ -1321,6 +1411,36 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 		desc.WriteString("\n")
 	}
 
+	if len(digest.ByAgent) > 0 {
+		desc.WriteString("## By Agent\n")
+		agents := make([]string, 0, len(digest.ByAgent))
+		for agent := range digest.ByAgent {
+			agents = append(agents, agent)
+		}
+		sort.Strings(agents)
+		for _, agent := range agents {
+			desc.WriteString(fmt.Sprintf("- %s: $%.2f\n", agent, digest.ByAgent[agent]))
+		}
+		desc.WriteString("\n")
+	}
+
+	for _, section := range []struct {
+		title string
+		costs map[string]float64
+	}{
+		{"By Convoy", digest.ByConvoy},
+		{"By Formula", digest.ByFormula},
+	} {
+		if len(section.costs) == 0 {
+			continue
+		}
+		desc.WriteString("## " + section.title + "\n")
+		for _, key := range sortedCostKeys(section.costs) {
+			desc.WriteString(fmt.Sprintf("- %s: $%.2f\n", key, section.costs[key]))
+		}
+		desc.WriteString("\n")
+	}
+
 	// Build compact payload (aggregate only, no per-session details).
 	// Per-session details can be thousands of records and exceed Dolt column limits.
 	compactPayload := CostDigestPayload{
@@ -1329,6 +1449,10 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 		SessionCount: digest.SessionCount,
 		ByRole:       digest.ByRole,
 		ByRig:        digest.ByRig,
+		ByAgent:      digest.ByAgent,
+		ByConvoy:     digest.ByConvoy,
+		ByBead:       digest.ByBead,
+		ByFormula:    digest.ByFormula,
 	}
 	payloadJSON, err := json.Marshal(compactPayload)
 	if err != nil {
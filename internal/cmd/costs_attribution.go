@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// maxDigestBeads caps the per-bead breakdown stored in a digest payload;
+// the cheapest remaining beads are folded into otherBeadsKey so the payload
+// stays well within Dolt column limits.
+const maxDigestBeads = 200
+
+// otherBeadsKey collects the beads left out of a capped per-bead breakdown.
+const otherBeadsKey = "(other)"
+
+// costWork is the work a session cost is attributed to.
+type costWork struct {
+	Bead     string // Work bead (hooked or given with --work-item)
+	Convoy   string // Convoy tracking the bead
+	Formula  string // Formula of the molecule attached to the bead
+	Molecule string // Attached molecule root ID
+}
+
+// attributeSessionWork links a session's cost to its work: the given work
+// item, or else the bead hooked by the session's agent, plus the convoy and
+// formula/molecule recorded on the bead when it was slung. Best effort:
+// lookups that fail leave fields empty.
+func attributeSessionWork(sess, workDir, workItem string) costWork {
+	work := costWork{Bead: workItem}
+	if workDir == "" {
+		return work
+	}
+	b := beads.New(workDir)
+
+	var issue *beads.Issue
+	if work.Bead == "" {
+		identity, err := session.ParseSessionName(sess)
+		if err != nil || identity.Address() == "" {
+			return work
+		}
+		hooked, err := b.List(beads.ListOptions{
+			Status:   beads.StatusHooked,
+			Assignee: identity.Address(),
+			Priority: -1,
+		})
+		if err != nil || len(hooked) == 0 {
+			return work
+		}
+		issue = hooked[0]
+		work.Bead = issue.ID
+	} else {
+		var err error
+		issue, err = b.Show(work.Bead)
+		if err != nil {
+			if costsVerbose {
+				fmt.Fprintf(os.Stderr, "[costs] could not show work item %s: %v\n", work.Bead, err)
+			}
+			return work
+		}
+	}
+
+	if fields := beads.ParseAttachmentFields(issue); fields != nil {
+		work.Convoy = fields.ConvoyID
+		work.Formula = fields.AttachedFormula
+		work.Molecule = fields.AttachedMolecule
+	}
+	return work
+}
+
+// costBreakdown accumulates cost totals across log entries and daily digests.
+type costBreakdown struct {
+	Total     float64
+	Sessions  int
+	ByRole    map[string]float64
+	ByRig     map[string]float64
+	ByAgent   map[string]float64
+	ByConvoy  map[string]float64
+	ByBead    map[string]float64
+	ByFormula map[string]float64
+}
+
+func newCostBreakdown() *costBreakdown {
+	return &costBreakdown{
+		ByRole:    make(map[string]float64),
+		ByRig:     make(map[string]float64),
+		ByAgent:   make(map[string]float64),
+		ByConvoy:  make(map[string]float64),
+		ByBead:    make(map[string]float64),
+		ByFormula: make(map[string]float64),
+	}
+}
+
+// addEntry adds one session cost entry.
+func (b *costBreakdown) addEntry(e CostEntry) {
+	b.Total += e.CostUSD
+	b.Sessions++
+	b.ByRole[e.Role] += e.CostUSD
+	b.ByAgent[costsAgentName(e.Agent)] += e.CostUSD
+	addCostKey(b.ByRig, e.Rig, e.CostUSD)
+	addCostKey(b.ByConvoy, e.Convoy, e.CostUSD)
+	addCostKey(b.ByBead, e.WorkItem, e.CostUSD)
+	addCostKey(b.ByFormula, e.Formula, e.CostUSD)
+}
+
+// addDigest adds a daily digest. Old digests carry their sessions; newer
+// ones only carry aggregates, which are added as they are.
+func (b *costBreakdown) addDigest(d CostDigest) {
+	if len(d.Sessions) > 0 {
+		for _, e := range d.Sessions {
+			b.addEntry(e)
+		}
+		return
+	}
+	b.Total += d.TotalUSD
+	b.Sessions += d.SessionCount
+	for _, m := range []struct{ dst, src map[string]float64 }{
+		{b.ByRole, d.ByRole},
+		{b.ByRig, d.ByRig},
+		{b.ByAgent, d.ByAgent},
+		{b.ByConvoy, d.ByConvoy},
+		{b.ByBead, d.ByBead},
+		{b.ByFormula, d.ByFormula},
+	} {
+		for k, v := range m.src {
+			m.dst[k] += v
+		}
+	}
+}
+
+func addCostKey(m map[string]float64, key string, cost float64) {
+	if key != "" {
+		m[key] += cost
+	}
+}
+
+// capCostKeys keeps the n most expensive keys of m and folds the rest into
+// otherBeadsKey. Returns m unchanged when it has at most n keys.
+func capCostKeys(m map[string]float64, n int) map[string]float64 {
+	if len(m) <= n {
+		return m
+	}
+	keys := sortedCostKeys(m)
+	capped := make(map[string]float64, n+1)
+	for i, k := range keys {
+		if i < n {
+			capped[k] = m[k]
+		} else {
+			capped[otherBeadsKey] += m[k]
+		}
+	}
+	return capped
+}
+
+// sortedCostKeys returns the keys of m by descending cost, then by name.
+func sortedCostKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if m[keys[i]] != m[keys[j]] {
+			return m[keys[i]] > m[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// convoyCosts returns the recorded cost of a convoy and of each of its
+// beads, from undigested log entries and all daily digests in townBeads.
+func convoyCosts(townBeads, convoyID string, beadIDs []string) (float64, map[string]float64) {
+	b := newCostBreakdown()
+	digests, err := queryCostDigests(townBeads, 0)
+	if err != nil && costsVerbose {
+		fmt.Fprintf(os.Stderr, "[costs] could not query digests: %v\n", err)
+	}
+	for _, d := range digests {
+		b.addDigest(d)
+	}
+	entries, err := readCostLogEntries()
+	if err != nil && costsVerbose {
+		fmt.Fprintf(os.Stderr, "[costs] could not read costs log: %v\n", err)
+	}
+	for _, e := range entries {
+		b.addEntry(e)
+	}
+
+	byBead := make(map[string]float64)
+	for _, id := range beadIDs {
+		if cost, ok := b.ByBead[id]; ok {
+			byBead[id] = cost
+		}
+	}
+	return b.ByConvoy[convoyID], byBead
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// trackBudgetSpend charges a recorded session cost against the configured
+// budgets and escalates any warning level or limit crossed for the first
+// time this period. Best effort: budget tracking never fails cost recording.
+func trackBudgetSpend(settings *config.TownSettings, entry CostLogEntry) {
+	if settings == nil || !settings.Budgets.Enabled() {
+		return
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	cfg := settings.Budgets
+
+	var crossings []budget.Crossing
+	err = budget.Update(townRoot, func(s *budget.State) error {
+		crossings = s.Apply(cfg, budgetCharge(entry))
+		return nil
+	})
+	if err != nil {
+		if costsVerbose {
+			fmt.Fprintf(os.Stderr, "[costs] could not update budget state: %v\n", err)
+		}
+		return
+	}
+
+	for _, c := range crossings {
+		escalateBudgetCrossing(townRoot, c)
+	}
+}
+
+// budgetCharge converts a cost log entry to a budget charge. The charge is
+// keyed by the agent's session, not the tmux session name: a respawned
+// polecat reuses the name, and its new running total must not be netted
+// against the previous run's.
+func budgetCharge(entry CostLogEntry) budget.Charge {
+	return budget.Charge{
+		Session: entry.AgentSession,
+		CostUSD: entry.CostUSD,
+		Rig:     entry.Rig,
+		Convoy:  entry.Convoy,
+		At:      entry.EndedAt,
+	}
+}
+
+// escalateBudgetCrossing escalates a budget crossing and logs it to the feed.
+// Warnings escalate at medium severity, exceeded budgets at high.
+func escalateBudgetCrossing(townRoot string, c budget.Crossing) {
+	severity := config.SeverityMedium
+	desc := fmt.Sprintf("%s budget at %.0f%% ($%.2f of $%.2f)", c.Name(), c.Percent(), c.SpentUSD, c.LimitUSD)
+	reason := fmt.Sprintf("Spend reached the warning level of the %s budget.", c.Name())
+	if c.Level == budget.LevelExceeded {
+		severity = config.SeverityHigh
+		desc = fmt.Sprintf("%s budget exceeded ($%.2f of $%.2f)", c.Name(), c.SpentUSD, c.LimitUSD)
+		switch c.Action {
+		case budget.ActionPause:
+			reason = "The scheduler holds queued work for this scope until the budget resets or is raised."
+		case budget.ActionStop:
+			reason = "The scheduler holds queued work and gt sling refuses new polecats for this scope\nuntil the budget resets or is raised (override with gt sling --force)."
+		default:
+			reason = "No work is held (budget action: escalate)."
+		}
+	}
+	if c.Period != "" {
+		reason += "\nPeriod: " + c.Period
+	}
+
+	escCmd := exec.Command("gt", "escalate", "-s", severity,
+		"--source", "budget:"+c.Kind,
+		"-r", reason,
+		desc)
+	escCmd.Dir = townRoot
+	if out, err := escCmd.CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Could not escalate %s: %v (%s)\n",
+			style.Warning.Render("⚠"), desc, err, out)
+	}
+
+	_ = events.LogFeed(events.TypeBudgetThreshold, "budget",
+		events.BudgetThresholdPayload(c.Kind, c.Key, c.Period, c.Level, c.Action, c.SpentUSD, c.LimitUSD))
+}
+
+// budgetSlingHold reports whether an exceeded budget with the stop action
+// refuses a new polecat in rig for hookBead (whose convoy is looked up only
+// when convoys are budgeted).
+func budgetSlingHold(townRoot, rig, hookBead string) (string, bool) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || !settings.Budgets.Enabled() || settings.Budgets.GetAction() != budget.ActionStop {
+		return "", false
+	}
+	state, err := budget.LoadState(townRoot)
+	if err != nil {
+		return "", false
+	}
+	var convoy string
+	if len(settings.Budgets.ConvoyUSD) > 0 {
+		if info := getConvoyInfoFromIssue(hookBead, townRoot); info != nil {
+			convoy = info.ID
+		}
+	}
+	return state.Hold(settings.Budgets, rig, convoy, time.Now(), true)
+}
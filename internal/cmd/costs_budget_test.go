@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/budget"
+)
+
+func TestBudgetCharge_ReusedSessionName(t *testing.T) {
+	cfg := &budget.Config{RigDailyUSD: map[string]float64{"gastown": 100}}
+	s := &budget.State{}
+	at := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	// The first run of toast ends at $6. The polecat is respawned under the
+	// same tmux session name and its new transcript reaches $7.
+	for _, e := range []CostLogEntry{
+		{SessionID: "gt-gastown-toast", AgentSession: "/claude/projects/toast/run1.jsonl", CostUSD: 6, Rig: "gastown", EndedAt: at},
+		{SessionID: "gt-gastown-toast", AgentSession: "/claude/projects/toast/run2.jsonl", CostUSD: 7, Rig: "gastown", EndedAt: at.Add(time.Hour)},
+	} {
+		s.Apply(cfg, budgetCharge(e))
+	}
+
+	if got := s.Scopes["rig/gastown"].SpentUSD; math.Abs(got-13) > 1e-9 {
+		t.Errorf("rig spend = %v, want 13 (both runs charged in full)", got)
+	}
+}
@@ -292,3 +292,67 @@ func TestCostDigestPayload_ExcludesSessions(t *testing.T) {
 		t.Errorf("by_role should have 3 entries, got %d", len(asDigest.ByRole))
 	}
 }
+
+func TestCostBreakdown_EntriesAndDigests(t *testing.T) {
+	b := newCostBreakdown()
+	b.addDigest(CostDigest{
+		Date:         "2026-10-15",
+		TotalUSD:     10,
+		SessionCount: 4,
+		ByRole:       map[string]float64{"polecat": 10},
+		ByConvoy:     map[string]float64{"hq-cv-1": 6},
+		ByBead:       map[string]float64{"gt-a": 6, "gt-b": 4},
+		ByFormula:    map[string]float64{"mol-polecat-work": 10},
+	})
+	// Old-format digests carry their sessions.
+	b.addDigest(CostDigest{
+		Date:     "2026-10-14",
+		TotalUSD: 99, // ignored in favor of the sessions
+		Sessions: []CostEntry{{Role: "witness", Rig: "gastown", CostUSD: 1}},
+	})
+	b.addEntry(CostEntry{Role: "polecat", Rig: "gastown", CostUSD: 2, WorkItem: "gt-a", Convoy: "hq-cv-1", Formula: "mol-polecat-work"})
+	b.addEntry(CostEntry{Role: "polecat", Rig: "gastown", Agent: "codex", CostUSD: 3, WorkItem: "gt-c", Formula: "shiny"})
+
+	if b.Total != 16 || b.Sessions != 7 {
+		t.Errorf("total = %.2f over %d sessions, want 16 over 7", b.Total, b.Sessions)
+	}
+	checks := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"convoy hq-cv-1", b.ByConvoy["hq-cv-1"], 8},
+		{"bead gt-a", b.ByBead["gt-a"], 8},
+		{"bead gt-c", b.ByBead["gt-c"], 3},
+		{"formula mol-polecat-work", b.ByFormula["mol-polecat-work"], 12},
+		{"formula shiny", b.ByFormula["shiny"], 3},
+		{"agent claude", b.ByAgent["claude"], 3},
+		{"agent codex", b.ByAgent["codex"], 3},
+		{"role polecat", b.ByRole["polecat"], 15},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %.2f, want %.2f", c.name, c.got, c.want)
+		}
+	}
+	if _, ok := b.ByConvoy[""]; ok {
+		t.Error("entries without a convoy should not be attributed to an empty key")
+	}
+}
+
+func TestCapCostKeys(t *testing.T) {
+	m := map[string]float64{"a": 5, "b": 1, "c": 3, "d": 2}
+	if got := capCostKeys(m, 4); len(got) != 4 {
+		t.Errorf("capCostKeys under the cap changed the map: %v", got)
+	}
+	got := capCostKeys(m, 2)
+	want := map[string]float64{"a": 5, "c": 3, otherBeadsKey: 3}
+	if len(got) != len(want) {
+		t.Fatalf("capCostKeys = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("capCostKeys[%s] = %.2f, want %.2f", k, got[k], v)
+		}
+	}
+}
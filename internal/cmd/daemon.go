@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/templates"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -187,6 +188,8 @@ func runDaemonStart(cmd *cobra.Command, args []string) error {
 
 	daemonCmd := exec.Command(gtPath, "daemon", "run")
 	daemonCmd.Dir = townRoot
+	// The daemon outlives this command; don't parent its traces to our span.
+	daemonCmd.Env = telemetry.EnvWithoutTraceContext()
 
 	// Detach from terminal
 	daemonCmd.Stdin = nil
@@ -12,14 +12,17 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/web"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
-	dashboardPort int
-	dashboardBind string
-	dashboardOpen bool
+	dashboardPort    int
+	dashboardBind    string
+	dashboardOpen    bool
+	dashboardMetrics bool
 )
 
 var dashboardCmd = &cobra.Command{
@@ -34,11 +37,16 @@ The dashboard shows real-time convoy status with:
 - Last activity indicator (green/yellow/red)
 - Auto-refresh every 30 seconds via htmx
 
+With --metrics, the server also exposes Prometheus metrics at /metrics:
+scheduler queue depth and capacity, per-rig polecat counts, merge queue
+phase counts, account quota state, and this process's OTel instruments.
+
 Example:
   gt dashboard                    # Start on default port 8080
   gt dashboard --port 3000        # Start on port 3000
   gt dashboard --bind 0.0.0.0     # Listen on all interfaces
-  gt dashboard --open             # Start and open browser`,
+  gt dashboard --open             # Start and open browser
+  gt dashboard --metrics          # Also serve Prometheus metrics at /metrics`,
 	RunE: runDashboard,
 }
 
@@ -46,6 +54,7 @@ func init() {
 	dashboardCmd.Flags().IntVar(&dashboardPort, "port", 8080, "HTTP port to listen on")
 	dashboardCmd.Flags().StringVar(&dashboardBind, "bind", "127.0.0.1", "Address to bind to (use 0.0.0.0 for all interfaces)")
 	dashboardCmd.Flags().BoolVar(&dashboardOpen, "open", false, "Open browser automatically")
+	dashboardCmd.Flags().BoolVar(&dashboardMetrics, "metrics", false, "Serve Prometheus metrics at /metrics")
 	rootCmd.AddCommand(dashboardCmd)
 }
 
@@ -80,6 +89,16 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("creating dashboard handler: %w", err)
 		}
+
+		if dashboardMetrics {
+			if err := telemetry.EnablePrometheus(cmd.Context(), "gastown", Version); err != nil {
+				return fmt.Errorf("enabling Prometheus metrics: %w", err)
+			}
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", daemon.NewTownMetrics(townRoot, "").Handler())
+			mux.Handle("/", handler)
+			handler = mux
+		}
 	}
 
 	// Build the listen address and display URL
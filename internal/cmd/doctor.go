@@ -62,6 +62,9 @@ Clone divergence checks:
   - default-branch-all-rigs  Verify default_branch exists on remote for all rigs
   - worktree-gitdir-valid    Verify worktree .git files reference existing paths (fixable)
 
+Sandbox checks:
+  - sandbox-tooling          Check that sandboxed rigs have working bwrap/unshare
+
 Crew workspace checks:
   - crew-state               Validate crew worker state.json files (fixable)
   - crew-worktrees           Detect stale cross-rig worktrees (fixable)
@@ -161,6 +164,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewBeadsBinaryCheck())
 	d.Register(doctor.NewDoltBinaryCheck())
 	d.Register(doctor.NewDoltServerReachableCheck())
+	d.Register(doctor.NewSandboxCheck())
 
 	d.Register(doctor.NewTownGitCheck())
 	d.Register(doctor.NewTownRootBranchCheck())
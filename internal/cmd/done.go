@@ -763,6 +763,10 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			description += "\nretry_count: 0"
 			description += "\nlast_conflict_sha: null"
 			description += "\nconflict_task_id: null"
+			// Carry the trace on to the Refinery's gates and merge.
+			if tp := telemetry.TraceParent(context.Background()); tp != "" {
+				description += fmt.Sprintf("\ntrace_parent: %s", tp)
+			}
 
 			mrIssue, err := bd.Create(beads.CreateOptions{
 				Title:       title,
@@ -897,6 +901,9 @@ notifyWitness:
 	// Update agent bead state (ZFC: self-report completion)
 	updateAgentStateOnDone(cwd, townRoot, exitType, issueID)
 
+	// Record session cost for runtimes without a Stop hook (e.g., Codex)
+	recordCostOnDone(issueID)
+
 	// Persistent polecat model (gt-hdf8): polecats transition to IDLE after completion.
 	// Session stays alive, sandbox preserved, worktree synced to main for reuse.
 	// "done means idle" - not "done means dead".
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/notify"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -129,7 +131,13 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
+	deliveries := executeExternalActions(actions, escalationConfig, notify.Notification{
+		EscalationID: issue.ID,
+		Severity:     severity,
+		Subject:      fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description),
+		Body:         formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
+	})
+	recordDeliveries(bd, issue.ID, deliveries)
 
 	// Log to activity feed
 	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
@@ -151,6 +159,13 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		if escalateSource != "" {
 			result["source"] = escalateSource
 		}
+		if len(deliveries) > 0 {
+			var outcomes []string
+			for _, r := range deliveries {
+				outcomes = append(outcomes, r.String())
+			}
+			result["deliveries"] = outcomes
+		}
 		out, _ := json.MarshalIndent(result, "", "  ")
 		fmt.Println(string(out))
 	} else {
@@ -394,6 +409,15 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 				}
 			}
 
+			// Deliver externally when the new severity routes outside the town
+			deliveries := executeExternalActions(actions, escalationConfig, notify.Notification{
+				EscalationID: result.ID,
+				Severity:     result.NewSeverity,
+				Subject:      fmt.Sprintf("[%s→%s] Re-escalated: %s", strings.ToUpper(result.OldSeverity), strings.ToUpper(result.NewSeverity), result.Title),
+				Body:         formatReescalationMailBody(result, reescalatedBy),
+			})
+			recordDeliveries(bd, result.ID, deliveries)
+
 			// Log to activity feed
 			_ = events.LogFeed(events.TypeEscalationSent, reescalatedBy, map[string]interface{}{
 				"escalation_id":    result.ID,
@@ -553,41 +577,60 @@ func extractMailTargetsFromActions(actions []string) []string {
 	return targets
 }
 
-// executeExternalActions processes external notification actions (email:, sms:, slack).
-// For now, this logs warnings if contacts aren't configured - actual sending is future work.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) {
-	for _, action := range actions {
+// executeExternalActions delivers an escalation through its external route
+// actions (email:, sms:, slack), retrying failed deliveries, and prints the
+// outcome of each. Actions without a configured contact or channel are
+// skipped with a warning.
+func executeExternalActions(actions []string, cfg *config.EscalationConfig, n notify.Notification) []notify.Result {
+	results := notify.Deliver(context.Background(), cfg, actions, n)
+	for _, r := range results {
 		switch {
-		case strings.HasPrefix(action, "email:"):
-			if cfg.Contacts.HumanEmail == "" {
-				style.PrintWarning("email action '%s' skipped: contacts.human_email not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual email sending
-				fmt.Printf("  📧 Would send email to %s (not yet implemented)\n", cfg.Contacts.HumanEmail)
-			}
-
-		case strings.HasPrefix(action, "sms:"):
-			if cfg.Contacts.HumanSMS == "" {
-				style.PrintWarning("sms action '%s' skipped: contacts.human_sms not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual SMS sending
-				fmt.Printf("  📱 Would send SMS to %s (not yet implemented)\n", cfg.Contacts.HumanSMS)
-			}
-
-		case action == "slack":
-			if cfg.Contacts.SlackWebhook == "" {
-				style.PrintWarning("slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
-			} else {
-				// TODO: Implement actual Slack webhook posting
-				fmt.Printf("  💬 Would post to Slack (not yet implemented)\n")
-			}
+		case r.Skipped != "":
+			style.PrintWarning("%s skipped: %s in settings/escalation.json", r.Action, r.Skipped)
+		case r.Err != nil:
+			style.PrintWarning("%s failed after %d attempt(s): %v", r.Action, r.Attempts, r.Err)
+		default:
+			fmt.Printf("  %s Delivered %s to %s\n", deliveryEmoji(r.Action), r.Action, r.Target)
+		}
+	}
 
-		case action == "log":
+	for _, action := range actions {
+		if action == "log" {
 			// Log action always succeeds - writes to escalation log file
 			// TODO: Implement actual log file writing
 			fmt.Printf("  📝 Logged to escalation log\n")
 		}
 	}
+	return results
+}
+
+// recordDeliveries records external delivery outcomes on the escalation bead.
+func recordDeliveries(bd *beads.Beads, id string, results []notify.Result) {
+	if len(results) == 0 {
+		return
+	}
+	var parts []string
+	failed := false
+	for _, r := range results {
+		parts = append(parts, strings.Join(strings.Fields(r.String()), " "))
+		if r.Err != nil {
+			failed = true
+		}
+	}
+	if err := bd.RecordEscalationDeliveries(id, strings.Join(parts, "; "), failed); err != nil {
+		style.PrintWarning("failed to record deliveries on %s: %v", id, err)
+	}
+}
+
+func deliveryEmoji(action string) string {
+	switch {
+	case strings.HasPrefix(action, "email:"):
+		return "📧"
+	case strings.HasPrefix(action, "sms:"):
+		return "📱"
+	default:
+		return "💬"
+	}
 }
 
 func formatEscalationMailBody(beadID, severity, reason, from, related string) string {
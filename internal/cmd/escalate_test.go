@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -8,6 +10,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/notify"
 )
 
 func TestGetNextSeverity(t *testing.T) {
@@ -332,13 +335,17 @@ func TestDetectSenderFallback(t *testing.T) {
 }
 
 func TestExecuteExternalActions(t *testing.T) {
-	// executeExternalActions prints warnings/info but doesn't return errors.
-	// We test that it doesn't panic with various configurations.
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slack.Close()
 
 	tests := []struct {
-		name    string
-		actions []string
-		cfg     *config.EscalationConfig
+		name      string
+		actions   []string
+		cfg       *config.EscalationConfig
+		wantOK    int
+		wantSkips int
 	}{
 		{
 			name:    "no external actions",
@@ -346,46 +353,52 @@ func TestExecuteExternalActions(t *testing.T) {
 			cfg:     &config.EscalationConfig{},
 		},
 		{
-			name:    "email action without contact",
-			actions: []string{"email:human"},
-			cfg:     &config.EscalationConfig{},
+			name:      "email action without contact",
+			actions:   []string{"email:human"},
+			cfg:       &config.EscalationConfig{},
+			wantSkips: 1,
 		},
 		{
-			name:    "email action with contact",
+			name:    "email action without smtp",
 			actions: []string{"email:human"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
 					HumanEmail: "test@example.com",
 				},
 			},
+			wantSkips: 1,
 		},
 		{
-			name:    "sms action without contact",
-			actions: []string{"sms:human"},
-			cfg:     &config.EscalationConfig{},
+			name:      "sms action without contact",
+			actions:   []string{"sms:human"},
+			cfg:       &config.EscalationConfig{},
+			wantSkips: 1,
 		},
 		{
-			name:    "sms action with contact",
+			name:    "sms action without gateway",
 			actions: []string{"sms:human"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
 					HumanSMS: "+15551234567",
 				},
 			},
+			wantSkips: 1,
 		},
 		{
-			name:    "slack action without webhook",
-			actions: []string{"slack"},
-			cfg:     &config.EscalationConfig{},
+			name:      "slack action without webhook",
+			actions:   []string{"slack"},
+			cfg:       &config.EscalationConfig{},
+			wantSkips: 1,
 		},
 		{
 			name:    "slack action with webhook",
 			actions: []string{"slack"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
-					SlackWebhook: "https://hooks.slack.com/test",
+					SlackWebhook: slack.URL,
 				},
 			},
+			wantOK: 1,
 		},
 		{
 			name:    "log action",
@@ -399,9 +412,11 @@ func TestExecuteExternalActions(t *testing.T) {
 				Contacts: config.EscalationContacts{
 					HumanEmail:   "test@example.com",
 					HumanSMS:     "+15551234567",
-					SlackWebhook: "https://hooks.slack.com/test",
+					SlackWebhook: slack.URL,
 				},
 			},
+			wantOK:    1,
+			wantSkips: 2,
 		},
 		{
 			name:    "empty actions",
@@ -412,8 +427,23 @@ func TestExecuteExternalActions(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Should not panic
-			executeExternalActions(tt.actions, tt.cfg, "hq-test", "high", "Test escalation")
+			results := executeExternalActions(tt.actions, tt.cfg, notify.Notification{
+				EscalationID: "hq-test",
+				Severity:     "high",
+				Subject:      "Test escalation",
+			})
+			var ok, skips int
+			for _, r := range results {
+				if r.OK() {
+					ok++
+				}
+				if r.Skipped != "" {
+					skips++
+				}
+			}
+			if ok != tt.wantOK || skips != tt.wantSkips {
+				t.Errorf("delivered %d, skipped %d; want %d, %d: %v", ok, skips, tt.wantOK, tt.wantSkips, results)
+			}
 		})
 	}
 }
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	eventsQueryTypes   []string
+	eventsQueryActors  []string
+	eventsQueryRig     string
+	eventsQueryBead    string
+	eventsQuerySince   string
+	eventsQueryUntil   string
+	eventsQueryLimit   int
+	eventsQueryCount   bool
+	eventsQueryGroupBy string
+	eventsQueryFollow  bool
+	eventsQueryJSON    bool
+)
+
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	GroupID: GroupDiag,
+	Short:   "Query the raw events log",
+	Long: `Query the raw events log (~/gt/.events.jsonl).
+
+Unlike 'gt feed', which shows the curated activity feed, these commands read
+every event, including audit-only ones, through a segment index
+(~/gt/.events.idx.json) so filtered queries skip history that can't match.
+
+Subcommands:
+  query   Filter, count and group events`,
+	RunE: requireSubcommand,
+}
+
+var eventsQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Filter, count and group events",
+	Long: `Filter, count and group events from the raw events log.
+
+Filters combine with AND. --type and --actor may be repeated (or comma-
+separated) and accept shell globs. --rig matches the payload rig, or the
+first component of a rig-scoped actor. --bead matches the payload bead,
+issue, mr or related field.
+
+--since/--until take a duration back from now (30m, 24h, 7d), a date
+(2026-03-01), a local date and time ("2026-03-01 14:00"), or RFC3339.
+
+The index is updated on each query by reading only newly appended lines,
+and rebuilt automatically if the log is rewritten (e.g. by 'gt krc prune').
+
+Examples:
+  gt events query --type mass_death --since 7d
+  gt events query --type 'session_*' --rig gastown --since 2h
+  gt events query --actor 'gastown/polecats/*' --count
+  gt events query --since 24h --group-by type
+  gt events query --bead gt-abc --json
+  gt events query --type 'scheduler_*' --follow`,
+	Args: cobra.NoArgs,
+	RunE: runEventsQuery,
+}
+
+func init() {
+	eventsQueryCmd.Flags().StringSliceVarP(&eventsQueryTypes, "type", "t", nil, "Event type(s), globs allowed (e.g. session_death, 'scheduler_*')")
+	eventsQueryCmd.Flags().StringSliceVarP(&eventsQueryActors, "actor", "a", nil, "Actor(s), globs allowed (e.g. 'gastown/polecats/*')")
+	eventsQueryCmd.Flags().StringVar(&eventsQueryRig, "rig", "", "Rig name")
+	eventsQueryCmd.Flags().StringVar(&eventsQueryBead, "bead", "", "Bead ID referenced by the event")
+	eventsQueryCmd.Flags().StringVar(&eventsQuerySince, "since", "", "Only events at or after this time (e.g. 1h, 7d, 2026-03-01)")
+	eventsQueryCmd.Flags().StringVar(&eventsQueryUntil, "until", "", "Only events before this time (same formats as --since)")
+	eventsQueryCmd.Flags().IntVarP(&eventsQueryLimit, "limit", "n", 0, "Show only the newest N matches (0 = all)")
+	eventsQueryCmd.Flags().BoolVarP(&eventsQueryCount, "count", "c", false, "Print the number of matches only")
+	eventsQueryCmd.Flags().StringVarP(&eventsQueryGroupBy, "group-by", "g", "", "Count matches by field: "+strings.Join(events.GroupFields, ", "))
+	eventsQueryCmd.Flags().BoolVarP(&eventsQueryFollow, "follow", "f", false, "Keep printing new matching events as they are logged")
+	eventsQueryCmd.Flags().BoolVar(&eventsQueryJSON, "json", false, "Output as JSON (JSON lines with --follow)")
+
+	eventsCmd.AddCommand(eventsQueryCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func runEventsQuery(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+
+	if eventsQueryFollow && (eventsQueryCount || eventsQueryGroupBy != "") {
+		return fmt.Errorf("--follow cannot be combined with --count or --group-by")
+	}
+
+	now := time.Now()
+	q := events.Query{
+		Types:  eventsQueryTypes,
+		Actors: eventsQueryActors,
+		Rig:    eventsQueryRig,
+		Bead:   eventsQueryBead,
+		Limit:  eventsQueryLimit,
+	}
+	if eventsQuerySince != "" {
+		if q.Since, err = parseEventsTime(eventsQuerySince, now); err != nil {
+			return fmt.Errorf("--since: %w", err)
+		}
+	}
+	if eventsQueryUntil != "" {
+		if q.Until, err = parseEventsTime(eventsQueryUntil, now); err != nil {
+			return fmt.Errorf("--until: %w", err)
+		}
+	}
+	if eventsQueryCount || eventsQueryGroupBy != "" {
+		q.Limit = 0 // aggregates cover every match
+	}
+
+	store := events.NewStore(townRoot)
+	matches, err := store.Query(q)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case eventsQueryGroupBy != "":
+		groups, err := events.GroupBy(matches, eventsQueryGroupBy)
+		if err != nil {
+			return err
+		}
+		return printEventGroups(groups, len(matches))
+	case eventsQueryCount:
+		if eventsQueryJSON {
+			return json.NewEncoder(os.Stdout).Encode(map[string]int{"count": len(matches)})
+		}
+		fmt.Println(len(matches))
+		return nil
+	}
+
+	if eventsQueryFollow {
+		for _, e := range matches {
+			printQueriedEvent(e)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		// --until is a bound on history, not on how long to follow.
+		q.Until = time.Time{}
+		return store.Follow(ctx, q, func(e events.Event) error {
+			printQueriedEvent(e)
+			return nil
+		})
+	}
+
+	if eventsQueryJSON {
+		if matches == nil {
+			matches = []events.Event{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(matches)
+	}
+	if len(matches) == 0 {
+		fmt.Println("No matching events.")
+		return nil
+	}
+	for _, e := range matches {
+		printQueriedEvent(e)
+	}
+	return nil
+}
+
+// parseEventsTime parses a --since/--until value: a duration back from now,
+// a date, a local date and time, or RFC3339.
+func parseEventsTime(s string, now time.Time) (time.Time, error) {
+	if d, err := parseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use a duration like 24h or 7d, YYYY-MM-DD, \"YYYY-MM-DD HH:MM\", or RFC3339)", s)
+}
+
+// printQueriedEvent prints one event: a line per event in text mode, or a
+// JSON line when --json is combined with --follow.
+func printQueriedEvent(e events.Event) {
+	if eventsQueryJSON {
+		data, err := json.Marshal(e)
+		if err == nil {
+			fmt.Println(string(data))
+		}
+		return
+	}
+
+	ts := e.Timestamp
+	if t := events.EventTime(e); !t.IsZero() {
+		ts = t.Local().Format("2006-01-02 15:04:05")
+	}
+	line := fmt.Sprintf("%s %s %s", style.Dim.Render(ts), style.Bold.Render(e.Type), e.Actor)
+	if payload := formatEventPayload(e.Payload); payload != "" {
+		line += " " + style.Dim.Render(payload)
+	}
+	fmt.Println(line)
+}
+
+// formatEventPayload renders a payload as sorted key=value pairs.
+func formatEventPayload(payload map[string]interface{}) string {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := payload[k]
+		if s, ok := v.(string); ok {
+			if strings.ContainsAny(s, " \t\n") {
+				s = fmt.Sprintf("%q", s)
+			}
+			parts = append(parts, k+"="+s)
+			continue
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		parts = append(parts, k+"="+string(data))
+	}
+	return strings.Join(parts, " ")
+}
+
+func printEventGroups(groups []events.Group, total int) error {
+	if eventsQueryJSON {
+		if groups == nil {
+			groups = []events.Group{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]interface{}{
+			"group_by": eventsQueryGroupBy,
+			"total":    total,
+			"groups":   groups,
+		})
+	}
+	if len(groups) == 0 {
+		fmt.Println("No matching events.")
+		return nil
+	}
+	width := 0
+	for _, g := range groups {
+		width = max(width, len(g.Key))
+	}
+	for _, g := range groups {
+		fmt.Printf("  %-*s  %d\n", width, g.Key, g.Count)
+	}
+	fmt.Printf("%s %d events\n", style.Dim.Render("Total:"), total)
+	return nil
+}
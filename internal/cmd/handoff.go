@@ -719,6 +719,7 @@ var claudeEnvVars = []string{
 	// GT telemetry source vars — needed to recompute derived vars after handoff
 	"GT_OTEL_METRICS_URL",
 	"GT_OTEL_LOGS_URL",
+	"GT_OTEL_TRACES_URL",
 }
 
 // buildRestartCommand creates the command to run when respawning a session's pane.
@@ -734,6 +735,9 @@ type buildRestartCommandOpts struct {
 	// ContinueSession is true. If empty, falls back to a generic
 	// continuation message.
 	ContinuePrompt string
+	// ConfigDir is the runtime config dir the restarted agent will use.
+	// A sandboxed agent needs it writable; defaults to CLAUDE_CONFIG_DIR.
+	ConfigDir string
 }
 
 func buildRestartCommand(sessionName string) (string, error) {
@@ -899,10 +903,17 @@ func buildRestartCommandWithOpts(sessionName string, opts buildRestartCommandOpt
 		exports = append(exports, "NODE_OPTIONS=")
 	}
 
+	restartCmd := fmt.Sprintf("cd %s && exec %s", workDir, runtimeCmd)
 	if len(exports) > 0 {
-		return fmt.Sprintf("cd %s && export %s && exec %s", workDir, strings.Join(exports, " "), runtimeCmd), nil
+		restartCmd = fmt.Sprintf("cd %s && export %s && exec %s", workDir, strings.Join(exports, " "), runtimeCmd)
 	}
-	return fmt.Sprintf("cd %s && exec %s", workDir, runtimeCmd), nil
+
+	// respawn-pane runs outside any sandbox, so rewrap sandboxed roles.
+	configDir := opts.ConfigDir
+	if configDir == "" {
+		configDir = os.Getenv("CLAUDE_CONFIG_DIR")
+	}
+	return session.SandboxCommand(restartCmd, session.LoadSandbox(rigPath), simpleRole, workDir, townRoot, configDir)
 }
 
 // updateSessionEnvForHandoff updates the tmux session environment with the
@@ -43,6 +43,11 @@ var (
 	// Status command flags
 	mqStatusJSON bool
 
+	// Show command flags
+	mqShowGate string
+	mqShowRun  string
+	mqShowJSON bool
+
 	// Integration land flags
 	mqIntegrationLandForce     bool
 	mqIntegrationLandSkipTests bool
@@ -200,6 +205,31 @@ Example:
 	RunE: runMqStatus,
 }
 
+var mqShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show landing phase and quality gate logs for a merge request",
+	Long: `Display the landing phase and persisted quality gate run for a merge request.
+
+The phase (merging, merged or failed) is recorded by the refinery on the MR
+bead; an MR landing through a forge pull request shows merging while the
+refinery waits on it, with the pull request URL.
+
+The refinery keeps each gate's full stdout/stderr, exit code, duration and
+declared artifacts (junit, coverage) under the rig. Without --gate, shows a
+summary of every gate in the MR's latest run; with --gate, prints that
+gate's full output and artifact paths.
+
+Batch runs are recorded for every MR on the stack, so after a bisection
+the culprit's logs are shown here too.
+
+Examples:
+  gt mq show gp-mr-abc123
+  gt mq show gp-mr-abc123 --gate test
+  gt mq show gp-mr-abc123 --gate test --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMqShow,
+}
+
 var mqIntegrationCmd = &cobra.Command{
 	Use:   "integration",
 	Short: "Manage integration branches for epics",
@@ -328,6 +358,11 @@ func init() {
 	// Status flags
 	mqStatusCmd.Flags().BoolVar(&mqStatusJSON, "json", false, "Output as JSON")
 
+	// Show flags
+	mqShowCmd.Flags().StringVar(&mqShowGate, "gate", "", "Print the full output of this gate")
+	mqShowCmd.Flags().StringVar(&mqShowRun, "run", "", "Show this gate run instead of the MR's latest")
+	mqShowCmd.Flags().BoolVar(&mqShowJSON, "json", false, "Output as JSON")
+
 	// Post-merge flags
 	mqPostMergeCmd.Flags().BoolVar(&mqPostMergeSkipBranchDelete, "skip-branch-delete", false, "Skip remote branch deletion")
 
@@ -337,6 +372,7 @@ func init() {
 	mqCmd.AddCommand(mqListCmd)
 	mqCmd.AddCommand(mqRejectCmd)
 	mqCmd.AddCommand(mqStatusCmd)
+	mqCmd.AddCommand(mqShowCmd)
 	mqCmd.AddCommand(mqPostMergeCmd)
 
 	// Integration branch subcommands
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQShowOutput is the JSON output of gt mq show.
+type MQShowOutput struct {
+	MR          string            `json:"mr"`
+	Phase       string            `json:"phase,omitempty"`
+	PullRequest string            `json:"pull_request,omitempty"`
+	GateRun     *refinery.GateRun `json:"gate_run,omitempty"`
+}
+
+// MQGateLogOutput is the JSON output of gt mq show --gate.
+type MQGateLogOutput struct {
+	Run       string              `json:"run"`
+	MR        string              `json:"mr"`
+	Gate      refinery.GateRecord `json:"gate"`
+	Stdout    string              `json:"stdout"`
+	Stderr    string              `json:"stderr"`
+	Artifacts []string            `json:"artifact_paths,omitempty"`
+}
+
+func runMqShow(cmd *cobra.Command, args []string) error {
+	mrID := args[0]
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+
+	bd := beads.New(workDir)
+	issue, err := bd.Show(mrID)
+	if err != nil {
+		if err == beads.ErrNotFound {
+			return fmt.Errorf("merge request '%s' not found", mrID)
+		}
+		return fmt.Errorf("fetching merge request: %w", err)
+	}
+	mrFields := beads.ParseMRFields(issue)
+	if mrFields == nil || mrFields.Rig == "" {
+		return fmt.Errorf("merge request '%s' has no rig field", mrID)
+	}
+
+	_, r, err := getRig(mrFields.Rig)
+	if err != nil {
+		return err
+	}
+
+	run, err := findGateRun(r.Path, mrID, mrFields.GateRun)
+	if err != nil {
+		// An MR without gate logs may still have a landing phase to show.
+		if mqShowGate != "" || mqShowRun != "" || mrFields.Phase == "" {
+			return err
+		}
+		run = nil
+	}
+
+	if mqShowGate != "" {
+		return showGateLog(r.Path, mrID, run, mqShowGate)
+	}
+
+	if mqShowJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(MQShowOutput{
+			MR:          mrID,
+			Phase:       mrFields.Phase,
+			PullRequest: mrFields.PullRequest,
+			GateRun:     run,
+		})
+	}
+	printMRLanding(mrID, mrFields)
+	if run != nil {
+		printGateRun(r.Path, mrID, run)
+	}
+	return nil
+}
+
+// printMRLanding prints the landing phase recorded on the MR bead.
+func printMRLanding(mrID string, fields *beads.MRFields) {
+	if fields.Phase == "" {
+		return
+	}
+	phase := fields.Phase
+	switch phase {
+	case string(refinery.MRPhaseMerged):
+		phase = style.Success.Render(phase)
+	case string(refinery.MRPhaseFailed), string(refinery.MRPhaseRejected):
+		phase = style.Error.Render(phase)
+	}
+	fmt.Printf("%s %s %s\n", style.Bold.Render("📦 Merge request:"), mrID, phase)
+	if fields.PullRequest != "" {
+		fmt.Printf("   Pull request: %s\n", fields.PullRequest)
+	}
+	fmt.Println()
+}
+
+// findGateRun resolves the gate run to show: --run if given, then the run
+// linked from the MR bead, then the newest persisted run covering the MR.
+func findGateRun(rigPath, mrID, linked string) (*refinery.GateRun, error) {
+	if mqShowRun != "" {
+		return refinery.LoadGateRun(rigPath, mqShowRun)
+	}
+	if linked != "" {
+		if run, err := refinery.LoadGateRun(rigPath, linked); err == nil {
+			return run, nil
+		}
+	}
+	runs, err := refinery.ListGateRuns(rigPath, mrID)
+	if err != nil {
+		return nil, fmt.Errorf("listing gate runs: %w", err)
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("no gate logs for merge request '%s'", mrID)
+	}
+	return runs[0], nil
+}
+
+// printGateRun prints a summary of every gate in a run.
+func printGateRun(rigPath, mrID string, run *refinery.GateRun) {
+	result := style.Success.Render("passed")
+	if !run.Success {
+		result = style.Error.Render("failed")
+	}
+	fmt.Printf("%s %s %s\n", style.Bold.Render("🚦 Gate run:"), run.ID, result)
+	fmt.Printf("   MR:      %s\n", mrID)
+	if len(run.MRs) > 1 {
+		fmt.Printf("   Batch:   %d MRs %v\n", len(run.MRs), run.MRs)
+	}
+	if run.Target != "" {
+		fmt.Printf("   Target:  %s\n", run.Target)
+	}
+	if head := run.HeadSHA; head != "" {
+		if len(head) > 8 {
+			head = head[:8]
+		}
+		fmt.Printf("   Head:    %s\n", head)
+	}
+	fmt.Printf("   Started: %s\n", run.StartedAt.Local().Format(time.RFC3339))
+	fmt.Printf("   Logs:    %s\n\n", refinery.GateRunDir(rigPath, run.ID))
+
+	for _, g := range run.Gates {
+		switch {
+		case g.Skipped:
+			fmt.Printf("   %s %-20s %s\n", style.Dim.Render("○"), g.Name, style.Dim.Render("skipped (paths not touched)"))
+		case g.Success:
+			fmt.Printf("   %s %-20s %8s  exit %d\n", style.Success.Render("✓"), g.Name, formatGateDuration(g.DurationMs), g.ExitCode)
+		default:
+			fmt.Printf("   %s %-20s %8s  exit %d\n", style.Error.Render("✗"), g.Name, formatGateDuration(g.DurationMs), g.ExitCode)
+		}
+		for _, a := range g.Artifacts {
+			fmt.Printf("       %s\n", style.Dim.Render(a))
+		}
+		if len(g.FailedTests) > 0 && !g.Success {
+			fmt.Printf("       failed: %s\n", strings.Join(g.FailedTests, ", "))
+		}
+		if len(g.FlakyTests) > 0 {
+			fmt.Printf("       %s\n", style.Warning.Render("flaky: "+strings.Join(g.FlakyTests, ", ")))
+		}
+		if len(g.QuarantinedTests) > 0 {
+			fmt.Printf("       %s\n", style.Dim.Render("quarantined: "+strings.Join(g.QuarantinedTests, ", ")))
+		}
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("Full output: gt mq show %s --gate <name>", mrID)))
+}
+
+// showGateLog prints one gate's full stdout and stderr.
+func showGateLog(rigPath, mrID string, run *refinery.GateRun, name string) error {
+	g := run.Gate(name)
+	if g == nil {
+		return fmt.Errorf("gate '%s' not found in run %s", name, run.ID)
+	}
+
+	var stdout, stderr []byte
+	if g.Stdout != "" {
+		stdout, _ = refinery.ReadGateLog(rigPath, run.ID, g.Stdout)
+	}
+	if g.Stderr != "" {
+		stderr, _ = refinery.ReadGateLog(rigPath, run.ID, g.Stderr)
+	}
+	artifacts := make([]string, len(g.Artifacts))
+	for i, a := range g.Artifacts {
+		artifacts[i] = filepath.Join(refinery.GateRunDir(rigPath, run.ID), filepath.FromSlash(a))
+	}
+
+	if mqShowJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(MQGateLogOutput{
+			Run:       run.ID,
+			MR:        mrID,
+			Gate:      *g,
+			Stdout:    string(stdout),
+			Stderr:    string(stderr),
+			Artifacts: artifacts,
+		})
+	}
+
+	status := style.Success.Render("passed")
+	switch {
+	case g.Skipped:
+		status = style.Dim.Render("skipped")
+	case !g.Success:
+		status = style.Error.Render("failed")
+	}
+	fmt.Printf("%s %s %s (run %s)\n", style.Bold.Render("🚦 Gate:"), g.Name, status, run.ID)
+	if g.Cmd != "" {
+		fmt.Printf("   Command:  %s\n", g.Cmd)
+	}
+	if !g.Skipped {
+		fmt.Printf("   Exit:     %d\n", g.ExitCode)
+		fmt.Printf("   Duration: %s\n", formatGateDuration(g.DurationMs))
+	}
+	if g.Error != "" {
+		fmt.Printf("   Error:    %s\n", g.Error)
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("stdout"))
+	fmt.Print(string(stdout))
+	fmt.Printf("\n%s\n", style.Bold.Render("stderr"))
+	fmt.Print(string(stderr))
+
+	if len(artifacts) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Artifacts"))
+		for _, a := range artifacts {
+			fmt.Printf("   %s\n", a)
+		}
+	}
+	return nil
+}
+
+func formatGateDuration(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(100 * time.Millisecond).String()
+}
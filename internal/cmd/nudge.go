@@ -126,7 +126,9 @@ var waitIdleTimeout = 15 * time.Second
 // For "immediate" mode: sends directly via tmux (current behavior).
 // For "queue" mode: writes to the nudge queue for cooperative delivery.
 // For "wait-idle" mode: waits for idle, then delivers or falls back to queue.
-func deliverNudge(t *tmux.Tmux, sessionName, message, sender string) error {
+// Sessions outside tmux (headless polecats) can't be watched for idleness, so
+// wait-idle queues for them.
+func deliverNudge(t session.SessionBackend, sessionName, message, sender string) error {
 	townRoot, _ := workspace.FindFromCwd()
 
 	// For direct tmux delivery, prefix with sender attribution.
@@ -152,7 +154,10 @@ func deliverNudge(t *tmux.Tmux, sessionName, message, sender string) error {
 			return fmt.Errorf("--mode=wait-idle requires a Gas Town workspace")
 		}
 		// Try to wait for idle
-		err := t.WaitForIdle(sessionName, waitIdleTimeout)
+		err := errors.New("idle detection needs tmux")
+		if tm := session.AsTmux(t); tm != nil {
+			err = tm.WaitForIdle(sessionName, waitIdleTimeout)
+		}
 		if err == nil {
 			// Agent is idle — safe to deliver directly
 			return t.NudgeSession(sessionName, prefixedMessage)
@@ -349,6 +354,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 		}
 
 		var sessionName string
+		var backend session.SessionBackend = t
 
 		// Check if this is a crew address (polecatName starts with "crew/")
 		if strings.HasPrefix(polecatName, "crew/") {
@@ -364,6 +370,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 				return err
 			}
 			sessionName = mgr.SessionName(pcName)
+			backend = mgr.Backend()
 		} else {
 			// Short address (e.g., "gastown/holden") - could be crew or polecat.
 			// Try crew first (matches mail system's addressToSessionIDs pattern),
@@ -377,6 +384,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 					return err
 				}
 				sessionName = mgr.SessionName(polecatName)
+				backend = mgr.Backend()
 			}
 		}
 
@@ -384,7 +392,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 		// Without this, queue mode silently succeeds for nonexistent sessions —
 		// the file is written but never drained.
 		if nudgeModeFlag != NudgeModeImmediate {
-			exists, err := t.HasSession(sessionName)
+			exists, err := backend.HasSession(sessionName)
 			if err != nil {
 				return fmt.Errorf("checking session: %w", err)
 			}
@@ -394,7 +402,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 		}
 
 		// Send nudge using the configured delivery mode
-		if err := deliverNudge(t, sessionName, message, sender); err != nil {
+		if err := deliverNudge(backend, sessionName, message, sender); err != nil {
 			return fmt.Errorf("nudging session: %w", err)
 		}
 
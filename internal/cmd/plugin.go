@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -90,6 +91,10 @@ var pluginRunCmd = &cobra.Command{
 By default, checks if the gate would allow execution and informs you
 if it wouldn't. Use --force to bypass gate checks.
 
+Script plugins ([execution] mode = "script") run their entrypoint
+directly, with the plugin's timeout, and record the actual result.
+Other plugins print their instructions for you to carry out.
+
 Examples:
   gt plugin run rebuild-gt              # Run if gate allows
   gt plugin run rebuild-gt --force      # Bypass gate check
@@ -341,6 +346,10 @@ func outputPluginShowText(p *plugin.Plugin) error {
 	if p.Execution != nil {
 		fmt.Println()
 		fmt.Printf("%s\n", style.Bold.Render("Execution:"))
+		if p.IsScript() {
+			fmt.Printf("  Mode: %s\n", plugin.ModeScript)
+			fmt.Printf("  Entrypoint: %s\n", p.Execution.Entrypoint)
+		}
 		if p.Execution.Timeout != "" {
 			fmt.Printf("  Timeout: %s\n", p.Execution.Timeout)
 		}
@@ -410,6 +419,8 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 		}
 		if !gateOpen {
 			fmt.Printf("%s %s (use --force to override)\n", style.Warning.Render("Gate closed:"), gateReason)
+		} else if p.IsScript() {
+			fmt.Printf("%s Would run: %s\n", style.Success.Render("Gate open:"), p.Execution.Entrypoint)
 		} else {
 			fmt.Printf("%s Would execute plugin instructions\n", style.Success.Render("Gate open:"))
 		}
@@ -430,6 +441,9 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  %s\n", style.Dim.Render("(gate bypassed with --force)"))
 	}
 	fmt.Println()
+	if p.IsScript() {
+		return runScriptPluginManually(p, townRoot)
+	}
 	fmt.Printf("%s\n", style.Bold.Render("Instructions:"))
 	fmt.Println(p.Instructions)
 
@@ -450,6 +464,45 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runScriptPluginManually runs a script plugin's entrypoint, prints its
+// captured output and records the run with its actual result.
+func runScriptPluginManually(p *plugin.Plugin, townRoot string) error {
+	fmt.Printf("%s %s\n", style.Bold.Render("Entrypoint:"), p.Execution.Entrypoint)
+
+	run, err := plugin.RunScript(context.Background(), p, townRoot)
+	if err != nil {
+		return err
+	}
+	if out := strings.TrimSpace(run.Output); out != "" {
+		fmt.Println()
+		fmt.Println(out)
+	}
+	fmt.Println()
+
+	beadID, recErr := plugin.NewRecorder(townRoot).RecordRun(plugin.PluginRunRecord{
+		PluginName: p.Name,
+		RigName:    p.RigName,
+		Result:     run.Result,
+		Body:       run.FormatBody(p),
+	})
+	if recErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record run: %v\n", recErr)
+	}
+
+	if run.Result == plugin.ResultFailure {
+		fmt.Printf("%s Script %s\n", style.Error.Render("✗"), run.Summary())
+	} else {
+		fmt.Printf("%s Script %s\n", style.Success.Render("✓"), run.Summary())
+	}
+	if recErr == nil {
+		fmt.Printf("%s Recorded run: %s\n", style.Dim.Render("●"), beadID)
+	}
+	if run.Result == plugin.ResultFailure {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
 func runPluginHistory(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
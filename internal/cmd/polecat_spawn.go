@@ -15,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/witness"
@@ -27,13 +28,14 @@ type SpawnedPolecatInfo struct {
 	PolecatName string // Polecat name (e.g., "Toast")
 	ClonePath   string // Path to polecat's git worktree
 	SessionName string // Tmux session name (e.g., "gt-gastown-p-Toast")
-	Pane        string // Tmux pane ID (empty until StartSession is called)
+	Pane        string // Tmux pane ID (empty until StartSession is called, and for headless sessions)
 	BaseBranch  string // Effective base branch (e.g., "main", "integration/epic-id")
 	Branch      string // Git branch name (for cleanup on rollback)
 
 	// Internal fields for deferred session start
 	account string
 	agent   string
+	started bool
 }
 
 // AgentID returns the agent identifier (e.g., "gastown/polecats/Toast")
@@ -41,9 +43,9 @@ func (s *SpawnedPolecatInfo) AgentID() string {
 	return fmt.Sprintf("%s/polecats/%s", s.RigName, s.PolecatName)
 }
 
-// SessionStarted returns true if the tmux session has been started.
+// SessionStarted returns true if the session has been started.
 func (s *SpawnedPolecatInfo) SessionStarted() bool {
-	return s.Pane != ""
+	return s.started || s.Pane != ""
 }
 
 // SlingSpawnOptions contains options for spawning a polecat via sling.
@@ -102,7 +104,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	// per-bead respawn limit in the witness. Default cap: 25 per town.
 	// TODO: make configurable via rig config (max_polecats already exists for scheduler)
 	const defaultMaxActivePolecats = 25
-	activeCount := countActivePolecats()
+	activeCount := countActivePolecats(townRoot)
 	if activeCount >= defaultMaxActivePolecats {
 		return nil, fmt.Errorf("polecat cap reached: %d active polecats (max %d). "+
 			"This is a safety limit to prevent spawn storms. "+
@@ -110,6 +112,16 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 			activeCount, defaultMaxActivePolecats)
 	}
 
+	// Spend budgets: with the stop action, an exceeded town, rig or convoy
+	// budget refuses new polecats until it resets or is raised.
+	if !opts.Force {
+		if reason, held := budgetSlingHold(townRoot, rigName, opts.HookBead); held {
+			return nil, fmt.Errorf("%s (budget action: stop).\n"+
+				"Raise it with gt config set budget.*, or override: gt sling <bead> %s --force",
+				reason, rigName)
+		}
+	}
+
 	// Per-bead respawn circuit breaker (clown show #22):
 	// Track how many times this bead has been slung. Block after N attempts
 	// to prevent witness→deacon→sling feedback loops.
@@ -351,10 +363,11 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	}, nil
 }
 
-// StartSession starts the tmux session for a spawned polecat.
+// StartSession starts the session for a spawned polecat.
 // This is called after the molecule/bead is attached, so the polecat
 // sees its work when gt prime runs on session start.
-// Returns the pane ID after session start.
+// Returns the pane ID after session start, or "" when the session runs
+// headless under the PTY supervisor.
 func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	if s.SessionStarted() {
 		return s.Pane, nil
@@ -425,7 +438,8 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	} else {
 		runtimeConfig = config.ResolveRoleAgentConfig("polecat", spawnTownRoot, r.Path)
 	}
-	if err := t.WaitForRuntimeReady(s.SessionName, runtimeConfig, 30*time.Second); err != nil {
+	backend := polecatSessMgr.Backend()
+	if err := backend.WaitForRuntimeReady(s.SessionName, runtimeConfig, 30*time.Second); err != nil {
 		style.PrintWarning("runtime may not be fully ready: %v", err)
 	}
 
@@ -447,16 +461,35 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 		style.PrintWarning("could not update issue status to in_progress: %v", err)
 	}
 
-	// Get pane — if this fails, the session may have died during startup.
-	// Kill the dead session to prevent "session already running" on next attempt (gt-jn40ft).
-	pane, err := getSessionPane(s.SessionName)
+	pane, err := startedSessionPane(backend, s.SessionName)
 	if err != nil {
-		// Session likely died — clean up the tmux session so it doesn't block re-sling
-		_ = t.KillSession(s.SessionName)
-		return "", fmt.Errorf("getting pane for %s (session likely died during startup): %w", s.SessionName, err)
+		return "", err
 	}
 
 	s.Pane = pane
+	s.started = true
+	return pane, nil
+}
+
+// startedSessionPane returns the pane of a just-started session. Headless
+// backends have no pane and return ""; sling then skips its pane nudge,
+// since SessionManager.Start already sent the startup nudge. If the session
+// died during startup it is killed, to prevent "session already running"
+// on the next attempt (gt-jn40ft).
+func startedSessionPane(backend session.SessionBackend, sessionName string) (string, error) {
+	if session.AsTmux(backend) == nil {
+		if alive, err := backend.HasSession(sessionName); err != nil || !alive {
+			_ = backend.KillSessionWithProcesses(sessionName)
+			return "", fmt.Errorf("session %s not found (session likely died during startup)", sessionName)
+		}
+		return "", nil
+	}
+
+	pane, err := getSessionPane(sessionName)
+	if err != nil {
+		_ = backend.KillSessionWithProcesses(sessionName)
+		return "", fmt.Errorf("getting pane for %s (session likely died during startup): %w", sessionName, err)
+	}
 	return pane, nil
 }
 
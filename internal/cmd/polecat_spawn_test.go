@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/ptyd"
+)
+
+// TestStartedSessionPane_PTYBackend covers the tail of sling's polecat
+// spawn when the town runs polecats under the PTY supervisor: there is no
+// tmux pane, so StartSession must return "" (sling then skips the pane
+// nudge) instead of failing and killing the session.
+func TestStartedSessionPane_PTYBackend(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("PTY supervisor sessions need Linux")
+	}
+	townRoot := t.TempDir()
+	socket := ptyd.SocketPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(socket), 0755); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- ptyd.NewSupervisor().Serve(ctx, socket) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	backend := ptyd.NewClient(townRoot)
+	for deadline := time.Now().Add(5 * time.Second); !backend.Running(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("supervisor did not start")
+		}
+	}
+
+	if err := backend.NewSessionWithCommand("gt-gastown-p-Toast", townRoot, "cat"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	pane, err := startedSessionPane(backend, "gt-gastown-p-Toast")
+	if err != nil || pane != "" {
+		t.Errorf("startedSessionPane = %q, %v; want no pane and no error", pane, err)
+	}
+	if ok, _ := backend.HasSession("gt-gastown-p-Toast"); !ok {
+		t.Error("running headless session was killed")
+	}
+
+	// A session that died during startup is reported.
+	if _, err := startedSessionPane(backend, "gt-gastown-p-Nux"); err == nil {
+		t.Error("startedSessionPane succeeded for a missing session")
+	}
+}
@@ -208,6 +208,7 @@ var quotaScanCmd = &cobra.Command{
 
 Captures recent pane output from each session and checks for rate-limit
 messages. Reports which sessions are blocked and which account they use.
+Headless polecats (session_backend "pty") are not scanned.
 
 Use --update to automatically update quota state with detected limits.
 
@@ -336,6 +337,8 @@ var quotaRotateCmd = &cobra.Command{
 
 Scans all sessions for rate limits, plans account assignments using
 least-recently-used ordering, and restarts blocked sessions with fresh accounts.
+Only tmux sessions are rotated; headless polecats (session_backend "pty")
+are skipped.
 
 Use --from to preemptively rotate sessions using a specific account before
 it hits its rate limit. This is useful for switching idle sessions while
@@ -344,7 +347,9 @@ it's not disruptive.
 The rotation process:
   1. Scans all Gas Town sessions for rate-limit indicators
   2. Selects available accounts (LRU order)
-  3. Swaps macOS Keychain credentials (same config dir preserved)
+  3. Swaps account credentials (same config dir preserved): the macOS
+     Keychain entry, or on Linux the plaintext <config dir>/.credentials.json
+     that Claude Code reads (no Secret Service or encrypted store)
   4. Restarts blocked sessions via respawn-pane
   5. Sends /resume to recover conversation context
 
@@ -695,6 +700,7 @@ func executeKeychainRotation(
 	// agent silently resumes where it left off without a fresh handoff cycle.
 	restartCmd, err := buildRestartCommandWithOpts(session, buildRestartCommandOpts{
 		ContinueSession: true,
+		ConfigDir:       currentConfigDir,
 	})
 	if err != nil {
 		result.Error = fmt.Sprintf("building restart command: %v", err)
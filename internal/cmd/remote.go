@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/federation"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// remote command flags
+var (
+	remoteAddEntity string
+	remoteAddChain  string
+	remoteListJSON  bool
+)
+
+var remoteCmd = &cobra.Command{
+	Use:     "remote",
+	GroupID: GroupWorkspace,
+	Short:   "Manage peer towns for cross-town federation",
+	RunE:    requireSubcommand,
+	Long: `Register peer Gas Towns so their beads can be referenced by hop:// URI.
+
+A peer is identified by its entity (owner) and chain (town name), which
+form the first two segments of a HOP URI:
+
+  hop://entity/chain/rig/issue-id
+
+Registered peers are stored in mayor/remotes.json. Remote beads are
+fetched read-only by cloning the peer's rig database from its Dolt remote
+into .federation/ and querying it locally.
+
+Commands that accept hop:// references:
+  gt show hop://...           Show a remote bead
+  gt sling hop://... <rig>    Sling a local mirror of a remote bead
+  gt convoy create/add        Track a local mirror of a remote bead`,
+}
+
+var remoteAddCmd = &cobra.Command{
+	Use:   "add <name> <dolt-url>",
+	Short: "Register a peer town",
+	Long: `Register a peer town under a local name.
+
+The dolt-url is the base URL the peer serves its databases from; rig R
+is fetched from <dolt-url>/R.
+
+Examples:
+  gt remote add acme http://town-a.example.com:8000 --entity ops@acme.com --chain main-town
+  gt remote add steve https://doltremoteapi.dolthub.com/steveyegge --entity steve@example.com`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRemoteAdd,
+}
+
+var remoteListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered peer towns",
+	Args:  cobra.NoArgs,
+	RunE:  runRemoteList,
+}
+
+var remoteRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Unregister a peer town",
+	Long: `Unregister a peer town and delete its cached databases.
+
+Local mirror beads created from the peer are left untouched.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRemoteRemove,
+}
+
+func init() {
+	remoteAddCmd.Flags().StringVar(&remoteAddEntity, "entity", "", "Peer's owner identity (first hop:// segment, required)")
+	remoteAddCmd.Flags().StringVar(&remoteAddChain, "chain", "", "Peer's town name (second hop:// segment, default: <name>)")
+	_ = remoteAddCmd.MarkFlagRequired("entity")
+	remoteListCmd.Flags().BoolVar(&remoteListJSON, "json", false, "Output as JSON")
+
+	remoteCmd.AddCommand(remoteAddCmd)
+	remoteCmd.AddCommand(remoteListCmd)
+	remoteCmd.AddCommand(remoteRemoveCmd)
+	rootCmd.AddCommand(remoteCmd)
+}
+
+func runRemoteAdd(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	remotes, err := federation.LoadRemotes(townRoot)
+	if err != nil {
+		return err
+	}
+
+	chain := remoteAddChain
+	if chain == "" {
+		chain = args[0]
+	}
+	remote := &federation.Remote{
+		Name:    args[0],
+		Entity:  remoteAddEntity,
+		Chain:   chain,
+		DoltURL: args[1],
+	}
+	if err := remotes.Add(remote); err != nil {
+		return err
+	}
+	if err := federation.SaveRemotes(townRoot, remotes); err != nil {
+		return fmt.Errorf("saving remotes: %w", err)
+	}
+
+	fmt.Printf("%s Added remote %s\n", style.Bold.Render("✓"), remote.Name)
+	fmt.Printf("  Entity: %s\n", remote.Entity)
+	fmt.Printf("  Chain:  %s\n", remote.Chain)
+	fmt.Printf("  Dolt:   %s\n", remote.DoltURL)
+	fmt.Printf("\n  %s\n", style.Dim.Render("Reference beads as "+remote.URI("<rig>", "<issue-id>")))
+	return nil
+}
+
+func runRemoteList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	remotes, err := federation.LoadRemotes(townRoot)
+	if err != nil {
+		return err
+	}
+	list := remotes.List()
+
+	if remoteListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No remotes registered")
+		fmt.Printf("  %s\n", style.Dim.Render("Add one with: gt remote add <name> <dolt-url> --entity <owner>"))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tENTITY\tCHAIN\tDOLT URL")
+	for _, r := range list {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Name, r.Entity, r.Chain, r.DoltURL)
+	}
+	return w.Flush()
+}
+
+func runRemoteRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	remotes, err := federation.LoadRemotes(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := remotes.Remove(args[0]); err != nil {
+		return err
+	}
+	if err := federation.SaveRemotes(townRoot, remotes); err != nil {
+		return fmt.Errorf("saving remotes: %w", err)
+	}
+	if err := os.RemoveAll(filepath.Join(federation.CacheDir(townRoot), args[0])); err != nil {
+		style.PrintWarning("couldn't remove cached databases: %v", err)
+	}
+
+	fmt.Printf("%s Removed remote %s\n", style.Bold.Render("✓"), args[0])
+	return nil
+}
+
+// resolveHopRefs replaces hop:// references in ids with local mirror bead IDs,
+// creating mirrors in town beads as needed. Other IDs are returned unchanged.
+func resolveHopRefs(ids []string) ([]string, error) {
+	var townRoot string
+	var resolver *federation.Resolver
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		if !federation.IsHopURI(id) {
+			out[i] = id
+			continue
+		}
+		if resolver == nil {
+			var err error
+			townRoot, err = workspace.FindFromCwdOrError()
+			if err != nil {
+				return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+			}
+			resolver, err = federation.NewResolver(townRoot)
+			if err != nil {
+				return nil, err
+			}
+		}
+		localID, err := ensureHopMirror(townRoot, resolver, id)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = localID
+	}
+	return out, nil
+}
+
+// lookupHopMirrors is the read-only counterpart of resolveHopRefs used by
+// dry runs: hop:// references are replaced by their existing mirror IDs, and
+// references without a mirror are returned in missing (left unchanged in ids).
+func lookupHopMirrors(ids []string) (out, missing []string, err error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	townBeads := beads.NewWithBeadsDir(townRoot, filepath.Join(townRoot, ".beads"))
+	mirrors, err := townBeads.List(beads.ListOptions{Label: federation.MirrorLabel, Status: "all", Priority: -1})
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing federated mirrors: %w", err)
+	}
+
+	out = make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id
+		if !federation.IsHopURI(id) {
+			continue
+		}
+		u, err := federation.ParseHopURI(id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if mirror := federation.FindMirror(mirrors, u.String()); mirror != nil {
+			out[i] = mirror.ID
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return out, missing, nil
+}
+
+// mirrorClosedReason is the close reason of a mirror whose remote bead closed.
+const mirrorClosedReason = "closed in remote town"
+
+// ensureHopMirror returns the ID of the town-level bead mirroring a remote bead,
+// creating it if needed. An existing mirror is closed when the remote bead has
+// been closed, so convoys tracking it can complete.
+func ensureHopMirror(townRoot string, resolver *federation.Resolver, ref string) (string, error) {
+	remoteIssue, err := resolver.Resolve(ref)
+	if err != nil {
+		return "", err
+	}
+	if remoteIssue.Stale {
+		style.PrintWarning("remote %s unreachable; using cached copy of %s", remoteIssue.Remote, remoteIssue.URI)
+	}
+
+	townBeads := beads.NewWithBeadsDir(townRoot, filepath.Join(townRoot, ".beads"))
+	mirrors, err := townBeads.List(beads.ListOptions{Label: federation.MirrorLabel, Status: "all", Priority: -1})
+	if err != nil {
+		return "", fmt.Errorf("listing federated mirrors: %w", err)
+	}
+
+	if mirror := federation.FindMirror(mirrors, remoteIssue.URI); mirror != nil {
+		if remoteIssue.Status == "closed" && mirror.Status != "closed" {
+			if err := townBeads.CloseWithReason(mirrorClosedReason, mirror.ID); err != nil {
+				style.PrintWarning("couldn't close mirror %s: %v", mirror.ID, err)
+			}
+		}
+		return mirror.ID, nil
+	}
+
+	mirror, err := townBeads.Create(beads.CreateOptions{
+		Title:       remoteIssue.Title,
+		Priority:    remoteIssue.Priority,
+		Description: federation.MirrorDescription(remoteIssue),
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating mirror of %s: %w", remoteIssue.URI, err)
+	}
+	if err := townBeads.Update(mirror.ID, beads.UpdateOptions{AddLabels: []string{federation.MirrorLabel}}); err != nil {
+		return "", fmt.Errorf("labeling mirror %s: %w", mirror.ID, err)
+	}
+	fmt.Printf("%s Mirrored %s as %s\n", style.Bold.Render("✓"), remoteIssue.URI, mirror.ID)
+	return mirror.ID, nil
+}
+
+// refreshHopMirrors re-resolves every open mirror bead and closes those whose
+// remote bead has been closed, so convoys tracking remote work can complete.
+// Mirrors otherwise only refresh when the bead is slung or added again.
+// Unreachable remotes are skipped with a warning; the next check retries.
+func refreshHopMirrors(townRoot string, dryRun bool) error {
+	resolver, err := federation.NewResolver(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(resolver.Remotes.List()) == 0 {
+		return nil
+	}
+
+	townBeads := beads.NewWithBeadsDir(townRoot, filepath.Join(townRoot, ".beads"))
+	mirrors, err := townBeads.List(beads.ListOptions{Label: federation.MirrorLabel, Status: "all", Priority: -1})
+	if err != nil {
+		return fmt.Errorf("listing federated mirrors: %w", err)
+	}
+
+	for _, mirror := range mirrors {
+		ref := federation.HopRef(mirror)
+		if ref == "" || mirror.Status == "closed" {
+			continue
+		}
+		remoteIssue, err := resolver.Resolve(ref)
+		if err != nil {
+			style.PrintWarning("couldn't refresh mirror %s: %v", mirror.ID, err)
+			continue
+		}
+		if remoteIssue.Status != "closed" {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("Would close mirror %s (%s closed in remote town)\n", mirror.ID, remoteIssue.URI)
+			continue
+		}
+		if err := townBeads.CloseWithReason(mirrorClosedReason, mirror.ID); err != nil {
+			style.PrintWarning("couldn't close mirror %s: %v", mirror.ID, err)
+			continue
+		}
+		fmt.Printf("%s Closed mirror %s (%s closed in remote town)\n", style.Bold.Render("✓"), mirror.ID, remoteIssue.URI)
+	}
+	return nil
+}
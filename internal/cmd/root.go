@@ -287,7 +287,11 @@ func Execute() int {
 		telemetry.SetProcessOTELAttrs()
 	}
 
-	if err := rootCmd.Execute(); err != nil {
+	cmdCtx, endSpan := startCommandSpan(ctx, os.Args[1:])
+	err = rootCmd.ExecuteContext(cmdCtx)
+	endSpan(err)
+
+	if err != nil {
 		// Check for silent exit (scripting commands that signal status via exit code)
 		if code, ok := IsSilentExit(err); ok {
 			return code
@@ -298,6 +302,35 @@ func Execute() int {
 	return 0
 }
 
+// untracedCommands run for as long as their process, keyed by command path
+// below the root. They get no command span: it would only be exported on
+// exit, and everything they start over days would join its trace.
+var untracedCommands = map[string]bool{
+	"daemon run": true,
+	"dashboard":  true,
+}
+
+// startCommandSpan starts the span of a gt invocation, named after the
+// subcommand args resolve to (e.g. "gt sling"). It is parented on the
+// TRACEPARENT this process inherited, if any, and exported to everything the
+// process spawns. Returns the command's context and a function ending the
+// span.
+func startCommandSpan(ctx context.Context, args []string) (context.Context, func(error)) {
+	cmd, _, err := rootCmd.Find(args)
+	if err != nil {
+		cmd = rootCmd
+	}
+	if untracedCommands[strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")] {
+		// Nor do they adopt the trace of whoever started them.
+		_ = os.Unsetenv(telemetry.EnvTraceParent)
+		_ = os.Unsetenv(telemetry.EnvTraceState)
+		return ctx, func(error) {}
+	}
+	cmdCtx, span := telemetry.StartSpan(ctx, cmd.CommandPath())
+	telemetry.SetProcessTraceContext(cmdCtx)
+	return cmdCtx, func(err error) { telemetry.EndSpan(span, err) }
+}
+
 // Command group IDs - used by subcommands to organize help output
 const (
 	GroupWork      = "work"
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -8,6 +9,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestCheckHelpFlag(t *testing.T) {
@@ -248,3 +252,38 @@ func TestPersistentPreRunMalformedAgentRegistry(t *testing.T) {
 		t.Fatalf("GetProcessNames(claude) after malformed registry = %v, want builtin [node claude ...]", got)
 	}
 }
+
+func TestStartCommandSpan_LongRunningCommands(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	t.Cleanup(telemetry.UseSpanExporter(exp))
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	// The daemon neither starts a span nor passes on the one it inherited.
+	t.Setenv(telemetry.EnvTraceParent, parent)
+	ctx, end := startCommandSpan(context.Background(), []string{"daemon", "run"})
+	end(nil)
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		t.Error("daemon run context carries a span")
+	}
+	if got := os.Getenv(telemetry.EnvTraceParent); got != "" {
+		t.Errorf("TRACEPARENT = %q after daemon run, want unset", got)
+	}
+	if spans := exp.GetSpans(); len(spans) != 0 {
+		t.Errorf("daemon run exported %d spans, want 0", len(spans))
+	}
+
+	// Other commands get a span under the inherited trace.
+	t.Setenv(telemetry.EnvTraceParent, parent)
+	_, end = startCommandSpan(context.Background(), []string{"version"})
+	end(nil)
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("version exported %d spans, want 1", len(spans))
+	}
+	if want := rootCmd.Name() + " version"; spans[0].Name != want {
+		t.Errorf("span name = %q, want %q", spans[0].Name, want)
+	}
+	if got := spans[0].Parent.TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("parent trace ID = %s", got)
+	}
+}
@@ -6,9 +6,12 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/quota"
 	"github.com/steveyegge/gastown/internal/scheduler/capacity"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -47,7 +50,14 @@ Config:
 var schedulerStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show scheduler state: pending, capacity, active polecats",
-	RunE:  runSchedulerStatus,
+	Long: `Show scheduler state: pending beads, active polecats, and how much of
+each configured limit is in use.
+
+Limits include the town-wide scheduler.max_polecats plus any per-rig,
+per-account, and per-agent limits (scheduler.rig_max_polecats.<rig>, etc.).
+Accounts that quota tracking reports as rate-limited are listed too; beads
+that would run on them wait until the limit resets.`,
+	RunE: runSchedulerStatus,
 }
 
 var schedulerListCmd = &cobra.Command{
@@ -124,6 +134,12 @@ type scheduledBeadInfo struct {
 	Status    string `json:"status"`
 	TargetRig string `json:"target_rig"`
 	Blocked   bool   `json:"blocked,omitempty"`
+
+	NotBefore  string `json:"not_before,omitempty"`
+	Deadline   string `json:"deadline,omitempty"`
+	Recurrence string `json:"recurrence,omitempty"`
+	Held       bool   `json:"held,omitempty"`
+	Overdue    bool   `json:"overdue,omitempty"`
 }
 
 func runSchedulerStatus(cmd *cobra.Command, args []string) error {
@@ -142,22 +158,36 @@ func runSchedulerStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("listing scheduled beads: %w", err)
 	}
 
-	activePolecats := countActivePolecats()
+	activePolecats := countActivePolecats(townRoot)
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	schedulerCfg := settings.Scheduler
+	if schedulerCfg == nil {
+		schedulerCfg = capacity.DefaultSchedulerConfig()
+	}
+	limits := buildSchedulerLimiter(townRoot, schedulerCfg, settings).Usage()
 
 	if schedulerStatusJSON {
 		out := struct {
-			Paused         bool               `json:"paused"`
-			PausedBy       string             `json:"paused_by,omitempty"`
-			ScheduledTotal int                `json:"queued_total"`
-			ScheduledReady int                `json:"queued_ready"`
-			ActivePolecats int                `json:"active_polecats"`
-			LastDispatchAt string             `json:"last_dispatch_at,omitempty"`
-			Beads          []scheduledBeadInfo `json:"beads"`
+			Paused         bool                  `json:"paused"`
+			PausedBy       string                `json:"paused_by,omitempty"`
+			ScheduledTotal int                   `json:"queued_total"`
+			ScheduledReady int                   `json:"queued_ready"`
+			ActivePolecats int                   `json:"active_polecats"`
+			MaxPolecats    int                   `json:"max_polecats"`
+			Limits         []capacity.LimitUsage `json:"limits,omitempty"`
+			LastDispatchAt string                `json:"last_dispatch_at,omitempty"`
+			Beads          []scheduledBeadInfo   `json:"beads"`
 		}{
 			Paused:         state.Paused,
 			PausedBy:       state.PausedBy,
 			ScheduledTotal: len(scheduled),
 			ActivePolecats: activePolecats,
+			MaxPolecats:    schedulerCfg.GetMaxPolecats(),
+			Limits:         limits,
 			LastDispatchAt: state.LastDispatchAt,
 			Beads:          scheduled,
 		}
@@ -185,7 +215,32 @@ func runSchedulerStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  State:    active\n")
 	}
 	fmt.Printf("  Scheduled: %d total, %d ready\n", len(scheduled), readyCount)
-	fmt.Printf("  Active:    %d polecats\n", activePolecats)
+	if maxPolecats := schedulerCfg.GetMaxPolecats(); maxPolecats > 0 {
+		fmt.Printf("  Active:    %d/%d polecats\n", activePolecats, maxPolecats)
+	} else {
+		fmt.Printf("  Active:    %d polecats\n", activePolecats)
+	}
+	if len(limits) > 0 {
+		fmt.Printf("  Limits:\n")
+		for _, u := range limits {
+			used := fmt.Sprintf("%d", u.Active)
+			if u.Max > 0 {
+				used = fmt.Sprintf("%d/%d", u.Active, u.Max)
+				if u.Active >= u.Max {
+					used = style.Warning.Render(used + " (full)")
+				}
+			}
+			line := fmt.Sprintf("    %-8s %-20s %s", u.Kind, u.Key, used)
+			if u.RateLimited {
+				note := "rate-limited"
+				if u.ResetsAt != "" {
+					note += ", resets " + u.ResetsAt
+				}
+				line += " " + style.Warning.Render("("+note+")")
+			}
+			fmt.Println(line)
+		}
+	}
 	if state.LastDispatchAt != "" {
 		fmt.Printf("  Last dispatch: %s (%d beads)\n", state.LastDispatchAt, state.LastDispatchCount)
 	}
@@ -228,8 +283,26 @@ func runSchedulerList(cmd *cobra.Command, args []string) error {
 			indicator := "○"
 			if b.Blocked {
 				indicator = "⏸"
+			} else if b.Held {
+				indicator = "⏰"
+			}
+			var notes []string
+			if b.Held {
+				notes = append(notes, "until "+formatScheduleTime(b.NotBefore))
+			}
+			if b.Overdue {
+				notes = append(notes, style.Warning.Render("overdue since "+formatScheduleTime(b.Deadline)))
+			} else if b.Deadline != "" {
+				notes = append(notes, "due "+formatScheduleTime(b.Deadline))
 			}
-			fmt.Printf("    %s %s: %s\n", indicator, b.ID, b.Title)
+			if b.Recurrence != "" {
+				notes = append(notes, "repeats "+b.Recurrence)
+			}
+			line := fmt.Sprintf("    %s %s: %s", indicator, b.ID, b.Title)
+			if len(notes) > 0 {
+				line += " " + style.Dim.Render("("+strings.Join(notes, ", ")+")")
+			}
+			fmt.Println(line)
 		}
 		fmt.Println()
 	}
@@ -237,6 +310,15 @@ func runSchedulerList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// formatScheduleTime renders a stored RFC3339 time in local time for display.
+func formatScheduleTime(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
 func runSchedulerPause(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -386,6 +468,7 @@ func listScheduledBeads(townRoot string) ([]scheduledBeadInfo, error) {
 	readyWorkIDs := listReadyWorkBeadIDs(townRoot)
 	workBeadInfo := batchFetchBeadInfoByIDs(townRoot, workBeadIDs)
 
+	now := time.Now()
 	seenWork := make(map[string]bool)
 	var result []scheduledBeadInfo
 	for _, ctx := range allContexts {
@@ -423,6 +506,12 @@ func listScheduledBeads(townRoot string) ([]scheduledBeadInfo, error) {
 			Status:    status,
 			TargetRig: fields.TargetRig,
 			Blocked:   !readyWorkIDs[fields.WorkBeadID],
+
+			NotBefore:  fields.NotBefore,
+			Deadline:   fields.Deadline,
+			Recurrence: fields.Recurrence,
+			Held:       fields.IsHeld(now),
+			Overdue:    fields.IsOverdue(now),
 		})
 	}
 
@@ -482,25 +571,98 @@ func beadsSearchDirs(townRoot string) []string {
 }
 
 // countActivePolecats counts all running polecats across all rigs in the town.
-func countActivePolecats() int {
-	listCmd := tmux.BuildCommand("list-sessions", "-F", "#{session_name}")
-	out, err := listCmd.Output()
+func countActivePolecats(townRoot string) int {
+	count := 0
+	for _, n := range countActivePolecatsByRig(townRoot) {
+		count += n
+	}
+	return count
+}
+
+// countActivePolecatsByRig counts running polecats per rig.
+func countActivePolecatsByRig(townRoot string) map[string]int {
+	counts := make(map[string]int)
+	for _, p := range listActivePolecats(townRoot, nil, false) {
+		counts[p.Rig]++
+	}
+	return counts
+}
+
+// countActivePolecatsByConvoy counts running polecats per convoy, from the
+// convoy_id recorded on each polecat's hooked bead. One bd query per rig with
+// running polecats.
+func countActivePolecatsByConvoy(townRoot string) map[string]int {
+	counts := make(map[string]int)
+	names, err := polecatSessionBackend(townRoot).ListSessions()
 	if err != nil {
-		return 0
+		return counts
 	}
 
-	count := 0
-	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
-		if line == "" {
+	running := make(map[string]map[string]bool) // rig → polecat address
+	for _, name := range names {
+		identity, err := session.ParseSessionName(name)
+		if err != nil || identity.Role != session.RolePolecat {
 			continue
 		}
-		identity, err := session.ParseSessionName(line)
+		if running[identity.Rig] == nil {
+			running[identity.Rig] = make(map[string]bool)
+		}
+		running[identity.Rig][identity.Address()] = true
+	}
+
+	for rig, addresses := range running {
+		hooked, err := beads.New(filepath.Join(townRoot, rig)).List(beads.ListOptions{
+			Status:   beads.StatusHooked,
+			Priority: -1,
+		})
 		if err != nil {
 			continue
 		}
-		if identity.Role == session.RolePolecat {
-			count++
+		for _, issue := range hooked {
+			if !addresses[issue.Assignee] {
+				continue
+			}
+			if fields := beads.ParseAttachmentFields(issue); fields != nil && fields.ConvoyID != "" {
+				counts[fields.ConvoyID]++
+			}
 		}
 	}
-	return count
+	return counts
+}
+
+// polecatSessionBackend returns the backend the town's polecats run in:
+// tmux, or the PTY supervisor for headless polecats. Tests replace it.
+var polecatSessionBackend = func(townRoot string) session.SessionBackend {
+	return session.PolecatBackend(townRoot, tmux.NewTmux())
+}
+
+// listActivePolecats returns the town's running polecats. With withRuntime,
+// each polecat's account and agent preset are read from its session
+// environment (extra calls per session, so only when needed).
+func listActivePolecats(townRoot string, accounts *config.AccountsConfig, withRuntime bool) []capacity.ActivePolecat {
+	backend := polecatSessionBackend(townRoot)
+	names, err := backend.ListSessions()
+	if err != nil {
+		return nil
+	}
+
+	var polecats []capacity.ActivePolecat
+	for _, name := range names {
+		identity, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		if identity.Role != session.RolePolecat {
+			continue
+		}
+		p := capacity.ActivePolecat{Rig: identity.Rig}
+		if withRuntime {
+			p.Account = quota.SessionAccount(backend, accounts, name)
+			if agent, err := backend.GetEnvironment(name, "GT_AGENT"); err == nil {
+				p.Agent = strings.TrimSpace(agent)
+			}
+		}
+		polecats = append(polecats, p)
+	}
+	return polecats
 }
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// fakePolecatBackend lists sessions and serves their environment; other
+// backend methods are not used by the polecat counts.
+type fakePolecatBackend struct {
+	session.SessionBackend
+	sessions []string
+	env      map[string]map[string]string
+}
+
+func (f *fakePolecatBackend) ListSessions() ([]string, error) { return f.sessions, nil }
+
+func (f *fakePolecatBackend) GetEnvironment(sess, key string) (string, error) {
+	if v, ok := f.env[sess][key]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("unknown variable: %s", key)
+}
+
+func TestListActivePolecats_HeadlessSessions(t *testing.T) {
+	backend := &fakePolecatBackend{
+		sessions: []string{"gt-toast", "gt-nux", "gt-witness", "gt-crew-max", "hq-mayor"},
+		env: map[string]map[string]string{
+			"gt-toast": {"GT_AGENT": "codex", "GT_QUOTA_ACCOUNT": "work"},
+			"gt-nux":   {"GT_AGENT": "claude"},
+		},
+	}
+	orig := polecatSessionBackend
+	polecatSessionBackend = func(string) session.SessionBackend { return backend }
+	t.Cleanup(func() { polecatSessionBackend = orig })
+
+	setupCostsTestRegistry(t)
+	rig := "gastown"
+
+	if got := countActivePolecats(t.TempDir()); got != 2 {
+		t.Errorf("countActivePolecats() = %d, want 2", got)
+	}
+	if got := countActivePolecatsByRig(t.TempDir()); got[rig] != 2 || len(got) != 1 {
+		t.Errorf("countActivePolecatsByRig() = %v, want %s: 2", got, rig)
+	}
+
+	accounts := &config.AccountsConfig{Accounts: map[string]config.Account{"work": {}}}
+	got := listActivePolecats(t.TempDir(), accounts, true)
+	want := []capacity.ActivePolecat{
+		{Rig: rig, Account: "work", Agent: "codex"},
+		{Rig: rig, Agent: "claude"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("listActivePolecats() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("listActivePolecats()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// applyScheduleWindow fills the time-window fields of a new sling context from
+// user flags. A recurrence without --not-before starts at its next occurrence.
+func applyScheduleWindow(fields *capacity.SlingContextFields, notBefore, deadline, every string, now time.Time) error {
+	var nb time.Time
+	if notBefore != "" {
+		t, err := capacity.ParseScheduleTime(notBefore, now)
+		if err != nil {
+			return fmt.Errorf("--not-before: %w", err)
+		}
+		nb = t
+	}
+	if every != "" {
+		rule, err := capacity.ParseRecurrence(every)
+		if err != nil {
+			return fmt.Errorf("--every: %w", err)
+		}
+		if nb.IsZero() {
+			nb = rule.Next(now)
+		}
+		fields.Recurrence = rule.String()
+	}
+	if !nb.IsZero() {
+		fields.NotBefore = nb.UTC().Format(time.RFC3339)
+	}
+	if deadline != "" {
+		t, err := capacity.ParseScheduleTime(deadline, now)
+		if err != nil {
+			return fmt.Errorf("--deadline: %w", err)
+		}
+		if !nb.IsZero() && !t.After(nb) {
+			return fmt.Errorf("--deadline %s is not after --not-before %s", t.Format(time.RFC3339), nb.Format(time.RFC3339))
+		}
+		fields.Deadline = t.UTC().Format(time.RFC3339)
+	}
+	return nil
+}
+
+// validateSlingScheduleFlags rejects time-window flags when dispatch isn't
+// deferred: only the scheduler can hold work until a time.
+func validateSlingScheduleFlags(deferred bool) error {
+	if slingNotBefore == "" && slingDeadline == "" && slingEvery == "" {
+		return nil
+	}
+	if !deferred {
+		return fmt.Errorf("--not-before, --deadline and --every need deferred dispatch\nEnable it with: gt config set scheduler.max_polecats <N>")
+	}
+	return applyScheduleWindow(&capacity.SlingContextFields{}, slingNotBefore, slingDeadline, slingEvery, time.Now())
+}
+
+// escalateOverdueContexts escalates open sling contexts whose deadline has
+// passed while still queued. Each context is escalated once.
+func escalateOverdueContexts(townRoot string) {
+	contexts, err := listAllSlingContexts(townRoot)
+	if err != nil {
+		return
+	}
+	townBeads := beads.NewWithBeadsDir(townRoot, filepath.Join(townRoot, ".beads"))
+	now := time.Now()
+	for _, ctx := range contexts {
+		fields := beads.ParseSlingContextFields(ctx.Description)
+		if fields == nil || fields.DeadlineEscalated || !fields.IsOverdue(now) {
+			continue
+		}
+
+		desc := fmt.Sprintf("Scheduled bead %s → %s missed its deadline (%s)", fields.WorkBeadID, fields.TargetRig, fields.Deadline)
+		reason := fmt.Sprintf("Sling context %s is still queued past its deadline.\nEnqueued: %s", ctx.ID, fields.EnqueuedAt)
+		if fields.NotBefore != "" {
+			reason += "\nNot before: " + fields.NotBefore
+		}
+		if fields.LastFailure != "" {
+			reason += fmt.Sprintf("\nLast dispatch failure (%d): %s", fields.DispatchFailures, fields.LastFailure)
+		}
+		escCmd := exec.Command("gt", "escalate", "-s", "high",
+			"--source", "scheduler:deadline",
+			"--related", fields.WorkBeadID,
+			"-r", reason,
+			desc)
+		escCmd.Dir = townRoot
+		if out, err := escCmd.CombinedOutput(); err != nil {
+			fmt.Fprintf(os.Stderr, "%s Could not escalate missed deadline for %s: %v (%s)\n",
+				style.Warning.Render("⚠"), fields.WorkBeadID, err, out)
+			continue
+		}
+
+		_ = events.LogFeed(events.TypeSchedulerDeadline, "scheduler",
+			events.SchedulerDeadlinePayload(fields.WorkBeadID, fields.TargetRig, fields.Deadline))
+
+		fields.DeadlineEscalated = true
+		if err := townBeads.UpdateSlingContextFields(ctx.ID, fields); err != nil {
+			fmt.Fprintf(os.Stderr, "%s Could not mark deadline escalated on %s: %v\n",
+				style.Warning.Render("⚠"), ctx.ID, err)
+		}
+	}
+}
+
+// enqueueNextOccurrence schedules the next run of a recurring bead after a
+// successful dispatch. The dispatched work bead is now owned by its polecat,
+// so the next run gets a fresh copy of it in the same rig database.
+func enqueueNextOccurrence(townRoot, actor string, b capacity.PendingBead) error {
+	rule, err := capacity.ParseRecurrence(b.Context.Recurrence)
+	if err != nil {
+		return err
+	}
+
+	// Step from the scheduled time (not dispatch time) so a late dispatch
+	// doesn't drift the schedule; skip occurrences already in the past.
+	now := time.Now()
+	from := b.Context.NotBeforeTime()
+	if from.IsZero() {
+		from = now
+	}
+	next := rule.Next(from)
+	for !next.IsZero() && next.Before(now) {
+		next = rule.Next(next)
+	}
+	if next.IsZero() {
+		return fmt.Errorf("recurrence %q has no further occurrences", rule)
+	}
+
+	info, err := getBeadInfo(b.WorkBeadID)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", b.WorkBeadID, err)
+	}
+	rigBeads := beads.New(beads.ResolveHookDir(townRoot, b.WorkBeadID, ""))
+	work, err := rigBeads.Create(beads.CreateOptions{
+		Title:       info.Title,
+		Type:        info.IssueType,
+		Priority:    b.Priority,
+		Description: info.Description,
+		Actor:       actor,
+	})
+	if err != nil {
+		return fmt.Errorf("creating next occurrence of %s: %w", b.WorkBeadID, err)
+	}
+
+	fields := *b.Context
+	fields.WorkBeadID = work.ID
+	fields.EnqueuedAt = now.UTC().Format(time.RFC3339)
+	fields.NotBefore = next.UTC().Format(time.RFC3339)
+	fields.Convoy = ""
+	fields.DispatchFailures = 0
+	fields.LastFailure = ""
+	fields.DeadlineEscalated = false
+	fields.TraceParent = "" // each run gets its own trace
+	if deadline := b.Context.DeadlineTime(); !deadline.IsZero() && !from.IsZero() {
+		// Keep the same window length (not_before → deadline) for each run.
+		fields.Deadline = next.Add(deadline.Sub(from)).UTC().Format(time.RFC3339)
+	}
+
+	townBeads := beads.NewWithBeadsDir(townRoot, filepath.Join(townRoot, ".beads"))
+	ctxBead, err := townBeads.CreateSlingContext(info.Title, work.ID, &fields)
+	if err != nil {
+		return fmt.Errorf("creating sling context for %s: %w", work.ID, err)
+	}
+
+	_ = events.LogFeed(events.TypeSchedulerEnqueue, actor, events.SchedulerEnqueuePayload(work.ID, fields.TargetRig))
+	fmt.Printf("  %s Next run of %s: %s at %s (context: %s)\n",
+		style.Dim.Render("↻"), b.WorkBeadID, work.ID, next.Local().Format("2006-01-02 15:04"), ctxBead.ID)
+	return nil
+}
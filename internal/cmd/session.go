@@ -81,7 +81,10 @@ var sessionAtCmd = &cobra.Command{
 	Short:   "Attach to a running session",
 	Long: `Attach to a running polecat session.
 
-Attaches the current terminal to the tmux session. Detach with Ctrl-B D.`,
+Attaches the current terminal to the tmux session. Detach with Ctrl-B D.
+
+With the headless PTY backend (town setting session_backend: "pty"), the
+session's scrollback is replayed first. Detach with Ctrl-].`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSessionAttach,
 }
@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
+	"strings"
 	"syscall"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/federation"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 func init() {
@@ -23,12 +29,16 @@ Delegates to 'bd show' - all bd show flags are supported.
 Works with any bead prefix (gt-, bd-, hq-, etc.) and routes
 to the correct beads database automatically.
 
+hop://entity/chain/rig/issue-id references are resolved read-only
+against a peer town registered with 'gt remote add'.
+
 Examples:
   gt show gt-abc123          # Show a gastown issue
   gt show hq-xyz789          # Show a town-level bead (convoy, mail, etc.)
   gt show bd-def456          # Show a beads issue
   gt show gt-abc123 --json   # Output as JSON
-  gt show gt-abc123 -v       # Verbose output`,
+  gt show gt-abc123 -v       # Verbose output
+  gt show hop://ops@acme.com/main-town/backend/be-x1  # Show a remote bead`,
 	DisableFlagParsing: true, // Pass all flags through to bd show
 	RunE:               runShow,
 }
@@ -43,9 +53,60 @@ func runShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("bead ID required\n\nUsage: gt show <bead-id> [flags]")
 	}
 
+	if federation.IsHopURI(args[0]) {
+		return showHopBead(args[0], slices.Contains(args[1:], "--json"))
+	}
+
 	return execBdShow(args)
 }
 
+// showHopBead prints a bead fetched from a peer town.
+func showHopBead(ref string, asJSON bool) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	resolver, err := federation.NewResolver(townRoot)
+	if err != nil {
+		return err
+	}
+	issue, err := resolver.Resolve(ref)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(issue)
+	}
+
+	fmt.Printf("%s: %s\n", style.Bold.Render(issue.ID), issue.Title)
+	fmt.Printf("  URI:      %s\n", issue.URI)
+	fmt.Printf("  Remote:   %s (read-only)\n", issue.Remote)
+	fmt.Printf("  Status:   %s\n", issue.Status)
+	fmt.Printf("  Priority: P%d\n", issue.Priority)
+	if issue.Type != "" {
+		fmt.Printf("  Type:     %s\n", issue.Type)
+	}
+	if issue.Assignee != "" {
+		fmt.Printf("  Assignee: %s\n", issue.Assignee)
+	}
+	if len(issue.Labels) > 0 {
+		fmt.Printf("  Labels:   %s\n", strings.Join(issue.Labels, ", "))
+	}
+	if issue.UpdatedAt != "" {
+		fmt.Printf("  Updated:  %s\n", issue.UpdatedAt)
+	}
+	if issue.Stale {
+		fmt.Printf("  %s\n", style.Warning.Render("remote unreachable: showing cached copy"))
+	}
+	if issue.Description != "" {
+		fmt.Printf("\n%s\n", issue.Description)
+	}
+	return nil
+}
+
 // execBdShow replaces the current process with 'bd show'.
 func execBdShow(args []string) error {
 	bdPath, err := exec.LookPath("bd")
@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/sink"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	sinkListJSON   bool
+	sinkAddWebhook string
+	sinkAddCommand string
+	sinkAddPipe    string
+	sinkAddEvents  []string
+	sinkAddRig     string
+	sinkAddSecEnv  string
+	sinkDeadJSON   bool
+)
+
+var sinkCmd = &cobra.Command{
+	Use:     "sink",
+	GroupID: GroupDiag,
+	Short:   "Manage outbound notification sinks for town events",
+	Long: `Manage outbound notification sinks for town events.
+
+A sink subscribes to event types from the events log and delivers each
+matching event as JSON:
+
+  webhook   POST to a URL, signed with HMAC-SHA256 when a secret is set
+            (read from the environment variable named by --secret-env)
+  command   Run a shell command with the JSON on stdin
+  pipe      Write a JSON line to a named pipe (or append to a file)
+
+The daemon delivers events with a durable per-sink cursor, retries failures
+with exponential backoff, and moves events that still fail to a dead-letter
+file (daemon/sinks/deadletter.jsonl). Config lives in settings/sinks.json
+and is picked up without restarting the daemon.
+
+Webhook requests carry these headers:
+  X-Gastown-Event       Event type (e.g. merge_failed)
+  X-Gastown-Delivery    Stable event ID (same across retries)
+  X-Gastown-Timestamp   Unix seconds when sent
+  X-Gastown-Signature   sha256=HEX(HMAC-SHA256(secret, timestamp + "." + body))
+
+Subcommands:
+  list      Show configured sinks
+  add       Add a sink
+  remove    Remove a sink
+  test      Send a test event to a sink
+  dead      List dead-lettered deliveries
+  replay    Retry dead-lettered deliveries`,
+	RunE: requireSubcommand,
+}
+
+var sinkListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show configured sinks",
+	Args:  cobra.NoArgs,
+	RunE:  runSinkList,
+}
+
+var sinkAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a notification sink",
+	Long: `Add a notification sink. Exactly one of --webhook, --command or --pipe is required.
+
+Examples:
+  gt sink add oncall --webhook https://hooks.example.com/gt \
+      --events merge_failed,escalation_sent,mass_death,scheduler_dispatch_failed \
+      --secret-env GT_SINK_SECRET
+  gt sink add notify --command 'jq -r .event.type | xargs notify-send "Gas Town"' --events mass_death
+  gt sink add fifo --pipe /tmp/gt-events.fifo --events 'merge_*'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSinkAdd,
+}
+
+var sinkRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a notification sink",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSinkRemove,
+}
+
+var sinkTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Send a test event to a sink (single attempt)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSinkTest,
+}
+
+var sinkDeadCmd = &cobra.Command{
+	Use:   "dead",
+	Short: "List dead-lettered deliveries",
+	Args:  cobra.NoArgs,
+	RunE:  runSinkDead,
+}
+
+var sinkReplayCmd = &cobra.Command{
+	Use:   "replay [name]",
+	Short: "Retry dead-lettered deliveries (optionally for one sink)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runSinkReplay,
+}
+
+func init() {
+	sinkListCmd.Flags().BoolVar(&sinkListJSON, "json", false, "Output as JSON")
+
+	sinkAddCmd.Flags().StringVar(&sinkAddWebhook, "webhook", "", "Webhook URL to POST events to")
+	sinkAddCmd.Flags().StringVar(&sinkAddCommand, "command", "", "Shell command to run with the event JSON on stdin")
+	sinkAddCmd.Flags().StringVar(&sinkAddPipe, "pipe", "", "Named pipe or file to write JSON lines to")
+	sinkAddCmd.Flags().StringSliceVar(&sinkAddEvents, "events", nil, "Event types to deliver, globs allowed (default: all)")
+	sinkAddCmd.Flags().StringVar(&sinkAddRig, "rig", "", "Only deliver events about this rig")
+	sinkAddCmd.Flags().StringVar(&sinkAddSecEnv, "secret-env", "", "Environment variable holding the webhook signing secret (the daemon's environment)")
+
+	sinkDeadCmd.Flags().BoolVar(&sinkDeadJSON, "json", false, "Output as JSON")
+
+	sinkCmd.AddCommand(sinkListCmd, sinkAddCmd, sinkRemoveCmd, sinkTestCmd, sinkDeadCmd, sinkReplayCmd)
+	rootCmd.AddCommand(sinkCmd)
+}
+
+// loadSinksConfig returns the town root and its sinks config.
+func loadSinksConfig() (string, *config.SinksConfig, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, err
+	}
+	cfg, err := config.LoadOrCreateSinksConfig(config.SinksConfigPath(townRoot))
+	if err != nil {
+		return "", nil, err
+	}
+	return townRoot, cfg, nil
+}
+
+func findSink(cfg *config.SinksConfig, name string) *config.SinkConfig {
+	for i := range cfg.Sinks {
+		if cfg.Sinks[i].Name == name {
+			return &cfg.Sinks[i]
+		}
+	}
+	return nil
+}
+
+func runSinkList(cmd *cobra.Command, args []string) error {
+	_, cfg, err := loadSinksConfig()
+	if err != nil {
+		return err
+	}
+
+	if sinkListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(cfg.Sinks)
+	}
+
+	if len(cfg.Sinks) == 0 {
+		fmt.Println("No sinks configured.")
+		fmt.Println("Add one with: gt sink add <name> --webhook <url> --events merge_failed,mass_death")
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Notification Sinks"))
+	for _, s := range cfg.Sinks {
+		status := "●"
+		if !s.IsEnabled() {
+			status = style.Dim.Render("○ (disabled)")
+		}
+		target := s.URL + s.Command + s.Path
+		fmt.Printf("  %s %s  %s %s\n", status, style.Bold.Render(s.Name), s.Kind, style.Dim.Render(target))
+		eventsDesc := "all events"
+		if len(s.Events) > 0 {
+			eventsDesc = strings.Join(s.Events, ", ")
+		}
+		if s.Rig != "" {
+			eventsDesc += " (rig " + s.Rig + ")"
+		}
+		fmt.Printf("      events: %s\n", eventsDesc)
+		if s.Kind == config.SinkKindWebhook {
+			signed := "unsigned"
+			if s.GetSecret() != "" {
+				signed = "signed"
+			} else if s.SecretEnv != "" {
+				signed = "unsigned ($" + s.SecretEnv + " not set)"
+			}
+			fmt.Printf("      %s\n", signed)
+		}
+	}
+	return nil
+}
+
+func runSinkAdd(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadSinksConfig()
+	if err != nil {
+		return err
+	}
+	name := args[0]
+	if findSink(cfg, name) != nil {
+		return fmt.Errorf("sink %q already exists (remove it first)", name)
+	}
+
+	sc := config.SinkConfig{
+		Name:      name,
+		Events:    sinkAddEvents,
+		Rig:       sinkAddRig,
+		SecretEnv: sinkAddSecEnv,
+	}
+	set := 0
+	if sinkAddWebhook != "" {
+		sc.Kind, sc.URL = config.SinkKindWebhook, sinkAddWebhook
+		set++
+	}
+	if sinkAddCommand != "" {
+		sc.Kind, sc.Command = config.SinkKindCommand, sinkAddCommand
+		set++
+	}
+	if sinkAddPipe != "" {
+		sc.Kind, sc.Path = config.SinkKindPipe, sinkAddPipe
+		set++
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of --webhook, --command or --pipe is required")
+	}
+	if sc.SecretEnv != "" && sc.Kind != config.SinkKindWebhook {
+		return fmt.Errorf("--secret-env only applies to webhooks")
+	}
+
+	cfg.Sinks = append(cfg.Sinks, sc)
+	if err := config.SaveSinksConfig(config.SinksConfigPath(townRoot), cfg); err != nil {
+		return err
+	}
+	fmt.Printf("%s Added %s sink %s\n", style.Bold.Render("✓"), sc.Kind, name)
+	fmt.Printf("  Try it: gt sink test %s\n", name)
+	return nil
+}
+
+func runSinkRemove(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadSinksConfig()
+	if err != nil {
+		return err
+	}
+	var kept []config.SinkConfig
+	for _, s := range cfg.Sinks {
+		if s.Name != args[0] {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) == len(cfg.Sinks) {
+		return fmt.Errorf("no sink named %q", args[0])
+	}
+	cfg.Sinks = kept
+	if err := config.SaveSinksConfig(config.SinksConfigPath(townRoot), cfg); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed sink %s\n", style.Bold.Render("✓"), args[0])
+	return nil
+}
+
+func runSinkTest(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadSinksConfig()
+	if err != nil {
+		return err
+	}
+	sc := findSink(cfg, args[0])
+	if sc == nil {
+		return fmt.Errorf("no sink named %q", args[0])
+	}
+
+	town, _ := workspace.GetTownName(townRoot)
+	e := events.Event{
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Source:     "gt",
+		Type:       sink.TypeTest,
+		Actor:      detectActor(),
+		Payload:    map[string]interface{}{"message": "Test event from gt sink test"},
+		Visibility: events.VisibilityAudit,
+	}
+	if err := sink.Deliver(context.Background(), sc, sink.NewMessage(sc.Name, town, e)); err != nil {
+		return fmt.Errorf("sink %s: %w", sc.Name, err)
+	}
+	fmt.Printf("%s Delivered test event to %s\n", style.Bold.Render("✓"), sc.Name)
+	return nil
+}
+
+func runSinkDead(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	letters, err := sink.ReadDeadLetters(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading dead letters: %w", err)
+	}
+
+	if sinkDeadJSON {
+		if letters == nil {
+			letters = []sink.DeadLetter{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(letters)
+	}
+	if len(letters) == 0 {
+		fmt.Println("No dead-lettered deliveries.")
+		return nil
+	}
+	fmt.Printf("%s (%d)\n\n", style.Bold.Render("Dead Letters"), len(letters))
+	for _, dl := range letters {
+		fmt.Printf("  %s %s → %s  %s\n", style.Dim.Render(dl.FailedAt.Local().Format("2006-01-02 15:04")),
+			dl.Message.Event.Type, dl.Sink, style.Dim.Render(fmt.Sprintf("(%d attempts)", dl.Attempts)))
+		fmt.Printf("      %s\n", dl.Error)
+	}
+	fmt.Printf("\nRetry with: gt sink replay [name]\n")
+	return nil
+}
+
+func runSinkReplay(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadSinksConfig()
+	if err != nil {
+		return err
+	}
+	only := ""
+	if len(args) > 0 {
+		only = args[0]
+	}
+	result, err := sink.Replay(context.Background(), townRoot, cfg, only)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Replayed dead letters: %d delivered, %d still failing", style.Bold.Render("✓"), result.Delivered, result.Failed)
+	if result.Skipped > 0 {
+		fmt.Printf(", %d skipped", result.Skipped)
+	}
+	fmt.Println()
+	return nil
+}
@@ -7,11 +7,13 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/federation"
 	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
@@ -94,6 +96,16 @@ Compare:
 
 The propulsion principle: if it's on your hook, YOU RUN IT.
 
+Time Windows (deferred dispatch only):
+  gt sling gt-abc gastown --not-before 22:00          # Hold until off-hours
+  gt sling gt-abc gastown --deadline "2026-03-01 09:00"  # Escalate if still queued
+  gt sling mol-security-audit --on gt-audit gastown --every @nightly
+                                                    # Re-enqueue after each run
+
+  --every takes a 5-field cron rule ("0 2 * * *"), @hourly/@daily/@nightly/
+  @weekly/@monthly, or "@every <duration>". Each run gets a fresh copy of the
+  bead; a deadline keeps the same offset from not-before on every run.
+
 Batch Slinging:
   gt sling gt-abc gt-def gt-ghi gastown   # Sling multiple beads to a rig
   gt sling gt-abc gt-def gastown --max-concurrent 3  # Limit concurrent spawns
@@ -129,6 +141,11 @@ var (
 	slingBaseBranch    string // --base-branch: override base branch for polecat worktree
 	slingRalph         bool   // --ralph: enable Ralph Wiggum loop mode for multi-step workflows
 	slingFormula       string // --formula: override formula for dispatch (default: mol-polecat-work)
+
+	// Time-window flags (deferred dispatch only)
+	slingNotBefore string // --not-before: hold scheduled work until this time
+	slingDeadline  string // --deadline: escalate if still queued after this time
+	slingEvery     string // --every: recurrence rule (cron or @daily/@every 6h)
 )
 
 func init() {
@@ -155,6 +172,9 @@ func init() {
 	slingCmd.Flags().StringVar(&slingBaseBranch, "base-branch", "", "Override base branch for polecat worktree (e.g., 'develop', 'release/v2')")
 	slingCmd.Flags().BoolVar(&slingRalph, "ralph", false, "Enable Ralph Wiggum loop mode (fresh context per step, for multi-step workflows)")
 	slingCmd.Flags().StringVar(&slingFormula, "formula", "", "Formula to apply (default: mol-polecat-work for polecat targets)")
+	slingCmd.Flags().StringVar(&slingNotBefore, "not-before", "", "Hold scheduled work until this time (RFC3339, \"YYYY-MM-DD HH:MM\", \"HH:MM\", or +duration)")
+	slingCmd.Flags().StringVar(&slingDeadline, "deadline", "", "Escalate if scheduled work is still queued after this time (same formats as --not-before)")
+	slingCmd.Flags().StringVar(&slingEvery, "every", "", "Re-enqueue after each dispatch on this schedule (cron \"0 2 * * *\", @daily, @nightly, \"@every 6h\")")
 
 	slingCmd.AddCommand(slingRespawnResetCmd)
 	rootCmd.AddCommand(slingCmd)
@@ -269,6 +289,34 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		args[i] = strings.TrimRight(args[i], "/")
 	}
 
+	// Resolve hop:// references to town-level mirror beads so the rest of
+	// sling treats remote work like any local bead.
+	if slices.ContainsFunc(args, federation.IsHopURI) || federation.IsHopURI(slingOnTarget) {
+		var resolved []string
+		if slingDryRun {
+			// Don't create mirrors in a dry run. Existing mirrors stand in
+			// for the remote bead; without one there is nothing to inspect.
+			var missing []string
+			resolved, missing, err = lookupHopMirrors(append(slices.Clone(args), slingOnTarget))
+			if err != nil {
+				return err
+			}
+			if len(missing) > 0 {
+				for _, ref := range missing {
+					fmt.Printf("Would mirror %s into town beads\n", ref)
+				}
+				fmt.Printf("Would sling the new mirror(s) once created\n")
+				return nil
+			}
+		} else {
+			resolved, err = resolveHopRefs(append(slices.Clone(args), slingOnTarget))
+			if err != nil {
+				return err
+			}
+		}
+		args, slingOnTarget = resolved[:len(args)], resolved[len(args)]
+	}
+
 	// Validate target format early, before any dispatch path (bead, formula, batch)
 	// can trigger resolveTarget side-effects like polecat spawning.
 	if len(args) > 1 {
@@ -282,6 +330,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 	if deferErr != nil {
 		return deferErr
 	}
+	if err := validateSlingScheduleFlags(deferred); err != nil {
+		return err
+	}
 
 	// Batch mode detection: multiple beads with optional rig target
 	// Pattern A (explicit rig):  gt sling gt-abc gt-def gt-ghi gastown
@@ -343,6 +394,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 				Agent:       slingAgent,
 				HookRawBead: slingHookRawBead,
 				Ralph:       slingRalph,
+				NotBefore:   slingNotBefore,
+				Deadline:    slingDeadline,
+				Every:       slingEvery,
 			})
 		}
 	}
@@ -382,6 +436,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 			Agent:       slingAgent,
 			HookRawBead: slingHookRawBead,
 			Ralph:       slingRalph,
+			NotBefore:   slingNotBefore,
+			Deadline:    slingDeadline,
+			Every:       slingEvery,
 		})
 	}
 
@@ -418,6 +475,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 				Agent:       slingAgent,
 				HookRawBead: slingHookRawBead,
 				Ralph:       slingRalph,
+				NotBefore:   slingNotBefore,
+				Deadline:    slingDeadline,
+				Every:       slingEvery,
 			})
 		}
 		// Non-rig target in deferred mode — reject to prevent bypassing capacity control
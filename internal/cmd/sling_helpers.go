@@ -326,6 +326,11 @@ func storeFieldsInBead(beadID string, updates beadFieldUpdates) error {
 	if updates.ConvoyOwned {
 		fields.ConvoyOwned = true
 	}
+	// Link the work to this sling's trace, so gt done and the Refinery
+	// can continue it.
+	if tp := telemetry.TraceParent(context.Background()); tp != "" {
+		fields.TraceParent = tp
+	}
 
 	// Write back once
 	newDesc := beads.SetAttachmentFields(issue, fields)
@@ -990,19 +995,25 @@ func resolveFormulaToTempFile(formulaName string) (resolved string, cleanup func
 // isHookedAgentDeadFn is a seam for tests. Production uses isHookedAgentDead.
 var isHookedAgentDeadFn = isHookedAgentDead
 
-// isHookedAgentDead checks if the tmux session for a hooked assignee is dead.
+// isHookedAgentDead checks if the session for a hooked assignee is dead.
+// Polecat sessions are looked up in the town's polecat session backend.
 // Used by sling to auto-force re-sling when the previous agent has no active session (gt-pqf9x).
 // Returns true if the session is confirmed dead. Returns false if alive or if we
 // can't determine liveness (conservative: don't auto-force on uncertainty).
 func isHookedAgentDead(assignee string) bool {
-	sessionName, _ := assigneeToSessionName(assignee)
+	sessionName, isPersistent := assigneeToSessionName(assignee)
 	if sessionName == "" {
 		return false // Unknown format, can't determine
 	}
 	t := tmux.NewTmux()
-	alive, err := t.HasSession(sessionName)
+	var backend session.SessionBackend = t
+	if !isPersistent {
+		townRoot, _ := workspace.FindFromCwd()
+		backend = session.PolecatBackend(townRoot, t)
+	}
+	alive, err := backend.HasSession(sessionName)
 	if err != nil {
-		return false // tmux not available or error, be conservative
+		return false // backend not available or error, be conservative
 	}
 	return !alive
 }
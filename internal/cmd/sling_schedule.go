@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/scheduler/capacity"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -59,6 +61,9 @@ type ScheduleOptions struct {
 	Agent       string   // Agent override (e.g., "gemini", "codex")
 	HookRawBead bool     // Hook raw bead without default formula
 	Ralph       bool     // Ralph Wiggum loop mode
+	NotBefore   string   // Hold until this time (see capacity.ParseScheduleTime)
+	Deadline    string   // Escalate if still queued after this time
+	Every       string   // Recurrence rule (see capacity.ParseRecurrence)
 }
 
 // scheduleBead schedules a bead for deferred dispatch via the capacity scheduler.
@@ -111,8 +116,23 @@ func scheduleBead(beadID, rigName string, opts ScheduleOptions) error {
 		}
 	}
 
+	// Resolve the time window up front so bad flags fail before side effects.
+	window := &capacity.SlingContextFields{}
+	if err := applyScheduleWindow(window, opts.NotBefore, opts.Deadline, opts.Every, time.Now()); err != nil {
+		return err
+	}
+
 	if opts.DryRun {
 		fmt.Printf("Would schedule %s → %s\n", beadID, rigName)
+		if window.NotBefore != "" {
+			fmt.Printf("  Not before: %s\n", window.NotBefore)
+		}
+		if window.Deadline != "" {
+			fmt.Printf("  Deadline: %s\n", window.Deadline)
+		}
+		if window.Recurrence != "" {
+			fmt.Printf("  Repeats: %s\n", window.Recurrence)
+		}
 		fmt.Printf("  Would create sling context bead\n")
 		if !opts.NoConvoy {
 			fmt.Printf("  Would create auto-convoy\n")
@@ -134,6 +154,8 @@ func scheduleBead(beadID, rigName string, opts ScheduleOptions) error {
 		WorkBeadID: beadID,
 		TargetRig:  rigName,
 		EnqueuedAt: time.Now().UTC().Format(time.RFC3339),
+		// Dispatch continues this sling's trace (see dispatchSingleBead).
+		TraceParent: telemetry.TraceParent(context.Background()),
 	}
 	if opts.Formula != "" {
 		fields.Formula = opts.Formula
@@ -162,6 +184,9 @@ func scheduleBead(beadID, rigName string, opts ScheduleOptions) error {
 		fields.Mode = "ralph"
 	}
 	fields.Owned = opts.Owned
+	fields.NotBefore = window.NotBefore
+	fields.Deadline = window.Deadline
+	fields.Recurrence = window.Recurrence
 
 	// Create sling context bead — single atomic operation. No two-step write.
 	ctxBead, err := townBeads.CreateSlingContext(info.Title, beadID, fields)
@@ -193,6 +218,15 @@ func scheduleBead(beadID, rigName string, opts ScheduleOptions) error {
 	_ = events.LogFeed(events.TypeSchedulerEnqueue, actor, events.SchedulerEnqueuePayload(beadID, rigName))
 
 	fmt.Printf("%s Scheduled %s → %s (context: %s)\n", style.Bold.Render("✓"), beadID, rigName, ctxBead.ID)
+	if fields.NotBefore != "" {
+		fmt.Printf("  Not before: %s\n", fields.NotBefore)
+	}
+	if fields.Deadline != "" {
+		fmt.Printf("  Deadline: %s\n", fields.Deadline)
+	}
+	if fields.Recurrence != "" {
+		fmt.Printf("  Repeats: %s\n", fields.Recurrence)
+	}
 	return nil
 }
 
@@ -227,6 +261,9 @@ func runBatchSchedule(beadIDs []string, rigName string) error {
 			Agent:       slingAgent,
 			HookRawBead: slingHookRawBead,
 			Ralph:       slingRalph,
+			NotBefore:   slingNotBefore,
+			Deadline:    slingDeadline,
+			Every:       slingEvery,
 		})
 		if err != nil {
 			fmt.Printf("  %s %s: %v\n", style.Dim.Render("✗"), beadID, err)
@@ -357,6 +394,7 @@ func detectSchedulerIDType(id string) (string, error) {
 var schedulerTaskOnlyFlagNames = []string{
 	"account", "agent", "ralph", "args", "var",
 	"merge", "base-branch", "no-convoy", "owned", "no-merge",
+	"not-before", "deadline", "every",
 }
 
 // validateNoTaskOnlySchedulerFlags checks that no task-only flags were set.
@@ -27,6 +27,22 @@ func resolveTargetAgent(target string) (agentID string, pane string, hookRoot st
 	// Convert session name to agent ID format (this doesn't require tmux)
 	agentID = sessionToAgentID(sessionName)
 
+	// Headless polecats have no pane: sling skips the pane nudge and the
+	// agent picks up its work via gt prime.
+	if identity, err := session.ParseSessionName(sessionName); err == nil && identity.Role == session.RolePolecat {
+		townRoot, _ := workspace.FindFromCwd()
+		if backend := session.PolecatBackend(townRoot, tmux.NewTmux()); session.AsTmux(backend) == nil {
+			if alive, err := backend.HasSession(sessionName); err != nil || !alive {
+				return "", "", "", fmt.Errorf("session %s not found", sessionName)
+			}
+			hookRoot, _ = backend.GetEnvironment(sessionName, "GT_POLECAT_PATH")
+			if hookRoot == "" {
+				return "", "", "", fmt.Errorf("getting working dir for %s: GT_POLECAT_PATH not set", sessionName)
+			}
+			return agentID, "", hookRoot, nil
+		}
+	}
+
 	// Get the pane for that session
 	pane, err = getSessionPane(sessionName)
 	if err != nil {
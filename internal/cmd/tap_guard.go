@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/guard"
 )
 
 var tapGuardCmd = &cobra.Command{
@@ -21,6 +22,11 @@ forbidden operation entirely.
 Available guards:
   pr-workflow        - Block PR creation and feature branches
   dangerous-command  - Block rm -rf, force push, hard reset, git clean
+  policy             - Check tool calls against the layered guard policy
+
+The guards share one declarative policy: built-in rules plus town, rig
+and role policy files (see gt tap guard policy --help). Every decision
+is recorded in the audit events log.
 
 Example hook configuration:
   {
@@ -52,7 +58,8 @@ The guard blocks in two scenarios:
   1. Running as a Gas Town agent (crew, polecat, witness, etc.)
   2. Origin remote is steveyegge/gastown (maintainer should push directly)
 
-Humans running outside Gas Town with a fork origin can still use PRs.`,
+Humans running outside Gas Town with a fork origin can still use PRs.
+A guard policy layer can also allow PRs by disabling the pr-workflow rule.`,
 	RunE: runTapGuardPRWorkflow,
 }
 
@@ -62,6 +69,24 @@ func init() {
 }
 
 func runTapGuardPRWorkflow(cmd *cobra.Command, args []string) error {
+	// Consult the guard policy when called as a hook: a policy layer that
+	// disables or overrides the pr-workflow rule allows PRs.
+	in, hasInput := parseGuardInput(readHookInput())
+	if hasInput {
+		if policy, vars, err := loadGuardPolicy(); err == nil {
+			if d := policy.Evaluate(in, vars); !d.Blocked() {
+				recordGuardDecision("pr-workflow", in, d)
+				return nil
+			}
+		}
+	}
+	block := func() error {
+		if hasInput {
+			recordGuardDecision("pr-workflow", in, guard.Decision{Action: guard.ActionBlock, Rule: "pr-workflow", Layer: guard.LayerBuiltin})
+		}
+		return NewSilentExit(2) // Exit 2 = BLOCK in Claude Code hooks
+	}
+
 	// Check if we're in a Gas Town agent context
 	if isGasTownAgentContext() {
 		fmt.Fprintln(os.Stderr, "")
@@ -77,7 +102,7 @@ func runTapGuardPRWorkflow(cmd *cobra.Command, args []string) error {
 		fmt.Fprintln(os.Stderr, "║  See: ~/gt/docs/PRIMING.md (GUPP principle)                     ║")
 		fmt.Fprintln(os.Stderr, "╚══════════════════════════════════════════════════════════════════╝")
 		fmt.Fprintln(os.Stderr, "")
-		return NewSilentExit(2) // Exit 2 = BLOCK in Claude Code hooks
+		return block()
 	}
 
 	// Check if origin is the maintainer's repo (steveyegge/gastown)
@@ -93,10 +118,13 @@ func runTapGuardPRWorkflow(cmd *cobra.Command, args []string) error {
 		fmt.Fprintln(os.Stderr, "║  Do this:     git push origin main                              ║")
 		fmt.Fprintln(os.Stderr, "╚══════════════════════════════════════════════════════════════════╝")
 		fmt.Fprintln(os.Stderr, "")
-		return NewSilentExit(2) // Exit 2 = BLOCK in Claude Code hooks
+		return block()
 	}
 
 	// Not in Gas Town context and not maintainer origin - allow PRs
+	if hasInput {
+		recordGuardDecision("pr-workflow", in, guard.Decision{Action: guard.ActionAllow})
+	}
 	return nil
 }
 
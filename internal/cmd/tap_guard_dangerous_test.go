@@ -1,48 +1,70 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/guard"
 )
 
-func TestExtractCommand(t *testing.T) {
+func TestParseGuardInput(t *testing.T) {
 	tests := []struct {
-		name  string
-		input string
-		want  string
+		name        string
+		input       string
+		wantOK      bool
+		wantTool    string
+		wantCommand string
+		wantPath    string
 	}{
 		{
-			name:  "valid hook input",
-			input: `{"tool_name":"Bash","tool_input":{"command":"rm -rf /tmp/foo"}}`,
-			want:  "rm -rf /tmp/foo",
+			name:        "valid hook input",
+			input:       `{"tool_name":"Bash","tool_input":{"command":"rm -rf /tmp/foo"}}`,
+			wantOK:      true,
+			wantTool:    "Bash",
+			wantCommand: "rm -rf /tmp/foo",
 		},
 		{
 			name:  "empty input",
 			input: "",
-			want:  "",
 		},
 		{
 			name:  "invalid json",
 			input: "not json",
-			want:  "",
 		},
 		{
-			name:  "no command field",
-			input: `{"tool_name":"Write","tool_input":{"file_path":"/tmp/foo"}}`,
-			want:  "",
+			name:     "file tool",
+			input:    `{"tool_name":"Write","tool_input":{"file_path":"/tmp/foo"}}`,
+			wantOK:   true,
+			wantTool: "Write",
+			wantPath: "/tmp/foo",
+		},
+		{
+			name:  "nothing to check",
+			input: `{"tool_name":"Task","tool_input":{"prompt":"hi"}}`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := extractCommand([]byte(tt.input))
-			if got != tt.want {
-				t.Errorf("extractCommand() = %q, want %q", got, tt.want)
+			in, ok := parseGuardInput([]byte(tt.input))
+			if ok != tt.wantOK {
+				t.Fatalf("parseGuardInput() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if in.Tool != tt.wantTool || in.Command != tt.wantCommand {
+				t.Errorf("parseGuardInput() = %+v, want tool %q command %q", in, tt.wantTool, tt.wantCommand)
+			}
+			if tt.wantPath != "" && (len(in.Paths) != 1 || in.Paths[0] != tt.wantPath) {
+				t.Errorf("parseGuardInput() paths = %v, want [%s]", in.Paths, tt.wantPath)
 			}
 		})
 	}
 }
 
-func TestMatchesDangerous(t *testing.T) {
+func TestDangerousCommandPolicy(t *testing.T) {
 	tests := []struct {
 		name    string
 		command string
@@ -56,6 +78,11 @@ func TestMatchesDangerous(t *testing.T) {
 		{"git reset hard", "git reset --hard HEAD~1", true},
 		{"git clean f", "git clean -f", true},
 		{"git clean fd", "git clean -fd", true},
+		{"rm split flags", "rm -r -f /var/lib", true},
+		{"chained", "cd repo && git push --force", true},
+		{"force with lease", "git push --force-with-lease", true},
+		{"sudo", "sudo rm -rf /opt/app", true},
+		{"sh -c", `sh -c "git reset --hard"`, true},
 
 		// Should allow
 		{"rm single file", "rm foo.txt", false},
@@ -65,20 +92,38 @@ func TestMatchesDangerous(t *testing.T) {
 		{"git status", "git status", false},
 		{"ls", "ls -la", false},
 		{"empty", "", false},
+		{"quoted mention", `echo "never git push --force"`, false},
 	}
 
+	policy := guard.DefaultPolicy()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			blocked := false
-			for _, pattern := range dangerousPatterns {
-				if matchesDangerous(tt.command, pattern) {
-					blocked = true
-					break
-				}
-			}
-			if blocked != tt.want {
-				t.Errorf("matchesDangerous(%q) = %v, want %v", tt.command, blocked, tt.want)
+			d := policy.Evaluate(guard.Input{Tool: "Bash", Command: tt.command, Cwd: "/work"}, guard.Vars{})
+			if d.Blocked() != tt.want {
+				t.Errorf("Evaluate(%q) blocked = %v (rule %q), want %v", tt.command, d.Blocked(), d.Rule, tt.want)
 			}
 		})
 	}
 }
+
+func TestRunTapGuardPolicy_MalformedPolicyBlocks(t *testing.T) {
+	townRoot := t.TempDir()
+	for path, content := range map[string]string{
+		filepath.Join(townRoot, "mayor", "town.json"):            `{"type":"town","version":1,"name":"test"}`,
+		filepath.Join(townRoot, "settings", "guard-policy.json"): `{"rules": [`,
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(townRoot)
+	t.Setenv("GT_ROLE", "")
+
+	err := runTapGuardPolicy(tapGuardPolicyCmd, nil)
+	if code, ok := IsSilentExit(err); !ok || code != 2 {
+		t.Errorf("runTapGuardPolicy with malformed policy = %v, want exit 2", err)
+	}
+}
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/guard"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	tapGuardPolicyPrint bool
+	tapGuardPolicyCheck string
+)
+
+var tapGuardPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Check tool calls against the guard policy",
+	Long: `Check tool calls against the declarative guard policy.
+
+The policy is a list of rules matching parsed shell commands, git
+operations, network tools and file paths. Each rule blocks the call,
+allows it, or logs the match and lets later rules decide. Every decision
+is recorded in the audit events log (guard_decision).
+
+Policies are layered like hooks, most specific first:
+  <rig>/settings/guard-policy/<role>.json   Rig + role
+  <rig>/settings/guard-policy.json          Rig
+  settings/guard-policy/<role>.json         Town + role
+  settings/guard-policy.json                Town
+  built-in defaults                         Dangerous commands, PR workflow,
+                                            polecat worktree restriction
+
+A rule replaces the rule of the same name in less specific layers;
+"disabled": true removes it.
+
+Example policy file:
+  {
+    "rules": [
+      {"name": "no-network", "action": "block", "reason": "No network access",
+       "match": {"network": true}},
+      {"name": "secrets", "action": "block",
+       "match": {"paths": ["{home}/.ssh", "{home}/.aws"]}},
+      {"name": "force-push", "disabled": true}
+    ]
+  }
+
+The guard reads the tool call from stdin (Claude Code hook protocol) and
+exits with code 2 to block it. A policy file that can't be read or parsed
+blocks every call until it is fixed.
+
+Exit codes:
+  0 - Operation allowed
+  2 - Operation BLOCKED (or policy file invalid)
+
+Examples:
+  gt tap guard policy --print               # Show the merged policy
+  gt tap guard policy --check "rm -rf /"    # Test a command`,
+	RunE: runTapGuardPolicy,
+}
+
+func init() {
+	tapGuardCmd.AddCommand(tapGuardPolicyCmd)
+	tapGuardPolicyCmd.Flags().BoolVar(&tapGuardPolicyPrint, "print", false, "Print the merged policy for this agent")
+	tapGuardPolicyCmd.Flags().StringVar(&tapGuardPolicyCheck, "check", "", "Evaluate a shell command without recording the decision")
+}
+
+func runTapGuardPolicy(cmd *cobra.Command, args []string) error {
+	policy, vars, err := loadGuardPolicy()
+	if err != nil {
+		// Fail closed: exit 1 would let Claude Code run the call unchecked.
+		fmt.Fprintf(os.Stderr, "Error: %v\nBlocking until the policy file is fixed.\n", err)
+		return NewSilentExit(2) // Exit 2 = BLOCK
+	}
+
+	if tapGuardPolicyPrint {
+		return printGuardPolicy(policy)
+	}
+
+	if tapGuardPolicyCheck != "" {
+		cwd, _ := os.Getwd()
+		d := policy.Evaluate(guard.Input{Tool: "Bash", Command: tapGuardPolicyCheck, Cwd: cwd}, vars)
+		printGuardDecision(d)
+		if d.Blocked() {
+			return NewSilentExit(2)
+		}
+		return nil
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		// Can't read stdin — allow operation (fail open for non-hook usage)
+		return nil
+	}
+	in, ok := parseGuardInput(data)
+	if !ok {
+		return nil
+	}
+
+	d := policy.Evaluate(in, vars)
+	recordGuardDecision("policy", in, d)
+	if d.Blocked() {
+		printGuardBlock("POLICY BLOCKED", in, d)
+		return NewSilentExit(2) // Exit 2 = BLOCK
+	}
+	return nil
+}
+
+// loadGuardPolicy loads the guard policy for the current agent, with the
+// path variables for its rules. Outside a town only built-in rules apply.
+func loadGuardPolicy() (*guard.Policy, guard.Vars, error) {
+	vars := guard.Vars{Tmp: os.TempDir()}
+	if home, err := os.UserHomeDir(); err == nil {
+		vars.Home = home
+	}
+
+	townRoot, _ := workspace.FindFromCwd()
+	if townRoot == "" {
+		vars.Worktree = gitToplevel()
+		return guard.DefaultPolicy(), vars, nil
+	}
+	vars.Town = townRoot
+
+	var rig, role string
+	if info, err := GetRole(); err == nil {
+		rig, role = info.Rig, string(info.Role)
+		switch info.Role {
+		case RolePolecat, RoleCrew:
+			vars.Worktree = info.Home
+		}
+	}
+	if vars.Worktree == "" {
+		vars.Worktree = gitToplevel()
+	}
+	if rig != "" {
+		vars.Rig = filepath.Join(townRoot, rig)
+	}
+
+	policy, err := guard.Load(townRoot, rig, role)
+	if err != nil {
+		return nil, vars, fmt.Errorf("loading guard policy: %w", err)
+	}
+	return policy, vars, nil
+}
+
+// gitToplevel returns the root of the git worktree containing the current
+// directory, or "" if there is none.
+func gitToplevel() string {
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// parseGuardInput extracts the tool call from Claude Code hook input JSON:
+// {"tool_name": "...", "tool_input": {...}, "cwd": "..."}.
+func parseGuardInput(data []byte) (guard.Input, bool) {
+	var hookInput struct {
+		ToolName  string `json:"tool_name"`
+		Cwd       string `json:"cwd"`
+		ToolInput struct {
+			Command      string `json:"command"`
+			FilePath     string `json:"file_path"`
+			Path         string `json:"path"`
+			NotebookPath string `json:"notebook_path"`
+		} `json:"tool_input"`
+	}
+	if len(data) == 0 || json.Unmarshal(data, &hookInput) != nil {
+		return guard.Input{}, false
+	}
+
+	in := guard.Input{
+		Tool:    hookInput.ToolName,
+		Command: hookInput.ToolInput.Command,
+		Cwd:     hookInput.Cwd,
+	}
+	if in.Tool == "" && in.Command != "" {
+		in.Tool = "Bash"
+	}
+	for _, p := range []string{hookInput.ToolInput.FilePath, hookInput.ToolInput.Path, hookInput.ToolInput.NotebookPath} {
+		if p != "" {
+			in.Paths = append(in.Paths, p)
+		}
+	}
+	if in.Cwd == "" {
+		in.Cwd, _ = os.Getwd()
+	}
+	if in.Command == "" && len(in.Paths) == 0 {
+		return guard.Input{}, false
+	}
+	return in, true
+}
+
+// recordGuardDecision writes a guard decision to the audit events log.
+func recordGuardDecision(guardName string, in guard.Input, d guard.Decision) {
+	command, path := d.Command, d.Path
+	if command == "" {
+		command = in.Command
+	}
+	if path == "" && len(in.Paths) > 0 {
+		path = in.Paths[0]
+	}
+	_ = events.LogAudit(events.TypeGuardDecision, detectSender(),
+		events.GuardDecisionPayload(guardName, in.Tool, d.Action, d.Rule, d.Layer, command, path, d.Logged))
+}
+
+// printGuardBlock prints the block banner for a policy decision.
+func printGuardBlock(title string, in guard.Input, d guard.Decision) {
+	subject := d.Command
+	if subject == "" {
+		subject = in.Command
+	}
+	if d.Path != "" {
+		subject = d.Path
+	}
+	reason := d.Reason
+	if reason == "" {
+		reason = "Blocked by guard policy"
+	}
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "╔══════════════════════════════════════════════════════════════════╗")
+	fmt.Fprintf(os.Stderr, "║  ❌ %-60s ║\n", title)
+	fmt.Fprintln(os.Stderr, "╠══════════════════════════════════════════════════════════════════╣")
+	if d.Path != "" {
+		fmt.Fprintf(os.Stderr, "║  Path:    %-53s ║\n", truncateStr(subject, 53))
+	} else {
+		fmt.Fprintf(os.Stderr, "║  Command: %-53s ║\n", truncateStr(subject, 53))
+	}
+	fmt.Fprintf(os.Stderr, "║  Reason:  %-53s ║\n", truncateStr(reason, 53))
+	fmt.Fprintf(os.Stderr, "║  Rule:    %-53s ║\n", truncateStr(d.Rule+" ("+d.Layer+")", 53))
+	fmt.Fprintln(os.Stderr, "║                                                                  ║")
+	fmt.Fprintln(os.Stderr, "║  If this is intentional, ask the user to run it manually.        ║")
+	fmt.Fprintln(os.Stderr, "╚══════════════════════════════════════════════════════════════════╝")
+	fmt.Fprintln(os.Stderr, "")
+}
+
+func printGuardDecision(d guard.Decision) {
+	if d.Rule == "" {
+		fmt.Printf("%s (no rule matched)\n", d.Action)
+	} else {
+		fmt.Printf("%s by %s (%s)\n", d.Action, d.Rule, d.Layer)
+	}
+	if d.Reason != "" {
+		fmt.Printf("  reason:  %s\n", d.Reason)
+	}
+	if d.Command != "" {
+		fmt.Printf("  command: %s\n", d.Command)
+	}
+	if d.Path != "" {
+		fmt.Printf("  path:    %s\n", d.Path)
+	}
+	if len(d.Logged) > 0 {
+		fmt.Printf("  logged:  %s\n", strings.Join(d.Logged, ", "))
+	}
+}
+
+func printGuardPolicy(policy *guard.Policy) error {
+	type ruleView struct {
+		guard.Rule
+		Layer string `json:"layer"`
+	}
+	views := make([]ruleView, 0, len(policy.Rules))
+	for _, r := range policy.Rules {
+		views = append(views, ruleView{Rule: r, Layer: r.Layer})
+	}
+	data, err := json.MarshalIndent(map[string]interface{}{"rules": views}, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
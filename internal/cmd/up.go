@@ -26,6 +26,7 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -433,6 +434,8 @@ func ensureDaemon(townRoot string) error {
 
 	cmd := exec.Command(gtPath, "daemon", "run")
 	cmd.Dir = townRoot
+	// The daemon outlives this command; don't parent its traces to our span.
+	cmd.Env = telemetry.EnvWithoutTraceContext()
 	// Detach from parent I/O for background daemon (uses its own logging)
 	cmd.Stdin = nil
 	cmd.Stdout = nil
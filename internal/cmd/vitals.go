@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -35,6 +37,7 @@ func runVitals(cmd *cobra.Command, args []string) error {
 	printVitalsDatabases(townRoot)
 	fmt.Println()
 	printVitalsBackups(townRoot)
+	printVitalsBudgets(townRoot)
 	return nil
 }
 
@@ -218,6 +221,38 @@ func printVitalsBackups(townRoot string) {
 	fmt.Println()
 }
 
+// printVitalsBudgets shows spend against each configured budget in its
+// current period. Prints nothing when no budget is configured.
+func printVitalsBudgets(townRoot string) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || !settings.Budgets.Enabled() {
+		return
+	}
+	fmt.Println()
+	fmt.Printf("%s (action: %s)\n", style.Bold.Render("Budgets"), settings.Budgets.GetAction())
+
+	state, err := budget.LoadState(townRoot)
+	if err != nil {
+		fmt.Printf("  %s\n", style.Dim.Render("state unreadable: "+err.Error()))
+		return
+	}
+	for _, st := range state.Statuses(settings.Budgets, time.Now()) {
+		marker := style.Success.Render("●")
+		switch st.Level {
+		case budget.LevelWarn:
+			marker = style.Warning.Render("●")
+		case budget.LevelExceeded:
+			marker = style.Error.Render("●")
+		}
+		period := st.Period
+		if period == "" {
+			period = "total"
+		}
+		fmt.Printf("  %s %-20s %-10s $%8.2f / $%8.2f  %3.0f%%\n",
+			marker, st.Name(), period, st.SpentUSD, st.LimitUSD, st.Percent())
+	}
+}
+
 func vitalsFormatCount(n int) string {
 	if n < 1000 {
 		return fmt.Sprintf("%d", n)
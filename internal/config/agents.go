@@ -132,6 +132,13 @@ type AgentPresetInfo struct {
 	// EmitsPermissionWarning indicates the agent shows a bypass-permissions warning on startup
 	// that needs to be acknowledged via tmux.
 	EmitsPermissionWarning bool `json:"emits_permission_warning,omitempty"`
+
+	// UsageProvider is the session log format `gt costs` reads token usage from
+	// (e.g., "claude", "codex"). Custom agents that wrap a built-in runtime can
+	// reuse its format. Empty means spend is not tracked for this agent: the
+	// cursor, auggie, amp and copilot presets keep no local usage log that can
+	// be read, so `gt costs` reports their sessions as not metered.
+	UsageProvider string `json:"usage_provider,omitempty"`
 }
 
 // NonInteractiveConfig contains settings for running agents non-interactively.
@@ -185,6 +192,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		ReadyDelayMs:           10000,
 		InstructionsFile:       "CLAUDE.md",
 		EmitsPermissionWarning: true,
+		UsageProvider:          "claude",
 	},
 	AgentGemini: {
 		Name:                AgentGemini,
@@ -208,6 +216,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		HooksSettingsFile: "settings.json",
 		ReadyDelayMs:      5000,
 		InstructionsFile:  "AGENTS.md",
+		UsageProvider:     "gemini",
 	},
 	AgentCodex: {
 		Name:                AgentCodex,
@@ -227,6 +236,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		PromptMode:       "none",
 		ReadyDelayMs:     3000,
 		InstructionsFile: "AGENTS.md",
+		UsageProvider:    "codex",
 	},
 	AgentCursor: {
 		Name:                AgentCursor,
@@ -300,6 +310,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		HooksSettingsFile: "gastown.js",
 		ReadyDelayMs:      8000,
 		InstructionsFile:  "AGENTS.md",
+		UsageProvider:     "opencode",
 	},
 	AgentCopilot: {
 		Name:                AgentCopilot,
@@ -346,6 +357,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		// receive tmux input. Without a readiness delay, the startup nudge
 		// arrives before the TUI is ready and gets dropped silently.
 		ReadyDelayMs: 8000,
+		UsageProvider: "pi",
 	},
 	AgentOmp: {
 		Name:                AgentOmp,
@@ -361,6 +373,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		NonInteractive: &NonInteractiveConfig{
 			PromptFlag: "--prompt",
 		},
+		UsageProvider: "omp",
 	},
 }
 
@@ -577,6 +590,35 @@ func GetSessionIDEnvVar(agentName string) string {
 	return info.SessionIDEnv
 }
 
+// GetUsageProvider returns the usage log format `gt costs` reads for an agent.
+// Custom agents that are not registry presets (e.g., a "claude-sonnet" alias in
+// settings/config.json) resolve through the built-in preset whose Command matches
+// command. An empty agentName means a session started without GT_AGENT, which
+// predates non-Claude runtimes, so it defaults to Claude.
+// Returns "" when the agent's spend cannot be tracked.
+func GetUsageProvider(agentName, command string) string {
+	if agentName == "" {
+		return string(AgentClaude)
+	}
+	if info := GetAgentPresetByName(agentName); info != nil {
+		return info.UsageProvider
+	}
+	if command == "" {
+		return ""
+	}
+
+	registryMu.Lock()
+	initRegistryLocked()
+	defer registryMu.Unlock()
+	cmdBase := filepath.Base(command)
+	for _, info := range globalRegistry.Agents {
+		if info.Command == command || filepath.Base(info.Command) == cmdBase {
+			return info.UsageProvider
+		}
+	}
+	return ""
+}
+
 // GetProcessNames returns the process names used to detect if an agent is running.
 // Used by tmux.IsAgentRunning to check pane_current_command.
 // Returns ["node"] for Claude (default) if agent is not found or has no ProcessNames.
@@ -219,6 +219,9 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if err := c.Sandbox.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -2328,6 +2331,9 @@ func LoadEscalationConfig(path string) (*EscalationConfig, error) {
 		return nil, fmt.Errorf("parsing escalation config: %w", err)
 	}
 
+	if err := rejectPlaintextDeliverySecrets(data); err != nil {
+		return nil, err
+	}
 	if err := validateEscalationConfig(&config); err != nil {
 		return nil, err
 	}
@@ -2362,7 +2368,7 @@ func SaveEscalationConfig(path string, config *EscalationConfig) error {
 		return fmt.Errorf("encoding escalation config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: escalation config doesn't contain secrets
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: credentials belong in password_env/token_env, not the file
 		return fmt.Errorf("writing escalation config: %w", err)
 	}
 
@@ -2402,6 +2408,68 @@ func validateEscalationConfig(c *EscalationConfig) error {
 		return fmt.Errorf("%w: max_reescalations must be non-negative", ErrMissingField)
 	}
 
+	if c.Delivery != nil {
+		if err := validateEscalationDelivery(c.Delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rejectPlaintextDeliverySecrets fails on the delivery.smtp.password and
+// delivery.sms.token fields older configs could set, rather than silently
+// dropping them: credentials are read only from password_env/token_env.
+func rejectPlaintextDeliverySecrets(data []byte) error {
+	var raw struct {
+		Delivery *struct {
+			SMTP *struct {
+				Password string `json:"password"`
+			} `json:"smtp"`
+			SMS *struct {
+				Token string `json:"token"`
+			} `json:"sms"`
+		} `json:"delivery"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil || raw.Delivery == nil {
+		return nil
+	}
+	if raw.Delivery.SMTP != nil && raw.Delivery.SMTP.Password != "" {
+		return fmt.Errorf("delivery.smtp.password is not supported: move the password to an environment variable and name it in delivery.smtp.password_env")
+	}
+	if raw.Delivery.SMS != nil && raw.Delivery.SMS.Token != "" {
+		return fmt.Errorf("delivery.sms.token is not supported: move the token to an environment variable and name it in delivery.sms.token_env")
+	}
+	return nil
+}
+
+// validateEscalationDelivery validates the external delivery settings.
+func validateEscalationDelivery(d *EscalationDelivery) error {
+	for name, v := range map[string]string{"backoff": d.Backoff, "timeout": d.Timeout, "deadline": d.Deadline} {
+		if v != "" {
+			if _, err := time.ParseDuration(v); err != nil {
+				return fmt.Errorf("invalid delivery.%s: %w", name, err)
+			}
+		}
+	}
+	if d.SMTP != nil {
+		if d.SMTP.Host == "" || d.SMTP.From == "" {
+			return fmt.Errorf("%w: delivery.smtp requires host and from", ErrMissingField)
+		}
+		switch d.SMTP.TLS {
+		case "", SMTPTLSStartTLS, SMTPTLSImplicit, SMTPTLSNone:
+		default:
+			return fmt.Errorf("invalid delivery.smtp.tls %q (valid: starttls, tls, none)", d.SMTP.TLS)
+		}
+	}
+	if d.SMS != nil {
+		if d.SMS.Provider != SMSProviderHTTP {
+			return fmt.Errorf("invalid delivery.sms.provider %q (valid: http)", d.SMS.Provider)
+		}
+		if d.SMS.URL == "" {
+			return fmt.Errorf("%w: delivery.sms requires url", ErrMissingField)
+		}
+	}
 	return nil
 }
 
@@ -2428,6 +2496,219 @@ func (c *EscalationConfig) GetRouteForSeverity(severity string) []string {
 	return []string{"bead", "mail:mayor"}
 }
 
+// SinksConfigPath returns the standard path for notification sink config in a town.
+func SinksConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "sinks.json")
+}
+
+// LoadSinksConfig loads and validates a notification sink configuration file.
+func LoadSinksConfig(path string) (*SinksConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading sinks config: %w", err)
+	}
+
+	var config SinksConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing sinks config: %w", err)
+	}
+
+	if err := validateSinksConfig(&config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// LoadOrCreateSinksConfig loads the sinks config, returning an empty one if not found.
+func LoadOrCreateSinksConfig(path string) (*SinksConfig, error) {
+	config, err := LoadSinksConfig(path)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return NewSinksConfig(), nil
+		}
+		return nil, err
+	}
+	return config, nil
+}
+
+// SaveSinksConfig saves a notification sink configuration to a file.
+// Commands and webhook URLs can carry credentials, so it is written owner-only.
+func SaveSinksConfig(path string, config *SinksConfig) error {
+	if err := validateSinksConfig(config); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding sinks config: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing sinks config: %w", err)
+	}
+
+	return nil
+}
+
+// validateSinksConfig validates a SinksConfig.
+func validateSinksConfig(c *SinksConfig) error {
+	if c.Type != "sinks" && c.Type != "" {
+		return fmt.Errorf("%w: expected type 'sinks', got '%s'", ErrInvalidType, c.Type)
+	}
+	if c.Version > CurrentSinksVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentSinksVersion)
+	}
+
+	seen := make(map[string]bool)
+	for i, s := range c.Sinks {
+		if s.Name == "" {
+			return fmt.Errorf("%w: sinks[%d].name", ErrMissingField, i)
+		}
+		// The name becomes a file name under daemon/sinks/.
+		if strings.ContainsAny(s.Name, `/\`) || strings.Contains(s.Name, "..") {
+			return fmt.Errorf("invalid sink name %q: must not contain path separators or \"..\"", s.Name)
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("duplicate sink name %q", s.Name)
+		}
+		seen[s.Name] = true
+
+		switch s.Kind {
+		case SinkKindWebhook:
+			if s.URL == "" {
+				return fmt.Errorf("%w: sink %q needs url", ErrMissingField, s.Name)
+			}
+		case SinkKindCommand:
+			if s.Command == "" {
+				return fmt.Errorf("%w: sink %q needs command", ErrMissingField, s.Name)
+			}
+		case SinkKindPipe:
+			if s.Path == "" {
+				return fmt.Errorf("%w: sink %q needs path", ErrMissingField, s.Name)
+			}
+		default:
+			return fmt.Errorf("sink %q: unknown kind %q (valid: webhook, command, pipe)", s.Name, s.Kind)
+		}
+
+		for field, v := range map[string]string{"timeout": s.Timeout, "backoff": s.Backoff} {
+			if v == "" {
+				continue
+			}
+			if _, err := time.ParseDuration(v); err != nil {
+				return fmt.Errorf("sink %q: invalid %s: %w", s.Name, field, err)
+			}
+		}
+		if s.MaxAttempts < 0 {
+			return fmt.Errorf("sink %q: max_attempts must be non-negative", s.Name)
+		}
+	}
+
+	return nil
+}
+
+// IsEnabled reports whether the sink is enabled (default true).
+func (s *SinkConfig) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
+// GetTimeout returns the per-attempt delivery timeout (default 10s).
+func (s *SinkConfig) GetTimeout() time.Duration {
+	return ParseDurationOrDefault(s.Timeout, 10*time.Second)
+}
+
+// GetBackoff returns the first retry delay (default 2s).
+func (s *SinkConfig) GetBackoff() time.Duration {
+	return ParseDurationOrDefault(s.Backoff, 2*time.Second)
+}
+
+// GetMaxAttempts returns the delivery attempts before dead-lettering (default 5).
+func (s *SinkConfig) GetMaxAttempts() int {
+	if s.MaxAttempts <= 0 {
+		return 5
+	}
+	return s.MaxAttempts
+}
+
+// GetSecret returns the signing secret from SecretEnv, or "" when unset.
+func (s *SinkConfig) GetSecret() string {
+	if s.SecretEnv == "" {
+		return ""
+	}
+	return os.Getenv(s.SecretEnv)
+}
+
+// GetMaxAttempts returns the attempts per external delivery (default 3).
+func (d *EscalationDelivery) GetMaxAttempts() int {
+	if d == nil || d.MaxAttempts <= 0 {
+		return 3
+	}
+	return d.MaxAttempts
+}
+
+// GetBackoff returns the first retry delay (default 2s).
+func (d *EscalationDelivery) GetBackoff() time.Duration {
+	if d == nil {
+		return 2 * time.Second
+	}
+	return ParseDurationOrDefault(d.Backoff, 2*time.Second)
+}
+
+// GetTimeout returns the per-attempt delivery timeout (default 10s).
+func (d *EscalationDelivery) GetTimeout() time.Duration {
+	if d == nil {
+		return 10 * time.Second
+	}
+	return ParseDurationOrDefault(d.Timeout, 10*time.Second)
+}
+
+// GetDeadline returns the time allowed for all deliveries of one
+// escalation, retries included (default 30s).
+func (d *EscalationDelivery) GetDeadline() time.Duration {
+	if d == nil {
+		return 30 * time.Second
+	}
+	return ParseDurationOrDefault(d.Deadline, 30*time.Second)
+}
+
+// GetPort returns the SMTP port, defaulting by TLS mode.
+func (s *SMTPConfig) GetPort() int {
+	if s.Port > 0 {
+		return s.Port
+	}
+	switch s.TLS {
+	case SMTPTLSImplicit:
+		return 465
+	case SMTPTLSNone:
+		return 25
+	default:
+		return 587
+	}
+}
+
+// GetPassword returns the SMTP password from PasswordEnv, or "" when unset.
+func (s *SMTPConfig) GetPassword() string {
+	if s.PasswordEnv == "" {
+		return ""
+	}
+	return os.Getenv(s.PasswordEnv)
+}
+
+// GetToken returns the gateway token from TokenEnv, or "" when unset.
+func (s *SMSGatewayConfig) GetToken() string {
+	if s.TokenEnv == "" {
+		return ""
+	}
+	return os.Getenv(s.TokenEnv)
+}
+
 // GetMaxReescalations returns the maximum number of re-escalations allowed.
 // Returns 2 if not configured (nil). Explicit 0 means "never re-escalate".
 func (c *EscalationConfig) GetMaxReescalations() int {
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/sandbox"
 )
 
 // skipIfAgentBinaryMissing skips the test if any of the specified agent binaries
@@ -372,6 +373,18 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid sandbox network",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Sandbox: &sandbox.Config{
+					Enabled: true,
+					Network: "lan",
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -3891,6 +3904,59 @@ func TestEscalationConfigValidation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "max_reescalations must be non-negative",
 		},
+		{
+			name: "valid delivery",
+			config: &EscalationConfig{
+				Type:    "escalation",
+				Version: 1,
+				Delivery: &EscalationDelivery{
+					Backoff: "5s",
+					SMTP:    &SMTPConfig{Host: "smtp.example.com", From: "gt@example.com", TLS: SMTPTLSImplicit},
+					SMS:     &SMSGatewayConfig{Provider: SMSProviderHTTP, URL: "https://sms.example.com/send"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "smtp without host",
+			config: &EscalationConfig{
+				Type:     "escalation",
+				Version:  1,
+				Delivery: &EscalationDelivery{SMTP: &SMTPConfig{From: "gt@example.com"}},
+			},
+			wantErr: true,
+			errMsg:  "delivery.smtp requires host and from",
+		},
+		{
+			name: "invalid smtp tls mode",
+			config: &EscalationConfig{
+				Type:     "escalation",
+				Version:  1,
+				Delivery: &EscalationDelivery{SMTP: &SMTPConfig{Host: "smtp.example.com", From: "gt@example.com", TLS: "ssl"}},
+			},
+			wantErr: true,
+			errMsg:  "invalid delivery.smtp.tls",
+		},
+		{
+			name: "unknown sms provider",
+			config: &EscalationConfig{
+				Type:     "escalation",
+				Version:  1,
+				Delivery: &EscalationDelivery{SMS: &SMSGatewayConfig{Provider: "pigeon", URL: "https://x"}},
+			},
+			wantErr: true,
+			errMsg:  "invalid delivery.sms.provider",
+		},
+		{
+			name: "invalid delivery timeout",
+			config: &EscalationConfig{
+				Type:     "escalation",
+				Version:  1,
+				Delivery: &EscalationDelivery{Timeout: "soon"},
+			},
+			wantErr: true,
+			errMsg:  "invalid delivery.timeout",
+		},
 	}
 
 	for _, tt := range tests {
@@ -3911,6 +3977,31 @@ func TestEscalationConfigValidation(t *testing.T) {
 	}
 }
 
+func TestLoadEscalationConfig_RejectsPlaintextSecrets(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		delivery string
+		errMsg   string
+	}{
+		{"smtp password", `{"smtp": {"host": "smtp.example.com", "from": "gt@example.com", "password": "hunter2"}}`, "delivery.smtp.password_env"},
+		{"sms token", `{"sms": {"provider": "http", "url": "https://sms.example.com/send", "token": "tok"}}`, "delivery.sms.token_env"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "escalation.json")
+			data := `{"type": "escalation", "version": 1, "delivery": ` + tt.delivery + `}`
+			if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadEscalationConfig(path)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("LoadEscalationConfig() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
 func TestEscalationConfigGetStaleThreshold(t *testing.T) {
 	t.Parallel()
 
@@ -4922,3 +5013,56 @@ func TestResolveRoleAgentConfig_EphemeralDefaultPreservesNonClaudeOverride(t *te
 		t.Errorf("expected gemini for polecat (non-Claude rig override with tier default), got Command=%q", rc.Command)
 	}
 }
+
+func TestSinksConfigRoundTripAndValidation(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "settings", "sinks.json")
+
+	cfg := NewSinksConfig()
+	cfg.Sinks = []SinkConfig{
+		{Name: "hook", Kind: SinkKindWebhook, URL: "https://example.com/gt", Events: []string{"merge_*"}, SecretEnv: "GT_SINK_SECRET", Timeout: "3s"},
+		{Name: "cmd", Kind: SinkKindCommand, Command: "cat"},
+	}
+	if err := SaveSinksConfig(path, cfg); err != nil {
+		t.Fatalf("SaveSinksConfig: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("sinks.json mode = %v, want 0600", info.Mode().Perm())
+	}
+	loaded, err := LoadSinksConfig(path)
+	if err != nil {
+		t.Fatalf("LoadSinksConfig: %v", err)
+	}
+	if len(loaded.Sinks) != 2 || loaded.Sinks[0].GetTimeout() != 3*time.Second || loaded.Sinks[1].GetMaxAttempts() != 5 {
+		t.Errorf("loaded = %+v", loaded.Sinks)
+	}
+
+	invalid := []SinkConfig{
+		{Kind: SinkKindWebhook, URL: "x"},
+		{Name: "a", Kind: SinkKindWebhook},
+		{Name: "a", Kind: SinkKindPipe},
+		{Name: "a", Kind: "email"},
+		{Name: "a", Kind: SinkKindCommand, Command: "x", Backoff: "soon"},
+		{Name: "../escape", Kind: SinkKindCommand, Command: "x"},
+		{Name: "a/b", Kind: SinkKindCommand, Command: "x"},
+		{Name: `a\b`, Kind: SinkKindCommand, Command: "x"},
+		{Name: "..", Kind: SinkKindCommand, Command: "x"},
+	}
+	for _, s := range invalid {
+		if err := validateSinksConfig(&SinksConfig{Sinks: []SinkConfig{s}}); err == nil {
+			t.Errorf("expected error for %+v", s)
+		}
+	}
+	dup := &SinksConfig{Sinks: []SinkConfig{cfg.Sinks[1], cfg.Sinks[1]}}
+	if err := validateSinksConfig(dup); err == nil {
+		t.Error("expected error for duplicate sink names")
+	}
+
+	if c, err := LoadOrCreateSinksConfig(filepath.Join(t.TempDir(), "missing.json")); err != nil || len(c.Sinks) != 0 {
+		t.Errorf("LoadOrCreateSinksConfig(missing) = %+v, %v", c, err)
+	}
+}
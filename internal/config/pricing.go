@@ -0,0 +1,88 @@
+package config
+
+import "strings"
+
+// ModelPricing is the USD price per million tokens for a model.
+// Used by `gt costs` to turn agent token usage into spend.
+type ModelPricing struct {
+	InputPerMillion      float64 `json:"input_per_million"`
+	OutputPerMillion     float64 `json:"output_per_million"`
+	CacheReadPerMillion  float64 `json:"cache_read_per_million,omitempty"`
+	CacheWritePerMillion float64 `json:"cache_write_per_million,omitempty"`
+}
+
+// DefaultPricingKey is the pricing table entry used for models that match
+// no other entry.
+const DefaultPricingKey = "default"
+
+// builtinModelPricing is the compiled-in pricing table. Keys are model names
+// or name prefixes; LookupModelPricing picks the longest matching key, so
+// "gpt-5-mini" wins over "gpt-5" and dated snapshots ("claude-sonnet-4-20250514")
+// match their family. Override or extend entries with "pricing" in
+// settings/config.json.
+var builtinModelPricing = map[string]*ModelPricing{
+	// Anthropic (cache read is a 90% discount, cache write a 25% premium)
+	"claude-opus-4-5-20251101":  {15.0, 75.0, 1.5, 18.75},
+	"claude-opus-4":             {15.0, 75.0, 1.5, 18.75},
+	"claude-sonnet-4-20250514":  {3.0, 15.0, 0.3, 3.75},
+	"claude-sonnet-4":           {3.0, 15.0, 0.3, 3.75},
+	"claude-haiku-4":            {1.0, 5.0, 0.1, 1.25},
+	"claude-3-5-haiku-20241022": {1.0, 5.0, 0.1, 1.25},
+
+	// OpenAI (Codex, OpenCode, Cursor)
+	"gpt-5":      {1.25, 10.0, 0.125, 0},
+	"gpt-5-mini": {0.25, 2.0, 0.025, 0},
+	"gpt-5-nano": {0.05, 0.4, 0.005, 0},
+	"gpt-4.1":    {2.0, 8.0, 0.5, 0},
+	"o3":         {2.0, 8.0, 0.5, 0},
+	"o4-mini":    {1.1, 4.4, 0.275, 0},
+
+	// Google (Gemini CLI)
+	"gemini-2.5-pro":        {1.25, 10.0, 0.31, 0},
+	"gemini-2.5-flash":      {0.3, 2.5, 0.075, 0},
+	"gemini-2.5-flash-lite": {0.1, 0.4, 0.025, 0},
+
+	// Fallback for unknown models (use Sonnet pricing)
+	DefaultPricingKey: {3.0, 15.0, 0.3, 3.75},
+}
+
+// LookupModelPricing returns the pricing for model. Entries in overrides
+// (from TownSettings.Pricing) take precedence over the built-in table. The
+// second return value is false when only the default entry matched.
+func LookupModelPricing(model string, overrides map[string]*ModelPricing) (ModelPricing, bool) {
+	if p, ok := longestPricingMatch(model, overrides); ok {
+		return p, true
+	}
+	if p, ok := longestPricingMatch(model, builtinModelPricing); ok {
+		return p, true
+	}
+	if p := overrides[DefaultPricingKey]; p != nil {
+		return *p, false
+	}
+	return *builtinModelPricing[DefaultPricingKey], false
+}
+
+// longestPricingMatch finds the entry whose key is the longest prefix of
+// model, ignoring the default entry.
+func longestPricingMatch(model string, table map[string]*ModelPricing) (ModelPricing, bool) {
+	if model == "" {
+		return ModelPricing{}, false
+	}
+	// Provider-qualified names ("openai/gpt-5") are priced by model.
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:]
+	}
+	best := ""
+	for key, p := range table {
+		if p == nil || key == DefaultPricingKey || !strings.HasPrefix(model, key) {
+			continue
+		}
+		if len(key) > len(best) {
+			best = key
+		}
+	}
+	if best == "" {
+		return ModelPricing{}, false
+	}
+	return *table[best], true
+}
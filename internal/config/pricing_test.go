@@ -0,0 +1,59 @@
+package config
+
+import "testing"
+
+func TestLookupModelPricing(t *testing.T) {
+	tests := []struct {
+		model     string
+		overrides map[string]*ModelPricing
+		wantIn    float64
+		wantKnown bool
+	}{
+		{model: "claude-sonnet-4-20250514", wantIn: 3.0, wantKnown: true},
+		{model: "claude-sonnet-4-5-20250929", wantIn: 3.0, wantKnown: true},
+		{model: "gpt-5-codex", wantIn: 1.25, wantKnown: true},
+		{model: "gpt-5-mini-2025-08-07", wantIn: 0.25, wantKnown: true},
+		{model: "openai/gpt-5", wantIn: 1.25, wantKnown: true},
+		{model: "gemini-2.5-flash-lite", wantIn: 0.1, wantKnown: true},
+		{model: "mystery-model", wantIn: 3.0, wantKnown: false},
+		{model: "", wantIn: 3.0, wantKnown: false},
+		{
+			model:     "gpt-5-codex",
+			overrides: map[string]*ModelPricing{"gpt-5-codex": {InputPerMillion: 2.0}},
+			wantIn:    2.0,
+			wantKnown: true,
+		},
+		{
+			model:     "mystery-model",
+			overrides: map[string]*ModelPricing{DefaultPricingKey: {InputPerMillion: 9.0}},
+			wantIn:    9.0,
+			wantKnown: false,
+		},
+	}
+	for _, tt := range tests {
+		got, known := LookupModelPricing(tt.model, tt.overrides)
+		if got.InputPerMillion != tt.wantIn || known != tt.wantKnown {
+			t.Errorf("LookupModelPricing(%q) = (%v, %v), want input %v known %v",
+				tt.model, got.InputPerMillion, known, tt.wantIn, tt.wantKnown)
+		}
+	}
+}
+
+func TestGetUsageProvider(t *testing.T) {
+	tests := []struct {
+		agent, command, want string
+	}{
+		{"", "", "claude"},
+		{"claude", "", "claude"},
+		{"codex", "", "codex"},
+		{"amp", "", ""},
+		{"claude-sonnet", "claude", "claude"},
+		{"my-codex", "/usr/local/bin/codex", "codex"},
+		{"custom", "aider", ""},
+	}
+	for _, tt := range tests {
+		if got := GetUsageProvider(tt.agent, tt.command); got != tt.want {
+			t.Errorf("GetUsageProvider(%q, %q) = %q, want %q", tt.agent, tt.command, got, tt.want)
+		}
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/sandbox"
 	"github.com/steveyegge/gastown/internal/scheduler/capacity"
 )
 
@@ -96,8 +98,31 @@ type TownSettings struct {
 	// These were previously hardcoded as Go constants throughout the codebase.
 	// All values are optional — omitted values use compiled-in defaults.
 	Operational *OperationalConfig `json:"operational,omitempty"`
+
+	// Pricing overrides or extends the built-in model pricing table used by
+	// `gt costs`. Keys are model names or prefixes (longest match wins);
+	// "default" prices models that match nothing else.
+	// Example: {"gpt-5-codex": {"input_per_million": 1.25, "output_per_million": 10}}
+	Pricing map[string]*ModelPricing `json:"pricing,omitempty"`
+
+	// Budgets caps agent spend per rig (daily), convoy (total) and town
+	// (monthly). Spend is tracked by `gt costs record`; crossing a budget
+	// escalates and, depending on the action, holds scheduling or slinging.
+	// Example: {"town_monthly_usd": 500, "rig_daily_usd": {"*": 40}, "action": "pause"}
+	Budgets *budget.Config `json:"budgets,omitempty"`
+
+	// SessionBackend selects what polecat sessions run in: "tmux" (default)
+	// or "pty", a headless PTY supervisor hosted by the daemon. Other roles
+	// always run in tmux.
+	SessionBackend string `json:"session_backend,omitempty"`
 }
 
+// Session backends for TownSettings.SessionBackend.
+const (
+	SessionBackendTmux = "tmux"
+	SessionBackendPTY  = "pty"
+)
+
 // NewTownSettings creates a new TownSettings with defaults.
 func NewTownSettings() *TownSettings {
 	return &TownSettings{
@@ -595,6 +620,12 @@ type RigSettings struct {
 	// Takes precedence over RoleAgents["crew"] but is overridden by explicit --agent flags.
 	// Example: {"denali": "codex", "glacier": "gemini"}
 	WorkerAgents map[string]string `json:"worker_agents,omitempty"`
+
+	// Sandbox runs agent sessions in a namespace sandbox (bubblewrap or
+	// unshare) where only the worktree is writable and $HOME is private.
+	// Applies to polecats unless roles are listed.
+	// Example: {"enabled": true, "network": "host", "read_write": ["~/.cache/go-build"]}
+	Sandbox *sandbox.Config `json:"sandbox,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -1092,6 +1123,11 @@ type MergeQueueConfig struct {
 	// OnConflict specifies conflict resolution strategy: "assign_back" or "auto_rebase".
 	OnConflict string `json:"on_conflict"`
 
+	// MergeStrategy controls how an MR lands on its target branch:
+	// "squash" (one commit per MR), "rebase" (replay the MR's commits and
+	// fast-forward) or "merge" (a merge commit). Empty defaults to "squash".
+	MergeStrategy string `json:"merge_strategy,omitempty"`
+
 	// RunTests controls whether to run tests before merging.
 	// Nil defaults to true (tests are run).
 	RunTests *bool `json:"run_tests,omitempty"`
@@ -1121,12 +1157,68 @@ type MergeQueueConfig struct {
 	// PollInterval is how often to poll for new merge requests (e.g., "30s").
 	PollInterval string `json:"poll_interval"`
 
-	// MaxConcurrent is the maximum number of concurrent merges.
+	// MaxConcurrent is the number of speculative batch stacks tested at once.
 	MaxConcurrent int `json:"max_concurrent"`
 
 	// StaleClaimTimeout is how long a claimed MR can go without updates before
 	// being considered abandoned and eligible for re-claim (e.g., "30m").
 	StaleClaimTimeout string `json:"stale_claim_timeout,omitempty"`
+
+	// PullRequest, when set, makes the refinery land protected targets by
+	// opening a forge pull request instead of pushing to them directly.
+	PullRequest *MergeQueuePullRequestConfig `json:"pull_request,omitempty"`
+
+	// Flaky, when set, tracks flaky tests in gates that declare a
+	// test_report and quarantines tests that flake too often.
+	Flaky *MergeQueueFlakyConfig `json:"flaky,omitempty"`
+}
+
+// MergeQueuePullRequestConfig configures landing through forge pull requests.
+type MergeQueuePullRequestConfig struct {
+	// Forge is the forge API flavour; only "github" is supported (default).
+	Forge string `json:"forge,omitempty"`
+
+	// APIURL is the REST API base URL (default "https://api.github.com").
+	APIURL string `json:"api_url,omitempty"`
+
+	// Repo is the forge repository as "owner/name".
+	Repo string `json:"repo"`
+
+	// TokenEnv names the environment variable holding the API token
+	// (default GITHUB_TOKEN, then GH_TOKEN).
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// Branches are target branch patterns that land through pull requests.
+	// Empty means the rig's default branch only.
+	Branches []string `json:"branches,omitempty"`
+
+	// MergeMethod is "merge", "squash" or "rebase". Empty derives it from
+	// MergeStrategy ("merge" for merge, otherwise "rebase").
+	MergeMethod string `json:"merge_method,omitempty"`
+
+	// WaitTimeout bounds one wait for checks and reviews (e.g., "30m").
+	WaitTimeout string `json:"wait_timeout,omitempty"`
+
+	// PollInterval is how often the pull request is polled (e.g., "30s").
+	PollInterval string `json:"poll_interval,omitempty"`
+}
+
+// MergeQueueFlakyConfig configures flaky-test detection and quarantine.
+type MergeQueueFlakyConfig struct {
+	// Threshold is the flake rate at or above which a test is quarantined
+	// (default 0.1).
+	Threshold float64 `json:"threshold,omitempty"`
+
+	// MinRuns is how many runs of a test are needed before it can be
+	// quarantined (default 10).
+	MinRuns int `json:"min_runs,omitempty"`
+
+	// Window is how many recent runs are kept per test (default 100).
+	Window int `json:"window,omitempty"`
+
+	// Retries is how many times a gate with failing tests is re-run to
+	// tell flakes from real failures (default 1).
+	Retries *int `json:"retries,omitempty"`
 }
 
 // OnConflict strategy constants.
@@ -1135,6 +1227,13 @@ const (
 	OnConflictAutoRebase = "auto_rebase"
 )
 
+// Merge strategy constants for MergeQueueConfig.MergeStrategy.
+const (
+	MergeStrategySquash = "squash"
+	MergeStrategyRebase = "rebase"
+	MergeStrategyMerge  = "merge"
+)
+
 // IsPolecatIntegrationEnabled returns whether polecat integration branch
 // sourcing is enabled. Nil-safe, defaults to true.
 func (c *MergeQueueConfig) IsPolecatIntegrationEnabled() bool {
@@ -1193,6 +1292,7 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 		IntegrationBranchPolecatEnabled:  boolPtr(true),
 		IntegrationBranchRefineryEnabled: boolPtr(true),
 		OnConflict:                       OnConflictAssignBack,
+		MergeStrategy:                    MergeStrategySquash,
 		RunTests:                         boolPtr(true),
 		TestCommand:                      "go test ./...",
 		DeleteMergedBranches:             boolPtr(true),
@@ -1367,6 +1467,10 @@ type EscalationConfig struct {
 	// Contacts contains contact information for external notification actions.
 	Contacts EscalationContacts `json:"contacts"`
 
+	// Delivery configures how email: and sms: actions are sent, and how
+	// external deliveries are retried. Nil means email and SMS are skipped.
+	Delivery *EscalationDelivery `json:"delivery,omitempty"`
+
 	// StaleThreshold is how long before an unacknowledged escalation
 	// is considered stale and gets re-escalated.
 	// Format: Go duration string (e.g., "4h", "30m", "24h")
@@ -1386,6 +1490,59 @@ type EscalationContacts struct {
 	SlackWebhook string `json:"slack_webhook,omitempty"` // webhook URL for slack action
 }
 
+// EscalationDelivery configures external delivery of escalations.
+type EscalationDelivery struct {
+	SMTP *SMTPConfig       `json:"smtp,omitempty"` // email: actions
+	SMS  *SMSGatewayConfig `json:"sms,omitempty"`  // sms: actions
+
+	// Each delivery gets MaxAttempts tries (default 3), each bounded by
+	// Timeout (default 10s), waiting Backoff (default 2s, doubling) between.
+	// Deadline (default 30s) bounds all deliveries of one escalation, which
+	// run in parallel, so gt escalate never holds up its caller for long.
+	MaxAttempts int    `json:"max_attempts,omitempty"`
+	Backoff     string `json:"backoff,omitempty"`
+	Timeout     string `json:"timeout,omitempty"`
+	Deadline    string `json:"deadline,omitempty"`
+}
+
+// SMTP TLS modes.
+const (
+	SMTPTLSStartTLS = "starttls" // Plain connection upgraded with STARTTLS (default, port 587)
+	SMTPTLSImplicit = "tls"      // TLS from the first byte (port 465)
+	SMTPTLSNone     = "none"     // No encryption (local relays only, port 25)
+)
+
+// SMTPConfig is the mail server used for email: escalation actions.
+type SMTPConfig struct {
+	Host string `json:"host"`
+	Port int    `json:"port,omitempty"` // Default depends on TLS mode
+	TLS  string `json:"tls,omitempty"`  // starttls (default), tls, none
+	From string `json:"from"`
+
+	// Username enables PLAIN auth. PasswordEnv names an environment
+	// variable holding the password; settings/ is tracked in git, so the
+	// password itself is never stored in the file.
+	Username    string `json:"username,omitempty"`
+	PasswordEnv string `json:"password_env,omitempty"`
+}
+
+// SMS gateway providers.
+const (
+	SMSProviderHTTP = "http" // POST JSON {"to","from","body"} to URL
+)
+
+// SMSGatewayConfig is the gateway used for sms: escalation actions.
+type SMSGatewayConfig struct {
+	Provider string `json:"provider"` // http
+	URL      string `json:"url"`
+	From     string `json:"from,omitempty"`
+
+	// TokenEnv names an environment variable holding a token sent as a
+	// bearer token. Like the SMTP password, it is never stored in the file.
+	TokenEnv string            `json:"token_env,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+}
+
 // CurrentEscalationVersion is the current schema version for EscalationConfig.
 const CurrentEscalationVersion = 1
 
@@ -1446,3 +1603,63 @@ func NewEscalationConfig() *EscalationConfig {
 		MaxReescalations: intPtr(2),
 	}
 }
+
+// SinksConfig configures outbound notification sinks for town events.
+// Stored in settings/sinks.json. The daemon tails the events log and
+// delivers matching events to each enabled sink.
+type SinksConfig struct {
+	Type    string `json:"type"`    // "sinks"
+	Version int    `json:"version"` // schema version
+
+	Sinks []SinkConfig `json:"sinks"`
+}
+
+// Sink kinds.
+const (
+	SinkKindWebhook = "webhook" // POST signed JSON to URL
+	SinkKindCommand = "command" // Run Command with the JSON on stdin
+	SinkKindPipe    = "pipe"    // Write a JSON line to Path (named pipe or file)
+)
+
+// SinkConfig is a single notification sink.
+type SinkConfig struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`              // webhook, command, pipe
+	Enabled *bool  `json:"enabled,omitempty"` // Default true
+
+	// Events lists the event types to deliver; globs allowed ("merge_*").
+	// Empty means every event.
+	Events []string `json:"events,omitempty"`
+	// Rig restricts delivery to events about one rig.
+	Rig string `json:"rig,omitempty"`
+
+	// Webhook settings.
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// SecretEnv names the environment variable holding the HMAC-SHA256
+	// signing secret. The secret itself is never stored in the config.
+	SecretEnv string `json:"secret_env,omitempty"`
+
+	// Command settings (run via sh -c).
+	Command string `json:"command,omitempty"`
+
+	// Pipe settings.
+	Path string `json:"path,omitempty"`
+
+	// Delivery settings.
+	Timeout     string `json:"timeout,omitempty"`      // Per attempt (default "10s")
+	MaxAttempts int    `json:"max_attempts,omitempty"` // Before dead-lettering (default 5)
+	Backoff     string `json:"backoff,omitempty"`      // First retry delay, doubled each retry (default "2s")
+}
+
+// CurrentSinksVersion is the current schema version for SinksConfig.
+const CurrentSinksVersion = 1
+
+// NewSinksConfig creates an empty SinksConfig.
+func NewSinksConfig() *SinksConfig {
+	return &SinksConfig{
+		Type:    "sinks",
+		Version: CurrentSinksVersion,
+		Sinks:   []SinkConfig{},
+	}
+}
@@ -0,0 +1,751 @@
+package connection
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Exit codes used by the remote helper scripts to signal well-known
+// failure modes back to the client (sysexits.h values).
+const (
+	sshExitNotFound   = 66 // EX_NOINPUT
+	sshExitPermission = 77 // EX_NOPERM
+)
+
+// DefaultSSHDialTimeout bounds how long connecting to a remote machine may take.
+const DefaultSSHDialTimeout = 15 * time.Second
+
+// SSHConfig describes how to reach a remote machine over SSH.
+type SSHConfig struct {
+	// Name is the machine name used in error messages and Name().
+	Name string
+
+	// Host is the target in "user@host[:port]" form. User defaults to $USER
+	// and port defaults to 22.
+	Host string
+
+	// KeyPath is the private key used for authentication. When empty, the
+	// running ssh-agent (SSH_AUTH_SOCK) is used instead.
+	KeyPath string
+
+	// HostKeyCallback verifies the server host key. When nil, host keys are
+	// checked against ~/.ssh/known_hosts.
+	HostKeyCallback ssh.HostKeyCallback
+
+	// DialTimeout bounds the TCP connect and handshake. Zero means DefaultSSHDialTimeout.
+	DialTimeout time.Duration
+
+	// TmuxSocket is the tmux socket name (-L flag) used on the remote side.
+	// Empty means the remote user's default tmux server.
+	TmuxSocket string
+}
+
+// SSHConnection implements Connection for a remote machine reached over SSH.
+// File operations and tmux commands are executed through POSIX shell commands
+// on the remote side; the underlying SSH client is shared through a pool so
+// that many Connection values for the same machine reuse one TCP connection.
+type SSHConnection struct {
+	cfg  SSHConfig
+	user string
+	addr string
+	pool *sshPool
+}
+
+// NewSSHConnection creates a new SSH connection using the shared client pool.
+// The network connection is established lazily on first use.
+func NewSSHConnection(cfg SSHConfig) (*SSHConnection, error) {
+	return newSSHConnection(cfg, defaultSSHPool)
+}
+
+func newSSHConnection(cfg SSHConfig, pool *sshPool) (*SSHConnection, error) {
+	user, addr, err := parseSSHHost(cfg.Host)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Name == "" {
+		cfg.Name = cfg.Host
+	}
+	if cfg.DialTimeout == 0 {
+		cfg.DialTimeout = DefaultSSHDialTimeout
+	}
+	return &SSHConnection{cfg: cfg, user: user, addr: addr, pool: pool}, nil
+}
+
+// parseSSHHost splits "user@host[:port]" into a user and a dialable address.
+func parseSSHHost(host string) (user, addr string, err error) {
+	if host == "" {
+		return "", "", fmt.Errorf("ssh host is required")
+	}
+	if idx := strings.LastIndex(host, "@"); idx >= 0 {
+		user = host[:idx]
+		host = host[idx+1:]
+	}
+	if user == "" {
+		user = os.Getenv("USER")
+	}
+	if user == "" {
+		return "", "", fmt.Errorf("ssh host %q: no user specified and $USER is unset", host)
+	}
+	if host == "" {
+		return "", "", fmt.Errorf("ssh host is required")
+	}
+	if _, _, splitErr := net.SplitHostPort(host); splitErr != nil {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), "22")
+	}
+	return user, host, nil
+}
+
+// Name returns the machine name.
+func (c *SSHConnection) Name() string {
+	return c.cfg.Name
+}
+
+// IsLocal returns false for SSH connections.
+func (c *SSHConnection) IsLocal() bool {
+	return false
+}
+
+// poolKey identifies clients that can be shared between connections.
+func (c *SSHConnection) poolKey() string {
+	return c.user + "@" + c.addr + "|" + c.cfg.KeyPath
+}
+
+// clientConfig builds the ssh.ClientConfig for this machine. The returned
+// release func frees auth resources (the ssh-agent socket) once the handshake
+// is done.
+func (c *SSHConnection) clientConfig() (*ssh.ClientConfig, func(), error) {
+	hostKeyCallback := c.cfg.HostKeyCallback
+	if hostKeyCallback == nil {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil, fmt.Errorf("locating known_hosts: %w", err)
+		}
+		hostKeyCallback, err = knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("loading known_hosts: %w", err)
+		}
+	}
+
+	auth, release, err := sshAuthMethods(c.cfg.KeyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &ssh.ClientConfig{
+		User:            c.user,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         c.cfg.DialTimeout,
+	}, release, nil
+}
+
+// sshAuthMethods returns public-key auth from keyPath, or from ssh-agent when
+// keyPath is empty. The release func closes the agent connection; call it
+// after the handshake.
+func sshAuthMethods(keyPath string) ([]ssh.AuthMethod, func(), error) {
+	if keyPath != "" {
+		if strings.HasPrefix(keyPath, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				keyPath = filepath.Join(home, keyPath[2:])
+			}
+		}
+		key, err := os.ReadFile(keyPath) //nolint:gosec // G304: key path comes from machine registry
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading ssh key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing ssh key %s: %w", keyPath, err)
+		}
+		return []ssh.AuthMethod{ssh.PublicKeys(signer)}, func() {}, nil
+	}
+
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, nil, fmt.Errorf("no ssh key_path configured and SSH_AUTH_SOCK is unset")
+	}
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connecting to ssh-agent: %w", err)
+	}
+	release := func() { _ = conn.Close() }
+	return []ssh.AuthMethod{ssh.PublicKeysCallback(agent.NewClient(conn).Signers)}, release, nil
+}
+
+// dial opens a new SSH client to the machine.
+func (c *SSHConnection) dial() (*ssh.Client, error) {
+	cfg, release, err := c.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return ssh.Dial("tcp", c.addr, cfg)
+}
+
+// newSession opens a session on a pooled client. A stale pooled client is
+// evicted and redialed once before giving up.
+func (c *SSHConnection) newSession() (*ssh.Session, error) {
+	key := c.poolKey()
+	for attempt := 0; attempt < 2; attempt++ {
+		client, err := c.pool.get(key, c.dial)
+		if err != nil {
+			return nil, &ConnectionError{Op: "connect", Machine: c.cfg.Name, Err: err}
+		}
+		sess, err := client.NewSession()
+		if err == nil {
+			return sess, nil
+		}
+		c.pool.evict(key, client)
+		if attempt == 1 {
+			return nil, &ConnectionError{Op: "session", Machine: c.cfg.Name, Err: err}
+		}
+	}
+	return nil, &ConnectionError{Op: "session", Machine: c.cfg.Name, Err: errors.New("unreachable")}
+}
+
+// sshResult holds the outcome of a remote command.
+type sshResult struct {
+	stdout   []byte
+	stderr   []byte
+	exitCode int
+}
+
+// run executes a shell command line remotely with optional stdin. Non-zero exit
+// statuses are reported in the result rather than as errors; only transport
+// failures are returned as errors (wrapped in ConnectionError).
+func (c *SSHConnection) run(cmdline string, stdin []byte) (*sshResult, error) {
+	sess, err := c.newSession()
+	if err != nil {
+		return nil, err
+	}
+	defer sess.Close()
+
+	var stdout, stderr bytes.Buffer
+	sess.Stdout = &stdout
+	sess.Stderr = &stderr
+	if stdin != nil {
+		sess.Stdin = bytes.NewReader(stdin)
+	}
+
+	res := &sshResult{}
+	if err := sess.Run(cmdline); err != nil {
+		var exitErr *ssh.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, &ConnectionError{Op: "exec", Machine: c.cfg.Name, Err: err}
+		}
+		res.exitCode = exitErr.ExitStatus()
+	}
+	res.stdout = stdout.Bytes()
+	res.stderr = stderr.Bytes()
+	return res, nil
+}
+
+// runCombined executes a shell command line and returns combined output,
+// mirroring exec.Cmd.CombinedOutput semantics.
+func (c *SSHConnection) runCombined(cmdline string) ([]byte, error) {
+	sess, err := c.newSession()
+	if err != nil {
+		return nil, err
+	}
+	defer sess.Close()
+
+	out, err := sess.CombinedOutput(cmdline)
+	if err != nil {
+		var exitErr *ssh.ExitError
+		if errors.As(err, &exitErr) {
+			return out, exitErr
+		}
+		return out, &ConnectionError{Op: "exec", Machine: c.cfg.Name, Err: err}
+	}
+	return out, nil
+}
+
+// fileError maps helper-script exit codes to the package error types.
+func fileError(res *sshResult, path, op string) error {
+	switch res.exitCode {
+	case 0:
+		return nil
+	case sshExitNotFound:
+		return &NotFoundError{Path: path}
+	case sshExitPermission:
+		return &PermissionError{Path: path, Op: op}
+	}
+	stderr := strings.TrimSpace(string(res.stderr))
+	if strings.Contains(stderr, "Permission denied") {
+		return &PermissionError{Path: path, Op: op}
+	}
+	if stderr == "" {
+		stderr = "exit status " + strconv.Itoa(res.exitCode)
+	}
+	return fmt.Errorf("%s %s: %s", op, path, stderr)
+}
+
+// ReadFile reads the named file on the remote machine.
+func (c *SSHConnection) ReadFile(path string) ([]byte, error) {
+	p := shellQuote(path)
+	script := fmt.Sprintf("[ -e %[1]s ] || exit %[2]d; [ -r %[1]s ] || exit %[3]d; exec cat -- %[1]s",
+		p, sshExitNotFound, sshExitPermission)
+	res, err := c.run(script, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := fileError(res, path, "read"); err != nil {
+		return nil, err
+	}
+	return res.stdout, nil
+}
+
+// WriteFile writes data to the named file on the remote machine.
+func (c *SSHConnection) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	p := shellQuote(path)
+	script := fmt.Sprintf("cat > %[1]s && chmod %[2]o %[1]s", p, perm.Perm())
+	if data == nil {
+		data = []byte{}
+	}
+	res, err := c.run(script, data)
+	if err != nil {
+		return err
+	}
+	return fileError(res, path, "write")
+}
+
+// MkdirAll creates a directory and all parent directories.
+func (c *SSHConnection) MkdirAll(path string, perm fs.FileMode) error {
+	res, err := c.run(fmt.Sprintf("mkdir -p -m %o -- %s", perm.Perm(), shellQuote(path)), nil)
+	if err != nil {
+		return err
+	}
+	return fileError(res, path, "mkdir")
+}
+
+// Remove removes the named file or empty directory. A missing path is not an error.
+func (c *SSHConnection) Remove(path string) error {
+	p := shellQuote(path)
+	script := fmt.Sprintf("if [ -d %[1]s ] && [ ! -L %[1]s ]; then rmdir -- %[1]s; else rm -f -- %[1]s; fi", p)
+	res, err := c.run(script, nil)
+	if err != nil {
+		return err
+	}
+	return fileError(res, path, "remove")
+}
+
+// RemoveAll removes the named file or directory and any children.
+func (c *SSHConnection) RemoveAll(path string) error {
+	res, err := c.run("rm -rf -- "+shellQuote(path), nil)
+	if err != nil {
+		return err
+	}
+	return fileError(res, path, "remove")
+}
+
+// Stat returns file info for the named file. Both GNU and BSD stat are supported.
+func (c *SSHConnection) Stat(path string) (FileInfo, error) {
+	p := shellQuote(path)
+	script := fmt.Sprintf("[ -e %[1]s ] || exit %[2]d; stat -L -c '%%s %%f %%Y' -- %[1]s 2>/dev/null || stat -L -f '%%z %%Xp %%m' -- %[1]s",
+		p, sshExitNotFound)
+	res, err := c.run(script, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := fileError(res, path, "stat"); err != nil {
+		return nil, err
+	}
+	return parseStatOutput(path, string(res.stdout))
+}
+
+// parseStatOutput parses "<size> <raw mode hex> <mtime epoch>" into a FileInfo.
+func parseStatOutput(path, out string) (FileInfo, error) {
+	fields := strings.Fields(out)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("stat %s: unexpected output %q", path, strings.TrimSpace(out))
+	}
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("stat %s: parsing size: %w", path, err)
+	}
+	rawMode, err := strconv.ParseUint(fields[1], 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("stat %s: parsing mode: %w", path, err)
+	}
+	mtime, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("stat %s: parsing mtime: %w", path, err)
+	}
+	mode := unixModeToFileMode(uint32(rawMode))
+	return BasicFileInfo{
+		FileName:    filepath.Base(path),
+		FileSize:    size,
+		FileMode:    mode,
+		FileModTime: time.Unix(mtime, 0),
+		FileIsDir:   mode.IsDir(),
+	}, nil
+}
+
+// unixModeToFileMode converts a raw st_mode value into an fs.FileMode.
+func unixModeToFileMode(m uint32) fs.FileMode {
+	mode := fs.FileMode(m & 0o777)
+	switch m & 0o170000 {
+	case 0o040000:
+		mode |= fs.ModeDir
+	case 0o120000:
+		mode |= fs.ModeSymlink
+	case 0o010000:
+		mode |= fs.ModeNamedPipe
+	case 0o140000:
+		mode |= fs.ModeSocket
+	case 0o020000:
+		mode |= fs.ModeDevice | fs.ModeCharDevice
+	case 0o060000:
+		mode |= fs.ModeDevice
+	}
+	if m&0o4000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if m&0o2000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if m&0o1000 != 0 {
+		mode |= fs.ModeSticky
+	}
+	return mode
+}
+
+// Glob returns the names of all files matching the pattern on the remote
+// machine, with filepath.Glob semantics (dotfiles included). The remote lists
+// candidates under the pattern's literal prefix with find, and matching is
+// done locally with filepath.Match.
+func (c *SSHConnection) Glob(pattern string) ([]string, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	root, depth := globRoot(pattern)
+	if depth == 0 {
+		if ok, err := c.Exists(pattern); err != nil || !ok {
+			return nil, err
+		}
+		return []string{pattern}, nil
+	}
+
+	script := fmt.Sprintf("find %s -mindepth %d -maxdepth %d -print0 2>/dev/null; exit 0", shellQuote(root), depth, depth)
+	res, err := c.run(script, nil)
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	for _, name := range strings.Split(string(res.stdout), "\x00") {
+		if name == "" {
+			continue
+		}
+		if root == "." {
+			name = strings.TrimPrefix(name, "./")
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			matches = append(matches, name)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// globRoot splits a pattern into its literal leading directory and the number
+// of path components below it, starting with the first one holding a
+// wildcard. depth is 0 for a pattern without wildcards.
+func globRoot(pattern string) (root string, depth int) {
+	parts := strings.Split(pattern, "/")
+	for i, part := range parts {
+		if strings.ContainsAny(part, `*?[\`) {
+			root = strings.Join(parts[:i], "/")
+			if root == "" {
+				root = "."
+				if strings.HasPrefix(pattern, "/") {
+					root = "/"
+				}
+			}
+			return root, len(parts) - i
+		}
+	}
+	return pattern, 0
+}
+
+// Exists returns true if the path exists on the remote machine.
+func (c *SSHConnection) Exists(path string) (bool, error) {
+	res, err := c.run("test -e "+shellQuote(path), nil)
+	if err != nil {
+		return false, err
+	}
+	switch res.exitCode {
+	case 0:
+		return true, nil
+	case 1:
+		return false, nil
+	}
+	return false, fileError(res, path, "stat")
+}
+
+// Exec runs a command remotely and returns its combined output.
+func (c *SSHConnection) Exec(cmd string, args ...string) ([]byte, error) {
+	return c.runCombined(shellCommand(cmd, args))
+}
+
+// ExecDir runs a command remotely in the specified directory.
+func (c *SSHConnection) ExecDir(dir, cmd string, args ...string) ([]byte, error) {
+	return c.runCombined("cd " + shellQuote(dir) + " && " + shellCommand(cmd, args))
+}
+
+// ExecEnv runs a command remotely with additional environment variables.
+func (c *SSHConnection) ExecEnv(env map[string]string, cmd string, args ...string) ([]byte, error) {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("env")
+	for _, k := range keys {
+		b.WriteString(" ")
+		b.WriteString(shellQuote(k + "=" + env[k]))
+	}
+	b.WriteString(" ")
+	b.WriteString(shellCommand(cmd, args))
+	return c.runCombined(b.String())
+}
+
+// tmux runs a tmux subcommand on the remote machine, translating well-known
+// failures into the tmux package's sentinel errors.
+func (c *SSHConnection) tmux(args ...string) (string, error) {
+	allArgs := []string{"-u"}
+	if c.cfg.TmuxSocket != "" {
+		allArgs = append(allArgs, "-L", c.cfg.TmuxSocket)
+	}
+	allArgs = append(allArgs, args...)
+
+	res, err := c.run(shellCommand("tmux", allArgs), nil)
+	if err != nil {
+		return "", err
+	}
+	if res.exitCode != 0 {
+		return "", tmuxError(string(res.stderr), res.exitCode, args[0])
+	}
+	return strings.TrimSpace(string(res.stdout)), nil
+}
+
+// tmuxError mirrors tmux.Tmux's error classification for remote invocations.
+func tmuxError(stderr string, exitCode int, subcmd string) error {
+	stderr = strings.TrimSpace(stderr)
+	switch {
+	case strings.Contains(stderr, "no server running"),
+		strings.Contains(stderr, "error connecting to"),
+		strings.Contains(stderr, "no current target"),
+		strings.Contains(stderr, "server exited unexpectedly"):
+		return tmux.ErrNoServer
+	case strings.Contains(stderr, "duplicate session"):
+		return tmux.ErrSessionExists
+	case strings.Contains(stderr, "session not found"),
+		strings.Contains(stderr, "can't find session"):
+		return tmux.ErrSessionNotFound
+	case stderr != "":
+		return fmt.Errorf("tmux %s: %s", subcmd, stderr)
+	}
+	return fmt.Errorf("tmux %s: exit status %d", subcmd, exitCode)
+}
+
+// TmuxNewSession creates a new tmux session on the remote machine.
+func (c *SSHConnection) TmuxNewSession(name, dir string) error {
+	args := []string{"new-session", "-d", "-s", name}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	if _, err := c.tmux(args...); err != nil {
+		return err
+	}
+	// Match local behavior: let the window follow the attaching client's size.
+	_, _ = c.tmux("set-option", "-wt", name, "window-size", "latest")
+	return nil
+}
+
+// TmuxKillSession terminates a remote tmux session. The pane's child processes
+// are signalled first so agents don't survive as orphans, mirroring
+// KillSessionWithProcesses for local sessions.
+func (c *SSHConnection) TmuxKillSession(name string) error {
+	pid, err := c.tmux("display-message", "-p", "-t", "="+name, "#{pane_pid}")
+	if err != nil {
+		if errors.Is(err, tmux.ErrSessionNotFound) || errors.Is(err, tmux.ErrNoServer) {
+			return nil
+		}
+		return err
+	}
+	if _, convErr := strconv.Atoi(pid); convErr == nil {
+		// Best-effort: pkill may be missing or find nothing.
+		_, _ = c.run("pkill -TERM -P "+pid+"; kill -TERM "+pid+" 2>/dev/null; exit 0", nil)
+	}
+	if _, err := c.tmux("kill-session", "-t", "="+name); err != nil {
+		if errors.Is(err, tmux.ErrSessionNotFound) || errors.Is(err, tmux.ErrNoServer) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// TmuxSendKeys sends literal keys followed by Enter to a remote tmux session.
+func (c *SSHConnection) TmuxSendKeys(session, keys string) error {
+	if _, err := c.tmux("send-keys", "-t", session, "-l", keys); err != nil {
+		return err
+	}
+	time.Sleep(time.Duration(constants.DefaultDebounceMs) * time.Millisecond)
+	_, err := c.tmux("send-keys", "-t", session, "Enter")
+	return err
+}
+
+// TmuxCapturePane captures the last N lines from a remote tmux pane.
+func (c *SSHConnection) TmuxCapturePane(session string, lines int) (string, error) {
+	return c.tmux("capture-pane", "-p", "-t", session, "-S", fmt.Sprintf("-%d", lines))
+}
+
+// TmuxHasSession returns true if the remote session exists.
+func (c *SSHConnection) TmuxHasSession(name string) (bool, error) {
+	if _, err := c.tmux("has-session", "-t", "="+name); err != nil {
+		if errors.Is(err, tmux.ErrSessionNotFound) || errors.Is(err, tmux.ErrNoServer) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// TmuxListSessions returns all remote tmux session names.
+func (c *SSHConnection) TmuxListSessions() ([]string, error) {
+	out, err := c.tmux("list-sessions", "-F", "#{session_name}")
+	if err != nil {
+		if errors.Is(err, tmux.ErrNoServer) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// shellQuote quotes s for safe use as a single POSIX shell word.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range s {
+		if !isShellSafe(r) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellCommand joins a command and its arguments into a quoted command line.
+func shellCommand(cmd string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, shellQuote(cmd))
+	for _, a := range args {
+		parts = append(parts, shellQuote(a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// isShellSafe reports whether r never needs quoting in a POSIX shell word.
+func isShellSafe(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+		r == '/' || r == '.' || r == '_' || r == '-' || r == '+' || r == ',' || r == ':' || r == '@' || r == '%'
+}
+
+// sshPool shares SSH clients between connections to the same machine.
+type sshPool struct {
+	mu      sync.Mutex
+	clients map[string]*ssh.Client
+}
+
+// defaultSSHPool is the process-wide pool used by NewSSHConnection.
+var defaultSSHPool = newSSHPool()
+
+func newSSHPool() *sshPool {
+	return &sshPool{clients: make(map[string]*ssh.Client)}
+}
+
+// get returns the pooled client for key, dialing a new one if needed. Dialing
+// happens outside the lock so a slow host doesn't stall calls to other
+// machines; if two callers race, the first client pooled wins.
+func (p *sshPool) get(key string, dial func() (*ssh.Client, error)) (*ssh.Client, error) {
+	p.mu.Lock()
+	c, ok := p.clients[key]
+	p.mu.Unlock()
+	if ok {
+		return c, nil
+	}
+
+	c, err := dial()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pooled, ok := p.clients[key]; ok {
+		_ = c.Close()
+		return pooled, nil
+	}
+	p.clients[key] = c
+	return c, nil
+}
+
+// evict closes and removes client from the pool if it is still the pooled one.
+func (p *sshPool) evict(key string, client *ssh.Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.clients[key]; ok && c == client {
+		delete(p.clients, key)
+	}
+	_ = client.Close()
+}
+
+// closeAll closes every pooled client.
+func (p *sshPool) closeAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, c := range p.clients {
+		_ = c.Close()
+		delete(p.clients, key)
+	}
+}
+
+// CloseSSHConnections closes all pooled SSH clients. Connections created
+// afterwards will redial on first use.
+func CloseSSHConnections() {
+	defaultSSHPool.closeAll()
+}
+
+// Verify SSHConnection implements Connection.
+var _ Connection = (*SSHConnection)(nil)
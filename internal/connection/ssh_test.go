@@ -0,0 +1,488 @@
+package connection
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// testSSHServer is a minimal in-process SSH server that runs "exec" requests
+// with the local /bin/sh. It is enough to exercise SSHConnection end to end.
+type testSSHServer struct {
+	addr    string
+	hostKey ssh.PublicKey
+	dials   atomic.Int32
+}
+
+func startTestSSHServer(t *testing.T, clientKey ssh.PublicKey) *testSSHServer {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) == string(clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown key")
+		},
+	}
+	cfg.AddHostKey(hostSigner)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &testSSHServer{addr: ln.Addr().String(), hostKey: hostSigner.PublicKey()}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			srv.dials.Add(1)
+			go srv.serveConn(nc, cfg)
+		}
+	}()
+	return srv
+}
+
+func (s *testSSHServer) serveConn(nc net.Conn, cfg *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(nc, cfg)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for nch := range chans {
+		if nch.ChannelType() != "session" {
+			_ = nch.Reject(ssh.UnknownChannelType, "unsupported")
+			continue
+		}
+		ch, chReqs, err := nch.Accept()
+		if err != nil {
+			continue
+		}
+		go serveSession(ch, chReqs)
+	}
+}
+
+func serveSession(ch ssh.Channel, reqs <-chan *ssh.Request) {
+	defer ch.Close()
+	for req := range reqs {
+		if req.Type != "exec" {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		var payload struct{ Command string }
+		if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+			_ = req.Reply(false, nil)
+			return
+		}
+		_ = req.Reply(true, nil)
+
+		cmd := exec.Command("sh", "-c", payload.Command)
+		cmd.Stdout = ch
+		cmd.Stderr = ch.Stderr()
+		stdin, _ := cmd.StdinPipe()
+		go func() {
+			_, _ = io.Copy(stdin, ch)
+			_ = stdin.Close()
+		}()
+
+		status := uint32(0)
+		if err := cmd.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+					status = uint32(ws.ExitStatus())
+				} else {
+					status = 1
+				}
+			} else {
+				status = 127
+			}
+		}
+		buf := make([]byte, 4)
+		binary.BigEndian.PutUint32(buf, status)
+		_, _ = ch.SendRequest("exit-status", false, buf)
+		return
+	}
+}
+
+// newTestSSHConnection starts a server and returns a connection to it that
+// uses a private pool.
+func newTestSSHConnection(t *testing.T) (*SSHConnection, *testSSHServer) {
+	t.Helper()
+	_, clientPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientSigner, err := ssh.NewSignerFromKey(clientPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemBlock, err := ssh.MarshalPrivateKey(clientPriv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(pemBlock), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := startTestSSHServer(t, clientSigner.PublicKey())
+	pool := newSSHPool()
+	t.Cleanup(pool.closeAll)
+
+	conn, err := newSSHConnection(SSHConfig{
+		Name:            "vm",
+		Host:            "tester@" + srv.addr,
+		KeyPath:         keyPath,
+		HostKeyCallback: ssh.FixedHostKey(srv.hostKey),
+	}, pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, srv
+}
+
+func TestSSHConnection_FileOperations(t *testing.T) {
+	conn, srv := newTestSSHConnection(t)
+	dir := t.TempDir()
+
+	if conn.IsLocal() {
+		t.Error("IsLocal() = true, want false")
+	}
+	if conn.Name() != "vm" {
+		t.Errorf("Name() = %q, want %q", conn.Name(), "vm")
+	}
+
+	path := filepath.Join(dir, "sub dir", "it's.txt")
+	if err := conn.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := conn.WriteFile(path, []byte("hello\nworld"), 0640); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	data, err := conn.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "hello\nworld" {
+		t.Errorf("ReadFile = %q", data)
+	}
+
+	fi, err := conn.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if fi.Size() != 11 || fi.IsDir() || fi.Mode().Perm() != 0640 || fi.Name() != "it's.txt" {
+		t.Errorf("Stat = size %d dir %v mode %v name %q", fi.Size(), fi.IsDir(), fi.Mode(), fi.Name())
+	}
+	dfi, err := conn.Stat(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("Stat dir: %v", err)
+	}
+	if !dfi.IsDir() {
+		t.Error("Stat dir: IsDir() = false")
+	}
+
+	if ok, err := conn.Exists(path); err != nil || !ok {
+		t.Errorf("Exists = %v, %v; want true", ok, err)
+	}
+
+	matches, err := conn.Glob(filepath.Join(dir, "sub dir", "*.txt"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != 1 || matches[0] != path {
+		t.Errorf("Glob = %v, want [%s]", matches, path)
+	}
+	if matches, err := conn.Glob(filepath.Join(dir, "nothing-*")); err != nil || len(matches) != 0 {
+		t.Errorf("Glob no match = %v, %v", matches, err)
+	}
+	hidden := filepath.Join(dir, "sub dir", ".hidden.txt")
+	if err := os.WriteFile(hidden, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	matches, err = conn.Glob(filepath.Join(dir, "*", "*.txt"))
+	if err != nil {
+		t.Fatalf("Glob dotfiles: %v", err)
+	}
+	if want := []string{hidden, path}; len(matches) != 2 || matches[0] != want[0] || matches[1] != want[1] {
+		t.Errorf("Glob dotfiles = %v, want %v", matches, want)
+	}
+	if err := os.Remove(hidden); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Glob("[bad"); err == nil {
+		t.Error("Glob bad pattern: expected error")
+	}
+
+	if err := conn.Remove(path); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := conn.Remove(path); err != nil {
+		t.Errorf("Remove missing: %v", err)
+	}
+	if ok, _ := conn.Exists(path); ok {
+		t.Error("Exists after Remove = true")
+	}
+
+	var nf *NotFoundError
+	if _, err := conn.ReadFile(path); !errors.As(err, &nf) {
+		t.Errorf("ReadFile missing: got %v, want NotFoundError", err)
+	}
+	if _, err := conn.Stat(path); !errors.As(err, &nf) {
+		t.Errorf("Stat missing: got %v, want NotFoundError", err)
+	}
+
+	if err := conn.RemoveAll(filepath.Join(dir, "sub dir")); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+
+	// All operations above should have shared one pooled client.
+	if got := srv.dials.Load(); got != 1 {
+		t.Errorf("server saw %d dials, want 1 (pooled)", got)
+	}
+}
+
+func TestSSHConnection_Exec(t *testing.T) {
+	conn, _ := newTestSSHConnection(t)
+	dir := t.TempDir()
+
+	out, err := conn.Exec("echo", "a b", "$HOME")
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if string(out) != "a b $HOME\n" {
+		t.Errorf("Exec = %q", out)
+	}
+
+	out, err = conn.ExecDir(dir, "pwd")
+	if err != nil {
+		t.Fatalf("ExecDir: %v", err)
+	}
+	if got := string(out); got != dir+"\n" {
+		t.Errorf("ExecDir = %q, want %q", got, dir+"\n")
+	}
+
+	out, err = conn.ExecEnv(map[string]string{"GT_TEST_VAR": "x y"}, "sh", "-c", "echo $GT_TEST_VAR")
+	if err != nil {
+		t.Fatalf("ExecEnv: %v", err)
+	}
+	if string(out) != "x y\n" {
+		t.Errorf("ExecEnv = %q", out)
+	}
+
+	out, err = conn.Exec("sh", "-c", "echo oops >&2; exit 3")
+	var exitErr *ssh.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitStatus() != 3 {
+		t.Errorf("Exec failing command: err = %v, want exit status 3", err)
+	}
+	if string(out) != "oops\n" {
+		t.Errorf("Exec failing command output = %q", out)
+	}
+}
+
+func TestSSHConnection_ConnectionError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	_, clientPriv, _ := ed25519.GenerateKey(rand.Reader)
+	pemBlock, _ := ssh.MarshalPrivateKey(clientPriv, "")
+	keyPath := filepath.Join(t.TempDir(), "id")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(pemBlock), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := newSSHConnection(SSHConfig{
+		Name:            "dead",
+		Host:            "tester@" + addr,
+		KeyPath:         keyPath,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), //nolint:gosec // test only
+	}, newSSHPool())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = conn.ReadFile("/etc/hostname")
+	var connErr *ConnectionError
+	if !errors.As(err, &connErr) {
+		t.Fatalf("got %v, want ConnectionError", err)
+	}
+	if connErr.Machine != "dead" || connErr.Op != "connect" {
+		t.Errorf("ConnectionError = %+v", connErr)
+	}
+}
+
+func TestParseSSHHost(t *testing.T) {
+	t.Setenv("USER", "fallback")
+	tests := []struct {
+		in       string
+		wantUser string
+		wantAddr string
+		wantErr  bool
+	}{
+		{in: "alice@box", wantUser: "alice", wantAddr: "box:22"},
+		{in: "alice@box:2222", wantUser: "alice", wantAddr: "box:2222"},
+		{in: "box", wantUser: "fallback", wantAddr: "box:22"},
+		{in: "bob@[::1]:2200", wantUser: "bob", wantAddr: "[::1]:2200"},
+		{in: "", wantErr: true},
+		{in: "alice@", wantErr: true},
+	}
+	for _, tt := range tests {
+		user, addr, err := parseSSHHost(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSSHHost(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if user != tt.wantUser || addr != tt.wantAddr {
+			t.Errorf("parseSSHHost(%q) = %q, %q; want %q, %q", tt.in, user, addr, tt.wantUser, tt.wantAddr)
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"":             "''",
+		"plain/path.x": "plain/path.x",
+		"a b":          "'a b'",
+		"it's":         `'it'\''s'`,
+		"$HOME":        "'$HOME'",
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGlobRoot(t *testing.T) {
+	tests := []struct {
+		pattern string
+		root    string
+		depth   int
+	}{
+		{"/a/b/*.txt", "/a/b", 1},
+		{"/a/*/c/*.md", "/a", 3},
+		{"/*", "/", 1},
+		{"*.go", ".", 1},
+		{"sub/[ab]*", "sub", 1},
+		{"/a/b.txt", "/a/b.txt", 0},
+	}
+	for _, tt := range tests {
+		root, depth := globRoot(tt.pattern)
+		if root != tt.root || depth != tt.depth {
+			t.Errorf("globRoot(%q) = %q, %d; want %q, %d", tt.pattern, root, depth, tt.root, tt.depth)
+		}
+	}
+}
+
+func TestSSHPool_DialOutsideLock(t *testing.T) {
+	pool := newSSHPool()
+	slow := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_, _ = pool.get("slow", func() (*ssh.Client, error) {
+			close(started)
+			<-slow
+			return nil, errors.New("unreachable host")
+		})
+	}()
+	<-started
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := pool.get("other", func() (*ssh.Client, error) {
+			return nil, errors.New("dialed")
+		})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil || err.Error() != "dialed" {
+			t.Errorf("get(other) = %v, want the dial error", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("a slow dial to one host blocked the pool")
+	}
+	close(slow)
+}
+
+func TestSSHConnection_Tmux(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	conn, _ := newTestSSHConnection(t)
+	conn.cfg.TmuxSocket = fmt.Sprintf("gt-ssh-test-%d", os.Getpid())
+	t.Cleanup(func() {
+		_ = exec.Command("tmux", "-L", conn.cfg.TmuxSocket, "kill-server").Run()
+	})
+
+	if sessions, err := conn.TmuxListSessions(); err != nil || len(sessions) != 0 {
+		t.Fatalf("TmuxListSessions before start = %v, %v", sessions, err)
+	}
+	if ok, err := conn.TmuxHasSession("gt-ssh-test"); err != nil || ok {
+		t.Fatalf("TmuxHasSession before start = %v, %v", ok, err)
+	}
+
+	if err := conn.TmuxNewSession("gt-ssh-test", t.TempDir()); err != nil {
+		t.Fatalf("TmuxNewSession: %v", err)
+	}
+	if ok, err := conn.TmuxHasSession("gt-ssh-test"); err != nil || !ok {
+		t.Fatalf("TmuxHasSession = %v, %v; want true", ok, err)
+	}
+	sessions, err := conn.TmuxListSessions()
+	if err != nil || len(sessions) != 1 || sessions[0] != "gt-ssh-test" {
+		t.Fatalf("TmuxListSessions = %v, %v", sessions, err)
+	}
+
+	if err := conn.TmuxSendKeys("gt-ssh-test", "echo remote-marker"); err != nil {
+		t.Fatalf("TmuxSendKeys: %v", err)
+	}
+	if _, err := conn.TmuxCapturePane("gt-ssh-test", 50); err != nil {
+		t.Fatalf("TmuxCapturePane: %v", err)
+	}
+
+	if err := conn.TmuxKillSession("gt-ssh-test"); err != nil {
+		t.Fatalf("TmuxKillSession: %v", err)
+	}
+	if err := conn.TmuxKillSession("gt-ssh-test"); err != nil {
+		t.Errorf("TmuxKillSession on missing session: %v", err)
+	}
+	if ok, _ := conn.TmuxHasSession("gt-ssh-test"); ok {
+		t.Error("TmuxHasSession after kill = true")
+	}
+}
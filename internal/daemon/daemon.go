@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -29,9 +30,11 @@ import (
 	"github.com/steveyegge/gastown/internal/feed"
 	gitpkg "github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/ptyd"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/sink"
 	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
@@ -55,6 +58,8 @@ type Daemon struct {
 	beadsStores   map[string]beadsdk.Storage
 	doltServer *DoltServerManager
 	krcPruner  *KRCPruner
+	sinks      *sink.Dispatcher
+	ptyStop    func()
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
@@ -87,6 +92,10 @@ type Daemon struct {
 	otelProvider *telemetry.Provider
 	metrics      *daemonMetrics
 
+	// metricsServer serves /metrics for Prometheus when GT_PROMETHEUS_ADDR
+	// is set. Nil otherwise.
+	metricsServer *http.Server
+
 	// jsonlPushFailures tracks consecutive git push failures for JSONL backup.
 	// Only accessed from heartbeat loop goroutine - no sync needed.
 	jsonlPushFailures int
@@ -99,6 +108,10 @@ type Daemon struct {
 	// lastMaintenanceRun tracks when scheduled maintenance last ran.
 	// Only accessed from heartbeat loop goroutine - no sync needed.
 	lastMaintenanceRun time.Time
+
+	// scriptPlugins holds the script plugins currently running, keyed by
+	// "rig/name", so a slow run isn't started again on the next heartbeat.
+	scriptPlugins sync.Map
 }
 
 // sessionDeath records a detected session death for mass death analysis.
@@ -213,7 +226,22 @@ func New(config *Config) (*Daemon, error) {
 	if otelErr != nil {
 		logger.Printf("Warning: telemetry init failed: %v", otelErr)
 	}
+	// The Prometheus endpoint needs the daemon instruments even when OTLP
+	// export is off.
+	promAddr := os.Getenv(telemetry.EnvPrometheusAddr)
+	if promAddr != "" {
+		if err := telemetry.EnablePrometheus(ctx, "gastown-daemon", ""); err != nil {
+			logger.Printf("Warning: Prometheus metrics init failed: %v", err)
+			promAddr = ""
+		}
+	}
 	var dm *daemonMetrics
+	if promAddr != "" && otelProvider == nil {
+		if dm, err = newDaemonMetrics(); err != nil {
+			logger.Printf("Warning: failed to register daemon metrics: %v", err)
+			dm = nil
+		}
+	}
 	if otelProvider != nil {
 		dm, err = newDaemonMetrics()
 		if err != nil {
@@ -361,6 +389,24 @@ func (d *Daemon) Run() error {
 		}
 	}
 
+	// Start the Prometheus endpoint (opt-in via GT_PROMETHEUS_ADDR)
+	if addr := os.Getenv(telemetry.EnvPrometheusAddr); addr != "" {
+		if err := d.startMetricsServer(addr); err != nil {
+			d.logger.Printf("Warning: failed to start Prometheus endpoint on %s: %v", addr, err)
+		} else {
+			d.logger.Printf("Prometheus endpoint listening on %s/metrics", addr)
+		}
+	}
+
+	// Start notification sinks (settings/sinks.json; idle until configured)
+	d.sinks = sink.NewDispatcher(d.config.TownRoot, d.logger.Printf)
+	if err := d.sinks.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start notification sinks: %v", err)
+	}
+
+	// Host the PTY supervisor when polecats run headless (session_backend "pty")
+	d.startPTYSupervisor()
+
 	// Start dedicated Dolt health check ticker if Dolt server is configured.
 	// This runs at a much higher frequency (default 30s) than the general
 	// heartbeat (3 min) so Dolt crashes are detected quickly.
@@ -1403,6 +1449,24 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("KRC pruner stopped")
 	}
 
+	// Stop notification sinks (undelivered events resume from their cursors)
+	if d.sinks != nil {
+		d.sinks.Stop()
+		d.logger.Println("Notification sinks stopped")
+	}
+
+	// Stop the PTY supervisor (kills the polecat sessions it owns)
+	if d.ptyStop != nil {
+		d.ptyStop()
+		d.logger.Println("PTY supervisor stopped")
+	}
+
+	// Stop the Prometheus endpoint
+	if d.metricsServer != nil {
+		_ = d.metricsServer.Close()
+		d.logger.Println("Prometheus endpoint stopped")
+	}
+
 	// Push Dolt remotes before stopping the server (if patrol is enabled)
 	d.pushDoltRemotes()
 
@@ -1747,8 +1811,9 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	// Build the expected tmux session name
 	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
 
-	// Check if tmux session exists
-	sessionAlive, err := d.tmux.HasSession(sessionName)
+	// Check if the session exists (tmux, or the PTY supervisor)
+	backend := session.PolecatBackend(d.config.TownRoot, d.tmux)
+	sessionAlive, err := backend.HasSession(sessionName)
 	if err != nil {
 		d.logger.Printf("Error checking session %s: %v", sessionName, err)
 		return
@@ -1807,7 +1872,7 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	// TOCTOU guard: re-verify session is still dead before restarting.
 	// Between the initial check and now, the session may have been restarted
 	// by another heartbeat cycle, witness, or the polecat itself.
-	sessionRevived, err := d.tmux.HasSession(sessionName)
+	sessionRevived, err := backend.HasSession(sessionName)
 	if err == nil && sessionRevived {
 		return // Session came back - no restart needed
 	}
@@ -1885,6 +1950,29 @@ func (d *Daemon) emitMassDeathEvent() {
 	d.recentDeaths = nil
 }
 
+// startPTYSupervisor serves headless polecat sessions when town settings
+// select the PTY backend. The sessions live as long as the daemon.
+func (d *Daemon) startPTYSupervisor() {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot))
+	if err != nil || settings.SessionBackend != config.SessionBackendPTY {
+		return
+	}
+	ctx, cancel := context.WithCancel(d.ctx)
+	done := make(chan struct{})
+	socket := ptyd.SocketPath(d.config.TownRoot)
+	go func() {
+		defer close(done)
+		if err := ptyd.NewSupervisor().Serve(ctx, socket); err != nil {
+			d.logger.Printf("Warning: PTY supervisor: %v", err)
+		}
+	}()
+	d.ptyStop = func() {
+		cancel()
+		<-done
+	}
+	d.logger.Printf("PTY supervisor listening on %s", socket)
+}
+
 // restartPolecatSession restarts a crashed polecat session.
 func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string) error {
 	// Check rig operational state before auto-restarting
@@ -1892,6 +1980,12 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 		return fmt.Errorf("cannot restart polecat: %s", reason)
 	}
 
+	// The restart below recreates the session in tmux. Headless polecats are
+	// restarted by the witness, which goes through the polecat session manager.
+	if session.AsTmux(session.PolecatBackend(d.config.TownRoot, d.tmux)) == nil {
+		return fmt.Errorf("cannot restart polecat: daemon auto-restart is tmux-only")
+	}
+
 	// Calculate rig path for agent config resolution
 	rigPath := filepath.Join(d.config.TownRoot, rigName)
 
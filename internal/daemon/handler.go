@@ -193,7 +193,8 @@ func (d *Daemon) reapIdleDogs(mgr *dog.Manager, sm *dog.SessionManager, daemonCf
 }
 
 // dispatchPlugins scans for plugins, evaluates cooldown gates, and dispatches
-// eligible plugins to idle dogs.
+// eligible plugins to idle dogs. Script plugins are run by the daemon directly,
+// whether or not a dog is idle.
 func (d *Daemon) dispatchPlugins(mgr *dog.Manager, sm *dog.SessionManager, rigsConfig *config.RigsConfig) {
 	// Get rig names for scanner
 	var rigNames []string
@@ -217,24 +218,21 @@ func (d *Daemon) dispatchPlugins(mgr *dog.Manager, sm *dog.SessionManager, rigsC
 	recorder := plugin.NewRecorder(d.config.TownRoot)
 	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
 
+	// Script plugins are run by the daemon itself and don't need a dog, so
+	// they go first: running out of dogs must not hold them back.
+	var agentPlugins []*plugin.Plugin
 	for _, p := range plugins {
-		// Only dispatch plugins with cooldown gates.
-		if p.Gate == nil || p.Gate.Type != plugin.GateCooldown {
+		if !d.pluginDue(recorder, p) {
 			continue
 		}
-
-		// Evaluate cooldown: skip if plugin ran recently.
-		if p.Gate.Duration != "" {
-			count, err := recorder.CountRunsSince(p.Name, p.Gate.Duration)
-			if err != nil {
-				d.logger.Printf("Handler: error checking cooldown for plugin %s: %v", p.Name, err)
-				continue
-			}
-			if count > 0 {
-				continue // Still in cooldown
-			}
+		if p.IsScript() {
+			d.startScriptPlugin(p)
+			continue
 		}
+		agentPlugins = append(agentPlugins, p)
+	}
 
+	for _, p := range agentPlugins {
 		// Find an idle dog.
 		idleDog, err := mgr.GetIdleDog()
 		if err != nil {
@@ -282,6 +280,23 @@ func (d *Daemon) dispatchPlugins(mgr *dog.Manager, sm *dog.SessionManager, rigsC
 	}
 }
 
+// pluginDue reports whether a plugin's cooldown gate is open. Plugins with
+// other gates are left to the Deacon.
+func (d *Daemon) pluginDue(recorder *plugin.Recorder, p *plugin.Plugin) bool {
+	if p.Gate == nil || p.Gate.Type != plugin.GateCooldown {
+		return false
+	}
+	if p.Gate.Duration == "" {
+		return true
+	}
+	count, err := recorder.CountRunsSince(p.Name, p.Gate.Duration)
+	if err != nil {
+		d.logger.Printf("Handler: error checking cooldown for plugin %s: %v", p.Name, err)
+		return false
+	}
+	return count == 0 // A run inside the window means it is cooling down
+}
+
 // loadRigsConfig loads the rigs configuration from mayor/rigs.json.
 func (d *Daemon) loadRigsConfig() (*config.RigsConfig, error) {
 	rigsPath := filepath.Join(d.config.TownRoot, "mayor", "rigs.json")
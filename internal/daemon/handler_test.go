@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"os"
@@ -395,3 +396,47 @@ func TestReapIdleDogs_Constants(t *testing.T) {
 		t.Errorf("maxDogPoolSize = %d, want 4", maxDogPoolSize)
 	}
 }
+
+func TestDispatchPlugins_ScriptsRunWithoutIdleDogs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("script plugins run through sh")
+	}
+	townRoot := t.TempDir()
+	d := testHandlerDaemon(t, townRoot)
+	d.ctx = context.Background()
+
+	writePlugin := func(name, execution string) string {
+		dir := filepath.Join(townRoot, "plugins", name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		content := "+++\nname = \"" + name + "\"\n\n[gate]\ntype = \"cooldown\"\n" + execution + "+++\n"
+		if err := os.WriteFile(filepath.Join(dir, "plugin.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	// Several agent plugins, so one is likely listed before the script.
+	for _, name := range []string{"agent-a", "agent-b", "agent-c", "agent-d"} {
+		writePlugin(name, "")
+	}
+	scriptDir := writePlugin("script", "\n[execution]\nmode = \"script\"\nentrypoint = \"touch ran\"\n")
+
+	// The only dog is busy.
+	testSetupDogState(t, townRoot, "alpha", dog.StateWorking, time.Now())
+	rigsConfig := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{}}
+	mgr := dog.NewManager(townRoot, rigsConfig)
+
+	d.dispatchPlugins(mgr, nil, rigsConfig)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(filepath.Join(scriptDir, "ran")); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("script plugin did not run while no dog was idle")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
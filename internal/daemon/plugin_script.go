@@ -0,0 +1,91 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/plugin"
+)
+
+// startScriptPlugin runs a script plugin in the background, unless a run of
+// the same plugin is still in flight. Script plugins don't need a dog or the
+// Deacon: the daemon runs the entrypoint itself and records the result.
+func (d *Daemon) startScriptPlugin(p *plugin.Plugin) {
+	key := p.RigName + "/" + p.Name
+	if _, running := d.scriptPlugins.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+	go func() {
+		defer d.scriptPlugins.Delete(key)
+		d.runScriptPlugin(p)
+	}()
+}
+
+// runScriptPlugin runs a script plugin's entrypoint, records a plugin run bead
+// (which also starts the plugin's cooldown) and escalates failures when the
+// plugin asks for it.
+func (d *Daemon) runScriptPlugin(p *plugin.Plugin) {
+	record := plugin.PluginRunRecord{
+		PluginName: p.Name,
+		RigName:    p.RigName,
+		Result:     plugin.ResultFailure,
+	}
+
+	d.logger.Printf("Handler: running script plugin %s", p.Name)
+	run, err := plugin.RunScript(d.ctx, p, d.config.TownRoot)
+	var summary string
+	if err != nil {
+		summary = err.Error()
+		record.Body = fmt.Sprintf("Script plugin failed to start: %v\n", err)
+	} else {
+		summary = run.Summary()
+		record.Result = run.Result
+		record.Body = run.FormatBody(p)
+	}
+	d.logger.Printf("Handler: script plugin %s %s", p.Name, summary)
+
+	// A run cut short by daemon shutdown isn't the plugin's failure; leave
+	// it unrecorded so it runs again after restart.
+	if d.ctx.Err() != nil {
+		return
+	}
+
+	beadID, err := plugin.NewRecorder(d.config.TownRoot).RecordRun(record)
+	if err != nil {
+		d.logger.Printf("Handler: failed to record run of plugin %s: %v", p.Name, err)
+	}
+
+	if record.Result == plugin.ResultFailure && p.Execution.NotifyOnFailure {
+		d.escalatePluginFailure(p, summary, beadID)
+	}
+}
+
+// escalatePluginFailure escalates a failed script plugin run at the plugin's
+// configured severity (medium by default), pointing at the run bead that
+// holds the script's output.
+func (d *Daemon) escalatePluginFailure(p *plugin.Plugin, summary, beadID string) {
+	severity := strings.ToLower(p.Execution.Severity)
+	if severity == "" {
+		severity = config.SeverityMedium
+	}
+	reason := "The run could not be recorded; see the daemon log."
+	if beadID != "" {
+		reason = fmt.Sprintf("Script output is on run bead %s (bd show %s).", beadID, beadID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.gtPath, "escalate", "-s", severity, //nolint:gosec // G204: args are constructed internally
+		"--source", "plugin:"+p.Name,
+		"-r", reason,
+		fmt.Sprintf("Script plugin %s %s", p.Name, summary))
+	cmd.Dir = d.config.TownRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		d.logger.Printf("Handler: escalation for plugin %s failed: %v (%s)", p.Name, err, strings.TrimSpace(string(output)))
+	}
+}
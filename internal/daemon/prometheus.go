@@ -0,0 +1,271 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/quota"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// townMetricsTTL is how long computed town gauges are served before a
+// scrape recomputes them. Collection shells out to gt and bd, so this keeps
+// aggressive scrape intervals from loading the town.
+const townMetricsTTL = 15 * time.Second
+
+// schedulerStatus is the subset of `gt scheduler status --json` exported
+// as metrics.
+type schedulerStatus struct {
+	Paused         bool                  `json:"paused"`
+	QueuedTotal    int                   `json:"queued_total"`
+	QueuedReady    int                   `json:"queued_ready"`
+	ActivePolecats int                   `json:"active_polecats"`
+	MaxPolecats    int                   `json:"max_polecats"`
+	Limits         []capacity.LimitUsage `json:"limits"`
+}
+
+// TownMetrics computes the town-wide gauges served on /metrics alongside
+// the OTel instruments: scheduler queue depth and capacity, per-rig polecat
+// counts, merge queue phase counts and account quota state. Used by the
+// daemon's Prometheus endpoint and by `gt dashboard --metrics`.
+type TownMetrics struct {
+	townRoot string
+
+	mu       sync.Mutex
+	cached   []telemetry.Sample
+	cachedAt time.Time
+
+	// Data sources; replaced in tests.
+	schedulerStatus func(ctx context.Context) (*schedulerStatus, error)
+	listSessions    func() ([]string, error)
+	listMRs         func(rigPath string) ([]*beads.Issue, error)
+	loadQuota       func() (*config.QuotaState, error)
+}
+
+// NewTownMetrics returns a TownMetrics for the town at townRoot, running
+// gt from gtPath (or $PATH when empty).
+func NewTownMetrics(townRoot, gtPath string) *TownMetrics {
+	if gtPath == "" {
+		gtPath = "gt"
+	}
+	t := tmux.NewTmux()
+	return &TownMetrics{
+		townRoot: townRoot,
+		schedulerStatus: func(ctx context.Context) (*schedulerStatus, error) {
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			defer cancel()
+			cmd := exec.CommandContext(ctx, gtPath, "scheduler", "status", "--json")
+			cmd.Dir = townRoot
+			out, err := cmd.Output()
+			if err != nil {
+				return nil, err
+			}
+			var st schedulerStatus
+			if err := json.Unmarshal(out, &st); err != nil {
+				return nil, err
+			}
+			return &st, nil
+		},
+		// Polecats may be headless (session_backend "pty"); count them
+		// where they run.
+		listSessions: func() ([]string, error) {
+			return session.PolecatBackend(townRoot, t).ListSessions()
+		},
+		listMRs: func(rigPath string) ([]*beads.Issue, error) {
+			return beads.New(rigPath).List(beads.ListOptions{
+				Status:   "open",
+				Label:    "gt:merge-request",
+				Priority: -1,
+			})
+		},
+		loadQuota: quota.NewManager(townRoot).Load,
+	}
+}
+
+// Handler returns the /metrics handler.
+func (m *TownMetrics) Handler() http.Handler {
+	return telemetry.PrometheusHandler(m.Samples)
+}
+
+// Samples returns the town gauges, recomputing them when the cached set is
+// older than townMetricsTTL.
+func (m *TownMetrics) Samples(ctx context.Context) []telemetry.Sample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cached == nil || time.Since(m.cachedAt) > townMetricsTTL {
+		m.cached = m.collect(ctx)
+		m.cachedAt = time.Now()
+	}
+	return m.cached
+}
+
+// collect computes every town gauge. Sources that fail are skipped, and
+// reported through gastown_town_metrics_source_up so a scrape never fails
+// as a whole.
+func (m *TownMetrics) collect(ctx context.Context) []telemetry.Sample {
+	var out []telemetry.Sample
+	add := func(name, help string, value float64, labels ...string) {
+		s := telemetry.Sample{Name: name, Help: help, Value: value}
+		if len(labels) > 0 {
+			s.Labels = make(map[string]string, len(labels)/2)
+			for i := 0; i+1 < len(labels); i += 2 {
+				s.Labels[labels[i]] = labels[i+1]
+			}
+		}
+		out = append(out, s)
+	}
+	up := func(source string, err error) {
+		add("gastown_town_metrics_source_up", "Whether the source of a group of town gauges could be read (1) or not (0)",
+			boolGauge(err == nil), "source", source)
+	}
+
+	// Scheduler queue and capacity
+	st, err := m.schedulerStatus(ctx)
+	up("scheduler", err)
+	if err == nil {
+		add("gastown_scheduler_queue_depth", "Beads waiting in the scheduler queue", float64(st.QueuedTotal), "state", "total")
+		add("gastown_scheduler_queue_depth", "Beads waiting in the scheduler queue", float64(st.QueuedReady), "state", "ready")
+		add("gastown_scheduler_paused", "Whether dispatch is paused (1) or not (0)", boolGauge(st.Paused))
+		add("gastown_scheduler_active_polecats", "Polecats counted against scheduler capacity", float64(st.ActivePolecats))
+		add("gastown_scheduler_max_polecats", "Town-wide polecat limit (0 or less = direct dispatch, no limit)", float64(st.MaxPolecats))
+		for _, l := range st.Limits {
+			add("gastown_scheduler_limit_active", "Polecats counted against a per-rig, per-account or per-agent limit", float64(l.Active), "kind", l.Kind, "key", l.Key)
+			if l.Max > 0 {
+				add("gastown_scheduler_limit_max", "Configured per-rig, per-account or per-agent polecat limit", float64(l.Max), "kind", l.Kind, "key", l.Key)
+			}
+		}
+	}
+
+	// Per-rig polecats and merge queue
+	rigs := m.rigNames()
+	sessions := make(map[string]int)
+	names, err := m.listSessions()
+	if errors.Is(err, tmux.ErrNoServer) {
+		err = nil
+	}
+	up("tmux", err)
+	for _, name := range names {
+		if id, perr := session.ParseSessionName(name); perr == nil && id.Role == session.RolePolecat {
+			sessions[id.Rig]++
+		}
+	}
+	var mqErr error
+	for _, rigName := range rigs {
+		rigPath := filepath.Join(m.townRoot, rigName)
+		polecats, _ := listPolecatWorktrees(filepath.Join(rigPath, "polecats"))
+		add("gastown_rig_polecats", "Polecat worktrees per rig", float64(len(polecats)), "rig", rigName)
+		add("gastown_rig_polecat_sessions", "Running polecat sessions per rig", float64(sessions[rigName]), "rig", rigName)
+
+		issues, err := m.listMRs(rigPath)
+		if err != nil {
+			mqErr = err
+			continue
+		}
+		phases := map[string]int{"ready": 0, "claimed": 0, "blocked": 0}
+		for _, issue := range issues {
+			phases[mrPhase(issue)]++
+		}
+		for _, phase := range []string{"ready", "claimed", "blocked"} {
+			add("gastown_mq_merge_requests", "Open merge requests per rig by phase", float64(phases[phase]), "rig", rigName, "phase", phase)
+		}
+	}
+	up("merge_queue", mqErr)
+
+	// Account quota
+	qs, err := m.loadQuota()
+	up("quota", err)
+	if err == nil {
+		handles := make([]string, 0, len(qs.Accounts))
+		for h := range qs.Accounts {
+			handles = append(handles, h)
+		}
+		sort.Strings(handles)
+		counts := map[config.AccountQuotaStatus]int{config.QuotaStatusAvailable: 0, config.QuotaStatusLimited: 0}
+		for _, h := range handles {
+			st := qs.Accounts[h]
+			counts[st.Status]++
+			add("gastown_quota_account_limited", "Whether an account is rate-limited (1) or not (0)",
+				boolGauge(st.Status == config.QuotaStatusLimited), "account", h)
+		}
+		for _, status := range []config.AccountQuotaStatus{config.QuotaStatusAvailable, config.QuotaStatusLimited} {
+			add("gastown_quota_accounts", "Accounts by quota status", float64(counts[status]), "status", string(status))
+		}
+	}
+
+	return sortSamples(out)
+}
+
+// rigNames returns the registered rigs, sorted.
+func (m *TownMetrics) rigNames() []string {
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(m.townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mrPhase classifies an open MR bead. Only the phases visible in the bead
+// are reported: the Refinery's in-flight phases (preparing, merging) live
+// in its own process, and show up here as claimed.
+func mrPhase(issue *beads.Issue) string {
+	switch {
+	case issue.Assignee != "":
+		return "claimed"
+	case len(issue.BlockedBy) > 0:
+		return "blocked"
+	default:
+		return "ready"
+	}
+}
+
+// sortSamples orders samples by metric name, keeping each family's
+// samples in the order they were added.
+func sortSamples(samples []telemetry.Sample) []telemetry.Sample {
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Name < samples[j].Name })
+	return samples
+}
+
+func boolGauge(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// startMetricsServer serves /metrics on addr until ctx is cancelled.
+func (d *Daemon) startMetricsServer(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", NewTownMetrics(d.config.TownRoot, d.gtPath).Handler())
+	d.metricsServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := d.metricsServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			d.logger.Printf("Prometheus endpoint stopped: %v", err)
+		}
+	}()
+	return nil
+}
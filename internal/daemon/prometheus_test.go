@@ -0,0 +1,108 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+)
+
+// newTestTownMetrics returns TownMetrics over a town with rigs "alpha"
+// (two polecat worktrees) and "beta", and stubbed data sources.
+func newTestTownMetrics(t *testing.T) *TownMetrics {
+	t.Helper()
+	townRoot := t.TempDir()
+	for _, dir := range []string{"mayor", "alpha/polecats/nux", "alpha/polecats/toast", "beta"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rigs := `{"version":1,"rigs":{"alpha":{"git_url":"a"},"beta":{"git_url":"b"}}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(rigs), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return &TownMetrics{
+		townRoot: townRoot,
+		schedulerStatus: func(context.Context) (*schedulerStatus, error) {
+			return &schedulerStatus{
+				QueuedTotal: 5, QueuedReady: 3, ActivePolecats: 2, MaxPolecats: 4,
+				Limits: []capacity.LimitUsage{{Kind: "rig", Key: "alpha", Active: 2, Max: 2}},
+			}, nil
+		},
+		listSessions: func() ([]string, error) { return nil, nil },
+		listMRs: func(rigPath string) ([]*beads.Issue, error) {
+			if filepath.Base(rigPath) == "beta" {
+				return nil, errors.New("bd unavailable")
+			}
+			return []*beads.Issue{
+				{ID: "mr-1"},
+				{ID: "mr-2", Assignee: "alpha/refinery"},
+				{ID: "mr-3", BlockedBy: []string{"gt-conflict"}},
+				{ID: "mr-4"},
+			}, nil
+		},
+		loadQuota: func() (*config.QuotaState, error) {
+			return &config.QuotaState{Accounts: map[string]config.AccountQuotaState{
+				"work":     {Status: config.QuotaStatusLimited},
+				"personal": {Status: config.QuotaStatusAvailable},
+			}}, nil
+		},
+	}
+}
+
+func TestTownMetrics_Handler(t *testing.T) {
+	m := newTestTownMetrics(t)
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+
+	for _, want := range []string{
+		`gastown_scheduler_queue_depth{state="total"} 5`,
+		`gastown_scheduler_queue_depth{state="ready"} 3`,
+		`gastown_scheduler_max_polecats 4`,
+		`gastown_scheduler_limit_active{key="alpha",kind="rig"} 2`,
+		`gastown_rig_polecats{rig="alpha"} 2`,
+		`gastown_rig_polecats{rig="beta"} 0`,
+		`gastown_mq_merge_requests{phase="ready",rig="alpha"} 2`,
+		`gastown_mq_merge_requests{phase="claimed",rig="alpha"} 1`,
+		`gastown_mq_merge_requests{phase="blocked",rig="alpha"} 1`,
+		`gastown_quota_account_limited{account="work"} 1`,
+		`gastown_quota_accounts{status="available"} 1`,
+		`gastown_town_metrics_source_up{source="merge_queue"} 0`,
+		`gastown_town_metrics_source_up{source="scheduler"} 1`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `gastown_mq_merge_requests{phase="ready",rig="beta"}`) {
+		t.Errorf("unreadable rig reported merge requests:\n%s", out)
+	}
+	if n := strings.Count(out, "# TYPE gastown_rig_polecats gauge"); n != 1 {
+		t.Errorf("gastown_rig_polecats family written %d times", n)
+	}
+}
+
+func TestTownMetrics_CachesSamples(t *testing.T) {
+	m := newTestTownMetrics(t)
+	calls := 0
+	status := m.schedulerStatus
+	m.schedulerStatus = func(ctx context.Context) (*schedulerStatus, error) {
+		calls++
+		return status(ctx)
+	}
+
+	m.Samples(context.Background())
+	m.Samples(context.Background())
+	if calls != 1 {
+		t.Errorf("scheduler status read %d times within the TTL, want 1", calls)
+	}
+}
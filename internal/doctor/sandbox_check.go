@@ -0,0 +1,83 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/sandbox"
+)
+
+// SandboxCheck verifies that rigs with sandboxed agents can actually build
+// their sandbox: the backend is installed and the kernel allows the
+// namespaces it needs. A broken sandbox stops those agents from starting.
+type SandboxCheck struct {
+	BaseCheck
+}
+
+// NewSandboxCheck creates a new sandbox tooling check.
+func NewSandboxCheck() *SandboxCheck {
+	return &SandboxCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "sandbox-tooling",
+			CheckDescription: "Check that sandboxed rigs have working bwrap/unshare",
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// checkSandbox is overridden in tests.
+var checkSandbox = sandbox.Check
+
+// Run checks the sandbox backend of every rig that enables sandboxing.
+func (c *SandboxCheck) Run(ctx *CheckContext) *CheckResult {
+	// Group rigs by backend so each backend is probed once.
+	byBackend := make(map[string][]string)
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+		if err != nil || settings.Sandbox == nil || !settings.Sandbox.Enabled {
+			continue
+		}
+		byBackend[settings.Sandbox.Backend] = append(byBackend[settings.Sandbox.Backend], filepath.Base(rigPath))
+	}
+	if len(byBackend) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No rigs use sandboxing",
+		}
+	}
+
+	backends := make([]string, 0, len(byBackend))
+	for b := range byBackend {
+		backends = append(backends, b)
+	}
+	sort.Strings(backends)
+
+	var ok, details []string
+	for _, b := range backends {
+		rigs := strings.Join(byBackend[b], ", ")
+		resolved, err := checkSandbox(b)
+		if err != nil {
+			details = append(details, fmt.Sprintf("%s: %v", rigs, err))
+			continue
+		}
+		ok = append(ok, fmt.Sprintf("%s (%s)", resolved, rigs))
+	}
+	if len(details) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "Sandboxed agents cannot start",
+			Details: details,
+			FixHint: "Install bubblewrap (bwrap) or util-linux 2.38+, and allow unprivileged user namespaces (kernel.unprivileged_userns_clone=1)",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: "Sandbox available: " + strings.Join(ok, "; "),
+	}
+}
@@ -0,0 +1,62 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSandboxCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	writeRig := func(name, settings string) {
+		t.Helper()
+		rigPath := filepath.Join(townRoot, name)
+		if err := os.MkdirAll(filepath.Join(rigPath, "polecats"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if settings == "" {
+			return
+		}
+		if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(settings), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var probed []string
+	orig := checkSandbox
+	defer func() { checkSandbox = orig }()
+	checkSandbox = func(backend string) (string, error) {
+		probed = append(probed, backend)
+		if backend == "bwrap" {
+			return backend, errors.New("sandbox backend bwrap not found in PATH")
+		}
+		return "unshare", nil
+	}
+
+	check := NewSandboxCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+
+	writeRig("plain", "")
+	writeRig("off", `{"type": "rig-settings", "sandbox": {"enabled": false, "backend": "bwrap"}}`)
+	if result := check.Run(ctx); result.Status != StatusOK || len(probed) != 0 {
+		t.Errorf("no sandboxed rigs: status %v, probed %v", result.Status, probed)
+	}
+
+	writeRig("auto", `{"type": "rig-settings", "sandbox": {"enabled": true}}`)
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("auto backend: status %v (%s), want OK", result.Status, result.Message)
+	}
+
+	writeRig("strict", `{"type": "rig-settings", "sandbox": {"enabled": true, "backend": "bwrap"}}`)
+	result := check.Run(ctx)
+	if result.Status != StatusError {
+		t.Fatalf("missing bwrap: status %v, want error", result.Status)
+	}
+	if len(result.Details) != 1 || result.Details[0] != "strict: sandbox backend bwrap not found in PATH" {
+		t.Errorf("details = %v", result.Details)
+	}
+}
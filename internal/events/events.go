@@ -74,6 +74,13 @@ const (
 	TypeSchedulerDispatch       = "scheduler_dispatch"        // Bead dispatched from scheduler
 	TypeSchedulerDispatchFailed = "scheduler_dispatch_failed" // Bead dispatch failed (requeued)
 	TypeSchedulerCloseRetry     = "scheduler_close_retry"     // Context close needed last-resort attempt
+	TypeSchedulerDeadline       = "scheduler_deadline"        // Scheduled bead still queued past its deadline
+
+	// Budget events
+	TypeBudgetThreshold = "budget_threshold" // Spend reached a budget's warning level or limit
+
+	// Guard events
+	TypeGuardDecision = "guard_decision" // tap guard allowed or blocked a tool call
 )
 
 // EventsFile is the name of the raw events log.
@@ -360,6 +367,15 @@ func SchedulerDispatchPayload(beadID, rig, polecat string) map[string]interface{
 	}
 }
 
+// SchedulerDeadlinePayload creates a payload for scheduler deadline events.
+func SchedulerDeadlinePayload(beadID, rig, deadline string) map[string]interface{} {
+	return map[string]interface{}{
+		"bead":     beadID,
+		"rig":      rig,
+		"deadline": deadline,
+	}
+}
+
 // SchedulerDispatchFailedPayload creates a payload for scheduler dispatch failure events.
 func SchedulerDispatchFailedPayload(beadID, rig, errMsg string) map[string]interface{} {
 	return map[string]interface{}{
@@ -368,3 +384,49 @@ func SchedulerDispatchFailedPayload(beadID, rig, errMsg string) map[string]inter
 		"error": errMsg,
 	}
 }
+
+// BudgetThresholdPayload creates a payload for budget threshold events.
+// level is "warn" or "exceeded"; key is empty for the town budget.
+func BudgetThresholdPayload(kind, key, period, level, action string, spentUSD, limitUSD float64) map[string]interface{} {
+	p := map[string]interface{}{
+		"scope":     kind,
+		"level":     level,
+		"spent_usd": spentUSD,
+		"limit_usd": limitUSD,
+	}
+	if key != "" {
+		p["key"] = key
+	}
+	if period != "" {
+		p["period"] = period
+	}
+	if action != "" {
+		p["action"] = action
+	}
+	return p
+}
+
+// GuardDecisionPayload creates a payload for guard decision events.
+// rule is empty when no rule decided (the default allow); command or path
+// are set when the decision matched them.
+func GuardDecisionPayload(guard, tool, action, rule, layer, command, path string, logged []string) map[string]interface{} {
+	p := map[string]interface{}{
+		"guard":  guard,
+		"tool":   tool,
+		"action": action,
+	}
+	if rule != "" {
+		p["rule"] = rule
+		p["layer"] = layer
+	}
+	if command != "" {
+		p["command"] = command
+	}
+	if path != "" {
+		p["path"] = path
+	}
+	if len(logged) > 0 {
+		p["logged"] = logged
+	}
+	return p
+}
@@ -0,0 +1,187 @@
+package events
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Query selects events from the events log. Zero-valued fields match
+// everything. Types and Actors accept shell globs ("scheduler_*",
+// "gastown/polecats/*"); a list matches if any entry matches.
+type Query struct {
+	Types  []string
+	Actors []string
+	Rig    string
+	Bead   string
+	Since  time.Time // inclusive
+	Until  time.Time // exclusive
+	Limit  int       // keep the newest N matches (0 = all)
+}
+
+// beadKeys are the payload keys that carry bead IDs.
+var beadKeys = []string{"bead", "issue", "mr", "related"}
+
+// EventRig returns the rig an event is about: the payload "rig" field, or
+// the first component of a rig-scoped actor ("gastown/witness").
+func EventRig(e Event) string {
+	if rig, ok := e.Payload["rig"].(string); ok && rig != "" {
+		return rig
+	}
+	if i := strings.Index(e.Actor, "/"); i > 0 {
+		return e.Actor[:i]
+	}
+	return ""
+}
+
+// EventBeads returns the bead IDs referenced by an event's payload.
+func EventBeads(e Event) []string {
+	var ids []string
+	for _, k := range beadKeys {
+		if id, ok := e.Payload[k].(string); ok && id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// EventTime returns the parsed event timestamp, or zero if it is invalid.
+func EventTime(e Event) time.Time {
+	t, err := time.Parse(time.RFC3339, e.Timestamp)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// Match reports whether e satisfies every filter in q.
+func (q *Query) Match(e Event) bool {
+	if len(q.Types) > 0 && !matchAny(q.Types, e.Type) {
+		return false
+	}
+	if len(q.Actors) > 0 && !matchAny(q.Actors, e.Actor) {
+		return false
+	}
+	if q.Rig != "" && EventRig(e) != q.Rig {
+		return false
+	}
+	if q.Bead != "" && !contains(EventBeads(e), q.Bead) {
+		return false
+	}
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		ts := EventTime(e)
+		if ts.IsZero() {
+			return false
+		}
+		if !q.Since.IsZero() && ts.Before(q.Since) {
+			return false
+		}
+		if !q.Until.IsZero() && !ts.Before(q.Until) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAny reports whether s matches any of the glob patterns.
+func matchAny(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if p == s {
+			return true
+		}
+		if ok, _ := path.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Group-by fields accepted by GroupBy.
+var GroupFields = []string{"type", "actor", "rig", "bead", "source", "visibility", "hour", "day"}
+
+// Group is one row of a GroupBy aggregation.
+type Group struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// GroupBy counts events by the given field, most frequent first (ties by
+// key). Time buckets ("hour", "day") are sorted chronologically instead.
+// Events with no value for the field are counted under "(none)".
+func GroupBy(evts []Event, field string) ([]Group, error) {
+	keyFn, err := groupKey(field)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, e := range evts {
+		keys := keyFn(e)
+		if len(keys) == 0 {
+			keys = []string{"(none)"}
+		}
+		for _, k := range keys {
+			counts[k]++
+		}
+	}
+
+	groups := make([]Group, 0, len(counts))
+	for k, n := range counts {
+		groups = append(groups, Group{Key: k, Count: n})
+	}
+	chronological := field == "hour" || field == "day"
+	sort.Slice(groups, func(i, j int) bool {
+		if !chronological && groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups, nil
+}
+
+func groupKey(field string) (func(Event) []string, error) {
+	one := func(s string) []string {
+		if s == "" {
+			return nil
+		}
+		return []string{s}
+	}
+	bucket := func(layout string) func(Event) []string {
+		return func(e Event) []string {
+			ts := EventTime(e)
+			if ts.IsZero() {
+				return nil
+			}
+			return []string{ts.UTC().Format(layout)}
+		}
+	}
+	switch field {
+	case "type":
+		return func(e Event) []string { return one(e.Type) }, nil
+	case "actor":
+		return func(e Event) []string { return one(e.Actor) }, nil
+	case "rig":
+		return func(e Event) []string { return one(EventRig(e)) }, nil
+	case "bead":
+		return EventBeads, nil
+	case "source":
+		return func(e Event) []string { return one(e.Source) }, nil
+	case "visibility":
+		return func(e Event) []string { return one(e.Visibility) }, nil
+	case "hour":
+		return bucket("2006-01-02T15:00Z"), nil
+	case "day":
+		return bucket("2006-01-02"), nil
+	}
+	return nil, fmt.Errorf("unknown group-by field %q (valid: %s)", field, strings.Join(GroupFields, ", "))
+}
@@ -0,0 +1,473 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// IndexFile is the name of the segment index kept next to the events log.
+const IndexFile = ".events.idx.json"
+
+const (
+	indexVersion = 1
+
+	// segmentMaxEvents bounds how many events one index segment covers.
+	// Smaller segments skip more precisely but make the index larger.
+	segmentMaxEvents = 2000
+
+	// fingerprintLen is how many bytes of the log are kept to detect rewrites.
+	fingerprintLen = 256
+
+	// maxLineSize matches the scanner buffer used elsewhere for JSONL files.
+	maxLineSize = 1024 * 1024
+)
+
+// Segment summarizes a contiguous byte range of the events log so queries
+// can skip ranges that can't contain a match.
+type Segment struct {
+	Offset  int64    `json:"offset"`
+	End     int64    `json:"end"`
+	Count   int      `json:"count"`
+	MinTime string   `json:"min_time,omitempty"`
+	MaxTime string   `json:"max_time,omitempty"`
+	Types   []string `json:"types,omitempty"`
+	Actors  []string `json:"actors,omitempty"`
+	Rigs    []string `json:"rigs,omitempty"`
+	Beads   []string `json:"beads,omitempty"`
+}
+
+// Index is the segment index over the events log. Head and Tail hold the
+// log's first bytes and the bytes just before Size; if either no longer
+// matches (krc prune rewrites the file), the index is rebuilt.
+type Index struct {
+	Version  int       `json:"version"`
+	Size     int64     `json:"size"`
+	Head     string    `json:"head"`
+	Tail     string    `json:"tail"`
+	Segments []Segment `json:"segments"`
+}
+
+// Store queries the events log through its segment index.
+type Store struct {
+	logPath   string
+	indexPath string
+
+	// indexed is the log offset covered by the last Refresh; Follow starts there.
+	indexed int64
+}
+
+// NewStore returns a Store for the events log in townRoot.
+func NewStore(townRoot string) *Store {
+	return &Store{
+		logPath:   filepath.Join(townRoot, EventsFile),
+		indexPath: filepath.Join(townRoot, IndexFile),
+		indexed:   -1,
+	}
+}
+
+// Refresh brings the index up to date with the log, reading only what was
+// appended since the last refresh. Saving the index is best-effort: a query
+// still works from the in-memory index if the town root isn't writable.
+func (s *Store) Refresh() (*Index, error) {
+	f, err := os.Open(s.logPath)
+	if os.IsNotExist(err) {
+		s.indexed = 0
+		return &Index{Version: indexVersion}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening events log: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat events log: %w", err)
+	}
+
+	idx := s.loadIndex()
+	if !s.indexValid(f, info.Size(), idx) {
+		idx = &Index{Version: indexVersion}
+	}
+	if idx.Size == info.Size() {
+		s.indexed = idx.Size
+		return idx, nil
+	}
+
+	if err := idx.extend(f, info.Size()); err != nil {
+		return nil, err
+	}
+	idx.Head = readAt(f, 0, fingerprintLen)
+	idx.Tail = readAt(f, max(0, idx.Size-fingerprintLen), int(min(idx.Size, fingerprintLen)))
+	s.indexed = idx.Size
+	_ = s.saveIndex(idx)
+	return idx, nil
+}
+
+// loadIndex reads the saved index, or returns an empty one.
+func (s *Store) loadIndex() *Index {
+	data, err := os.ReadFile(s.indexPath)
+	if err != nil {
+		return &Index{Version: indexVersion}
+	}
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return &Index{Version: indexVersion}
+	}
+	return &idx
+}
+
+// indexValid reports whether idx still describes a prefix of the log.
+func (s *Store) indexValid(f *os.File, size int64, idx *Index) bool {
+	if idx.Version != indexVersion || idx.Size > size {
+		return false
+	}
+	if idx.Size == 0 {
+		return true
+	}
+	if readAt(f, 0, len(idx.Head)) != idx.Head {
+		return false
+	}
+	return readAt(f, idx.Size-int64(len(idx.Tail)), len(idx.Tail)) == idx.Tail
+}
+
+func (s *Store) saveIndex(idx *Index) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	tmp := s.indexPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: index is non-sensitive operational data
+		return err
+	}
+	if err := os.Rename(tmp, s.indexPath); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func readAt(f *os.File, off int64, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	buf := make([]byte, n)
+	read, _ := f.ReadAt(buf, off)
+	return string(buf[:read])
+}
+
+// extend indexes complete lines from idx.Size up to size. A trailing line
+// without a newline is still being written and is left for the next refresh.
+func (idx *Index) extend(f *os.File, size int64) error {
+	reader := bufio.NewReaderSize(io.NewSectionReader(f, idx.Size, size-idx.Size), 64*1024)
+
+	var seg *segmentBuilder
+	if n := len(idx.Segments); n > 0 && idx.Segments[n-1].Count < segmentMaxEvents {
+		seg = newSegmentBuilder(idx.Segments[n-1])
+		idx.Segments = idx.Segments[:n-1]
+	}
+
+	offset := idx.Size
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("reading events log: %w", err)
+		}
+		if seg == nil {
+			seg = newSegmentBuilder(Segment{Offset: offset})
+		}
+		offset += int64(len(line))
+		seg.add(bytes.TrimSpace(line), offset)
+		if seg.Count >= segmentMaxEvents {
+			idx.Segments = append(idx.Segments, seg.build())
+			seg = nil
+		}
+	}
+	if seg != nil {
+		idx.Segments = append(idx.Segments, seg.build())
+	}
+	idx.Size = offset
+	return nil
+}
+
+// segmentBuilder accumulates a Segment's value sets while indexing.
+type segmentBuilder struct {
+	Segment
+	types, actors, rigs, beads map[string]bool
+}
+
+func newSegmentBuilder(seg Segment) *segmentBuilder {
+	return &segmentBuilder{
+		Segment: seg,
+		types:   toSet(seg.Types),
+		actors:  toSet(seg.Actors),
+		rigs:    toSet(seg.Rigs),
+		beads:   toSet(seg.Beads),
+	}
+}
+
+func (b *segmentBuilder) add(line []byte, end int64) {
+	b.End = end
+	if len(line) == 0 {
+		return
+	}
+	b.Count++
+	var e Event
+	if err := json.Unmarshal(line, &e); err != nil {
+		return
+	}
+	b.types[e.Type] = true
+	b.actors[e.Actor] = true
+	if rig := EventRig(e); rig != "" {
+		b.rigs[rig] = true
+	}
+	for _, id := range EventBeads(e) {
+		b.beads[id] = true
+	}
+	if ts := EventTime(e); !ts.IsZero() {
+		// Stored in UTC RFC3339, so string order is time order.
+		t := ts.UTC().Format(time.RFC3339)
+		if b.MinTime == "" || t < b.MinTime {
+			b.MinTime = t
+		}
+		if t > b.MaxTime {
+			b.MaxTime = t
+		}
+	}
+}
+
+func (b *segmentBuilder) build() Segment {
+	seg := b.Segment
+	seg.Types = fromSet(b.types)
+	seg.Actors = fromSet(b.actors)
+	seg.Rigs = fromSet(b.rigs)
+	seg.Beads = fromSet(b.beads)
+	return seg
+}
+
+func toSet(list []string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, v := range list {
+		set[v] = true
+	}
+	return set
+}
+
+func fromSet(set map[string]bool) []string {
+	list := make([]string, 0, len(set))
+	for v := range set {
+		list = append(list, v)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// mayMatch reports whether the segment could hold an event matching q.
+func (seg *Segment) mayMatch(q *Query) bool {
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		if seg.MaxTime == "" {
+			return false
+		}
+		maxT, _ := time.Parse(time.RFC3339, seg.MaxTime)
+		minT, _ := time.Parse(time.RFC3339, seg.MinTime)
+		if !q.Since.IsZero() && maxT.Before(q.Since.Truncate(time.Second)) {
+			return false
+		}
+		if !q.Until.IsZero() && !minT.Before(q.Until) {
+			return false
+		}
+	}
+	if len(q.Types) > 0 && !anyMatch(q.Types, seg.Types) {
+		return false
+	}
+	if len(q.Actors) > 0 && !anyMatch(q.Actors, seg.Actors) {
+		return false
+	}
+	if q.Rig != "" && !containsSorted(seg.Rigs, q.Rig) {
+		return false
+	}
+	if q.Bead != "" && !containsSorted(seg.Beads, q.Bead) {
+		return false
+	}
+	return true
+}
+
+func anyMatch(patterns, values []string) bool {
+	for _, v := range values {
+		if matchAny(patterns, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsSorted(list []string, s string) bool {
+	i := sort.SearchStrings(list, s)
+	return i < len(list) && list[i] == s
+}
+
+// Query returns the events matching q in log order. Only segments whose
+// index entry could match are read. With q.Limit set, segments are read
+// newest first and reading stops once enough matches are found.
+func (s *Store) Query(q Query) ([]Event, error) {
+	idx, err := s.Refresh()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(s.logPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening events log: %w", err)
+	}
+	defer f.Close()
+
+	var result []Event
+	if q.Limit > 0 {
+		for i := len(idx.Segments) - 1; i >= 0 && len(result) < q.Limit; i-- {
+			matches, err := readSegment(f, &idx.Segments[i], &q)
+			if err != nil {
+				return nil, err
+			}
+			result = append(matches, result...)
+		}
+		if len(result) > q.Limit {
+			result = result[len(result)-q.Limit:]
+		}
+		return result, nil
+	}
+	for i := range idx.Segments {
+		matches, err := readSegment(f, &idx.Segments[i], &q)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, matches...)
+	}
+	return result, nil
+}
+
+// readSegment returns the events in seg that match q.
+func readSegment(f *os.File, seg *Segment, q *Query) ([]Event, error) {
+	if !seg.mayMatch(q) {
+		return nil, nil
+	}
+	scanner := bufio.NewScanner(io.NewSectionReader(f, seg.Offset, seg.End-seg.Offset))
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	var result []Event
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if q.Match(e) {
+			result = append(result, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("scanning events log: %w", err)
+	}
+	return result, nil
+}
+
+// followPollInterval is how often Follow checks the log for new lines.
+const followPollInterval = 250 * time.Millisecond
+
+// Follow calls fn for each event matching q appended to the log, until ctx
+// is cancelled or fn returns an error. It starts where the last Refresh (or
+// Query) stopped, so nothing is missed between a query and following it, or
+// at the end of the log if the store hasn't been refreshed. If the log
+// shrinks underneath (krc prune rewrites it with older events), following
+// resumes at the new end rather than replaying what was kept.
+func (s *Store) Follow(ctx context.Context, q Query, fn func(Event) error) error {
+	offset := s.indexed
+	if offset < 0 {
+		offset = s.Size()
+	}
+
+	ticker := time.NewTicker(followPollInterval)
+	defer ticker.Stop()
+	for {
+		evts, next, err := s.ReadFrom(offset)
+		if err != nil {
+			return err
+		}
+		offset = next
+		for _, e := range evts {
+			if !q.Match(e) {
+				continue
+			}
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Size returns the current size of the events log (0 if it doesn't exist).
+func (s *Store) Size() int64 {
+	info, err := os.Stat(s.logPath)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// ReadFrom returns the complete events logged at or after offset and the
+// offset just past the last one read; a trailing line still being written
+// is left for the next call. If the log has shrunk below offset (krc prune
+// rewrote it), nothing is returned and next is the new end of the log.
+func (s *Store) ReadFrom(offset int64) ([]Event, int64, error) {
+	f, err := os.Open(s.logPath)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, offset, fmt.Errorf("opening events log: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, offset, fmt.Errorf("stat events log: %w", err)
+	}
+	if info.Size() <= offset {
+		return nil, info.Size(), nil
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(f, offset, info.Size()-offset))
+	var result []Event
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, offset, fmt.Errorf("reading events log: %w", err)
+		}
+		offset += int64(len(line))
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result, offset, nil
+}
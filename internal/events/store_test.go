@@ -0,0 +1,218 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var storeBase = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func appendEvents(t *testing.T, townRoot string, evts ...Event) {
+	t.Helper()
+	f, err := os.OpenFile(filepath.Join(townRoot, EventsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, e := range evts {
+		data, _ := json.Marshal(e)
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func ev(minute int, typ, actor string, payload map[string]interface{}) Event {
+	return Event{
+		Timestamp:  storeBase.Add(time.Duration(minute) * time.Minute).Format(time.RFC3339),
+		Source:     "gt",
+		Type:       typ,
+		Actor:      actor,
+		Payload:    payload,
+		Visibility: VisibilityFeed,
+	}
+}
+
+func types(evts []Event) string {
+	var ts []string
+	for _, e := range evts {
+		ts = append(ts, e.Type)
+	}
+	return strings.Join(ts, ",")
+}
+
+func TestStoreQueryFilters(t *testing.T) {
+	dir := t.TempDir()
+	appendEvents(t, dir,
+		ev(0, TypeSling, "mayor", SlingPayload("gt-1", "gastown")),
+		ev(1, TypeSpawn, "gastown/witness", SpawnPayload("gastown", "Toast")),
+		ev(2, TypeSessionDeath, "daemon", map[string]interface{}{"rig": "beads"}),
+		ev(3, TypeMassDeath, "daemon", MassDeathPayload(3, "5s", nil, "")),
+		ev(4, TypeDone, "gastown/polecats/Toast", DonePayload("gt-1", "polecat/Toast")),
+		ev(5, TypeSchedulerDispatch, "scheduler", SchedulerDispatchPayload("gt-2", "gastown", "Nux")),
+	)
+	store := NewStore(dir)
+
+	tests := []struct {
+		name string
+		q    Query
+		want string
+	}{
+		{"all", Query{}, "sling,spawn,session_death,mass_death,done,scheduler_dispatch"},
+		{"type", Query{Types: []string{TypeMassDeath}}, "mass_death"},
+		{"type glob", Query{Types: []string{"s*"}}, "sling,spawn,session_death,scheduler_dispatch"},
+		{"actor glob", Query{Actors: []string{"gastown/*/*"}}, "done"},
+		{"rig from payload or actor", Query{Rig: "gastown"}, "spawn,done,scheduler_dispatch"},
+		{"bead", Query{Bead: "gt-1"}, "sling,done"},
+		{"time range", Query{Since: storeBase.Add(2 * time.Minute), Until: storeBase.Add(4 * time.Minute)}, "session_death,mass_death"},
+		{"limit keeps newest", Query{Limit: 2}, "done,scheduler_dispatch"},
+		{"combined", Query{Types: []string{"done", "sling"}, Rig: "gastown"}, "done"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Query(tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if types(got) != tt.want {
+				t.Errorf("got %s, want %s", types(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreIndexSkipsSegments(t *testing.T) {
+	dir := t.TempDir()
+	var evts []Event
+	for i := 0; i < segmentMaxEvents*2; i++ {
+		evts = append(evts, ev(i, TypeNudge, "deacon", nil))
+	}
+	evts = append(evts, ev(segmentMaxEvents*2, TypeMassDeath, "daemon", nil))
+	appendEvents(t, dir, evts...)
+
+	store := NewStore(dir)
+	idx, err := store.Refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(idx.Segments) != 3 {
+		t.Fatalf("segments = %d, want 3", len(idx.Segments))
+	}
+	q := Query{Types: []string{TypeMassDeath}}
+	for i, seg := range idx.Segments[:2] {
+		if seg.mayMatch(&q) {
+			t.Errorf("segment %d (nudges only) should be skipped", i)
+		}
+	}
+	q = Query{Since: storeBase.Add(time.Duration(segmentMaxEvents*2) * time.Minute)}
+	if idx.Segments[0].mayMatch(&q) {
+		t.Error("segment 0 is entirely before --since and should be skipped")
+	}
+}
+
+func TestStoreIncrementalAndRewrite(t *testing.T) {
+	dir := t.TempDir()
+	appendEvents(t, dir, ev(0, TypeSling, "mayor", nil))
+	store := NewStore(dir)
+	if _, err := store.Query(Query{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Appended lines are picked up; a partial trailing line is not.
+	appendEvents(t, dir, ev(1, TypeDone, "mayor", nil))
+	f, _ := os.OpenFile(filepath.Join(dir, EventsFile), os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = f.WriteString(`{"ts":"2026-03-01T12:05:00Z","type":"half`)
+	_ = f.Close()
+
+	got, err := NewStore(dir).Query(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types(got) != "sling,done" {
+		t.Errorf("after append: got %s, want sling,done", types(got))
+	}
+
+	// Rewriting the log (as krc prune does) invalidates the saved index even
+	// when the new file is larger.
+	var evts []Event
+	for i := 0; i < 5; i++ {
+		evts = append(evts, ev(10+i, TypeKill, "deacon", nil))
+	}
+	if err := os.Remove(filepath.Join(dir, EventsFile)); err != nil {
+		t.Fatal(err)
+	}
+	appendEvents(t, dir, evts...)
+	got, err = NewStore(dir).Query(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types(got) != "kill,kill,kill,kill,kill" {
+		t.Errorf("after rewrite: got %s", types(got))
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	evts := []Event{
+		ev(0, TypeSling, "mayor", nil),
+		ev(61, TypeSling, "mayor", nil),
+		ev(62, TypeDone, "gastown/polecats/Toast", nil),
+	}
+	groups, err := GroupBy(evts, "type")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 || groups[0] != (Group{"sling", 2}) || groups[1] != (Group{"done", 1}) {
+		t.Errorf("by type = %v", groups)
+	}
+
+	groups, _ = GroupBy(evts, "hour")
+	if len(groups) != 2 || groups[0].Key != "2026-03-01T12:00Z" || groups[1].Count != 2 {
+		t.Errorf("by hour = %v", groups)
+	}
+
+	groups, _ = GroupBy(evts, "rig")
+	if len(groups) != 2 || groups[0] != (Group{"(none)", 2}) {
+		t.Errorf("by rig = %v", groups)
+	}
+
+	if _, err := GroupBy(evts, "color"); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestStoreFollow(t *testing.T) {
+	dir := t.TempDir()
+	appendEvents(t, dir, ev(0, TypeSling, "mayor", nil))
+	store := NewStore(dir)
+	if _, err := store.Query(Query{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Logged between the query and following: must not be missed.
+	appendEvents(t, dir, ev(1, TypeDone, "mayor", nil), ev(2, TypeNudge, "deacon", nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var got []Event
+	err := store.Follow(ctx, Query{Types: []string{TypeDone, TypeKill}}, func(e Event) error {
+		got = append(got, e)
+		if len(got) == 1 {
+			appendEvents(t, dir, ev(3, TypeKill, "deacon", nil))
+		}
+		if len(got) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types(got) != "done,kill" {
+		t.Errorf("followed %s, want done,kill", types(got))
+	}
+}
@@ -0,0 +1,73 @@
+// Package federation implements cross-town references for Gas Town.
+//
+// Peer towns are registered locally (mayor/remotes.json) with the Dolt
+// remote their rig databases are served from. Work units in a peer town are
+// addressed with HOP URIs:
+//
+//	hop://entity/chain/rig/issue-id
+//	hop://steve@example.com/main-town/greenplace/gp-xyz
+//
+// Remote beads are fetched read-only: each referenced peer rig database is
+// cloned into a local cache and queried there. Nothing is ever pushed back.
+//
+// See docs/design/federation.md for the overall design.
+package federation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// HopScheme is the URI scheme for cross-town work unit references.
+const HopScheme = "hop://"
+
+// issueIDPattern restricts issue IDs to characters bd generates. It also keeps
+// IDs safe to embed in SQL string literals.
+var issueIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// HopURI is a parsed hop://entity/chain/rig/issue-id reference.
+type HopURI struct {
+	Entity  string // Person or organization (e.g., "steve@example.com")
+	Chain   string // Town/workspace name of the entity
+	Rig     string // Rig (database) within the chain
+	IssueID string // Bead ID within the rig
+}
+
+// IsHopURI reports whether s uses the hop:// scheme.
+func IsHopURI(s string) bool {
+	return strings.HasPrefix(s, HopScheme)
+}
+
+// ParseHopURI parses a hop://entity/chain/rig/issue-id reference.
+func ParseHopURI(s string) (*HopURI, error) {
+	if !IsHopURI(s) {
+		return nil, fmt.Errorf("invalid hop URI %q: must start with %s", s, HopScheme)
+	}
+	parts := strings.Split(strings.TrimPrefix(s, HopScheme), "/")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("invalid hop URI %q: expected %sentity/chain/rig/issue-id", s, HopScheme)
+	}
+	for i, name := range []string{"entity", "chain", "rig", "issue-id"} {
+		if parts[i] == "" {
+			return nil, fmt.Errorf("invalid hop URI %q: empty %s", s, name)
+		}
+	}
+	if !issueIDPattern.MatchString(parts[3]) {
+		return nil, fmt.Errorf("invalid hop URI %q: malformed issue ID %q", s, parts[3])
+	}
+	if !issueIDPattern.MatchString(parts[2]) {
+		return nil, fmt.Errorf("invalid hop URI %q: malformed rig name %q", s, parts[2])
+	}
+	return &HopURI{
+		Entity:  parts[0],
+		Chain:   parts[1],
+		Rig:     parts[2],
+		IssueID: parts[3],
+	}, nil
+}
+
+// String returns the URI in canonical form.
+func (u *HopURI) String() string {
+	return HopScheme + u.Entity + "/" + u.Chain + "/" + u.Rig + "/" + u.IssueID
+}
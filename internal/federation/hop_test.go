@@ -0,0 +1,59 @@
+package federation
+
+import "testing"
+
+func TestParseHopURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    HopURI
+		wantErr bool
+	}{
+		{
+			name:  "email entity",
+			input: "hop://steve@example.com/main-town/greenplace/gp-xyz",
+			want:  HopURI{Entity: "steve@example.com", Chain: "main-town", Rig: "greenplace", IssueID: "gp-xyz"},
+		},
+		{
+			name:  "dotted issue id",
+			input: "hop://acme/hq/gastown/gt-abc.1",
+			want:  HopURI{Entity: "acme", Chain: "hq", Rig: "gastown", IssueID: "gt-abc.1"},
+		},
+		{name: "wrong scheme", input: "beads://github/acme/backend/ac-123", wantErr: true},
+		{name: "too few segments", input: "hop://acme/hq/gt-abc", wantErr: true},
+		{name: "too many segments", input: "hop://acme/hq/gastown/gt-abc/extra", wantErr: true},
+		{name: "empty chain", input: "hop://acme//gastown/gt-abc", wantErr: true},
+		{name: "sql in issue id", input: "hop://acme/hq/gastown/x'or'1", wantErr: true},
+		{name: "bare id", input: "gt-abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHopURI(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseHopURI(%q) = %+v, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseHopURI(%q) error: %v", tt.input, err)
+			}
+			if *got != tt.want {
+				t.Errorf("ParseHopURI(%q) = %+v, want %+v", tt.input, *got, tt.want)
+			}
+			if got.String() != tt.input {
+				t.Errorf("String() = %q, want %q", got.String(), tt.input)
+			}
+		})
+	}
+}
+
+func TestIsHopURI(t *testing.T) {
+	if !IsHopURI("hop://a/b/c/d") {
+		t.Error("IsHopURI(hop://...) = false")
+	}
+	if IsHopURI("gt-abc") {
+		t.Error("IsHopURI(gt-abc) = true")
+	}
+}
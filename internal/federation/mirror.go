@@ -0,0 +1,53 @@
+package federation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// MirrorLabel marks local beads that mirror a bead from a peer town.
+const MirrorLabel = "gt:federated"
+
+// hopRefKey is the description field that links a mirror to its remote bead.
+const hopRefKey = "hop_ref"
+
+// MirrorDescription builds the description of a local mirror bead. The
+// hop_ref field identifies the remote bead; the remote description follows.
+func MirrorDescription(ri *RemoteIssue) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s\n", hopRefKey, ri.URI)
+	fmt.Fprintf(&b, "remote: %s\n", ri.Remote)
+	fmt.Fprintf(&b, "remote_status: %s\n", ri.Status)
+	if ri.Description != "" {
+		b.WriteString("\n")
+		b.WriteString(ri.Description)
+	}
+	return b.String()
+}
+
+// HopRef returns the hop:// URI a mirror bead points at, or "" if the bead is
+// not a mirror.
+func HopRef(issue *beads.Issue) string {
+	if issue == nil {
+		return ""
+	}
+	for _, line := range strings.Split(issue.Description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && strings.TrimSpace(key) == hopRefKey {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// FindMirror returns the mirror of uri among issues, or nil.
+func FindMirror(issues []*beads.Issue, uri string) *beads.Issue {
+	for _, issue := range issues {
+		if HopRef(issue) == uri {
+			return issue
+		}
+	}
+	return nil
+}
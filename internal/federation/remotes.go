@@ -0,0 +1,164 @@
+package federation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// CurrentRemotesVersion is the schema version of mayor/remotes.json.
+const CurrentRemotesVersion = 1
+
+// ErrRemoteNotFound indicates no registered peer matches a name or URI.
+var ErrRemoteNotFound = errors.New("remote not found")
+
+// remoteNamePattern matches valid local names for peer towns.
+var remoteNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// Remote is a registered peer town.
+type Remote struct {
+	// Name is the local alias for the peer (e.g., "acme").
+	Name string `json:"name"`
+
+	// Entity is the peer's owner identity, the first hop:// path segment
+	// (matches the peer's town.json "owner").
+	Entity string `json:"entity"`
+
+	// Chain is the peer's town name, the second hop:// path segment
+	// (matches the peer's town.json "name").
+	Chain string `json:"chain"`
+
+	// DoltURL is the base Dolt remote URL the peer serves its databases from.
+	// The database for rig R is fetched from DoltURL + "/" + R, e.g.
+	// "http://town-a.example.com:8000" or "https://doltremoteapi.dolthub.com/acme".
+	DoltURL string `json:"dolt_url"`
+
+	// AddedAt is when the peer was registered.
+	AddedAt time.Time `json:"added_at"`
+}
+
+// DatabaseURL returns the Dolt remote URL for a rig database in this peer.
+func (r *Remote) DatabaseURL(rig string) string {
+	return strings.TrimRight(r.DoltURL, "/") + "/" + rig
+}
+
+// URI returns the hop:// URI for an issue in one of this peer's rigs.
+func (r *Remote) URI(rig, issueID string) string {
+	return (&HopURI{Entity: r.Entity, Chain: r.Chain, Rig: rig, IssueID: issueID}).String()
+}
+
+// Remotes is the on-disk registry of peer towns (mayor/remotes.json).
+type Remotes struct {
+	Version int                `json:"version"`
+	Remotes map[string]*Remote `json:"remotes"`
+}
+
+// RemotesPath returns the path of the peer registry for a town.
+func RemotesPath(townRoot string) string {
+	return filepath.Join(townRoot, "mayor", "remotes.json")
+}
+
+// LoadRemotes loads the peer registry. A missing file yields an empty registry.
+func LoadRemotes(townRoot string) (*Remotes, error) {
+	r := &Remotes{Version: CurrentRemotesVersion, Remotes: make(map[string]*Remote)}
+	data, err := os.ReadFile(RemotesPath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return r, nil
+		}
+		return nil, fmt.Errorf("reading remotes: %w", err)
+	}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("parsing remotes: %w", err)
+	}
+	if r.Remotes == nil {
+		r.Remotes = make(map[string]*Remote)
+	}
+	for name, remote := range r.Remotes {
+		remote.Name = name
+	}
+	return r, nil
+}
+
+// SaveRemotes writes the peer registry atomically.
+func SaveRemotes(townRoot string, r *Remotes) error {
+	if err := os.MkdirAll(filepath.Dir(RemotesPath(townRoot)), 0755); err != nil {
+		return fmt.Errorf("creating mayor directory: %w", err)
+	}
+	r.Version = CurrentRemotesVersion
+	return util.AtomicWriteJSON(RemotesPath(townRoot), r)
+}
+
+// Add registers a peer. An existing peer with the same name is replaced; a
+// different peer with the same entity/chain is rejected since hop:// URIs
+// would become ambiguous.
+func (r *Remotes) Add(remote *Remote) error {
+	if !remoteNamePattern.MatchString(remote.Name) {
+		return fmt.Errorf("invalid remote name %q: use letters, digits, '-' or '_'", remote.Name)
+	}
+	if remote.Entity == "" || strings.Contains(remote.Entity, "/") {
+		return fmt.Errorf("invalid entity %q: must be non-empty and contain no '/'", remote.Entity)
+	}
+	if remote.Chain == "" || strings.Contains(remote.Chain, "/") {
+		return fmt.Errorf("invalid chain %q: must be non-empty and contain no '/'", remote.Chain)
+	}
+	if remote.DoltURL == "" {
+		return fmt.Errorf("dolt URL is required")
+	}
+	for name, existing := range r.Remotes {
+		if name != remote.Name && existing.Entity == remote.Entity && existing.Chain == remote.Chain {
+			return fmt.Errorf("%s/%s is already registered as remote %q", remote.Entity, remote.Chain, name)
+		}
+	}
+	if remote.AddedAt.IsZero() {
+		remote.AddedAt = time.Now().UTC()
+	}
+	r.Remotes[remote.Name] = remote
+	return nil
+}
+
+// Remove unregisters a peer by name.
+func (r *Remotes) Remove(name string) error {
+	if _, ok := r.Remotes[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrRemoteNotFound, name)
+	}
+	delete(r.Remotes, name)
+	return nil
+}
+
+// Get returns a peer by name.
+func (r *Remotes) Get(name string) (*Remote, error) {
+	remote, ok := r.Remotes[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRemoteNotFound, name)
+	}
+	return remote, nil
+}
+
+// ForURI returns the peer that serves the entity/chain of a hop:// URI.
+func (r *Remotes) ForURI(u *HopURI) (*Remote, error) {
+	for _, remote := range r.Remotes {
+		if remote.Entity == u.Entity && remote.Chain == u.Chain {
+			return remote, nil
+		}
+	}
+	return nil, fmt.Errorf("%w for %s/%s (register it with 'gt remote add')", ErrRemoteNotFound, u.Entity, u.Chain)
+}
+
+// List returns all peers sorted by name.
+func (r *Remotes) List() []*Remote {
+	list := make([]*Remote, 0, len(r.Remotes))
+	for _, remote := range r.Remotes {
+		list = append(list, remote)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
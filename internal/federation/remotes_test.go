@@ -0,0 +1,86 @@
+package federation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRemotes_SaveLoadRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+
+	empty, err := LoadRemotes(townRoot)
+	if err != nil {
+		t.Fatalf("LoadRemotes on missing file: %v", err)
+	}
+	if len(empty.List()) != 0 {
+		t.Fatalf("expected empty registry, got %v", empty.List())
+	}
+
+	if err := empty.Add(&Remote{Name: "acme", Entity: "ops@acme.com", Chain: "main", DoltURL: "http://acme:8000/"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := empty.Add(&Remote{Name: "beta", Entity: "beta", Chain: "hq", DoltURL: "http://beta:8000"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := SaveRemotes(townRoot, empty); err != nil {
+		t.Fatalf("SaveRemotes: %v", err)
+	}
+
+	loaded, err := LoadRemotes(townRoot)
+	if err != nil {
+		t.Fatalf("LoadRemotes: %v", err)
+	}
+	list := loaded.List()
+	if len(list) != 2 || list[0].Name != "acme" || list[1].Name != "beta" {
+		t.Fatalf("List() = %+v", list)
+	}
+	if list[0].AddedAt.IsZero() {
+		t.Error("AddedAt not set")
+	}
+	if got := list[0].DatabaseURL("backend"); got != "http://acme:8000/backend" {
+		t.Errorf("DatabaseURL = %q", got)
+	}
+
+	u, _ := ParseHopURI("hop://ops@acme.com/main/backend/be-1")
+	r, err := loaded.ForURI(u)
+	if err != nil || r.Name != "acme" {
+		t.Errorf("ForURI = %v, %v; want acme", r, err)
+	}
+	u.Chain = "other"
+	if _, err := loaded.ForURI(u); !errors.Is(err, ErrRemoteNotFound) {
+		t.Errorf("ForURI unknown chain: err = %v, want ErrRemoteNotFound", err)
+	}
+
+	if err := loaded.Remove("acme"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := loaded.Remove("acme"); !errors.Is(err, ErrRemoteNotFound) {
+		t.Errorf("Remove twice: err = %v, want ErrRemoteNotFound", err)
+	}
+}
+
+func TestRemotes_AddValidation(t *testing.T) {
+	r := &Remotes{Remotes: make(map[string]*Remote)}
+	if err := r.Add(&Remote{Name: "acme", Entity: "e", Chain: "c", DoltURL: "http://x"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	bad := []*Remote{
+		{Name: "bad name", Entity: "e", Chain: "c2", DoltURL: "http://x"},
+		{Name: "n", Entity: "", Chain: "c2", DoltURL: "http://x"},
+		{Name: "n", Entity: "e/f", Chain: "c2", DoltURL: "http://x"},
+		{Name: "n", Entity: "e", Chain: "", DoltURL: "http://x"},
+		{Name: "n", Entity: "e", Chain: "c2", DoltURL: ""},
+		{Name: "dup", Entity: "e", Chain: "c", DoltURL: "http://y"}, // same entity/chain as acme
+	}
+	for _, remote := range bad {
+		if err := r.Add(remote); err == nil {
+			t.Errorf("Add(%+v) succeeded, want error", remote)
+		}
+	}
+
+	// Re-adding under the same name updates in place.
+	if err := r.Add(&Remote{Name: "acme", Entity: "e", Chain: "c", DoltURL: "http://z"}); err != nil {
+		t.Errorf("re-Add same name: %v", err)
+	}
+}
@@ -0,0 +1,225 @@
+package federation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// DefaultCacheMaxAge is how long a cached peer database is used before it is
+// pulled again.
+const DefaultCacheMaxAge = 5 * time.Minute
+
+// fetchedMarker is touched in a cache clone after each successful fetch.
+const fetchedMarker = ".gt-fetched"
+
+// DoltCLI abstracts the dolt subprocess calls used to read peer databases.
+type DoltCLI interface {
+	// Clone clones remoteURL into dir.
+	Clone(remoteURL, dir string) error
+	// Pull updates an existing clone from its origin.
+	Pull(dir string) error
+	// Query runs a read-only SQL query in dir and returns `dolt sql -r json` output.
+	Query(dir, query string) ([]byte, error)
+}
+
+// RemoteIssue is a bead fetched from a peer town.
+type RemoteIssue struct {
+	*beads.Issue
+
+	// URI is the canonical hop:// reference of the bead.
+	URI string `json:"uri"`
+
+	// Remote is the local name of the peer the bead came from.
+	Remote string `json:"remote"`
+
+	// Stale is true when the peer could not be reached and the bead was read
+	// from an older cached copy.
+	Stale bool `json:"stale,omitempty"`
+}
+
+// Resolver resolves hop:// references against registered peer towns.
+type Resolver struct {
+	TownRoot string
+	Remotes  *Remotes
+	CLI      DoltCLI
+
+	// MaxAge bounds how old a cached peer database may be before it is pulled.
+	MaxAge time.Duration
+
+	now func() time.Time
+}
+
+// NewResolver creates a Resolver for a town using the real dolt CLI.
+func NewResolver(townRoot string) (*Resolver, error) {
+	remotes, err := LoadRemotes(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return &Resolver{
+		TownRoot: townRoot,
+		Remotes:  remotes,
+		CLI:      &execDoltCLI{},
+		MaxAge:   DefaultCacheMaxAge,
+	}, nil
+}
+
+// CacheDir returns the directory holding read-only clones of peer databases.
+func CacheDir(townRoot string) string {
+	return filepath.Join(townRoot, ".federation")
+}
+
+// cloneDir returns the cache clone directory for one peer rig database.
+func (r *Resolver) cloneDir(remote *Remote, rig string) string {
+	return filepath.Join(CacheDir(r.TownRoot), remote.Name, rig)
+}
+
+func (r *Resolver) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// Resolve fetches the bead referenced by a hop:// URI from its peer town.
+func (r *Resolver) Resolve(ref string) (*RemoteIssue, error) {
+	u, err := ParseHopURI(ref)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := r.Remotes.ForURI(u)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := r.cloneDir(remote, u.Rig)
+	stale, err := r.sync(remote, u.Rig, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	issue, err := r.queryIssue(dir, u.IssueID)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s from remote %s: %w", u.IssueID, remote.Name, err)
+	}
+	return &RemoteIssue{Issue: issue, URI: u.String(), Remote: remote.Name, Stale: stale}, nil
+}
+
+// sync makes sure dir holds a reasonably fresh clone of the peer rig database.
+// When a refresh fails but an older clone exists, the old clone is used and
+// stale is reported true.
+func (r *Resolver) sync(remote *Remote, rig, dir string) (stale bool, err error) {
+	marker := filepath.Join(dir, fetchedMarker)
+
+	if _, statErr := os.Stat(filepath.Join(dir, ".dolt")); statErr != nil {
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return false, fmt.Errorf("creating federation cache: %w", err)
+		}
+		if err := r.CLI.Clone(remote.DatabaseURL(rig), dir); err != nil {
+			return false, fmt.Errorf("fetching %s from remote %s: %w", rig, remote.Name, err)
+		}
+		return false, touch(marker)
+	}
+
+	maxAge := r.MaxAge
+	if maxAge == 0 {
+		maxAge = DefaultCacheMaxAge
+	}
+	if fi, statErr := os.Stat(marker); statErr == nil && r.clock().Sub(fi.ModTime()) < maxAge {
+		return false, nil
+	}
+	if err := r.CLI.Pull(dir); err != nil {
+		return true, nil
+	}
+	return false, touch(marker)
+}
+
+// queryIssue reads one issue (and its labels) from a cached peer database.
+func (r *Resolver) queryIssue(dir, id string) (*beads.Issue, error) {
+	if !issueIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("malformed issue ID %q", id)
+	}
+
+	out, err := r.CLI.Query(dir, fmt.Sprintf(
+		"SELECT id, title, description, status, priority, issue_type, assignee, created_at, updated_at, closed_at "+
+			"FROM issues WHERE id = '%s'", id))
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Rows []*beads.Issue `json:"rows"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("parsing dolt output: %w", err)
+	}
+	if len(result.Rows) == 0 {
+		return nil, beads.ErrNotFound
+	}
+	issue := result.Rows[0]
+
+	// Labels live in their own table; a failure here shouldn't hide the issue.
+	if out, err := r.CLI.Query(dir, fmt.Sprintf("SELECT label FROM labels WHERE issue_id = '%s' ORDER BY label", id)); err == nil {
+		var labels struct {
+			Rows []struct {
+				Label string `json:"label"`
+			} `json:"rows"`
+		}
+		if json.Unmarshal(out, &labels) == nil {
+			for _, row := range labels.Rows {
+				issue.Labels = append(issue.Labels, row.Label)
+			}
+		}
+	}
+	return issue, nil
+}
+
+func touch(path string) error {
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err == nil {
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return os.WriteFile(path, nil, 0644)
+}
+
+// execDoltCLI implements DoltCLI using real dolt subprocess calls.
+type execDoltCLI struct{}
+
+func (e *execDoltCLI) Clone(remoteURL, dir string) error {
+	cmd := exec.Command("dolt", "clone", remoteURL, dir)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("dolt clone %s: %w (%s)", remoteURL, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (e *execDoltCLI) Pull(dir string) error {
+	cmd := exec.Command("dolt", "pull")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("dolt pull: %w (%s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (e *execDoltCLI) Query(dir, query string) ([]byte, error) {
+	cmd := exec.Command("dolt", "sql", "-r", "json", "-q", query)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("dolt sql: %w (%s)", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("dolt sql: %w", err)
+	}
+	return output, nil
+}
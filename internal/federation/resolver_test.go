@@ -0,0 +1,145 @@
+package federation
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// fakeDoltCLI is a test double for DoltCLI.
+type fakeDoltCLI struct {
+	Calls    []string
+	Rows     string // JSON returned for the issues query
+	Labels   string // JSON returned for the labels query
+	PullErr  error
+	CloneErr error
+}
+
+func (f *fakeDoltCLI) Clone(remoteURL, dir string) error {
+	f.Calls = append(f.Calls, "clone "+remoteURL)
+	if f.CloneErr != nil {
+		return f.CloneErr
+	}
+	return os.MkdirAll(filepath.Join(dir, ".dolt"), 0755)
+}
+
+func (f *fakeDoltCLI) Pull(dir string) error {
+	f.Calls = append(f.Calls, "pull")
+	return f.PullErr
+}
+
+func (f *fakeDoltCLI) Query(dir, query string) ([]byte, error) {
+	if strings.Contains(query, "FROM labels") {
+		f.Calls = append(f.Calls, "labels")
+		return []byte(f.Labels), nil
+	}
+	f.Calls = append(f.Calls, "query")
+	return []byte(f.Rows), nil
+}
+
+func newTestResolver(t *testing.T, cli *fakeDoltCLI) *Resolver {
+	t.Helper()
+	remotes := &Remotes{Remotes: make(map[string]*Remote)}
+	if err := remotes.Add(&Remote{Name: "acme", Entity: "ops@acme.com", Chain: "main", DoltURL: "http://acme:8000"}); err != nil {
+		t.Fatal(err)
+	}
+	return &Resolver{TownRoot: t.TempDir(), Remotes: remotes, CLI: cli, MaxAge: time.Minute}
+}
+
+const testRef = "hop://ops@acme.com/main/backend/be-x1"
+
+func TestResolver_Resolve(t *testing.T) {
+	cli := &fakeDoltCLI{
+		Rows:   `{"rows":[{"id":"be-x1","title":"Fix login","status":"open","priority":1,"issue_type":"bug","closed_at":null}]}`,
+		Labels: `{"rows":[{"label":"area:auth"},{"label":"urgent"}]}`,
+	}
+	r := newTestResolver(t, cli)
+
+	issue, err := r.Resolve(testRef)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if issue.ID != "be-x1" || issue.Title != "Fix login" || issue.Priority != 1 || issue.Type != "bug" {
+		t.Errorf("issue = %+v", issue.Issue)
+	}
+	if issue.URI != testRef || issue.Remote != "acme" || issue.Stale {
+		t.Errorf("URI/Remote/Stale = %q/%q/%v", issue.URI, issue.Remote, issue.Stale)
+	}
+	if strings.Join(issue.Labels, ",") != "area:auth,urgent" {
+		t.Errorf("Labels = %v", issue.Labels)
+	}
+	if got := strings.Join(cli.Calls, ","); got != "clone http://acme:8000/backend,query,labels" {
+		t.Errorf("calls = %s", got)
+	}
+
+	// A fresh cache is reused without pulling.
+	cli.Calls = nil
+	if _, err := r.Resolve(testRef); err != nil {
+		t.Fatalf("Resolve (cached): %v", err)
+	}
+	if got := strings.Join(cli.Calls, ","); got != "query,labels" {
+		t.Errorf("cached calls = %s", got)
+	}
+
+	// An expired cache is pulled; a failed pull falls back to the stale copy.
+	r.now = func() time.Time { return time.Now().Add(time.Hour) }
+	cli.Calls = nil
+	cli.PullErr = errors.New("connection refused")
+	issue, err = r.Resolve(testRef)
+	if err != nil {
+		t.Fatalf("Resolve (stale): %v", err)
+	}
+	if !issue.Stale {
+		t.Error("Stale = false after failed pull")
+	}
+	if cli.Calls[0] != "pull" {
+		t.Errorf("expected pull first, got %v", cli.Calls)
+	}
+}
+
+func TestResolver_Errors(t *testing.T) {
+	cli := &fakeDoltCLI{Rows: `{"rows":[]}`}
+	r := newTestResolver(t, cli)
+
+	if _, err := r.Resolve(testRef); !errors.Is(err, beads.ErrNotFound) {
+		t.Errorf("missing issue: err = %v, want ErrNotFound", err)
+	}
+	if _, err := r.Resolve("hop://someone/else/backend/be-x1"); !errors.Is(err, ErrRemoteNotFound) {
+		t.Errorf("unknown peer: err = %v, want ErrRemoteNotFound", err)
+	}
+
+	cli2 := &fakeDoltCLI{CloneErr: errors.New("no such database")}
+	r2 := newTestResolver(t, cli2)
+	if _, err := r2.Resolve(testRef); err == nil || !strings.Contains(err.Error(), "no such database") {
+		t.Errorf("clone failure: err = %v", err)
+	}
+}
+
+func TestMirrorDescription(t *testing.T) {
+	ri := &RemoteIssue{
+		Issue:  &beads.Issue{ID: "be-x1", Status: "open", Description: "Body text\nwith lines"},
+		URI:    testRef,
+		Remote: "acme",
+	}
+	desc := MirrorDescription(ri)
+	mirror := &beads.Issue{ID: "hq-1", Description: desc}
+	if got := HopRef(mirror); got != testRef {
+		t.Errorf("HopRef = %q, want %q", got, testRef)
+	}
+	if !strings.HasSuffix(desc, "\n\nBody text\nwith lines") {
+		t.Errorf("description does not end with remote body: %q", desc)
+	}
+
+	other := &beads.Issue{ID: "hq-2", Description: "unrelated"}
+	if got := FindMirror([]*beads.Issue{other, mirror}, testRef); got != mirror {
+		t.Errorf("FindMirror = %v, want mirror", got)
+	}
+	if got := FindMirror([]*beads.Issue{other}, testRef); got != nil {
+		t.Errorf("FindMirror = %v, want nil", got)
+	}
+}
@@ -0,0 +1,201 @@
+// Package forge talks to code-hosting forges (GitHub and compatible APIs) so
+// the refinery can land work through pull requests on protected branches
+// instead of pushing to the target directly.
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotMergeable is returned by MergePullRequest when the forge refuses the
+// merge (failing checks, missing reviews, conflicts).
+var ErrNotMergeable = errors.New("pull request is not mergeable")
+
+// ErrHeadChanged is returned by MergePullRequest when the pull request head
+// no longer matches the SHA the caller tested.
+var ErrHeadChanged = errors.New("pull request head changed")
+
+// Forge is the subset of a forge API the refinery needs.
+type Forge interface {
+	// EnsurePullRequest opens a pull request from spec.Head into spec.Base,
+	// or updates the title and body of the open one if it already exists.
+	EnsurePullRequest(ctx context.Context, spec PullRequestSpec) (*PullRequest, error)
+
+	// GetPullRequest returns the current state of a pull request.
+	GetPullRequest(ctx context.Context, number int) (*PullRequest, error)
+
+	// MergePullRequest merges a pull request and returns the resulting
+	// commit SHA on the base branch.
+	MergePullRequest(ctx context.Context, number int, opts MergeOptions) (string, error)
+}
+
+// PullRequestSpec describes the pull request to open or update.
+type PullRequestSpec struct {
+	Head  string // Branch holding the changes
+	Base  string // Branch to merge into
+	Title string
+	Body  string
+}
+
+// MergeOptions controls how a pull request is merged.
+type MergeOptions struct {
+	Method        string // "merge", "squash" or "rebase"
+	SHA           string // Expected head SHA; the merge fails if the head moved
+	CommitTitle   string
+	CommitMessage string
+}
+
+// Merge method constants for MergeOptions.Method.
+const (
+	MergeMethodMerge  = "merge"
+	MergeMethodSquash = "squash"
+	MergeMethodRebase = "rebase"
+)
+
+// ValidMergeMethod reports whether m is a known merge method.
+func ValidMergeMethod(m string) bool {
+	switch m {
+	case MergeMethodMerge, MergeMethodSquash, MergeMethodRebase:
+		return true
+	}
+	return false
+}
+
+// PullRequest is a forge-neutral view of a pull request.
+type PullRequest struct {
+	Number         int
+	URL            string
+	State          string // "open" or "closed"
+	Merged         bool
+	MergeCommitSHA string
+	Mergeable      *bool  // nil while the forge is still computing it
+	MergeableState string // GitHub's mergeable_state: clean, blocked, behind, dirty, ...
+	HeadRef        string
+	HeadSHA        string
+	BaseRef        string
+
+	// Filled in when MergeableState is "blocked", to tell why.
+	Checks         CheckState // Required checks on the head commit
+	FailedChecks   []string   // Names of failed or errored required checks
+	ReviewDecision string     // ReviewApproved, ReviewChangesRequested, ReviewRequired or ""
+}
+
+// CheckState aggregates the required checks of a pull request head.
+type CheckState string
+
+// CheckState values. The zero value means the checks are unknown.
+const (
+	ChecksPending CheckState = "pending"
+	ChecksSuccess CheckState = "success"
+	ChecksFailure CheckState = "failure"
+)
+
+// Review decisions for PullRequest.ReviewDecision.
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+	ReviewRequired         = "review_required"
+)
+
+// Status classifies a pull request for a merge loop.
+type Status int
+
+const (
+	// StatusPending means checks, reviews or the mergeability computation
+	// are still outstanding.
+	StatusPending Status = iota
+	// StatusMergeable means the pull request can be merged now.
+	StatusMergeable
+	// StatusMerged means the pull request was merged (possibly by someone else).
+	StatusMerged
+	// StatusConflict means the head conflicts with the base branch.
+	StatusConflict
+	// StatusBehind means the base moved and branch protection requires the
+	// head to be up to date before merging.
+	StatusBehind
+	// StatusClosed means the pull request was closed without merging.
+	StatusClosed
+	// StatusChecksFailed means a required check failed or errored on the head.
+	StatusChecksFailed
+	// StatusChangesRequested means a reviewer requested changes.
+	StatusChangesRequested
+)
+
+// String returns the status name.
+func (s Status) String() string {
+	switch s {
+	case StatusPending:
+		return "pending"
+	case StatusMergeable:
+		return "mergeable"
+	case StatusMerged:
+		return "merged"
+	case StatusConflict:
+		return "conflict"
+	case StatusBehind:
+		return "behind"
+	case StatusClosed:
+		return "closed"
+	case StatusChecksFailed:
+		return "checks_failed"
+	case StatusChangesRequested:
+		return "changes_requested"
+	}
+	return fmt.Sprintf("status(%d)", int(s))
+}
+
+// Status returns the merge-loop status of the pull request.
+func (pr *PullRequest) Status() Status {
+	if pr.Merged {
+		return StatusMerged
+	}
+	if pr.State == "closed" {
+		return StatusClosed
+	}
+	switch pr.MergeableState {
+	case "clean", "unstable", "has_hooks":
+		if pr.Mergeable == nil || *pr.Mergeable {
+			return StatusMergeable
+		}
+	case "dirty":
+		return StatusConflict
+	case "behind":
+		return StatusBehind
+	case "blocked":
+		// Blocked by branch protection: only outstanding checks or reviews
+		// are worth waiting for.
+		if pr.Checks == ChecksFailure {
+			return StatusChecksFailed
+		}
+		if pr.ReviewDecision == ReviewChangesRequested {
+			return StatusChangesRequested
+		}
+		return StatusPending
+	}
+	if pr.Mergeable != nil && !*pr.Mergeable && pr.MergeableState != "blocked" {
+		return StatusConflict
+	}
+	return StatusPending
+}
+
+// WaitMergeable polls a pull request every interval until it leaves
+// StatusPending or ctx is done. Callers bound the wait with a ctx deadline.
+func WaitMergeable(ctx context.Context, f Forge, number int, interval time.Duration) (*PullRequest, error) {
+	for {
+		pr, err := f.GetPullRequest(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		if pr.Status() != StatusPending {
+			return pr, nil
+		}
+		select {
+		case <-ctx.Done():
+			return pr, fmt.Errorf("waiting for pull request #%d (%s): %w", number, pr.MergeableState, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,440 @@
+// Package forgetest provides an in-memory, GitHub-compatible pull request
+// server for tests of code that uses the forge package.
+package forgetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Pull is a pull request held by the fake server.
+type Pull struct {
+	Number         int
+	Title          string
+	Body           string
+	Head           string
+	Base           string
+	HeadSHA        string
+	State          string // "open" or "closed"
+	Merged         bool
+	MergeCommitSHA string
+	MergeableState string
+	MergeMethod    string // Method of the successful merge
+}
+
+// CheckRun is a check run (or, with Status "", a commit status) reported on
+// a commit. Conclusion holds the status state for commit statuses.
+type CheckRun struct {
+	Name       string
+	Status     string // "queued", "in_progress", "completed"; "" for a commit status
+	Conclusion string // "success", "failure", ...; "pending", "error", ... for a commit status
+}
+
+// Review is a pull request review.
+type Review struct {
+	User  string
+	State string // "APPROVED", "CHANGES_REQUESTED", "COMMENTED", "DISMISSED"
+}
+
+// Server is a fake GitHub REST API for a single repository. It implements
+// the pull request list/create/update/get/merge endpoints used by
+// forge.GitHub, plus the checks, statuses, reviews and required checks read
+// for blocked pull requests.
+type Server struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	owner string
+	repo  string
+	pulls []*Pull
+
+	checks  map[string][]CheckRun // commit ref → check runs and statuses
+	reviews map[int][]Review      // pull number → reviews, oldest first
+
+	// DefaultChecks are reported for commits without checks set by SetChecks.
+	DefaultChecks []CheckRun
+
+	// RequiredChecks maps a base branch to the check names branch protection
+	// requires. Branches not listed answer 404, as without admin access.
+	RequiredChecks map[string][]string
+
+	// MergeableState is reported by newly opened pull requests ("clean" by default).
+	MergeableState string
+
+	// ResolveHead returns the current SHA of a head branch. When nil, pull
+	// requests report an empty head SHA and merges skip the SHA check.
+	ResolveHead func(branch string) string
+
+	// OnMerge is called when a merge is accepted and returns the resulting
+	// commit SHA. When nil, the head SHA is reported as the merge commit.
+	OnMerge func(pr Pull, method string) (string, error)
+
+	// Requests records "METHOD /path" for every request received.
+	Requests []string
+}
+
+// NewServer starts a fake server for owner/repo and closes it when the test ends.
+func NewServer(t testing.TB, owner, repo string) *Server {
+	t.Helper()
+	s := &Server{owner: owner, repo: repo, MergeableState: "clean"}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Repo returns the "owner/name" the server answers for.
+func (s *Server) Repo() string {
+	return s.owner + "/" + s.repo
+}
+
+// Pull returns a copy of pull request number n.
+func (s *Server) Pull(n int) (Pull, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pr := s.find(n); pr != nil {
+		return *pr, true
+	}
+	return Pull{}, false
+}
+
+// Pulls returns copies of all pull requests in creation order.
+func (s *Server) Pulls() []Pull {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Pull, len(s.pulls))
+	for i, pr := range s.pulls {
+		out[i] = *pr
+	}
+	return out
+}
+
+// SetMergeableState changes the mergeable_state of pull request n.
+func (s *Server) SetMergeableState(n int, state string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pr := s.find(n); pr != nil {
+		pr.MergeableState = state
+	}
+}
+
+// SetChecks replaces the check runs and commit statuses reported for ref
+// (a head SHA, or the head branch when ResolveHead is nil).
+func (s *Server) SetChecks(ref string, runs ...CheckRun) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.checks == nil {
+		s.checks = make(map[string][]CheckRun)
+	}
+	s.checks[ref] = runs
+}
+
+// AddReview records a review on pull request n.
+func (s *Server) AddReview(n int, review Review) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reviews == nil {
+		s.reviews = make(map[int][]Review)
+	}
+	s.reviews[n] = append(s.reviews[n], review)
+}
+
+// ClosePull marks pull request n as closed without merging.
+func (s *Server) ClosePull(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pr := s.find(n); pr != nil {
+		pr.State = "closed"
+	}
+}
+
+func (s *Server) find(n int) *Pull {
+	for _, pr := range s.pulls {
+		if pr.Number == n {
+			return pr
+		}
+	}
+	return nil
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Requests = append(s.Requests, r.Method+" "+r.URL.Path)
+
+	repoPrefix := fmt.Sprintf("/repos/%s/%s", s.owner, s.repo)
+	if r.Method == http.MethodGet && s.handleChecks(w, strings.TrimPrefix(r.URL.Path, repoPrefix)) {
+		return
+	}
+	prefix := repoPrefix + "/pulls"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	switch {
+	case strings.HasSuffix(rest, "/reviews") && r.Method == http.MethodGet:
+		s.withPull(w, strings.TrimSuffix(rest, "/reviews"), func(pr *Pull) { s.listReviews(w, pr) })
+	case rest == "" && r.Method == http.MethodGet:
+		s.list(w, r)
+	case rest == "" && r.Method == http.MethodPost:
+		s.create(w, r)
+	case strings.HasSuffix(rest, "/merge") && r.Method == http.MethodPut:
+		s.withPull(w, strings.TrimSuffix(rest, "/merge"), func(pr *Pull) { s.merge(w, r, pr) })
+	case r.Method == http.MethodGet:
+		s.withPull(w, rest, func(pr *Pull) { s.write(w, http.StatusOK, pr) })
+	case r.Method == http.MethodPatch:
+		s.withPull(w, rest, func(pr *Pull) { s.update(w, r, pr) })
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// handleChecks answers the commit check-runs and status endpoints and the
+// branch protection required checks. Returns false for other paths.
+func (s *Server) handleChecks(w http.ResponseWriter, path string) bool {
+	if branch, ok := strings.CutPrefix(path, "/branches/"); ok {
+		branch, ok = strings.CutSuffix(branch, "/protection/required_status_checks")
+		if !ok {
+			return false
+		}
+		contexts, ok := s.RequiredChecks[branch]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Branch not protected")
+			return true
+		}
+		writeJSON(w, map[string]interface{}{"contexts": contexts})
+		return true
+	}
+
+	ref, ok := strings.CutPrefix(path, "/commits/")
+	if !ok {
+		return false
+	}
+	checksFor := func(ref string) []CheckRun {
+		if runs, ok := s.checks[ref]; ok {
+			return runs
+		}
+		return s.DefaultChecks
+	}
+	if ref, ok := strings.CutSuffix(ref, "/check-runs"); ok {
+		runs := []map[string]string{}
+		for _, c := range checksFor(ref) {
+			if c.Status != "" {
+				runs = append(runs, map[string]string{"name": c.Name, "status": c.Status, "conclusion": c.Conclusion})
+			}
+		}
+		writeJSON(w, map[string]interface{}{"total_count": len(runs), "check_runs": runs})
+		return true
+	}
+	if ref, ok := strings.CutSuffix(ref, "/status"); ok {
+		statuses := []map[string]string{}
+		for _, c := range checksFor(ref) {
+			if c.Status == "" {
+				statuses = append(statuses, map[string]string{"context": c.Name, "state": c.Conclusion})
+			}
+		}
+		writeJSON(w, map[string]interface{}{"statuses": statuses})
+		return true
+	}
+	return false
+}
+
+func (s *Server) listReviews(w http.ResponseWriter, pr *Pull) {
+	out := []map[string]interface{}{}
+	for _, r := range s.reviews[pr.Number] {
+		out = append(out, map[string]interface{}{"user": map[string]string{"login": r.User}, "state": r.State})
+	}
+	writeJSON(w, out)
+}
+
+func (s *Server) withPull(w http.ResponseWriter, id string, fn func(*Pull)) {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	pr := s.find(n)
+	if pr == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	s.refreshHead(pr)
+	fn(pr)
+}
+
+func (s *Server) refreshHead(pr *Pull) {
+	if s.ResolveHead != nil && pr.State == "open" {
+		pr.HeadSHA = s.ResolveHead(pr.Head)
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	head := q.Get("head")
+	if _, branch, ok := strings.Cut(head, ":"); ok {
+		head = branch
+	}
+	matches := []map[string]interface{}{}
+	for _, pr := range s.pulls {
+		if state := q.Get("state"); state != "" && state != "all" && pr.State != state {
+			continue
+		}
+		if head != "" && pr.Head != head {
+			continue
+		}
+		if base := q.Get("base"); base != "" && pr.Base != base {
+			continue
+		}
+		s.refreshHead(pr)
+		matches = append(matches, s.toJSON(pr))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(matches)
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Title string `json:"title"`
+		Head  string `json:"head"`
+		Base  string `json:"base"`
+		Body  string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Head == "" || req.Base == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	for _, pr := range s.pulls {
+		if pr.State == "open" && pr.Head == req.Head && pr.Base == req.Base {
+			writeError(w, http.StatusUnprocessableEntity, "A pull request already exists for "+req.Head)
+			return
+		}
+	}
+	pr := &Pull{
+		Number:         len(s.pulls) + 1,
+		Title:          req.Title,
+		Body:           req.Body,
+		Head:           req.Head,
+		Base:           req.Base,
+		State:          "open",
+		MergeableState: s.MergeableState,
+	}
+	s.refreshHead(pr)
+	s.pulls = append(s.pulls, pr)
+	s.write(w, http.StatusCreated, pr)
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, pr *Pull) {
+	var req struct {
+		Title *string `json:"title"`
+		Body  *string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	if req.Title != nil {
+		pr.Title = *req.Title
+	}
+	if req.Body != nil {
+		pr.Body = *req.Body
+	}
+	s.write(w, http.StatusOK, pr)
+}
+
+func (s *Server) merge(w http.ResponseWriter, r *http.Request, pr *Pull) {
+	var req struct {
+		MergeMethod string `json:"merge_method"`
+		SHA         string `json:"sha"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	if pr.State != "open" || pr.Merged {
+		writeError(w, http.StatusMethodNotAllowed, "Pull Request is not mergeable")
+		return
+	}
+	switch pr.MergeableState {
+	case "clean", "unstable", "has_hooks":
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Pull Request is not mergeable")
+		return
+	}
+	if req.SHA != "" && pr.HeadSHA != "" && req.SHA != pr.HeadSHA {
+		writeError(w, http.StatusConflict, "Head branch was modified. Review and try the merge again.")
+		return
+	}
+	method := req.MergeMethod
+	if method == "" {
+		method = "merge"
+	}
+
+	sha := pr.HeadSHA
+	if s.OnMerge != nil {
+		var err error
+		if sha, err = s.OnMerge(*pr, method); err != nil {
+			writeError(w, http.StatusMethodNotAllowed, err.Error())
+			return
+		}
+	}
+	pr.State = "closed"
+	pr.Merged = true
+	pr.MergeCommitSHA = sha
+	pr.MergeMethod = method
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"sha":     sha,
+		"merged":  true,
+		"message": "Pull Request successfully merged",
+	})
+}
+
+func (s *Server) write(w http.ResponseWriter, status int, pr *Pull) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(s.toJSON(pr))
+}
+
+func (s *Server) toJSON(pr *Pull) map[string]interface{} {
+	var mergeable interface{}
+	switch pr.MergeableState {
+	case "", "unknown":
+		mergeable = nil
+	case "dirty":
+		mergeable = false
+	default:
+		mergeable = true
+	}
+	return map[string]interface{}{
+		"number":           pr.Number,
+		"html_url":         fmt.Sprintf("%s/%s/%s/pull/%d", s.URL, s.owner, s.repo, pr.Number),
+		"title":            pr.Title,
+		"body":             pr.Body,
+		"state":            pr.State,
+		"merged":           pr.Merged,
+		"merge_commit_sha": pr.MergeCommitSHA,
+		"mergeable":        mergeable,
+		"mergeable_state":  pr.MergeableState,
+		"head":             map[string]string{"ref": pr.Head, "sha": pr.HeadSHA},
+		"base":             map[string]string{"ref": pr.Base},
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
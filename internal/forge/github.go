@@ -0,0 +1,375 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultGitHubAPIURL is the public GitHub REST API. GitHub Enterprise and
+// compatible forges (Gitea, Forgejo) are configured with their own base URL.
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// GitHubToken returns the API token from the named environment variable, or
+// from GITHUB_TOKEN / GH_TOKEN when envVar is empty.
+func GitHubToken(envVar string) string {
+	if envVar != "" {
+		return os.Getenv(envVar)
+	}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token
+	}
+	return os.Getenv("GH_TOKEN")
+}
+
+// GitHub is a Forge backed by the GitHub REST API.
+type GitHub struct {
+	baseURL string
+	owner   string
+	repo    string
+	token   string
+	client  *http.Client
+}
+
+// NewGitHub creates a GitHub client for repo ("owner/name").
+// An empty apiURL uses DefaultGitHubAPIURL.
+func NewGitHub(apiURL, repo, token string) (*GitHub, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid repo %q (expected owner/name)", repo)
+	}
+	if apiURL == "" {
+		apiURL = DefaultGitHubAPIURL
+	}
+	return &GitHub{
+		baseURL: strings.TrimRight(apiURL, "/"),
+		owner:   owner,
+		repo:    name,
+		token:   token,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// githubPull is the subset of GitHub's pull request object we read.
+type githubPull struct {
+	Number         int    `json:"number"`
+	HTMLURL        string `json:"html_url"`
+	State          string `json:"state"`
+	Merged         bool   `json:"merged"`
+	MergeCommitSHA string `json:"merge_commit_sha"`
+	Mergeable      *bool  `json:"mergeable"`
+	MergeableState string `json:"mergeable_state"`
+	Head           struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (p *githubPull) toPullRequest() *PullRequest {
+	return &PullRequest{
+		Number:         p.Number,
+		URL:            p.HTMLURL,
+		State:          p.State,
+		Merged:         p.Merged,
+		MergeCommitSHA: p.MergeCommitSHA,
+		Mergeable:      p.Mergeable,
+		MergeableState: p.MergeableState,
+		HeadRef:        p.Head.Ref,
+		HeadSHA:        p.Head.SHA,
+		BaseRef:        p.Base.Ref,
+	}
+}
+
+// apiError is returned for non-2xx responses.
+type apiError struct {
+	Status  int
+	Message string
+}
+
+func (e *apiError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("GitHub API error (HTTP %d)", e.Status)
+	}
+	return fmt.Sprintf("GitHub API error (HTTP %d): %s", e.Status, e.Message)
+}
+
+// do sends a request to path (relative to the repo) and decodes the JSON
+// response into out when out is non-nil.
+func (g *GitHub) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshaling request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/%s%s", g.baseURL, url.PathEscape(g.owner), url.PathEscape(g.repo), path)
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHub API request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return &apiError{Status: resp.StatusCode, Message: errResp.Message}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding %s %s response: %w", method, path, err)
+	}
+	return nil
+}
+
+// EnsurePullRequest opens a pull request, or updates the open one for the
+// same head and base.
+func (g *GitHub) EnsurePullRequest(ctx context.Context, spec PullRequestSpec) (*PullRequest, error) {
+	query := url.Values{
+		"state": {"open"},
+		"head":  {g.owner + ":" + spec.Head},
+		"base":  {spec.Base},
+	}
+	var existing []githubPull
+	if err := g.do(ctx, http.MethodGet, "/pulls?"+query.Encode(), nil, &existing); err != nil {
+		return nil, fmt.Errorf("listing pull requests: %w", err)
+	}
+
+	var pull githubPull
+	if len(existing) > 0 {
+		update := map[string]string{"title": spec.Title, "body": spec.Body}
+		path := fmt.Sprintf("/pulls/%d", existing[0].Number)
+		if err := g.do(ctx, http.MethodPatch, path, update, &pull); err != nil {
+			return nil, fmt.Errorf("updating pull request #%d: %w", existing[0].Number, err)
+		}
+		return pull.toPullRequest(), nil
+	}
+
+	create := map[string]string{
+		"title": spec.Title,
+		"head":  spec.Head,
+		"base":  spec.Base,
+		"body":  spec.Body,
+	}
+	if err := g.do(ctx, http.MethodPost, "/pulls", create, &pull); err != nil {
+		return nil, fmt.Errorf("opening pull request %s -> %s: %w", spec.Head, spec.Base, err)
+	}
+	return pull.toPullRequest(), nil
+}
+
+// GetPullRequest returns the current state of a pull request.
+func (g *GitHub) GetPullRequest(ctx context.Context, number int) (*PullRequest, error) {
+	var pull githubPull
+	if err := g.do(ctx, http.MethodGet, fmt.Sprintf("/pulls/%d", number), nil, &pull); err != nil {
+		return nil, fmt.Errorf("getting pull request #%d: %w", number, err)
+	}
+	pr := pull.toPullRequest()
+	if pr.State == "open" && pr.MergeableState == "blocked" {
+		g.explainBlocked(ctx, pr)
+	}
+	return pr, nil
+}
+
+// failedConclusions are check run conclusions that fail a required check.
+var failedConclusions = map[string]bool{
+	"failure":         true,
+	"timed_out":       true,
+	"cancelled":       true,
+	"action_required": true,
+	"startup_failure": true,
+}
+
+// explainBlocked fills in the required checks and review decision of a
+// blocked pull request. Lookups that fail leave the fields empty, which
+// keeps the pull request pending.
+func (g *GitHub) explainBlocked(ctx context.Context, pr *PullRequest) {
+	ref := pr.HeadSHA
+	if ref == "" {
+		ref = pr.HeadRef
+	}
+	pr.Checks, pr.FailedChecks = g.requiredChecks(ctx, pr.BaseRef, ref)
+	pr.ReviewDecision = g.reviewDecision(ctx, pr.Number)
+}
+
+// requiredChecks aggregates the check runs and commit statuses on ref that
+// branch protection requires for base. When the required set can't be read
+// (no admin access, no protection), every check counts as required.
+func (g *GitHub) requiredChecks(ctx context.Context, base, ref string) (CheckState, []string) {
+	var protection struct {
+		Contexts []string `json:"contexts"`
+		Checks   []struct {
+			Context string `json:"context"`
+		} `json:"checks"`
+	}
+	var required map[string]bool
+	if err := g.do(ctx, http.MethodGet, "/branches/"+url.PathEscape(base)+"/protection/required_status_checks", nil, &protection); err == nil {
+		required = make(map[string]bool)
+		for _, c := range protection.Contexts {
+			required[c] = true
+		}
+		for _, c := range protection.Checks {
+			required[c.Context] = true
+		}
+	}
+
+	var runs struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := g.do(ctx, http.MethodGet, "/commits/"+url.PathEscape(ref)+"/check-runs?per_page=100", nil, &runs); err != nil {
+		return "", nil
+	}
+	var combined struct {
+		Statuses []struct {
+			Context string `json:"context"`
+			State   string `json:"state"`
+		} `json:"statuses"`
+	}
+	if err := g.do(ctx, http.MethodGet, "/commits/"+url.PathEscape(ref)+"/status", nil, &combined); err != nil {
+		return "", nil
+	}
+
+	seen := make(map[string]bool)
+	var failed []string
+	pending := false
+	for _, run := range runs.CheckRuns {
+		if required != nil && !required[run.Name] {
+			continue
+		}
+		seen[run.Name] = true
+		switch {
+		case run.Status != "completed":
+			pending = true
+		case failedConclusions[run.Conclusion]:
+			failed = append(failed, run.Name)
+		}
+	}
+	for _, st := range combined.Statuses {
+		if required != nil && !required[st.Context] {
+			continue
+		}
+		seen[st.Context] = true
+		switch st.State {
+		case "failure", "error":
+			failed = append(failed, st.Context)
+		case "pending":
+			pending = true
+		}
+	}
+	for name := range required {
+		if !seen[name] {
+			pending = true // Required check hasn't reported yet
+		}
+	}
+
+	switch {
+	case len(failed) > 0:
+		return ChecksFailure, failed
+	case pending:
+		return ChecksPending, nil
+	}
+	return ChecksSuccess, nil
+}
+
+// reviewDecision derives the review decision from each reviewer's latest
+// approving, rejecting or dismissed review.
+func (g *GitHub) reviewDecision(ctx context.Context, number int) string {
+	var reviews []struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		State string `json:"state"`
+	}
+	if err := g.do(ctx, http.MethodGet, fmt.Sprintf("/pulls/%d/reviews?per_page=100", number), nil, &reviews); err != nil {
+		return ""
+	}
+	latest := make(map[string]string)
+	for _, r := range reviews { // Oldest first
+		switch r.State {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			latest[r.User.Login] = r.State
+		}
+	}
+	decision := ReviewRequired
+	for _, state := range latest {
+		switch state {
+		case "CHANGES_REQUESTED":
+			return ReviewChangesRequested
+		case "APPROVED":
+			decision = ReviewApproved
+		}
+	}
+	return decision
+}
+
+// MergePullRequest merges a pull request. A 405 maps to ErrNotMergeable and
+// a 409 (head SHA mismatch) to ErrHeadChanged.
+func (g *GitHub) MergePullRequest(ctx context.Context, number int, opts MergeOptions) (string, error) {
+	body := map[string]string{}
+	if opts.Method != "" {
+		body["merge_method"] = opts.Method
+	}
+	if opts.SHA != "" {
+		body["sha"] = opts.SHA
+	}
+	if opts.CommitTitle != "" {
+		body["commit_title"] = opts.CommitTitle
+	}
+	if opts.CommitMessage != "" {
+		body["commit_message"] = opts.CommitMessage
+	}
+
+	var resp struct {
+		SHA     string `json:"sha"`
+		Merged  bool   `json:"merged"`
+		Message string `json:"message"`
+	}
+	err := g.do(ctx, http.MethodPut, fmt.Sprintf("/pulls/%d/merge", number), body, &resp)
+	if apiErr, ok := err.(*apiError); ok {
+		switch apiErr.Status {
+		case http.StatusMethodNotAllowed:
+			return "", fmt.Errorf("merging pull request #%d: %w: %s", number, ErrNotMergeable, apiErr.Message)
+		case http.StatusConflict:
+			return "", fmt.Errorf("merging pull request #%d: %w: %s", number, ErrHeadChanged, apiErr.Message)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("merging pull request #%d: %w", number, err)
+	}
+	if !resp.Merged {
+		return "", fmt.Errorf("merging pull request #%d: %w: %s", number, ErrNotMergeable, resp.Message)
+	}
+	return resp.SHA, nil
+}
@@ -0,0 +1,232 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/forge/forgetest"
+)
+
+func newTestGitHub(t *testing.T) (*GitHub, *forgetest.Server) {
+	t.Helper()
+	srv := forgetest.NewServer(t, "acme", "widgets")
+	gh, err := NewGitHub(srv.URL, srv.Repo(), "test-token")
+	if err != nil {
+		t.Fatalf("NewGitHub: %v", err)
+	}
+	return gh, srv
+}
+
+func TestNewGitHub_InvalidRepo(t *testing.T) {
+	for _, repo := range []string{"", "widgets", "/widgets", "acme/", "acme/widgets/extra"} {
+		if _, err := NewGitHub("", repo, ""); err == nil {
+			t.Errorf("NewGitHub(%q) succeeded, want error", repo)
+		}
+	}
+}
+
+func TestGitHub_EnsurePullRequest(t *testing.T) {
+	gh, srv := newTestGitHub(t)
+	ctx := context.Background()
+
+	spec := PullRequestSpec{Head: "gt-refinery/polecat/nux", Base: "main", Title: "first", Body: "body"}
+	pr, err := gh.EnsurePullRequest(ctx, spec)
+	if err != nil {
+		t.Fatalf("EnsurePullRequest (create): %v", err)
+	}
+	if pr.Number != 1 || pr.HeadRef != spec.Head || pr.BaseRef != "main" || pr.State != "open" {
+		t.Errorf("created PR = %+v", pr)
+	}
+	if !strings.HasSuffix(pr.URL, "/acme/widgets/pull/1") {
+		t.Errorf("URL = %q", pr.URL)
+	}
+
+	spec.Title = "second"
+	again, err := gh.EnsurePullRequest(ctx, spec)
+	if err != nil {
+		t.Fatalf("EnsurePullRequest (update): %v", err)
+	}
+	if again.Number != pr.Number {
+		t.Errorf("update opened PR #%d, want #%d", again.Number, pr.Number)
+	}
+	if got, _ := srv.Pull(1); got.Title != "second" {
+		t.Errorf("title = %q, want updated", got.Title)
+	}
+	if n := len(srv.Pulls()); n != 1 {
+		t.Errorf("server has %d PRs, want 1", n)
+	}
+
+	// A different base gets its own pull request.
+	spec.Base = "release"
+	other, err := gh.EnsurePullRequest(ctx, spec)
+	if err != nil {
+		t.Fatalf("EnsurePullRequest (other base): %v", err)
+	}
+	if other.Number == pr.Number {
+		t.Error("expected a new PR for a different base")
+	}
+}
+
+func TestGitHub_MergePullRequest(t *testing.T) {
+	gh, srv := newTestGitHub(t)
+	srv.ResolveHead = func(string) string { return "abc123" }
+	srv.OnMerge = func(forgetest.Pull, string) (string, error) { return "def456", nil }
+	ctx := context.Background()
+
+	pr, err := gh.EnsurePullRequest(ctx, PullRequestSpec{Head: "feature", Base: "main", Title: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := gh.MergePullRequest(ctx, pr.Number, MergeOptions{Method: MergeMethodRebase, SHA: "stale"}); !errors.Is(err, ErrHeadChanged) {
+		t.Errorf("merge with stale SHA: err = %v, want ErrHeadChanged", err)
+	}
+
+	srv.SetMergeableState(pr.Number, "blocked")
+	if _, err := gh.MergePullRequest(ctx, pr.Number, MergeOptions{SHA: "abc123"}); !errors.Is(err, ErrNotMergeable) {
+		t.Errorf("merge while blocked: err = %v, want ErrNotMergeable", err)
+	}
+
+	srv.SetMergeableState(pr.Number, "clean")
+	sha, err := gh.MergePullRequest(ctx, pr.Number, MergeOptions{Method: MergeMethodRebase, SHA: "abc123"})
+	if err != nil {
+		t.Fatalf("MergePullRequest: %v", err)
+	}
+	if sha != "def456" {
+		t.Errorf("merge SHA = %q, want def456", sha)
+	}
+	got, err := gh.GetPullRequest(ctx, pr.Number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status() != StatusMerged || got.MergeCommitSHA != "def456" {
+		t.Errorf("after merge: status %v, merge commit %q", got.Status(), got.MergeCommitSHA)
+	}
+	if p, _ := srv.Pull(pr.Number); p.MergeMethod != MergeMethodRebase {
+		t.Errorf("merge method = %q, want rebase", p.MergeMethod)
+	}
+}
+
+func TestGitHub_GetPullRequest_NotFound(t *testing.T) {
+	gh, _ := newTestGitHub(t)
+	if _, err := gh.GetPullRequest(context.Background(), 42); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("err = %v, want HTTP 404", err)
+	}
+}
+
+func TestGitHub_GetPullRequest_Blocked(t *testing.T) {
+	gh, srv := newTestGitHub(t)
+	srv.MergeableState = "blocked"
+	srv.RequiredChecks = map[string][]string{"main": {"ci", "lint"}}
+	ctx := context.Background()
+
+	pr, err := gh.EnsurePullRequest(ctx, PullRequestSpec{Head: "feature", Base: "main", Title: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func() *PullRequest {
+		t.Helper()
+		got, err := gh.GetPullRequest(ctx, pr.Number)
+		if err != nil {
+			t.Fatalf("GetPullRequest: %v", err)
+		}
+		return got
+	}
+
+	// A required check still running keeps the pull request pending; a
+	// failing optional check doesn't matter.
+	srv.SetChecks("feature",
+		forgetest.CheckRun{Name: "ci", Status: "in_progress"},
+		forgetest.CheckRun{Name: "lint", Conclusion: "success"},
+		forgetest.CheckRun{Name: "coverage", Status: "completed", Conclusion: "failure"},
+	)
+	if got := get(); got.Checks != ChecksPending || got.Status() != StatusPending {
+		t.Errorf("running checks: checks=%q status=%v, want pending", got.Checks, got.Status())
+	}
+
+	// A required commit status erroring fails the landing.
+	srv.SetChecks("feature",
+		forgetest.CheckRun{Name: "ci", Status: "completed", Conclusion: "success"},
+		forgetest.CheckRun{Name: "lint", Conclusion: "error"},
+	)
+	got := get()
+	if got.Status() != StatusChecksFailed || strings.Join(got.FailedChecks, ",") != "lint" {
+		t.Errorf("errored status: status=%v failed=%v, want checks_failed [lint]", got.Status(), got.FailedChecks)
+	}
+
+	// Checks green: the latest review per reviewer decides.
+	srv.SetChecks("feature",
+		forgetest.CheckRun{Name: "ci", Status: "completed", Conclusion: "success"},
+		forgetest.CheckRun{Name: "lint", Conclusion: "success"},
+	)
+	if got := get(); got.ReviewDecision != ReviewRequired || got.Status() != StatusPending {
+		t.Errorf("no reviews: decision=%q status=%v, want review_required/pending", got.ReviewDecision, got.Status())
+	}
+	srv.AddReview(pr.Number, forgetest.Review{User: "alice", State: "CHANGES_REQUESTED"})
+	if got := get(); got.Status() != StatusChangesRequested {
+		t.Errorf("changes requested: status=%v", got.Status())
+	}
+	srv.AddReview(pr.Number, forgetest.Review{User: "alice", State: "APPROVED"})
+	if got := get(); got.ReviewDecision != ReviewApproved || got.Status() != StatusPending {
+		t.Errorf("approved: decision=%q status=%v, want approved/pending", got.ReviewDecision, got.Status())
+	}
+}
+
+func TestPullRequest_Status(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		pr   PullRequest
+		want Status
+	}{
+		{PullRequest{State: "open", MergeableState: "clean", Mergeable: &yes}, StatusMergeable},
+		{PullRequest{State: "open", MergeableState: "unstable", Mergeable: &yes}, StatusMergeable},
+		{PullRequest{State: "open", MergeableState: "unknown"}, StatusPending},
+		{PullRequest{State: "open", MergeableState: "blocked", Mergeable: &yes}, StatusPending},
+		{PullRequest{State: "open", MergeableState: "blocked", Checks: ChecksPending, ReviewDecision: ReviewApproved}, StatusPending},
+		{PullRequest{State: "open", MergeableState: "blocked", Checks: ChecksFailure}, StatusChecksFailed},
+		{PullRequest{State: "open", MergeableState: "blocked", Checks: ChecksSuccess, ReviewDecision: ReviewChangesRequested}, StatusChangesRequested},
+		{PullRequest{State: "open", MergeableState: "dirty", Mergeable: &no}, StatusConflict},
+		{PullRequest{State: "open", MergeableState: "behind", Mergeable: &yes}, StatusBehind},
+		{PullRequest{State: "closed", Merged: true}, StatusMerged},
+		{PullRequest{State: "closed"}, StatusClosed},
+	}
+	for _, tt := range tests {
+		if got := tt.pr.Status(); got != tt.want {
+			t.Errorf("Status(%s/%v) = %v, want %v", tt.pr.State, tt.pr.MergeableState, got, tt.want)
+		}
+	}
+}
+
+func TestWaitMergeable(t *testing.T) {
+	gh, srv := newTestGitHub(t)
+	srv.MergeableState = "blocked"
+	ctx := context.Background()
+
+	pr, err := gh.EnsurePullRequest(ctx, PullRequestSpec{Head: "feature", Base: "main", Title: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Still blocked when the deadline passes.
+	short, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+	defer cancel()
+	if _, err := WaitMergeable(short, gh, pr.Number, 5*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want deadline exceeded", err)
+	}
+
+	// Checks pass while waiting.
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		srv.SetMergeableState(pr.Number, "clean")
+	}()
+	got, err := WaitMergeable(ctx, gh, pr.Number, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitMergeable: %v", err)
+	}
+	if got.Status() != StatusMergeable {
+		t.Errorf("status = %v, want mergeable", got.Status())
+	}
+}
@@ -0,0 +1,570 @@
+// Package guard implements the declarative command policies enforced by
+// `gt tap guard`.
+//
+// A policy is an ordered list of rules. Each rule matches tool calls by tool
+// name, parsed shell commands, git operations, network tools and file paths,
+// and either blocks the call, allows it, or logs the match and carries on.
+// Policies are layered like hooks: built-in defaults, then the town, role,
+// rig and rig/role policy files, with more specific layers taking precedence.
+package guard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Rule actions.
+const (
+	ActionBlock = "block" // Block the tool call
+	ActionAllow = "allow" // Allow the tool call without checking later rules
+	ActionLog   = "log"   // Record the match and keep evaluating
+)
+
+// Policy is an ordered list of rules. The first matching block or allow rule
+// decides; calls no rule decides are allowed.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is one policy rule.
+type Rule struct {
+	// Name identifies the rule. A rule in a more specific layer replaces
+	// the rule with the same name in the layers below it.
+	Name string `json:"name"`
+
+	// Action is what happens when the rule matches: block, allow or log.
+	Action string `json:"action"`
+
+	// Reason is shown when the rule blocks, and recorded in the audit log.
+	Reason string `json:"reason,omitempty"`
+
+	// Disabled removes a rule of the same name inherited from a lower layer.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Match selects the tool calls the rule applies to.
+	Match Match `json:"match"`
+
+	// Layer is the policy layer the rule was loaded from.
+	Layer string `json:"-"`
+}
+
+// Match selects tool calls. All criteria that are set must match; within a
+// list, any entry may match. A rule with no criteria matches every call.
+type Match struct {
+	// Tools are Claude Code tool names (e.g. "Bash", "Edit"). Defaults to
+	// Bash when command criteria are set, otherwise to every tool.
+	Tools []string `json:"tools,omitempty"`
+
+	// Commands are command patterns such as "rm -rf /*" or "gh pr create".
+	// The first word matches the program, positional words must appear in
+	// order, and flags match anywhere ("-rf" also matches "-r -f" and "-fr").
+	// Words may use * and ? wildcards.
+	Commands []string `json:"commands,omitempty"`
+
+	// Git are git operations, shorthand for "git <op>" command patterns
+	// (e.g. "push --force", "reset --hard").
+	Git []string `json:"git,omitempty"`
+
+	// Network matches commands that run a network tool (curl, ssh, ...).
+	Network bool `json:"network,omitempty"`
+
+	// Paths match when a path the call touches is under any of these
+	// path globs. PathsOutside match when a path is under none of them.
+	// Entries may use the variables {worktree}, {home}, {town}, {rig} and
+	// {tmp}; entries whose variables are unknown are ignored.
+	Paths        []string `json:"paths,omitempty"`
+	PathsOutside []string `json:"paths_outside,omitempty"`
+
+	// Writes limits path checks to paths the call writes: file edits,
+	// redirections and the operands of commands like rm, mv and cp.
+	Writes bool `json:"writes,omitempty"`
+}
+
+// networkTools are programs matched by Match.Network.
+var networkTools = map[string]bool{
+	"curl": true, "wget": true, "ssh": true, "scp": true, "sftp": true,
+	"rsync": true, "nc": true, "ncat": true, "netcat": true, "telnet": true,
+	"ftp": true, "http": true, "https": true, "aria2c": true,
+}
+
+// writeTools are Claude Code tools that modify files.
+var writeTools = map[string]bool{
+	"Edit": true, "Write": true, "MultiEdit": true, "NotebookEdit": true,
+}
+
+// writeCommands are programs whose operands are files they modify.
+var writeCommands = map[string]bool{
+	"rm": true, "rmdir": true, "mv": true, "cp": true, "touch": true,
+	"mkdir": true, "chmod": true, "chown": true, "ln": true, "tee": true,
+	"dd": true, "install": true, "truncate": true, "shred": true, "unlink": true,
+}
+
+// Validate checks a policy for unknown actions and unnamed or duplicate rules.
+func (p *Policy) Validate() error {
+	seen := make(map[string]bool)
+	for i, r := range p.Rules {
+		if r.Name == "" {
+			return fmt.Errorf("rule %d: name is required", i+1)
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("rule %q: duplicate name", r.Name)
+		}
+		seen[r.Name] = true
+		if r.Disabled {
+			continue
+		}
+		switch r.Action {
+		case ActionBlock, ActionAllow, ActionLog:
+		default:
+			return fmt.Errorf("rule %q: invalid action %q (want block, allow or log)", r.Name, r.Action)
+		}
+		for _, c := range append(append([]string{}, r.Match.Commands...), r.Match.Git...) {
+			if strings.TrimSpace(c) == "" {
+				return fmt.Errorf("rule %q: empty command pattern", r.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Merge layers override on top of base. Rules in override come first, so
+// they are evaluated before the base rules; a rule with the same name as a
+// base rule replaces it, or removes it when disabled.
+func Merge(base, override *Policy) *Policy {
+	result := &Policy{}
+	if override == nil {
+		if base != nil {
+			result.Rules = append(result.Rules, base.Rules...)
+		}
+		return result
+	}
+	named := make(map[string]bool, len(override.Rules))
+	for _, r := range override.Rules {
+		named[r.Name] = true
+		if !r.Disabled {
+			result.Rules = append(result.Rules, r)
+		}
+	}
+	if base != nil {
+		for _, r := range base.Rules {
+			if !named[r.Name] {
+				result.Rules = append(result.Rules, r)
+			}
+		}
+	}
+	return result
+}
+
+// Input is a tool call to check.
+type Input struct {
+	Tool    string   // Claude Code tool name
+	Command string   // Shell command line (Bash)
+	Paths   []string // File paths from the tool input (file tools)
+	Cwd     string   // Working directory of the call
+}
+
+// Vars are the values of the path variables used in rules.
+type Vars struct {
+	Worktree string // Agent's worktree (or home for roles without one)
+	Home     string // User home directory
+	Town     string // Town root
+	Rig      string // Rig root
+	Tmp      string // Temporary directory
+}
+
+// Decision is the outcome of evaluating a policy.
+type Decision struct {
+	Action  string   `json:"action"`            // ActionBlock or ActionAllow
+	Rule    string   `json:"rule,omitempty"`    // Deciding rule, empty by default
+	Layer   string   `json:"layer,omitempty"`   // Layer of the deciding rule
+	Reason  string   `json:"reason,omitempty"`  // Reason of the deciding rule
+	Command string   `json:"command,omitempty"` // Matched command, for Bash
+	Path    string   `json:"path,omitempty"`    // Matched path, for path rules
+	Logged  []string `json:"logged,omitempty"`  // Log rules that matched
+}
+
+// Blocked reports whether the call is blocked.
+func (d Decision) Blocked() bool {
+	return d.Action == ActionBlock
+}
+
+// Evaluate checks a tool call against the policy. Each command of a shell
+// command line is checked on its own, and the call is blocked if any command
+// is: an allow rule matching "git status" doesn't allow "git status && rm -rf /".
+// Log rules that match are collected and evaluation continues; the first
+// matching block or allow rule decides.
+func (p *Policy) Evaluate(in Input, vars Vars) Decision {
+	result := Decision{Action: ActionAllow}
+	for _, c := range in.calls() {
+		d := p.evaluateCall(in.Tool, c, vars)
+		for _, name := range d.Logged {
+			if !containsFold(result.Logged, name) {
+				result.Logged = append(result.Logged, name)
+			}
+		}
+		if d.Blocked() {
+			d.Logged = result.Logged
+			return d
+		}
+		if result.Rule == "" && d.Rule != "" {
+			d.Logged = result.Logged
+			result = d
+		}
+	}
+	return result
+}
+
+func (p *Policy) evaluateCall(tool string, c call, vars Vars) Decision {
+	var logged []string
+	for _, r := range p.Rules {
+		if r.Disabled {
+			continue
+		}
+		path, ok := r.Match.match(tool, c, vars)
+		if !ok {
+			continue
+		}
+		if r.Action == ActionLog {
+			logged = append(logged, r.Name)
+			continue
+		}
+		return Decision{Action: r.Action, Rule: r.Name, Layer: r.Layer, Reason: r.Reason, Command: c.text, Path: path, Logged: logged}
+	}
+	return Decision{Action: ActionAllow, Logged: logged}
+}
+
+// call is one command (or file tool call) with the paths it reads and writes.
+type call struct {
+	cmd    *Command
+	text   string
+	paths  []string // All paths named by the call
+	writes []string // Paths the call writes
+}
+
+// calls splits the input into the commands and paths rules are matched on.
+func (in Input) calls() []call {
+	if in.Command == "" {
+		c := call{text: strings.Join(in.Paths, " ")}
+		for _, p := range in.Paths {
+			abs := resolvePath(p, in.Cwd)
+			c.paths = append(c.paths, abs)
+			if writeTools[in.Tool] {
+				c.writes = append(c.writes, abs)
+			}
+		}
+		return []call{c}
+	}
+
+	cwd := in.Cwd
+	var calls []call
+	for _, cmd := range ParseCommands(in.Command) {
+		cmd := cmd
+		c := call{cmd: &cmd, text: strings.TrimSpace(cmd.Name + " " + strings.Join(cmd.Args, " "))}
+		for _, r := range cmd.Redirects {
+			if isDevice(r) {
+				continue
+			}
+			abs := resolvePath(r, cwd)
+			c.paths = append(c.paths, abs)
+			c.writes = append(c.writes, abs)
+		}
+		writer := writeCommands[cmd.Program()]
+		for _, a := range positionals(cmd.Args) {
+			if writer || looksLikePath(a) {
+				abs := resolvePath(a, cwd)
+				c.paths = append(c.paths, abs)
+				if writer {
+					c.writes = append(c.writes, abs)
+				}
+			}
+		}
+		// Follow cd so later commands resolve relative paths correctly.
+		if cmd.Program() == "cd" && len(cmd.Args) > 0 {
+			cwd = resolvePath(cmd.Args[0], cwd)
+		}
+		calls = append(calls, c)
+	}
+	return calls
+}
+
+// match reports whether the rule matches a call, returning the matched path.
+func (m *Match) match(tool string, c call, vars Vars) (string, bool) {
+	hasCommand := len(m.Commands) > 0 || len(m.Git) > 0 || m.Network
+	hasPaths := len(m.Paths) > 0 || len(m.PathsOutside) > 0
+
+	tools := m.Tools
+	if len(tools) == 0 && hasCommand {
+		tools = []string{"Bash"}
+	}
+	if len(tools) > 0 && !containsFold(tools, tool) {
+		return "", false
+	}
+	if hasCommand && (c.cmd == nil || !m.matchCommand(c.cmd)) {
+		return "", false
+	}
+	if !hasPaths {
+		return "", true
+	}
+	paths := c.paths
+	if m.Writes {
+		paths = c.writes
+	}
+	for _, p := range paths {
+		if m.matchPath(p, vars) {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+func (m *Match) matchCommand(cmd *Command) bool {
+	if m.Network && !networkTools[cmd.Program()] {
+		return false
+	}
+	if len(m.Commands) == 0 && len(m.Git) == 0 {
+		return true
+	}
+	for _, pattern := range m.Commands {
+		if matchCommandPattern(pattern, cmd) {
+			return true
+		}
+	}
+	for _, op := range m.Git {
+		if matchCommandPattern("git "+op, cmd) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Match) matchPath(path string, vars Vars) bool {
+	if len(m.Paths) > 0 && !underAny(path, m.Paths, vars) {
+		return false
+	}
+	if len(m.PathsOutside) > 0 {
+		roots := expandAll(m.PathsOutside, vars)
+		if len(roots) == 0 || underAny(path, roots, Vars{}) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchCommandPattern matches a command against a pattern like "rm -rf /*".
+func matchCommandPattern(pattern string, cmd *Command) bool {
+	words := strings.Fields(pattern)
+	if len(words) == 0 {
+		return false
+	}
+	name := cmd.Program()
+	if strings.Contains(words[0], "/") {
+		name = cmd.Name
+	}
+	if !globMatch(words[0], name) {
+		return false
+	}
+
+	args := positionals(cmd.Args)
+	next := 0
+	for _, w := range words[1:] {
+		if strings.HasPrefix(w, "-") && len(w) > 1 {
+			if !hasFlag(cmd.Args, w) {
+				return false
+			}
+			continue
+		}
+		// Positional words must appear in order.
+		found := false
+		for next < len(args) {
+			next++
+			if globMatch(w, args[next-1]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// hasFlag reports whether args contain the flag. Long flags match exactly
+// or with a value ("--force" matches "--force=x" but not "--force-with-lease");
+// short flags match when every letter appears in some short flag cluster.
+func hasFlag(args []string, flag string) bool {
+	flags := args
+	for i, a := range args {
+		if a == "--" {
+			flags = args[:i]
+			break
+		}
+	}
+	if strings.HasPrefix(flag, "--") || strings.ContainsAny(flag, "*?") {
+		for _, a := range flags {
+			if globMatch(flag, a) || strings.HasPrefix(a, flag+"=") {
+				return true
+			}
+		}
+		return false
+	}
+	for _, letter := range flag[1:] {
+		found := false
+		for _, a := range flags {
+			if len(a) > 1 && a[0] == '-' && a[1] != '-' && strings.ContainsRune(a[1:], letter) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// positionals returns the non-flag arguments. Everything after "--" is
+// positional.
+func positionals(args []string) []string {
+	var out []string
+	for i, a := range args {
+		if a == "--" {
+			return append(out, args[i+1:]...)
+		}
+		if strings.HasPrefix(a, "-") && a != "-" {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// underAny reports whether path is matched by, or is under a directory
+// matched by, any of the path globs.
+func underAny(path string, globs []string, vars Vars) bool {
+	for _, g := range globs {
+		g = vars.expand(g)
+		if g == "" {
+			continue
+		}
+		for p := path; ; p = filepath.Dir(p) {
+			if globMatch(g, p) {
+				return true
+			}
+			if p == filepath.Dir(p) {
+				break
+			}
+		}
+	}
+	return false
+}
+
+func expandAll(globs []string, vars Vars) []string {
+	var out []string
+	for _, g := range globs {
+		if e := vars.expand(g); e != "" {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// expand substitutes path variables, returning "" if one is unknown.
+// Patterns without variables are returned as they are, with ~ expanded.
+func (v Vars) expand(pattern string) string {
+	if !strings.Contains(pattern, "{") {
+		if v.Home != "" && (pattern == "~" || strings.HasPrefix(pattern, "~/")) {
+			return filepath.Join(v.Home, pattern[1:])
+		}
+		return pattern
+	}
+	for name, value := range map[string]string{
+		"{worktree}": v.Worktree,
+		"{home}":     v.Home,
+		"{town}":     v.Town,
+		"{rig}":      v.Rig,
+		"{tmp}":      v.Tmp,
+	} {
+		if !strings.Contains(pattern, name) {
+			continue
+		}
+		if value == "" {
+			return ""
+		}
+		pattern = strings.ReplaceAll(pattern, name, filepath.Clean(value))
+	}
+	return pattern
+}
+
+// globMatch matches s against a pattern where * matches any run of
+// characters (including /) and ? matches one character.
+func globMatch(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 0 && pattern[0] == '*' {
+				pattern = pattern[1:]
+			}
+			if pattern == "" {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if globMatch(pattern, s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if s == "" {
+				return false
+			}
+			pattern, s = pattern[1:], s[1:]
+		default:
+			if s == "" || s[0] != pattern[0] {
+				return false
+			}
+			pattern, s = pattern[1:], s[1:]
+		}
+	}
+	return s == ""
+}
+
+// looksLikePath reports whether a command argument names a file by path.
+func looksLikePath(a string) bool {
+	return a == ".." || a == "~" ||
+		strings.HasPrefix(a, "/") || strings.HasPrefix(a, "~/") ||
+		strings.HasPrefix(a, "./") || strings.HasPrefix(a, "../")
+}
+
+// resolvePath makes p absolute relative to cwd, expanding ~.
+func resolvePath(p, cwd string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			p = filepath.Join(home, p[1:])
+		}
+	}
+	if !filepath.IsAbs(p) && cwd != "" {
+		p = filepath.Join(cwd, p)
+	}
+	return filepath.Clean(p)
+}
+
+// isDevice reports whether a redirection target is a device rather than a file.
+func isDevice(p string) bool {
+	switch p {
+	case "/dev/null", "/dev/stdout", "/dev/stderr", "/dev/tty":
+		return true
+	}
+	return strings.HasPrefix(p, "/dev/fd/")
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
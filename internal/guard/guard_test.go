@@ -0,0 +1,182 @@
+package guard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchCommandPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		line    string
+		want    bool
+	}{
+		{"rm -rf /*", "rm -rf /", true},
+		{"rm -rf /*", "/bin/rm -fr /x", true},
+		{"rm -rf /*", "rm -r -f -v /x", true},
+		{"rm -rf /*", "rm -rf ./x", false},
+		{"rm -rf /*", "rm -f /x", false},
+		{"git push --force", "git push --force=true", true},
+		{"git push --force", "git push --force-with-lease", false},
+		{"git push +*", "git push origin +main", true},
+		{"gh pr create", "gh pr create --fill", true},
+		{"gh pr create", "gh pr list", false},
+		{"git checkout -b", "git checkout main", false},
+		{"npm *", "npm install", true},
+	}
+	for _, tt := range tests {
+		cmds := ParseCommands(tt.line)
+		if len(cmds) != 1 {
+			t.Fatalf("ParseCommands(%q) = %v, want one command", tt.line, cmds)
+		}
+		if got := matchCommandPattern(tt.pattern, &cmds[0]); got != tt.want {
+			t.Errorf("matchCommandPattern(%q, %q) = %v, want %v", tt.pattern, tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluate_PolecatWorktree(t *testing.T) {
+	policy := Merge(DefaultPolicy(), DefaultRolePolicies()["polecats"])
+	vars := Vars{
+		Worktree: "/gt/gastown/polecats/toast",
+		Home:     "/home/gt",
+		Town:     "/gt",
+		Tmp:      "/tmp",
+	}
+	cwd := "/gt/gastown/polecats/toast/gastown"
+
+	tests := []struct {
+		name string
+		in   Input
+		want bool
+	}{
+		{"edit in worktree", Input{Tool: "Edit", Paths: []string{cwd + "/main.go"}}, false},
+		{"edit relative", Input{Tool: "Write", Paths: []string{"notes.md"}, Cwd: cwd}, false},
+		{"edit other polecat", Input{Tool: "Edit", Paths: []string{"/gt/gastown/polecats/nux/gastown/main.go"}}, true},
+		{"escape with ..", Input{Tool: "Write", Paths: []string{"../../../mayor/x"}, Cwd: cwd}, true},
+		{"read outside", Input{Tool: "Read", Paths: []string{"/etc/passwd"}}, false},
+		{"redirect outside", Input{Tool: "Bash", Command: "echo hi > ~/.bashrc", Cwd: cwd}, true},
+		{"redirect to tmp", Input{Tool: "Bash", Command: "go test ./... > /tmp/out.txt 2>/dev/null", Cwd: cwd}, false},
+		{"rm outside", Input{Tool: "Bash", Command: "rm ../../nux/gastown/file", Cwd: cwd}, true},
+		{"rm inside", Input{Tool: "Bash", Command: "rm -f build/out", Cwd: cwd}, false},
+		{"cd then write", Input{Tool: "Bash", Command: "cd /gt/mayor && touch x", Cwd: cwd}, true},
+		{"cat outside", Input{Tool: "Bash", Command: "cat /gt/mayor/town.json", Cwd: cwd}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := policy.Evaluate(tt.in, vars)
+			if d.Blocked() != tt.want {
+				t.Errorf("blocked = %v (rule %q, path %q), want %v", d.Blocked(), d.Rule, d.Path, tt.want)
+			}
+			if tt.want && d.Rule != "polecat-worktree" {
+				t.Errorf("rule = %q, want polecat-worktree", d.Rule)
+			}
+		})
+	}
+}
+
+func TestEvaluate_LayerOrder(t *testing.T) {
+	rig := &Policy{Rules: []Rule{
+		{Name: "allow-status", Action: ActionAllow, Match: Match{Git: []string{"status"}}},
+		{Name: "no-network", Action: ActionBlock, Match: Match{Network: true}},
+		{Name: "audit-npm", Action: ActionLog, Match: Match{Commands: []string{"npm *"}}},
+		{Name: "hard-reset", Disabled: true},
+	}}
+	policy := Merge(DefaultPolicy(), rig)
+
+	tests := []struct {
+		line       string
+		wantAction string
+		wantRule   string
+		wantLogged int
+	}{
+		{"curl https://example.com", ActionBlock, "no-network", 0},
+		{"git reset --hard", ActionAllow, "", 0},
+		{"git status", ActionAllow, "allow-status", 0},
+		// An allow rule only covers its own command.
+		{"git status && git push -f", ActionBlock, "force-push", 0},
+		{"npm install && curl x", ActionBlock, "no-network", 1},
+		{"npm test", ActionAllow, "", 1},
+	}
+	for _, tt := range tests {
+		d := policy.Evaluate(Input{Tool: "Bash", Command: tt.line}, Vars{})
+		if d.Action != tt.wantAction || d.Rule != tt.wantRule || len(d.Logged) != tt.wantLogged {
+			t.Errorf("Evaluate(%q) = %+v, want %s by %q with %d logged", tt.line, d, tt.wantAction, tt.wantRule, tt.wantLogged)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr bool
+	}{
+		{"empty", Policy{}, false},
+		{"valid", Policy{Rules: []Rule{{Name: "a", Action: ActionLog}}}, false},
+		{"disabled needs no action", Policy{Rules: []Rule{{Name: "a", Disabled: true}}}, false},
+		{"missing name", Policy{Rules: []Rule{{Action: ActionBlock}}}, true},
+		{"duplicate", Policy{Rules: []Rule{{Name: "a", Action: ActionBlock}, {Name: "a", Action: ActionAllow}}}, true},
+		{"bad action", Policy{Rules: []Rule{{Name: "a", Action: "deny"}}}, true},
+		{"empty pattern", Policy{Rules: []Rule{{Name: "a", Action: ActionBlock, Match: Match{Commands: []string{" "}}}}}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoad_Layers(t *testing.T) {
+	townRoot := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(townRoot, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("settings/guard-policy.json", `{"rules": [{"name": "no-network", "action": "block", "match": {"network": true}}]}`)
+	write("settings/guard-policy/crew.json", `{"rules": [{"name": "no-network", "action": "log", "match": {"network": true}}]}`)
+	write("gastown/settings/guard-policy/polecats.json", `{"rules": [{"name": "polecat-worktree", "disabled": true}]}`)
+
+	crew, err := Load(townRoot, "gastown", "crew")
+	if err != nil {
+		t.Fatalf("Load(crew): %v", err)
+	}
+	if d := crew.Evaluate(Input{Tool: "Bash", Command: "curl x"}, Vars{}); d.Blocked() || len(d.Logged) != 1 {
+		t.Errorf("crew curl = %+v, want allowed and logged", d)
+	}
+
+	polecat, err := Load(townRoot, "gastown", "polecat")
+	if err != nil {
+		t.Fatalf("Load(polecat): %v", err)
+	}
+	d := polecat.Evaluate(Input{Tool: "Bash", Command: "curl x"}, Vars{})
+	if !d.Blocked() || d.Layer != "town" {
+		t.Errorf("polecat curl = %+v, want blocked by town layer", d)
+	}
+	for _, r := range polecat.Rules {
+		if r.Name == "polecat-worktree" {
+			t.Error("polecat-worktree should be disabled by the rig role layer")
+		}
+	}
+
+	// Other rigs keep the built-in worktree rule.
+	other, err := Load(townRoot, "beads", "polecats")
+	if err != nil {
+		t.Fatalf("Load(beads polecats): %v", err)
+	}
+	if d := other.Evaluate(Input{Tool: "Write", Paths: []string{"/etc/x"}}, Vars{Worktree: "/w"}); d.Rule != "polecat-worktree" {
+		t.Errorf("beads polecat write = %+v, want blocked by polecat-worktree", d)
+	}
+
+	write("settings/guard-policy.json", `{"rules": [{"name": "x", "action": "deny"}]}`)
+	if _, err := Load(townRoot, "", ""); err == nil {
+		t.Error("expected error for invalid policy file")
+	}
+}
@@ -0,0 +1,184 @@
+package guard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// PolicyFile is the name of a town or rig policy file, kept in the
+// settings directory. Role policies live in the PolicyDir directory next to
+// it, one <role>.json per role.
+const (
+	PolicyFile = "guard-policy.json"
+	PolicyDir  = "guard-policy"
+)
+
+// LayerBuiltin is the layer name of the built-in default rules.
+const LayerBuiltin = "built-in"
+
+// Layer is one policy layer.
+type Layer struct {
+	Name string // Display name (e.g. "town", "rig:gastown/polecats")
+	Path string // Policy file, empty for built-in defaults
+}
+
+// Roles accepted in role policy names. "polecat" is an alias of "polecats",
+// matching hooks override targets.
+var roles = map[string]bool{
+	"mayor": true, "deacon": true, "witness": true, "refinery": true,
+	"crew": true, "polecats": true, "dog": true, "boot": true,
+}
+
+// NormalizeRole maps a role name to its policy name ("polecat" → "polecats").
+// Returns "" for unknown roles.
+func NormalizeRole(role string) string {
+	if role == "polecat" {
+		return "polecats"
+	}
+	if roles[role] {
+		return role
+	}
+	return ""
+}
+
+// Layers returns the policy files that apply to an agent, least specific
+// first: town, town role, rig and rig role. rig and role may be empty.
+func Layers(townRoot, rig, role string) []Layer {
+	role = NormalizeRole(role)
+	townSettings := filepath.Join(townRoot, "settings")
+	layers := []Layer{{Name: "town", Path: filepath.Join(townSettings, PolicyFile)}}
+	if role != "" {
+		layers = append(layers, Layer{Name: "town:" + role, Path: filepath.Join(townSettings, PolicyDir, role+".json")})
+	}
+	if rig != "" {
+		rigSettings := filepath.Join(townRoot, rig, "settings")
+		layers = append(layers, Layer{Name: "rig:" + rig, Path: filepath.Join(rigSettings, PolicyFile)})
+		if role != "" {
+			layers = append(layers, Layer{Name: "rig:" + rig + "/" + role, Path: filepath.Join(rigSettings, PolicyDir, role+".json")})
+		}
+	}
+	return layers
+}
+
+// Load computes the policy for an agent: the built-in defaults for its role,
+// with each policy file from Layers merged on top. Missing files are skipped.
+func Load(townRoot, rig, role string) (*Policy, error) {
+	policy := DefaultPolicy()
+	if rp, ok := DefaultRolePolicies()[NormalizeRole(role)]; ok {
+		policy = Merge(policy, rp)
+	}
+	if townRoot == "" {
+		return policy, nil
+	}
+	for _, layer := range Layers(townRoot, rig, role) {
+		p, err := LoadFile(layer.Path, layer.Name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		policy = Merge(policy, p)
+	}
+	return policy, nil
+}
+
+// LoadFile loads and validates a policy file, tagging its rules with layer.
+// Returns an error satisfying os.IsNotExist if the file doesn't exist.
+func LoadFile(path, layer string) (*Policy, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from the town layout
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for i := range p.Rules {
+		p.Rules[i].Layer = layer
+	}
+	return &p, nil
+}
+
+// DefaultPolicy returns the built-in rules applied to every agent. These
+// replace the fixed patterns of the dangerous-command and pr-workflow guards.
+func DefaultPolicy() *Policy {
+	return builtin([]Rule{
+		{
+			Name:   "rm-rf-absolute",
+			Action: ActionBlock,
+			Reason: "rm -rf with absolute path can destroy system files",
+			Match:  Match{Commands: []string{"rm -rf /*", "rm -rf ~*"}},
+		},
+		{
+			Name:   "force-push",
+			Action: ActionBlock,
+			Reason: "Force push rewrites remote history and can destroy others' work",
+			Match:  Match{Git: []string{"push --force", "push --force-with-lease", "push -f", "push +*"}},
+		},
+		{
+			Name:   "hard-reset",
+			Action: ActionBlock,
+			Reason: "Hard reset discards all uncommitted changes irreversibly",
+			Match:  Match{Git: []string{"reset --hard"}},
+		},
+		{
+			Name:   "git-clean",
+			Action: ActionBlock,
+			Reason: "git clean -f deletes untracked files irreversibly",
+			Match:  Match{Git: []string{"clean -f"}},
+		},
+		{
+			Name:   "pr-workflow",
+			Action: ActionBlock,
+			Reason: "Gas Town workers push directly to main. PRs are forbidden.",
+			Match:  Match{Commands: []string{"gh pr create", "git checkout -b", "git switch -c"}},
+		},
+	})
+}
+
+// DefaultRolePolicies returns the built-in role rules, keyed by role.
+func DefaultRolePolicies() map[string]*Policy {
+	return map[string]*Policy{
+		// Polecats work in their own worktree; anything they write elsewhere
+		// is another agent's (or the user's) state.
+		"polecats": builtin([]Rule{
+			{
+				Name:   "polecat-worktree",
+				Action: ActionBlock,
+				Reason: "Polecats may only write inside their worktree",
+				Match: Match{
+					Tools:        []string{"Bash", "Edit", "Write", "MultiEdit", "NotebookEdit"},
+					PathsOutside: []string{"{worktree}", "{tmp}"},
+					Writes:       true,
+				},
+			},
+		}),
+		// Crew are trusted with the whole town, but writes outside it are
+		// recorded for review.
+		"crew": builtin([]Rule{
+			{
+				Name:   "crew-outside-town",
+				Action: ActionLog,
+				Reason: "Crew wrote outside the town",
+				Match: Match{
+					Tools:        []string{"Bash", "Edit", "Write", "MultiEdit", "NotebookEdit"},
+					PathsOutside: []string{"{town}", "{tmp}"},
+					Writes:       true,
+				},
+			},
+		}),
+	}
+}
+
+func builtin(rules []Rule) *Policy {
+	for i := range rules {
+		rules[i].Layer = LayerBuiltin
+	}
+	return &Policy{Rules: rules}
+}
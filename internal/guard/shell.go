@@ -0,0 +1,393 @@
+package guard
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Command is one simple command from a shell command line: a program and
+// its arguments, with quoting removed, leading variable assignments and
+// wrappers (sudo, env, timeout, ...) stripped, plus its redirection targets.
+type Command struct {
+	Name      string   // Program name as written (e.g. "git", "/bin/rm")
+	Args      []string // Arguments after the program name
+	Redirects []string // Files written by > and >> redirections
+}
+
+// Program returns the base name of the program (e.g. "rm" for "/bin/rm").
+func (c Command) Program() string {
+	return filepath.Base(c.Name)
+}
+
+// ParseCommands splits a shell command line into simple commands. It
+// understands quoting, escapes, the ; & && || | operators, subshells and
+// command substitution ($(...) and backticks, parsed as commands of their
+// own), and scripts passed to sh -c, bash -c and eval.
+//
+// This is a best-effort parser for policy checks, not a shell: it does not
+// expand variables, globs or aliases.
+func ParseCommands(line string) []Command {
+	var cmds []Command
+	p := &shellParser{src: []rune(line)}
+	for _, words := range p.parse() {
+		cmds = append(cmds, buildCommands(words)...)
+	}
+	for _, sub := range p.subs {
+		cmds = append(cmds, ParseCommands(sub)...)
+	}
+	return cmds
+}
+
+// word is a shell word, or an operator when op is set.
+type word struct {
+	text string
+	op   bool
+}
+
+type shellParser struct {
+	src  []rune
+	pos  int
+	subs []string // Command substitutions found while parsing
+}
+
+// parse returns the words of each simple command, split on operators.
+func (p *shellParser) parse() [][]word {
+	var (
+		cmds [][]word
+		cur  []word
+	)
+	flush := func() {
+		if len(cur) > 0 {
+			cmds = append(cmds, cur)
+			cur = nil
+		}
+	}
+	for {
+		w, ok := p.next()
+		if !ok {
+			break
+		}
+		if w.op {
+			switch w.text {
+			case ";", "&", "&&", "||", "|", "|&", "\n", "(", ")", "{", "}":
+				flush()
+				continue
+			}
+		}
+		cur = append(cur, w)
+	}
+	flush()
+	return cmds
+}
+
+// next returns the next word or operator.
+func (p *shellParser) next() (word, bool) {
+	// Skip blanks and comments
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == ' ' || c == '\t' || c == '\r' {
+			p.pos++
+			continue
+		}
+		if c == '\\' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '\n' {
+			p.pos += 2
+			continue
+		}
+		if c == '#' {
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+			continue
+		}
+		break
+	}
+	if p.pos >= len(p.src) {
+		return word{}, false
+	}
+
+	p.skipRedirectFD()
+	if op := p.operator(); op != "" {
+		return word{text: op, op: true}, true
+	}
+
+	var sb strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			return word{text: sb.String()}, true
+		case strings.ContainsRune(";&|()<>", c):
+			return word{text: sb.String()}, true
+		case c == '\\':
+			p.pos++
+			if p.pos < len(p.src) {
+				sb.WriteRune(p.src[p.pos])
+				p.pos++
+			}
+		case c == '\'':
+			p.pos++
+			for p.pos < len(p.src) && p.src[p.pos] != '\'' {
+				sb.WriteRune(p.src[p.pos])
+				p.pos++
+			}
+			p.pos++
+		case c == '"':
+			p.pos++
+			for p.pos < len(p.src) && p.src[p.pos] != '"' {
+				switch {
+				case p.src[p.pos] == '\\' && p.pos+1 < len(p.src) && strings.ContainsRune("\"\\$`", p.src[p.pos+1]):
+					sb.WriteRune(p.src[p.pos+1])
+					p.pos += 2
+				case p.src[p.pos] == '$' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '(':
+					sb.WriteString(p.substitution())
+				case p.src[p.pos] == '`':
+					sb.WriteString(p.backtick())
+				default:
+					sb.WriteRune(p.src[p.pos])
+					p.pos++
+				}
+			}
+			p.pos++
+		case c == '$' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '(':
+			sb.WriteString(p.substitution())
+		case c == '`':
+			sb.WriteString(p.backtick())
+		default:
+			sb.WriteRune(c)
+			p.pos++
+		}
+	}
+	return word{text: sb.String()}, true
+}
+
+// skipRedirectFD skips the fd number of a redirection like "2>file".
+func (p *shellParser) skipRedirectFD() {
+	i := p.pos
+	for i < len(p.src) && p.src[i] >= '0' && p.src[i] <= '9' {
+		i++
+	}
+	if i > p.pos && i < len(p.src) && (p.src[i] == '>' || p.src[i] == '<') {
+		p.pos = i
+	}
+}
+
+// operator reads an operator at the current position, or returns "".
+func (p *shellParser) operator() string {
+	for _, op := range []string{"&&", "||", "|&", ";;", ">>", "&>", ">&", ">|", "<<", ";", "&", "|", "(", ")", ">", "<", "\n"} {
+		if p.hasPrefix(op) {
+			p.pos += len([]rune(op))
+			return op
+		}
+	}
+	// Braces are only grouping operators as whole words.
+	if c := p.src[p.pos]; c == '{' || c == '}' {
+		if p.pos+1 >= len(p.src) || strings.ContainsRune(" \t\n;", p.src[p.pos+1]) {
+			p.pos++
+			return string(c)
+		}
+	}
+	return ""
+}
+
+func (p *shellParser) hasPrefix(s string) bool {
+	r := []rune(s)
+	if p.pos+len(r) > len(p.src) {
+		return false
+	}
+	for i, c := range r {
+		if p.src[p.pos+i] != c {
+			return false
+		}
+	}
+	return true
+}
+
+// substitution reads $(...) at the current position, records its contents
+// for separate parsing and returns the literal text.
+func (p *shellParser) substitution() string {
+	start := p.pos
+	p.pos += 2
+	depth := 1
+	var quote rune
+	for p.pos < len(p.src) && depth > 0 {
+		c := p.src[p.pos]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				p.pos++
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '\\':
+			p.pos++
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		}
+		p.pos++
+	}
+	end := p.pos
+	if depth == 0 {
+		end--
+	}
+	if start+2 <= end {
+		p.subs = append(p.subs, string(p.src[start+2:end]))
+	}
+	return string(p.src[start:p.pos])
+}
+
+// backtick reads `...` at the current position like substitution.
+func (p *shellParser) backtick() string {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.src) && p.src[p.pos] != '`' {
+		if p.src[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	end := p.pos
+	if p.pos < len(p.src) {
+		p.pos++
+	}
+	p.subs = append(p.subs, string(p.src[start+1:min(end, len(p.src))]))
+	return string(p.src[start:p.pos])
+}
+
+// buildCommands turns the words of one simple command into a Command,
+// resolving redirections and unwrapping wrapper programs and inline scripts.
+func buildCommands(words []word) []Command {
+	var (
+		cmd  Command
+		argv []string
+	)
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		if !w.op {
+			argv = append(argv, w.text)
+			continue
+		}
+		if i+1 >= len(words) || words[i+1].op {
+			continue
+		}
+		target := words[i+1].text
+		i++
+		switch w.text {
+		case ">", ">>", "&>", ">|":
+			cmd.Redirects = append(cmd.Redirects, target)
+		case ">&":
+			if !isFD(target) {
+				cmd.Redirects = append(cmd.Redirects, target)
+			}
+		}
+	}
+
+	argv = unwrap(argv)
+	if len(argv) == 0 {
+		if len(cmd.Redirects) == 0 {
+			return nil
+		}
+		return []Command{cmd}
+	}
+	cmd.Name, cmd.Args = argv[0], argv[1:]
+	cmds := []Command{cmd}
+
+	// Inline scripts are checked as commands of their own.
+	switch cmd.Program() {
+	case "sh", "bash", "zsh", "dash", "ksh":
+		for i, a := range cmd.Args {
+			if strings.HasPrefix(a, "-") && !strings.HasPrefix(a, "--") && strings.Contains(a, "c") && i+1 < len(cmd.Args) {
+				cmds = append(cmds, ParseCommands(cmd.Args[i+1])...)
+				break
+			}
+		}
+	case "eval":
+		cmds = append(cmds, ParseCommands(strings.Join(cmd.Args, " "))...)
+	}
+	return cmds
+}
+
+// wrapper describes a program that runs another command given as its
+// arguments.
+type wrapper struct {
+	operands  int      // Positional arguments the wrapper itself consumes
+	valueOpts []string // Options that take the next argument as their value
+}
+
+var wrappers = map[string]wrapper{
+	"sudo":    {valueOpts: []string{"-u", "-g", "-C", "-D", "-h", "-p", "-r", "-t", "-U", "--user", "--group", "--chdir", "--host", "--prompt", "--role", "--type", "--other-user", "--close-from"}},
+	"doas":    {valueOpts: []string{"-u", "-C"}},
+	"env":     {valueOpts: []string{"-u", "-C", "--unset", "--chdir"}},
+	"command": {},
+	"builtin": {},
+	"exec":    {valueOpts: []string{"-a"}},
+	"nohup":   {},
+	"time":    {valueOpts: []string{"-f", "-o", "--format", "--output"}},
+	"nice":    {valueOpts: []string{"-n", "--adjustment"}},
+	"ionice":  {valueOpts: []string{"-c", "-n", "-p", "--class", "--classdata"}},
+	"stdbuf":  {valueOpts: []string{"-i", "-o", "-e", "--input", "--output", "--error"}},
+	"xargs":   {valueOpts: []string{"-I", "-L", "-n", "-P", "-s", "-d", "-E", "-a", "--max-args", "--max-procs", "--delimiter", "--arg-file"}},
+	"timeout": {operands: 1, valueOpts: []string{"-s", "-k", "--signal", "--kill-after"}},
+}
+
+// unwrap strips leading variable assignments and wrapper programs (with
+// their options) from argv, returning the wrapped command.
+func unwrap(argv []string) []string {
+	for len(argv) > 0 {
+		if isAssignment(argv[0]) {
+			argv = argv[1:]
+			continue
+		}
+		w, ok := wrappers[filepath.Base(argv[0])]
+		if !ok {
+			return argv
+		}
+		argv = argv[1:]
+		for len(argv) > 0 && (strings.HasPrefix(argv[0], "-") || isAssignment(argv[0])) {
+			opt := argv[0]
+			argv = argv[1:]
+			if opt == "--" {
+				break
+			}
+			for _, v := range w.valueOpts {
+				if opt == v && len(argv) > 0 {
+					argv = argv[1:]
+					break
+				}
+			}
+		}
+		for n := w.operands; n > 0 && len(argv) > 0; n-- {
+			argv = argv[1:]
+		}
+	}
+	return argv
+}
+
+// isAssignment reports whether s is a NAME=value variable assignment.
+func isAssignment(s string) bool {
+	eq := strings.IndexByte(s, '=')
+	if eq <= 0 {
+		return false
+	}
+	for i, c := range s[:eq] {
+		if !(c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || i > 0 && c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isFD(s string) bool {
+	if s == "-" {
+		return true
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
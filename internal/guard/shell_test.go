@@ -0,0 +1,70 @@
+package guard
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCommands(t *testing.T) {
+	tests := []struct {
+		line string
+		want []Command
+	}{
+		{"", nil},
+		{"ls -la", []Command{{Name: "ls", Args: []string{"-la"}}}},
+		{
+			`git commit -m "fix: a; b" && git push`,
+			[]Command{
+				{Name: "git", Args: []string{"commit", "-m", "fix: a; b"}},
+				{Name: "git", Args: []string{"push"}},
+			},
+		},
+		{
+			"cat a | grep 'x y' > out.txt 2>&1",
+			[]Command{
+				{Name: "cat", Args: []string{"a"}},
+				{Name: "grep", Args: []string{"x y"}, Redirects: []string{"out.txt"}},
+			},
+		},
+		{
+			"FOO=1 sudo -u root env BAR=2 timeout 10 rm -rf /x",
+			[]Command{{Name: "rm", Args: []string{"-rf", "/x"}}},
+		},
+		{
+			`bash -c "rm -rf /x; echo done"`,
+			[]Command{
+				{Name: "bash", Args: []string{"-c", "rm -rf /x; echo done"}},
+				{Name: "rm", Args: []string{"-rf", "/x"}},
+				{Name: "echo", Args: []string{"done"}},
+			},
+		},
+		{
+			"echo $(git reset --hard) `whoami`",
+			[]Command{
+				{Name: "echo", Args: []string{"$(git reset --hard)", "`whoami`"}},
+				{Name: "git", Args: []string{"reset", "--hard"}},
+				{Name: "whoami", Args: []string{}},
+			},
+		},
+		{
+			"(cd /tmp && touch x) # comment; rm -rf /",
+			[]Command{
+				{Name: "cd", Args: []string{"/tmp"}},
+				{Name: "touch", Args: []string{"x"}},
+			},
+		},
+		{
+			`echo a\ b >>log`,
+			[]Command{{Name: "echo", Args: []string{"a b"}, Redirects: []string{"log"}}},
+		},
+	}
+	for _, tt := range tests {
+		got := ParseCommands(tt.line)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseCommands(%q) =\n  %#v\nwant\n  %#v", tt.line, got, tt.want)
+		}
+	}
+}
@@ -202,15 +202,48 @@ func Merge(base, override *HooksConfig) *HooksConfig {
 // Crew workers get auto-session-cycling on PreCompact: instead of compacting
 // context (which degrades quality), the session is replaced with a fresh one.
 // The successor picks up hooked work via SessionStart hook (gt prime --hook).
+//
+// Crew and polecats check every shell command and file edit against the
+// guard policy (gt tap guard policy), which restricts polecats to their
+// worktree. The policy's default rules cover the base dangerous-command and
+// pr-workflow guards, so those are removed for these roles rather than
+// evaluating each command twice.
 func DefaultOverrides() map[string]*HooksConfig {
 	pathSetup := `export PATH="$HOME/go/bin:$HOME/.local/bin:$PATH"`
+	policyGuard := []HookEntry{
+		{
+			Matcher: "Bash",
+			Hooks: []Hook{{
+				Type:    "command",
+				Command: fmt.Sprintf("%s && gt tap guard policy", pathSetup),
+			}},
+		},
+		{
+			Matcher: "Edit|Write|MultiEdit|NotebookEdit",
+			Hooks: []Hook{{
+				Type:    "command",
+				Command: fmt.Sprintf("%s && gt tap guard policy", pathSetup),
+			}},
+		},
+	}
+	for _, entry := range DefaultBase().PreToolUse {
+		if isPolicyCoveredGuard(entry) {
+			// Empty hooks remove the base entry on merge.
+			policyGuard = append(policyGuard, HookEntry{Matcher: entry.Matcher, Hooks: []Hook{}})
+		}
+	}
 
 	return map[string]*HooksConfig{
+		// Polecats: guard policy keeps them inside their worktree.
+		"polecats": {
+			PreToolUse: cloneEntries(policyGuard),
+		},
 		// Crew workers: auto-cycle session on context compaction (gt-op78).
 		// Instead of compacting (lossy), replace with fresh session that
 		// inherits hooked work. The --cycle flag does: collect state →
 		// send handoff mail → respawn pane with fresh Claude instance.
 		"crew": {
+			PreToolUse: cloneEntries(policyGuard),
 			PreCompact: []HookEntry{
 				{
 					Matcher: "",
@@ -708,6 +741,18 @@ func ValidTarget(target string) bool {
 	return ok
 }
 
+// isPolicyCoveredGuard reports whether a PreToolUse entry runs one of the
+// fixed-pattern guards superseded by the guard policy.
+func isPolicyCoveredGuard(entry HookEntry) bool {
+	for _, h := range entry.Hooks {
+		if strings.HasSuffix(h.Command, "gt tap guard dangerous-command") ||
+			strings.HasSuffix(h.Command, "gt tap guard pr-workflow") {
+			return true
+		}
+	}
+	return false
+}
+
 // DefaultBase returns a sensible default base configuration.
 // This includes PATH setup and gt prime hooks that all agents need.
 func DefaultBase() *HooksConfig {
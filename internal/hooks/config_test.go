@@ -444,18 +444,21 @@ func TestComputeExpected(t *testing.T) {
 	if len(expected.SessionStart) != 1 || expected.SessionStart[0].Hooks[0].Command != "gastown-crew-session" {
 		t.Errorf("expected gastown/crew SessionStart, got %v", expected.SessionStart)
 	}
-	// On-disk base has no PreToolUse, so DefaultBase's 3 pr-workflow guards are
-	// backfilled. The crew override adds Bash(git*), making 4 total.
-	defaultPTU := len(DefaultBase().PreToolUse)
-	if len(expected.PreToolUse) != defaultPTU+1 {
-		t.Errorf("expected %d PreToolUse (default %d + crew 1), got %d", defaultPTU+1, defaultPTU, len(expected.PreToolUse))
+	// On-disk base has no PreToolUse, so DefaultBase's guards are backfilled.
+	// The built-in crew override replaces them with the 2 policy guards, and
+	// the on-disk crew override adds Bash(git*).
+	if len(expected.PreToolUse) != 3 {
+		t.Errorf("expected 3 PreToolUse (policy 2 + crew 1), got %d: %+v", len(expected.PreToolUse), expected.PreToolUse)
 	}
-	// Verify crew-guard is present
+	// Verify crew-guard is present and the policy replaced the base guards
 	hasCrewGuard := false
 	for _, e := range expected.PreToolUse {
 		if e.Matcher == "Bash(git*)" && e.Hooks[0].Command == "crew-guard" {
 			hasCrewGuard = true
 		}
+		if isPolicyCoveredGuard(e) {
+			t.Errorf("base guard %q should be replaced by the policy guard", e.Matcher)
+		}
 	}
 	if !hasCrewGuard {
 		t.Error("expected crew PreToolUse guard to be present")
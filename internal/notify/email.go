@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// SendEmail sends n to the address to through the configured SMTP server,
+// using STARTTLS, implicit TLS or no encryption as configured, and PLAIN
+// auth when a username is set.
+func SendEmail(ctx context.Context, cfg *config.SMTPConfig, to string, n Notification) error {
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.GetPort()))
+	tlsConfig := &tls.Config{ServerName: cfg.Host, MinVersion: tls.VersionTLS12}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if cfg.TLS == config.SMTPTLSImplicit {
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return fmt.Errorf("TLS handshake with %s: %w", addr, err)
+		}
+		conn = tlsConn
+	}
+
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP greeting from %s: %w", addr, err)
+	}
+	defer c.Close()
+
+	if cfg.TLS == "" || cfg.TLS == config.SMTPTLSStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%s does not support STARTTLS (set tls to \"none\" for unencrypted relays)", addr)
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS with %s: %w", addr, err)
+		}
+	}
+	if cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", cfg.Username, cfg.GetPassword(), cfg.Host)); err != nil {
+			return fmt.Errorf("SMTP auth: %w", err)
+		}
+	}
+
+	if err := c.Mail(cfg.From); err != nil {
+		return fmt.Errorf("MAIL FROM: %w", err)
+	}
+	if err := c.Rcpt(to); err != nil {
+		return fmt.Errorf("RCPT TO %s: %w", to, err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("DATA: %w", err)
+	}
+	if _, err := w.Write(formatEmail(cfg.From, to, n, time.Now())); err != nil {
+		return fmt.Errorf("writing message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("sending message: %w", err)
+	}
+	return c.Quit()
+}
+
+// formatEmail renders an RFC 5322 plain-text message.
+func formatEmail(from, to string, n Notification, now time.Time) []byte {
+	var sb strings.Builder
+	header := func(k, v string) {
+		sb.WriteString(k + ": " + v + "\r\n")
+	}
+	header("From", from)
+	header("To", to)
+	header("Subject", oneLine(n.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	if n.EscalationID != "" {
+		header("X-Gastown-Escalation", n.EscalationID)
+	}
+	if n.Severity == config.SeverityCritical || n.Severity == config.SeverityHigh {
+		header("X-Priority", "1")
+	}
+	sb.WriteString("\r\n")
+	for _, line := range strings.Split(n.Body, "\n") {
+		// Dot-stuffing is handled by the smtp DATA writer.
+		sb.WriteString(strings.TrimRight(line, "\r") + "\r\n")
+	}
+	return []byte(sb.String())
+}
+
+// oneLine collapses a header value to a single line.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
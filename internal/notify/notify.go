@@ -0,0 +1,175 @@
+// Package notify delivers escalations to people outside the town: email over
+// SMTP, SMS through a gateway, and Slack incoming webhooks.
+//
+// Channels and credentials are configured in settings/escalation.json
+// (config.EscalationConfig contacts and delivery). Each delivery is retried
+// with exponential backoff within an overall deadline; the per-action
+// outcome is returned so callers can record it on the escalation bead.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Notification is an escalation rendered for external channels.
+type Notification struct {
+	EscalationID string
+	Severity     string
+	Subject      string // One line; used as email subject and SMS text
+	Body         string // Full text; used for email and Slack
+}
+
+// Result is the outcome of one external action.
+type Result struct {
+	Action   string // Route action, e.g. "email:human" or "slack"
+	Target   string // Address, number or "webhook"
+	Attempts int    // Delivery attempts made (0 when skipped)
+	Skipped  string // Why the action was not attempted
+	Err      error  // Last error when every attempt failed
+}
+
+// OK reports whether the action was delivered.
+func (r Result) OK() bool {
+	return r.Skipped == "" && r.Err == nil
+}
+
+// String formats the result for display and for the escalation bead.
+func (r Result) String() string {
+	switch {
+	case r.Skipped != "":
+		return fmt.Sprintf("%s skipped (%s)", r.Action, r.Skipped)
+	case r.Err != nil:
+		return fmt.Sprintf("%s failed after %d attempt(s) (%v)", r.Action, r.Attempts, r.Err)
+	default:
+		return fmt.Sprintf("%s sent to %s", r.Action, r.Target)
+	}
+}
+
+// IsExternal reports whether a route action is delivered by this package.
+func IsExternal(action string) bool {
+	return strings.HasPrefix(action, "email:") || strings.HasPrefix(action, "sms:") || action == "slack"
+}
+
+// Deliver sends n for each external action in actions and returns one
+// result per external action, in order. Actions are delivered in parallel
+// and retries stop at the delivery deadline, so an unreachable channel
+// costs the caller at most that long. Actions without a configured contact
+// or channel are skipped, not failed.
+func Deliver(ctx context.Context, cfg *config.EscalationConfig, actions []string, n Notification) []Result {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Delivery.GetDeadline())
+	defer cancel()
+
+	var external []string
+	for _, action := range actions {
+		if IsExternal(action) {
+			external = append(external, action)
+		}
+	}
+	results := make([]Result, len(external))
+	var wg sync.WaitGroup
+	for i, action := range external {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = deliverAction(ctx, cfg, action, n)
+		}()
+	}
+	wg.Wait()
+	if len(results) == 0 {
+		return nil
+	}
+	return results
+}
+
+func deliverAction(ctx context.Context, cfg *config.EscalationConfig, action string, n Notification) Result {
+	r := Result{Action: action}
+	d := cfg.Delivery
+
+	var send func(context.Context) error
+	switch {
+	case strings.HasPrefix(action, "email:"):
+		r.Target = cfg.Contacts.HumanEmail
+		switch {
+		case r.Target == "":
+			r.Skipped = "contacts.human_email not configured"
+		case d == nil || d.SMTP == nil:
+			r.Skipped = "delivery.smtp not configured"
+		default:
+			send = func(ctx context.Context) error { return SendEmail(ctx, d.SMTP, r.Target, n) }
+		}
+
+	case strings.HasPrefix(action, "sms:"):
+		r.Target = cfg.Contacts.HumanSMS
+		switch {
+		case r.Target == "":
+			r.Skipped = "contacts.human_sms not configured"
+		case d == nil || d.SMS == nil:
+			r.Skipped = "delivery.sms not configured"
+		default:
+			gw, err := NewSMSGateway(d.SMS)
+			if err != nil {
+				r.Err = err
+				return r
+			}
+			send = func(ctx context.Context) error { return gw.SendSMS(ctx, r.Target, SMSText(n)) }
+		}
+
+	case action == "slack":
+		r.Target = "webhook"
+		if cfg.Contacts.SlackWebhook == "" {
+			r.Skipped = "contacts.slack_webhook not configured"
+		} else {
+			send = func(ctx context.Context) error { return PostSlack(ctx, cfg.Contacts.SlackWebhook, n) }
+		}
+	}
+	if send == nil {
+		return r
+	}
+
+	r.Attempts, r.Err = Retry(ctx, d.GetMaxAttempts(), d.GetBackoff(), func() error {
+		attemptCtx, cancel := context.WithTimeout(ctx, d.GetTimeout())
+		defer cancel()
+		return send(attemptCtx)
+	})
+	return r
+}
+
+// Retry calls fn up to attempts times, sleeping backoff before the second
+// attempt and doubling it after each failure. It returns the attempts made
+// and the last error, or nil once fn succeeds.
+func Retry(ctx context.Context, attempts int, backoff time.Duration, fn func() error) (int, error) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for i := 1; i <= attempts; i++ {
+		if err = fn(); err == nil {
+			return i, nil
+		}
+		if i == attempts {
+			return i, err
+		}
+		select {
+		case <-ctx.Done():
+			return i, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return attempts, err
+}
+
+// SMSText returns the short text sent by SMS.
+func SMSText(n Notification) string {
+	text := n.Subject
+	if n.EscalationID != "" {
+		text += " (" + n.EscalationID + ")"
+	}
+	return text
+}
@@ -0,0 +1,280 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+var testNotification = Notification{
+	EscalationID: "hq-abc",
+	Severity:     config.SeverityCritical,
+	Subject:      "[CRITICAL] Disk full",
+	Body:         "Escalation ID: hq-abc\n.\nDisk >95%",
+}
+
+// smtpStub is a minimal plaintext SMTP server that accepts one message.
+type smtpStub struct {
+	addr string
+	auth chan string
+	msgs chan string
+}
+
+func startSMTPStub(t *testing.T) *smtpStub {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	s := &smtpStub{addr: ln.Addr().String(), auth: make(chan string, 1), msgs: make(chan string, 1)}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 stub ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"):
+				reply("250-stub")
+				reply("250 AUTH PLAIN")
+			case strings.HasPrefix(cmd, "AUTH PLAIN"):
+				raw, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(cmd, "AUTH PLAIN "))
+				s.auth <- string(raw)
+				reply("235 ok")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var msg strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					msg.WriteString(l)
+				}
+				s.msgs <- msg.String()
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return s
+}
+
+func (s *smtpStub) config(t *testing.T) *config.SMTPConfig {
+	host, port, _ := net.SplitHostPort(s.addr)
+	p, _ := strconv.Atoi(port)
+	t.Setenv("TEST_SMTP_PASSWORD", "hunter2")
+	return &config.SMTPConfig{
+		Host:        host,
+		Port:        p,
+		TLS:         config.SMTPTLSNone,
+		From:        "gastown@example.com",
+		Username:    "gastown",
+		PasswordEnv: "TEST_SMTP_PASSWORD",
+	}
+}
+
+func TestSendEmail(t *testing.T) {
+	stub := startSMTPStub(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := SendEmail(ctx, stub.config(t), "oncall@example.com", testNotification); err != nil {
+		t.Fatalf("SendEmail: %v", err)
+	}
+	if auth := <-stub.auth; auth != "\x00gastown\x00hunter2" {
+		t.Errorf("auth = %q", auth)
+	}
+	msg := <-stub.msgs
+	for _, want := range []string{
+		"From: gastown@example.com\r\n",
+		"To: oncall@example.com\r\n",
+		"Subject: [CRITICAL] Disk full\r\n",
+		"X-Gastown-Escalation: hq-abc\r\n",
+		"\r\n..\r\nDisk >95%\r\n", // dot-stuffed body line
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestSendEmail_RequiresStartTLS(t *testing.T) {
+	stub := startSMTPStub(t)
+	cfg := stub.config(t)
+	cfg.TLS = "" // default: STARTTLS, which the stub doesn't offer
+	err := SendEmail(context.Background(), cfg, "oncall@example.com", testNotification)
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("SendEmail without STARTTLS = %v, want STARTTLS error", err)
+	}
+}
+
+func TestHTTPGateway(t *testing.T) {
+	var got map[string]string
+	var authz string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authz = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	t.Setenv("GT_TEST_SMS_TOKEN", "tok")
+	gw, err := NewSMSGateway(&config.SMSGatewayConfig{
+		Provider: config.SMSProviderHTTP,
+		URL:      srv.URL,
+		From:     "+15550000000",
+		TokenEnv: "GT_TEST_SMS_TOKEN",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.SendSMS(context.Background(), "+15551234567", SMSText(testNotification)); err != nil {
+		t.Fatalf("SendSMS: %v", err)
+	}
+	if authz != "Bearer tok" {
+		t.Errorf("Authorization = %q", authz)
+	}
+	want := map[string]string{"to": "+15551234567", "from": "+15550000000", "body": "[CRITICAL] Disk full (hq-abc)"}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+
+	if _, err := NewSMSGateway(&config.SMSGatewayConfig{Provider: "pigeon"}); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
+func TestDeliver_RetriesUntilSuccess(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if !strings.Contains(body["text"], "Disk full") {
+			t.Errorf("slack text = %q", body["text"])
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.EscalationConfig{
+		Contacts: config.EscalationContacts{SlackWebhook: srv.URL},
+		Delivery: &config.EscalationDelivery{Backoff: "1ms"},
+	}
+	results := Deliver(context.Background(), cfg, []string{"bead", "mail:mayor", "slack"}, testNotification)
+	if len(results) != 1 {
+		t.Fatalf("results = %v, want one", results)
+	}
+	if r := results[0]; !r.OK() || r.Attempts != 3 {
+		t.Errorf("result = %+v, want delivered on attempt 3", r)
+	}
+}
+
+func TestDeliver_ReportsFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gateway down", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	cfg := &config.EscalationConfig{
+		Contacts: config.EscalationContacts{HumanSMS: "+15551234567", HumanEmail: "oncall@example.com"},
+		Delivery: &config.EscalationDelivery{
+			MaxAttempts: 2,
+			Backoff:     "1ms",
+			SMS:         &config.SMSGatewayConfig{Provider: config.SMSProviderHTTP, URL: srv.URL},
+		},
+	}
+	results := Deliver(context.Background(), cfg, []string{"email:human", "sms:human"}, testNotification)
+	if len(results) != 2 {
+		t.Fatalf("results = %v, want two", results)
+	}
+	if r := results[0]; r.Skipped != "delivery.smtp not configured" {
+		t.Errorf("email result = %+v, want skipped", r)
+	}
+	r := results[1]
+	if r.OK() || r.Attempts != 2 || !strings.Contains(r.Err.Error(), "502") {
+		t.Errorf("sms result = %+v, want failure after 2 attempts", r)
+	}
+	if s := r.String(); !strings.HasPrefix(s, "sms:human failed after 2 attempt(s)") {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestDeliver_BoundedByDeadline(t *testing.T) {
+	// Both channels hang until the test ends.
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	cfg := &config.EscalationConfig{
+		Contacts: config.EscalationContacts{HumanSMS: "+15551234567", SlackWebhook: srv.URL},
+		Delivery: &config.EscalationDelivery{
+			Backoff:  "50ms",
+			Deadline: "300ms",
+			SMS:      &config.SMSGatewayConfig{Provider: config.SMSProviderHTTP, URL: srv.URL},
+		},
+	}
+	start := time.Now()
+	results := Deliver(context.Background(), cfg, []string{"sms:human", "slack"}, testNotification)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Deliver took %v, want it bounded by the 300ms deadline", elapsed)
+	}
+	if len(results) != 2 || results[0].Action != "sms:human" || results[1].Action != "slack" {
+		t.Fatalf("results = %v, want sms:human then slack", results)
+	}
+	for _, r := range results {
+		if r.OK() || r.Err == nil {
+			t.Errorf("%s = %+v, want failure", r.Action, r)
+		}
+	}
+}
+
+func TestRetry_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	attempts, err := Retry(ctx, 5, time.Hour, func() error {
+		calls++
+		cancel()
+		return errors.New("boom")
+	})
+	if attempts != 1 || calls != 1 || err == nil {
+		t.Errorf("Retry = %d, %v after %d calls; want 1 attempt", attempts, err, calls)
+	}
+}
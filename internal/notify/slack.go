@@ -0,0 +1,26 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// PostSlack posts n to a Slack incoming webhook.
+func PostSlack(ctx context.Context, webhookURL string, n Notification) error {
+	body, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("*%s*\n```\n%s\n```", n.Subject, n.Body),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gastown-escalation")
+	return doPost(http.DefaultClient, req, "Slack webhook")
+}
@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// SMSGateway sends text messages. Providers implement it; NewSMSGateway
+// picks one from the escalation delivery config.
+type SMSGateway interface {
+	SendSMS(ctx context.Context, to, text string) error
+}
+
+// NewSMSGateway returns the gateway for cfg.Provider.
+func NewSMSGateway(cfg *config.SMSGatewayConfig) (SMSGateway, error) {
+	switch cfg.Provider {
+	case config.SMSProviderHTTP:
+		return &HTTPGateway{Config: cfg}, nil
+	}
+	return nil, fmt.Errorf("unknown SMS provider %q", cfg.Provider)
+}
+
+// HTTPGateway posts messages as JSON to a generic HTTP SMS endpoint:
+//
+//	{"to": "+15551234567", "from": "...", "body": "..."}
+//
+// with the configured token as a bearer token. Any 2xx response is success.
+type HTTPGateway struct {
+	Config *config.SMSGatewayConfig
+	Client *http.Client // nil means http.DefaultClient
+}
+
+// SendSMS implements SMSGateway.
+func (g *HTTPGateway) SendSMS(ctx context.Context, to, text string) error {
+	body, err := json.Marshal(map[string]string{
+		"to":   to,
+		"from": g.Config.From,
+		"body": text,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.Config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gastown-escalation")
+	if token := g.Config.GetToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for k, v := range g.Config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := g.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return doPost(client, req, "SMS gateway")
+}
+
+// doPost sends req and turns a non-2xx response into an error.
+func doPost(client *http.Client, req *http.Request, what string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting to %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("%s returned %s: %s", what, resp.Status, bytes.TrimSpace(snippet))
+	}
+	return nil
+}
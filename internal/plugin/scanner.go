@@ -173,6 +173,9 @@ func parsePluginMD(content []byte, pluginDir string, location Location, rigName
 	if fm.Name == "" {
 		return nil, fmt.Errorf("missing required field: name")
 	}
+	if err := fm.Execution.validate(fm.Gate); err != nil {
+		return nil, err
+	}
 
 	plugin := &Plugin{
 		Name:         fm.Name,
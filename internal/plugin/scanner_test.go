@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestParsePluginMD(t *testing.T) {
@@ -509,3 +510,80 @@ version = 1
 		t.Errorf("expected location 'rig', got %q", plugins[0].Location)
 	}
 }
+
+func TestParsePluginMD_ScriptMode(t *testing.T) {
+	content := []byte(`+++
+name = "script-plugin"
+description = "Runs a script"
+version = 1
+
+[execution]
+mode = "script"
+entrypoint = "./run.sh --quiet"
+timeout = "30s"
++++
+
+# Script Plugin
+`)
+
+	plugin, err := parsePluginMD(content, "/test/path", LocationTown, "")
+	if err != nil {
+		t.Fatalf("parsePluginMD failed: %v", err)
+	}
+	if !plugin.IsScript() {
+		t.Error("expected script plugin")
+	}
+	if plugin.Execution.Entrypoint != "./run.sh --quiet" {
+		t.Errorf("expected entrypoint './run.sh --quiet', got %q", plugin.Execution.Entrypoint)
+	}
+	if got := plugin.Execution.GetTimeout(); got != 30*time.Second {
+		t.Errorf("expected timeout 30s, got %v", got)
+	}
+}
+
+func TestParsePluginMD_InvalidExecution(t *testing.T) {
+	tests := []struct {
+		name      string
+		execution string
+	}{
+		{"unknown mode", `mode = "binary"`},
+		{"script without entrypoint", `mode = "script"`},
+		{"script with bad timeout", "mode = \"script\"\nentrypoint = \"./run.sh\"\ntimeout = \"soon\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := []byte("+++\nname = \"bad\"\n\n[execution]\n" + tt.execution + "\n+++\n")
+			if _, err := parsePluginMD(content, "/test/path", LocationTown, ""); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestParsePluginMD_ScriptGate(t *testing.T) {
+	tests := []struct {
+		name    string
+		gate    string
+		wantErr bool
+	}{
+		{"no gate", "", false},
+		{"cooldown", "type = \"cooldown\"\nduration = \"1h\"", false},
+		{"manual", "type = \"manual\"", false},
+		{"cron", "type = \"cron\"\nschedule = \"0 9 * * *\"", true},
+		{"condition", "type = \"condition\"\ncheck = \"true\"", true},
+		{"event", "type = \"event\"\non = \"startup\"", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := "+++\nname = \"script\"\n"
+			if tt.gate != "" {
+				content += "\n[gate]\n" + tt.gate + "\n"
+			}
+			content += "\n[execution]\nmode = \"script\"\nentrypoint = \"./run.sh\"\n+++\n"
+			_, err := parsePluginMD([]byte(content), "/test/path", LocationTown, "")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parsePluginMD() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// maxScriptOutput caps the output kept from a script run; the tail is kept,
+// since that's where errors usually are.
+const maxScriptOutput = 16 * 1024
+
+// scriptWaitDelay bounds how long a killed script's output pipes may stay
+// open (e.g. held by a detached grandchild) before Wait gives up.
+const scriptWaitDelay = 5 * time.Second
+
+// ScriptRun is the outcome of running a script plugin's entrypoint.
+type ScriptRun struct {
+	Result   RunResult
+	ExitCode int
+	TimedOut bool
+	Duration time.Duration
+	Output   string // Combined stdout/stderr, tail-truncated
+}
+
+// RunScript runs a script plugin's entrypoint with sh from the plugin
+// directory, killing its process tree when the execution timeout expires.
+// A non-zero exit or timeout is a failed run, not an error; the error is
+// reserved for scripts that could not be started at all.
+//
+// The script gets GT_TOWN_ROOT, GT_PLUGIN and GT_PLUGIN_DIR in its
+// environment, plus GT_RIG and GT_RIG_ROOT for rig-level plugins.
+func RunScript(ctx context.Context, p *Plugin, townRoot string) (*ScriptRun, error) {
+	if !p.IsScript() {
+		return nil, fmt.Errorf("plugin %s is not a script plugin", p.Name)
+	}
+
+	timeout := p.Execution.GetTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", p.Execution.Entrypoint) //nolint:gosec // G204: entrypoint comes from the town's own plugin definitions
+	cmd.Dir = p.Path
+	util.SetProcessGroup(cmd)
+	cmd.WaitDelay = scriptWaitDelay
+
+	env := append(os.Environ(),
+		"GT_TOWN_ROOT="+townRoot,
+		"GT_PLUGIN="+p.Name,
+		"GT_PLUGIN_DIR="+p.Path,
+	)
+	if p.RigName != "" {
+		env = append(env,
+			"GT_RIG="+p.RigName,
+			"GT_RIG_ROOT="+filepath.Join(townRoot, p.RigName),
+		)
+	}
+	cmd.Env = env
+
+	out := &tailBuffer{max: maxScriptOutput}
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting %s entrypoint: %w", p.Name, err)
+	}
+	err := cmd.Wait()
+
+	run := &ScriptRun{
+		Result:   ResultSuccess,
+		Duration: time.Since(start),
+		Output:   out.String(),
+	}
+	if err != nil {
+		run.Result = ResultFailure
+		run.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			run.ExitCode = exitErr.ExitCode()
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			run.TimedOut = true
+		}
+	}
+	return run, nil
+}
+
+// Summary returns a one-line description of the run's outcome.
+func (r *ScriptRun) Summary() string {
+	d := r.Duration.Round(time.Millisecond)
+	switch {
+	case r.TimedOut:
+		return fmt.Sprintf("timed out after %s", d)
+	case r.Result == ResultFailure:
+		return fmt.Sprintf("exited %d after %s", r.ExitCode, d)
+	default:
+		return fmt.Sprintf("succeeded in %s", d)
+	}
+}
+
+// FormatBody formats the run for a plugin run bead's description.
+func (r *ScriptRun) FormatBody(p *Plugin) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Script plugin %s\n\n", r.Summary()))
+	sb.WriteString(fmt.Sprintf("Entrypoint: %s\n", p.Execution.Entrypoint))
+	sb.WriteString(fmt.Sprintf("Exit code: %d\n", r.ExitCode))
+	if out := strings.TrimSpace(r.Output); out != "" {
+		sb.WriteString("\n## Output\n\n```\n")
+		sb.WriteString(out)
+		sb.WriteString("\n```\n")
+	}
+	return sb.String()
+}
+
+// tailBuffer is a concurrency-safe writer that keeps the last max bytes.
+type tailBuffer struct {
+	mu        sync.Mutex
+	buf       []byte
+	max       int
+	truncated bool
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.max; over > 0 {
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.truncated {
+		return "[output truncated]\n" + string(b.buf)
+	}
+	return string(b.buf)
+}
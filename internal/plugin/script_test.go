@@ -0,0 +1,93 @@
+//go:build !windows
+
+package plugin
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func scriptPlugin(t *testing.T, entrypoint, timeout string) *Plugin {
+	t.Helper()
+	return &Plugin{
+		Name:    "test-script",
+		Path:    t.TempDir(),
+		RigName: "gastown",
+		Execution: &Execution{
+			Mode:       ModeScript,
+			Entrypoint: entrypoint,
+			Timeout:    timeout,
+		},
+	}
+}
+
+func TestRunScript_Success(t *testing.T) {
+	p := scriptPlugin(t, `echo "$GT_PLUGIN $GT_RIG $GT_RIG_ROOT"; pwd`, "")
+
+	run, err := RunScript(context.Background(), p, "/town")
+	if err != nil {
+		t.Fatalf("RunScript: %v", err)
+	}
+	if run.Result != ResultSuccess || run.ExitCode != 0 {
+		t.Errorf("result = %s (exit %d), want success", run.Result, run.ExitCode)
+	}
+	if !strings.Contains(run.Output, "test-script gastown /town/gastown") {
+		t.Errorf("output missing plugin env: %q", run.Output)
+	}
+	dir, _ := filepath.EvalSymlinks(p.Path)
+	if !strings.Contains(run.Output, dir) {
+		t.Errorf("script did not run from plugin dir %s: %q", dir, run.Output)
+	}
+}
+
+func TestRunScript_Failure(t *testing.T) {
+	p := scriptPlugin(t, "echo boom >&2; exit 3", "")
+
+	run, err := RunScript(context.Background(), p, t.TempDir())
+	if err != nil {
+		t.Fatalf("RunScript: %v", err)
+	}
+	if run.Result != ResultFailure || run.ExitCode != 3 || run.TimedOut {
+		t.Errorf("run = %+v, want failure with exit 3", run)
+	}
+	if !strings.Contains(run.Output, "boom") {
+		t.Errorf("stderr not captured: %q", run.Output)
+	}
+	if body := run.FormatBody(p); !strings.Contains(body, "exited 3") || !strings.Contains(body, "boom") {
+		t.Errorf("unexpected body: %q", body)
+	}
+}
+
+func TestRunScript_Timeout(t *testing.T) {
+	p := scriptPlugin(t, "sleep 30 & wait", "200ms")
+
+	start := time.Now()
+	run, err := RunScript(context.Background(), p, t.TempDir())
+	if err != nil {
+		t.Fatalf("RunScript: %v", err)
+	}
+	if !run.TimedOut || run.Result != ResultFailure {
+		t.Errorf("run = %+v, want timed-out failure", run)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("timeout not enforced, took %v", elapsed)
+	}
+}
+
+func TestRunScript_NotScript(t *testing.T) {
+	if _, err := RunScript(context.Background(), &Plugin{Name: "agent"}, t.TempDir()); err == nil {
+		t.Error("expected error for agent plugin")
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := &tailBuffer{max: 4}
+	_, _ = b.Write([]byte("abc"))
+	_, _ = b.Write([]byte("defg"))
+	if got := b.String(); got != "[output truncated]\ndefg" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -11,6 +11,7 @@ package plugin
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Plugin represents a discovered plugin definition.
@@ -106,6 +107,14 @@ type Tracking struct {
 
 // Execution defines plugin execution settings.
 type Execution struct {
+	// Mode selects who runs the plugin: "agent" (default) dispatches the
+	// instructions to a dog; "script" has the daemon run Entrypoint itself.
+	Mode ExecutionMode `json:"mode,omitempty" toml:"mode,omitempty"`
+
+	// Entrypoint is the shell command run in script mode, from the plugin
+	// directory (e.g., "./run.sh").
+	Entrypoint string `json:"entrypoint,omitempty" toml:"entrypoint,omitempty"`
+
 	// Timeout is the maximum execution time (e.g., "5m").
 	Timeout string `json:"timeout,omitempty" toml:"timeout,omitempty"`
 
@@ -116,6 +125,64 @@ type Execution struct {
 	Severity string `json:"severity,omitempty" toml:"severity,omitempty"`
 }
 
+// ExecutionMode selects how a plugin is executed.
+type ExecutionMode string
+
+const (
+	// ModeAgent dispatches the plugin's instructions to a dog (default).
+	ModeAgent ExecutionMode = "agent"
+
+	// ModeScript runs the plugin's entrypoint directly, without an agent.
+	ModeScript ExecutionMode = "script"
+)
+
+// DefaultScriptTimeout bounds script runs that don't set a timeout.
+const DefaultScriptTimeout = 5 * time.Minute
+
+// validate checks the execution mode and its required fields, and that the
+// plugin's gate is one its mode can be run on.
+func (e *Execution) validate(gate *Gate) error {
+	if e == nil {
+		return nil
+	}
+	switch e.Mode {
+	case "", ModeAgent:
+	case ModeScript:
+		if strings.TrimSpace(e.Entrypoint) == "" {
+			return fmt.Errorf("execution mode %q requires an entrypoint", ModeScript)
+		}
+		// The daemon only opens cooldown gates and the Deacon skips script
+		// plugins, so a cron, condition or event gate would never fire.
+		if gate != nil && gate.Type != GateCooldown && gate.Type != GateManual {
+			return fmt.Errorf("execution mode %q requires a %q or %q gate, not %q", ModeScript, GateCooldown, GateManual, gate.Type)
+		}
+		// The daemon enforces script timeouts, so they must parse.
+		if e.Timeout != "" {
+			if d, err := time.ParseDuration(e.Timeout); err != nil || d <= 0 {
+				return fmt.Errorf("invalid execution timeout %q", e.Timeout)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid execution mode %q (expected %q or %q)", e.Mode, ModeAgent, ModeScript)
+	}
+	return nil
+}
+
+// GetTimeout returns the configured timeout, or DefaultScriptTimeout.
+func (e *Execution) GetTimeout() time.Duration {
+	if e != nil && e.Timeout != "" {
+		if d, err := time.ParseDuration(e.Timeout); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultScriptTimeout
+}
+
+// IsScript reports whether the daemon runs this plugin's entrypoint directly.
+func (p *Plugin) IsScript() bool {
+	return p.Execution != nil && p.Execution.Mode == ModeScript
+}
+
 // PluginFrontmatter represents the TOML frontmatter in plugin.md files.
 type PluginFrontmatter struct {
 	Name        string     `toml:"name"`
@@ -172,6 +239,10 @@ func (p *Plugin) FormatMailBody() string {
 	}
 	sb.WriteString("\n---\n\n")
 	sb.WriteString("## Instructions\n\n")
+	if p.IsScript() {
+		sb.WriteString("This is a script plugin. Run its entrypoint and record the result:\n\n")
+		sb.WriteString(fmt.Sprintf("```bash\ncd %s && %s\n```\n\n", p.Path, p.Execution.Entrypoint))
+	}
 	sb.WriteString(p.Instructions)
 	sb.WriteString("\n\n---\n\n")
 	sb.WriteString("After completion:\n")
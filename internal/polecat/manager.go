@@ -123,7 +123,7 @@ type Manager struct {
 	git      *git.Git
 	beads    *beads.Beads
 	namePool *NamePool
-	tmux     *tmux.Tmux
+	tmux     session.SessionBackend
 }
 
 // NewManager creates a new polecat manager.
@@ -168,12 +168,18 @@ func NewManager(r *rig.Rig, g *git.Git, t *tmux.Tmux) *Manager {
 
 	_ = pool.Load() // non-fatal: state file may not exist for new rigs
 
+	// Polecat sessions may run in the PTY supervisor instead of tmux.
+	var backend session.SessionBackend
+	if t != nil {
+		backend = session.PolecatBackend(filepath.Dir(r.Path), t)
+	}
+
 	return &Manager{
 		rig:      r,
 		git:      g,
 		beads:    beads.NewWithBeadsDir(beadsPath, resolvedBeads),
 		namePool: pool,
-		tmux:     t,
+		tmux:     backend,
 	}
 }
 
@@ -1503,7 +1509,7 @@ func (m *Manager) ReconcilePoolWith(namesWithDirs, namesWithSessions []string) {
 //
 // Returns true only when we can confirm the process is dead, not on transient
 // failures (gt-kncti: permission denied false positives).
-func isSessionProcessDead(t *tmux.Tmux, sessionName string, townRoot string) bool {
+func isSessionProcessDead(t session.SessionBackend, sessionName string, townRoot string) bool {
 	// Primary: heartbeat-based liveness check (gt-qjtq ZFC fix).
 	if townRoot != "" {
 		stale, exists := IsSessionHeartbeatStale(townRoot, sessionName)
@@ -20,7 +20,9 @@ import (
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // debugSession logs non-fatal errors during session startup when GT_DEBUG_SESSION=1.
@@ -40,18 +42,24 @@ var (
 
 // SessionManager handles polecat session lifecycle.
 type SessionManager struct {
-	tmux *tmux.Tmux
-	rig  *rig.Rig
+	backend session.SessionBackend
+	rig     *rig.Rig
 }
 
 // NewSessionManager creates a new polecat session manager for a rig.
+// Sessions run in t unless town settings select another session backend.
 func NewSessionManager(t *tmux.Tmux, r *rig.Rig) *SessionManager {
 	return &SessionManager{
-		tmux: t,
-		rig:  r,
+		backend: session.PolecatBackend(filepath.Dir(r.Path), t),
+		rig:     r,
 	}
 }
 
+// Backend returns the session backend the rig's polecats run in.
+func (m *SessionManager) Backend() session.SessionBackend {
+	return m.backend
+}
+
 // SessionStartOptions configures polecat session startup.
 type SessionStartOptions struct {
 	// WorkDir overrides the default working directory (polecat clone dir).
@@ -183,7 +191,17 @@ func (m *SessionManager) hasPolecat(polecat string) bool {
 }
 
 // Start creates and starts a new session for a polecat.
-func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
+func (m *SessionManager) Start(polecat string, opts SessionStartOptions) (retErr error) {
+	ctx := context.Background()
+	if !telemetry.HasTraceContext(ctx) {
+		// Started outside a traced command, e.g. a witness respawn:
+		// continue the trace of the sling that hooked the polecat's work.
+		ctx = telemetry.WithTraceParent(ctx, m.hookedTraceParent(polecat))
+	}
+	spanCtx, span := telemetry.StartSpan(ctx, "polecat.spawn",
+		attribute.String("rig", m.rig.Name), attribute.String("polecat", polecat))
+	defer func() { telemetry.EndSpan(span, retErr) }()
+
 	if !m.hasPolecat(polecat) {
 		return fmt.Errorf("%w: %s", ErrPolecatNotFound, polecat)
 	}
@@ -193,13 +211,13 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// Check if session already exists.
 	// If an existing session's pane process has died, kill the stale session
 	// and proceed rather than returning ErrSessionRunning (gt-jn40ft).
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
 	if running {
 		if m.isSessionStale(sessionID) {
-			if err := m.tmux.KillSessionWithProcesses(sessionID); err != nil {
+			if err := m.backend.KillSessionWithProcesses(sessionID); err != nil {
 				return fmt.Errorf("killing stale session %s: %w", sessionID, err)
 			}
 		} else {
@@ -314,11 +332,22 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	if polecatGitBranch != "" {
 		envVarsToInject["GT_BRANCH"] = polecatGitBranch
 	}
+	// The agent's gt and bd calls continue the trace of the sling that spawned it.
+	traceParent := telemetry.TraceParent(spanCtx)
+	if traceParent != "" {
+		envVarsToInject[telemetry.EnvTraceParent] = traceParent
+	}
 	command = config.PrependEnv(command, envVarsToInject)
 
+	// Run the agent in the rig's sandbox when enabled (worktree-only writes).
+	command, err = session.SandboxCommand(command, session.LoadSandbox(m.rig.Path), "polecat", workDir, townRoot, opts.RuntimeConfigDir)
+	if err != nil {
+		return err
+	}
+
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
-	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
+	if err := m.backend.NewSessionWithCommand(sessionID, workDir, command); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
@@ -334,7 +363,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		Agent:            opts.Agent,
 	})
 	for k, v := range envVars {
-		debugSession("SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
+		debugSession("SetEnvironment "+k, m.backend.SetEnvironment(sessionID, k, v))
 	}
 
 	// Fallback: set GT_AGENT from resolved config when no explicit --agent override.
@@ -345,26 +374,29 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// exec env, but tmux show-environment reads the session table, not process env.
 	// This mirrors the daemon's compensating logic (daemon.go ~line 1593-1595).
 	if _, hasGTAgent := envVars["GT_AGENT"]; !hasGTAgent && runtimeConfig.ResolvedAgent != "" {
-		debugSession("SetEnvironment GT_AGENT (resolved)", m.tmux.SetEnvironment(sessionID, "GT_AGENT", runtimeConfig.ResolvedAgent))
+		debugSession("SetEnvironment GT_AGENT (resolved)", m.backend.SetEnvironment(sessionID, "GT_AGENT", runtimeConfig.ResolvedAgent))
 	}
 
 	// Set GT_BRANCH and GT_POLECAT_PATH in tmux session environment.
 	// This ensures respawned processes also inherit these for gt done fallback.
 	if polecatGitBranch != "" {
-		debugSession("SetEnvironment GT_BRANCH", m.tmux.SetEnvironment(sessionID, "GT_BRANCH", polecatGitBranch))
+		debugSession("SetEnvironment GT_BRANCH", m.backend.SetEnvironment(sessionID, "GT_BRANCH", polecatGitBranch))
 	}
-	debugSession("SetEnvironment GT_POLECAT_PATH", m.tmux.SetEnvironment(sessionID, "GT_POLECAT_PATH", workDir))
-	debugSession("SetEnvironment GT_TOWN_ROOT", m.tmux.SetEnvironment(sessionID, "GT_TOWN_ROOT", townRoot))
+	if traceParent != "" {
+		debugSession("SetEnvironment "+telemetry.EnvTraceParent, m.backend.SetEnvironment(sessionID, telemetry.EnvTraceParent, traceParent))
+	}
+	debugSession("SetEnvironment GT_POLECAT_PATH", m.backend.SetEnvironment(sessionID, "GT_POLECAT_PATH", workDir))
+	debugSession("SetEnvironment GT_TOWN_ROOT", m.backend.SetEnvironment(sessionID, "GT_TOWN_ROOT", townRoot))
 
 	// Disable Dolt auto-commit in tmux session environment (gt-5cc2p).
 	// This ensures respawned processes also inherit the setting.
-	debugSession("SetEnvironment BD_DOLT_AUTO_COMMIT", m.tmux.SetEnvironment(sessionID, "BD_DOLT_AUTO_COMMIT", "off"))
+	debugSession("SetEnvironment BD_DOLT_AUTO_COMMIT", m.backend.SetEnvironment(sessionID, "BD_DOLT_AUTO_COMMIT", "off"))
 
 	// Set GT_PROCESS_NAMES for accurate liveness detection. Custom agents may
 	// shadow built-in preset names (e.g., custom "codex" running "opencode"),
 	// so we resolve process names from both agent name and actual command.
 	processNames := config.ResolveProcessNames(runtimeConfig.ResolvedAgent, runtimeConfig.Command)
-	debugSession("SetEnvironment GT_PROCESS_NAMES", m.tmux.SetEnvironment(sessionID, "GT_PROCESS_NAMES", strings.Join(processNames, ",")))
+	debugSession("SetEnvironment GT_PROCESS_NAMES", m.backend.SetEnvironment(sessionID, "GT_PROCESS_NAMES", strings.Join(processNames, ",")))
 	// Hook the issue to the polecat if provided via --issue flag
 	if opts.Issue != "" {
 		agentID := fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat)
@@ -375,45 +407,49 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 	// Apply theme (non-fatal)
 	theme := tmux.AssignTheme(m.rig.Name)
-	debugSession("ConfigureGasTownSession", m.tmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, polecat, "polecat"))
+	// Theme, pane-died hook and the wait for the agent command are tmux-only;
+	// the PTY backend has no status bar or panes.
+	if t := session.AsTmux(m.backend); t != nil {
+		debugSession("ConfigureGasTownSession", t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, polecat, "polecat"))
 
-	// Set pane-died hook for crash detection (non-fatal)
-	agentID := fmt.Sprintf("%s/%s", m.rig.Name, polecat)
-	debugSession("SetPaneDiedHook", m.tmux.SetPaneDiedHook(sessionID, agentID))
+		// Set pane-died hook for crash detection (non-fatal)
+		agentID := fmt.Sprintf("%s/%s", m.rig.Name, polecat)
+		debugSession("SetPaneDiedHook", t.SetPaneDiedHook(sessionID, agentID))
 
-	// Wait for Claude to start (non-fatal)
-	debugSession("WaitForCommand", m.tmux.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout))
+		// Wait for Claude to start (non-fatal)
+		debugSession("WaitForCommand", t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout))
+	}
 
 	// Accept startup dialogs (workspace trust + bypass permissions) if they appear
-	debugSession("AcceptStartupDialogs", m.tmux.AcceptStartupDialogs(sessionID))
+	debugSession("AcceptStartupDialogs", m.backend.AcceptStartupDialogs(sessionID))
 
 	// Wait for runtime to be fully ready at the prompt (not just started).
 	// Uses prompt-based polling for agents with ReadyPromptPrefix (e.g., Claude "❯ "),
 	// falling back to ReadyDelayMs sleep for agents without prompt detection.
-	debugSession("WaitForRuntimeReady", m.tmux.WaitForRuntimeReady(sessionID, runtimeConfig, constants.ClaudeStartTimeout))
+	debugSession("WaitForRuntimeReady", m.backend.WaitForRuntimeReady(sessionID, runtimeConfig, constants.ClaudeStartTimeout))
 
 	// Handle fallback nudges for non-hook agents.
 	// See StartupFallbackInfo in runtime package for the fallback matrix.
 	if fallbackInfo.SendBeaconNudge && fallbackInfo.SendStartupNudge && fallbackInfo.StartupNudgeDelayMs == 0 {
 		// Hooks + no prompt: Single combined nudge (hook already ran gt prime synchronously)
 		combined := beacon + "\n\n" + runtime.StartupNudgeContent()
-		debugSession("SendCombinedNudge", m.tmux.NudgeSession(sessionID, combined))
+		debugSession("SendCombinedNudge", m.backend.NudgeSession(sessionID, combined))
 	} else {
 		if fallbackInfo.SendBeaconNudge {
 			// Agent doesn't support CLI prompt - send beacon via nudge
-			debugSession("SendBeaconNudge", m.tmux.NudgeSession(sessionID, beacon))
+			debugSession("SendBeaconNudge", m.backend.NudgeSession(sessionID, beacon))
 		}
 
 		if fallbackInfo.StartupNudgeDelayMs > 0 {
 			// Wait for agent to finish processing beacon + gt prime before sending work instructions.
 			// Uses prompt-based detection where available; falls back to max(ReadyDelayMs, StartupNudgeDelayMs).
 			primeWaitRC := runtime.RuntimeConfigWithMinDelay(runtimeConfig, fallbackInfo.StartupNudgeDelayMs)
-			debugSession("WaitForPrimeReady", m.tmux.WaitForRuntimeReady(sessionID, primeWaitRC, constants.ClaudeStartTimeout))
+			debugSession("WaitForPrimeReady", m.backend.WaitForRuntimeReady(sessionID, primeWaitRC, constants.ClaudeStartTimeout))
 		}
 
 		if fallbackInfo.SendStartupNudge {
 			// Send work instructions via nudge
-			debugSession("SendStartupNudge", m.tmux.NudgeSession(sessionID, runtime.StartupNudgeContent()))
+			debugSession("SendStartupNudge", m.backend.NudgeSession(sessionID, runtime.StartupNudgeContent()))
 		}
 	}
 
@@ -425,11 +461,11 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	}
 
 	// Legacy fallback for other startup paths (non-fatal)
-	_ = runtime.RunStartupFallback(m.tmux, sessionID, "polecat", runtimeConfig)
+	_ = runtime.RunStartupFallback(m.backend, sessionID, "polecat", runtimeConfig)
 
 	// Verify session survived startup - if the command crashed, the session may have died.
 	// Without this check, Start() would return success even if the pane died during initialization.
-	running, err = m.tmux.HasSession(sessionID)
+	running, err = m.backend.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("verifying session: %w", err)
 	}
@@ -440,9 +476,9 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// Validate GT_AGENT is set. Without GT_AGENT, IsAgentAlive falls back to
 	// ["node", "claude"] process detection and witness patrol will auto-nuke
 	// polecats running non-Claude agents (e.g., opencode). Fail fast.
-	gtAgent, _ := m.tmux.GetEnvironment(sessionID, "GT_AGENT")
+	gtAgent, _ := m.backend.GetEnvironment(sessionID, "GT_AGENT")
 	if gtAgent == "" {
-		_ = m.tmux.KillSessionWithProcesses(sessionID)
+		_ = m.backend.KillSessionWithProcesses(sessionID)
 		return fmt.Errorf("GT_AGENT not set in session %s (command=%q); "+
 			"witness patrol will misidentify this polecat as a zombie and auto-nuke it. "+
 			"Ensure RuntimeConfig.ResolvedAgent is set during agent config resolution",
@@ -450,7 +486,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	}
 
 	// Track PID for defense-in-depth orphan cleanup (non-fatal)
-	_ = session.TrackSessionPID(townRoot, sessionID, m.tmux)
+	_ = session.TrackSessionPID(townRoot, sessionID, m.backend)
 
 	// Touch initial heartbeat so liveness detection works from the start (gt-qjtq).
 	// Subsequent touches happen on every gt command via persistentPreRun.
@@ -464,14 +500,14 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 // This happens when the agent crashes during startup but tmux keeps the dead pane.
 // Delegates to isSessionProcessDead to avoid duplicating process-check logic (gt-qgzj1h).
 func (m *SessionManager) isSessionStale(sessionID string) bool {
-	return isSessionProcessDead(m.tmux, sessionID, filepath.Dir(m.rig.Path))
+	return isSessionProcessDead(m.backend, sessionID, filepath.Dir(m.rig.Path))
 }
 
 // Stop terminates a polecat session.
 func (m *SessionManager) Stop(polecat string, force bool) error {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -481,13 +517,13 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 
 	// Try graceful shutdown first
 	if !force {
-		_ = m.tmux.SendKeysRaw(sessionID, "C-c")
-		session.WaitForSessionExit(m.tmux, sessionID, constants.GracefulShutdownTimeout)
+		_ = m.backend.SendKeysRaw(sessionID, "C-c")
+		session.WaitForSessionExit(m.backend, sessionID, constants.GracefulShutdownTimeout)
 	}
 
 	// Use KillSessionWithProcesses to ensure all descendant processes are killed.
 	// This prevents orphan bash processes from Claude's Bash tool surviving session termination.
-	if err := m.tmux.KillSessionWithProcesses(sessionID); err != nil {
+	if err := m.backend.KillSessionWithProcesses(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
 
@@ -499,15 +535,22 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 // reporting zombie sessions (tmux alive but Claude dead) as "running".
 func (m *SessionManager) IsRunning(polecat string) (bool, error) {
 	sessionID := m.SessionName(polecat)
-	status := m.tmux.CheckSessionHealth(sessionID, 0)
-	return status == tmux.SessionHealthy, nil
+	if t := session.AsTmux(m.backend); t != nil {
+		status := t.CheckSessionHealth(sessionID, 0)
+		return status == tmux.SessionHealthy, nil
+	}
+	running, err := m.backend.HasSession(sessionID)
+	if err != nil || !running {
+		return false, err
+	}
+	return m.backend.IsAgentAlive(sessionID), nil
 }
 
 // Status returns detailed status for a polecat session.
 func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -523,7 +566,11 @@ func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 		return info, nil
 	}
 
-	tmuxInfo, err := m.tmux.GetSessionInfo(sessionID)
+	t := session.AsTmux(m.backend)
+	if t == nil {
+		return info, nil
+	}
+	tmuxInfo, err := t.GetSessionInfo(sessionID)
 	if err != nil {
 		return info, nil
 	}
@@ -561,7 +608,7 @@ func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 // This includes polecats, witness, refinery, and crew sessions.
 // Use ListPolecats() to get only polecat sessions.
 func (m *SessionManager) List() ([]SessionInfo, error) {
-	sessions, err := m.tmux.ListSessions()
+	sessions, err := m.backend.ListSessions()
 	if err != nil {
 		return nil, err
 	}
@@ -610,7 +657,7 @@ func (m *SessionManager) ListPolecats() ([]SessionInfo, error) {
 func (m *SessionManager) Attach(polecat string) error {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -618,14 +665,14 @@ func (m *SessionManager) Attach(polecat string) error {
 		return ErrSessionNotFound
 	}
 
-	return m.tmux.AttachSession(sessionID)
+	return m.backend.AttachSession(sessionID)
 }
 
 // Capture returns the recent output from a polecat session.
 func (m *SessionManager) Capture(polecat string, lines int) (string, error) {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return "", fmt.Errorf("checking session: %w", err)
 	}
@@ -633,12 +680,12 @@ func (m *SessionManager) Capture(polecat string, lines int) (string, error) {
 		return "", ErrSessionNotFound
 	}
 
-	return m.tmux.CapturePane(sessionID, lines)
+	return m.backend.CapturePane(sessionID, lines)
 }
 
 // CaptureSession returns the recent output from a session by raw session ID.
 func (m *SessionManager) CaptureSession(sessionID string, lines int) (string, error) {
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return "", fmt.Errorf("checking session: %w", err)
 	}
@@ -646,14 +693,14 @@ func (m *SessionManager) CaptureSession(sessionID string, lines int) (string, er
 		return "", ErrSessionNotFound
 	}
 
-	return m.tmux.CapturePane(sessionID, lines)
+	return m.backend.CapturePane(sessionID, lines)
 }
 
 // Inject sends a message to a polecat session.
 func (m *SessionManager) Inject(polecat, message string) error {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.backend.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -666,7 +713,11 @@ func (m *SessionManager) Inject(polecat, message string) error {
 		debounceMs = 1500
 	}
 
-	return m.tmux.SendKeysDebounced(sessionID, message, debounceMs)
+	t := session.AsTmux(m.backend)
+	if t == nil {
+		return m.backend.NudgeSession(sessionID, message)
+	}
+	return t.SendKeysDebounced(sessionID, message, debounceMs)
 }
 
 // StopAll terminates all polecat sessions for this rig.
@@ -749,20 +800,20 @@ func (m *SessionManager) verifyStartupNudgeDelivery(sessionID string, rc *config
 		time.Sleep(constants.StartupNudgeVerifyDelay)
 
 		// Check if session is still alive
-		running, err := m.tmux.HasSession(sessionID)
+		running, err := m.backend.HasSession(sessionID)
 		if err != nil || !running {
 			return // Session died, nothing to verify
 		}
 
 		// If the agent is NOT at the prompt, it's working — nudge was received.
-		if !m.tmux.IsAtPrompt(sessionID, rc) {
+		if !m.backend.IsAtPrompt(sessionID, rc) {
 			return
 		}
 
 		// Agent is at the idle prompt — nudge was likely lost. Retry.
 		fmt.Fprintf(os.Stderr, "[startup-nudge] attempt %d/%d: agent %s idle at prompt, retrying nudge\n",
 			attempt, constants.StartupNudgeMaxRetries, sessionID)
-		if err := m.tmux.NudgeSession(sessionID, nudgeContent); err != nil {
+		if err := m.backend.NudgeSession(sessionID, nudgeContent); err != nil {
 			fmt.Fprintf(os.Stderr, "[startup-nudge] retry nudge failed for %s: %v\n", sessionID, err)
 			return
 		}
@@ -770,12 +821,34 @@ func (m *SessionManager) verifyStartupNudgeDelivery(sessionID string, rc *config
 
 	// If we exhausted retries and the agent is still idle, log a warning.
 	// The witness zombie patrol will handle this case.
-	if m.tmux.IsAtPrompt(sessionID, rc) {
+	if m.backend.IsAtPrompt(sessionID, rc) {
 		fmt.Fprintf(os.Stderr, "[startup-nudge] WARNING: agent %s still idle after %d nudge retries\n",
 			sessionID, constants.StartupNudgeMaxRetries)
 	}
 }
 
+// hookedTraceParent returns the trace_parent recorded by gt sling on the
+// bead hooked to a polecat, or "" if there is none.
+func (m *SessionManager) hookedTraceParent(polecat string) string {
+	resolvedBeads := beads.ResolveBeadsDir(m.rig.Path)
+	b := beads.NewWithBeadsDir(filepath.Dir(resolvedBeads), resolvedBeads)
+	issues, err := b.List(beads.ListOptions{
+		Status:   beads.StatusHooked,
+		Assignee: fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat),
+		Priority: -1,
+	})
+	if err != nil {
+		debugSession("list hooked beads", err)
+		return ""
+	}
+	for _, issue := range issues {
+		if fields := beads.ParseAttachmentFields(issue); fields != nil && fields.TraceParent != "" {
+			return fields.TraceParent
+		}
+	}
+	return ""
+}
+
 // hookIssue pins an issue to a polecat's hook using bd update.
 func (m *SessionManager) hookIssue(issueID, agentID, workDir string) error {
 	bdWorkDir := m.resolveBeadsDir(issueID, workDir)
@@ -1,6 +1,7 @@
 package polecat
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -14,7 +15,9 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func setupTestRegistryForSession(t *testing.T) {
@@ -112,6 +115,53 @@ func TestStartPolecatNotFound(t *testing.T) {
 	}
 }
 
+func TestStartParentsSpawnOnHookedBead(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("mock bd is a shell script")
+	}
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	// bd list returns Toast's hooked bead, as written by gt sling.
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+for arg in "$@"; do
+  case "$arg" in
+    --*) ;;
+    list) echo '[{"id":"gt-abc","status":"hooked","assignee":"gastown/polecats/Toast","description":"trace_parent: ` + parent + `"}]'; exit 0 ;;
+    *) exit 0 ;;
+  esac
+done
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv(telemetry.EnvTraceParent, "")
+	os.Unsetenv(telemetry.EnvTraceParent)
+
+	exp := tracetest.NewInMemoryExporter()
+	t.Cleanup(telemetry.UseSpanExporter(exp))
+
+	r := &rig.Rig{Name: "gastown", Path: t.TempDir()}
+	m := NewSessionManager(tmux.NewTmux(), r)
+	if err := m.Start("Toast", SessionStartOptions{}); !errors.Is(err, ErrPolecatNotFound) {
+		t.Fatalf("Start() = %v, want ErrPolecatNotFound", err)
+	}
+
+	var spawn *tracetest.SpanStub
+	for _, s := range exp.GetSpans() {
+		if s.Name == "polecat.spawn" {
+			spawn = &s
+		}
+	}
+	if spawn == nil {
+		t.Fatal("no polecat.spawn span")
+	}
+	if got := spawn.Parent; got.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || got.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("polecat.spawn parent = %s/%s, want the hooked bead's trace_parent", got.TraceID(), got.SpanID())
+	}
+}
+
 func TestIsRunningNoSession(t *testing.T) {
 	requireTmux(t)
 
@@ -0,0 +1,339 @@
+package ptyd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"golang.org/x/term"
+)
+
+// ErrNotRunning means no supervisor is listening on the socket.
+var ErrNotRunning = errors.New("pty supervisor not running (start it with: gt daemon start)")
+
+// defaultReadyPromptPrefix is Claude Code's prompt, used when the runtime
+// config doesn't name one.
+const defaultReadyPromptPrefix = "❯ "
+
+// Client talks to a supervisor. Its methods mirror the tmux ones so it can
+// stand in as a session backend.
+type Client struct {
+	socket string
+}
+
+// NewClient returns a client for the supervisor of a town.
+func NewClient(townRoot string) *Client {
+	return &Client{socket: SocketPath(townRoot)}
+}
+
+// Running reports whether a supervisor is listening.
+func (c *Client) Running() bool {
+	conn, err := net.DialTimeout("unix", c.socket, time.Second)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+func (c *Client) dial(req request) (net.Conn, *bufio.Reader, error) {
+	conn, err := net.DialTimeout("unix", c.socket, 5*time.Second)
+	if err != nil {
+		return nil, nil, ErrNotRunning
+	}
+	data, _ := json.Marshal(req)
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("reading pty supervisor response: %w", err)
+	}
+	var resp response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("invalid pty supervisor response: %w", err)
+	}
+	if !resp.OK {
+		_ = conn.Close()
+		return nil, nil, errors.New(resp.Error)
+	}
+	return conn, r, nil
+}
+
+func (c *Client) call(req request) (response, error) {
+	conn, err := net.DialTimeout("unix", c.socket, 5*time.Second)
+	if err != nil {
+		return response{}, ErrNotRunning
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	data, _ := json.Marshal(req)
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return response{}, err
+	}
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return response{}, fmt.Errorf("reading pty supervisor response: %w", err)
+	}
+	if !resp.OK {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+// NewSessionWithCommand starts a session running command in workDir.
+func (c *Client) NewSessionWithCommand(name, workDir, command string) error {
+	_, err := c.call(request{Op: opNew, Session: name, Dir: workDir, Command: command})
+	return err
+}
+
+// HasSession reports whether the session exists. No supervisor means no
+// sessions, like tmux without a server.
+func (c *Client) HasSession(name string) (bool, error) {
+	resp, err := c.call(request{Op: opHas, Session: name})
+	if errors.Is(err, ErrNotRunning) {
+		return false, nil
+	}
+	return resp.Bool, err
+}
+
+// KillSessionWithProcesses kills the session's process group.
+func (c *Client) KillSessionWithProcesses(name string) error {
+	_, err := c.call(request{Op: opKill, Session: name})
+	return err
+}
+
+// ListSessions returns the session names.
+func (c *Client) ListSessions() ([]string, error) {
+	resp, err := c.call(request{Op: opList})
+	if errors.Is(err, ErrNotRunning) {
+		return nil, nil
+	}
+	return resp.Values, err
+}
+
+// SendKeysRaw sends a tmux-style key ("Enter", "C-c", ...) or literal text.
+func (c *Client) SendKeysRaw(session, keys string) error {
+	return c.write(session, keyBytes(keys))
+}
+
+func (c *Client) write(session string, data []byte) error {
+	_, err := c.call(request{Op: opWrite, Session: session, Data: data})
+	return err
+}
+
+// NudgeSession types a message into the agent and submits it, with the same
+// pacing as the tmux nudge.
+func (c *Client) NudgeSession(session, message string) error {
+	if err := c.write(session, []byte(sanitizeMessage(message))); err != nil {
+		return err
+	}
+	time.Sleep(500 * time.Millisecond)
+	// Escape leaves vim insert mode; wait out readline's keyseq-timeout so
+	// it isn't read as a meta prefix for Enter.
+	if err := c.write(session, []byte{0x1b}); err != nil {
+		return err
+	}
+	time.Sleep(600 * time.Millisecond)
+	return c.write(session, []byte{'\r'})
+}
+
+// sanitizeMessage drops control characters except newlines; tabs become
+// spaces so they don't trigger completion.
+func sanitizeMessage(msg string) string {
+	var b strings.Builder
+	for _, r := range msg {
+		switch {
+		case r == '\t':
+			b.WriteRune(' ')
+		case r == '\n':
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// CapturePane returns the last lines of the session's output as text.
+func (c *Client) CapturePane(session string, lines int) (string, error) {
+	resp, err := c.call(request{Op: opCapture, Session: session, Lines: lines})
+	return resp.Value, err
+}
+
+// SetEnvironment records a variable on the session. Like tmux, it applies
+// to processes started afterwards (respawns), not the running agent.
+func (c *Client) SetEnvironment(session, key, value string) error {
+	_, err := c.call(request{Op: opSetEnv, Session: session, Key: key, Value: value})
+	return err
+}
+
+// GetEnvironment returns a variable recorded on the session.
+func (c *Client) GetEnvironment(session, key string) (string, error) {
+	resp, err := c.call(request{Op: opGetEnv, Session: session, Key: key})
+	return resp.Value, err
+}
+
+// GetPanePID returns the PID of the session's process.
+func (c *Client) GetPanePID(session string) (string, error) {
+	resp, err := c.call(request{Op: opPID, Session: session})
+	return resp.Value, err
+}
+
+// SetAutoRespawnHook restarts the session's command whenever it exits,
+// until the session is killed.
+func (c *Client) SetAutoRespawnHook(session string) error {
+	_, err := c.call(request{Op: opRespawn, Session: session})
+	return err
+}
+
+// IsAgentAlive reports whether the session's process is running.
+func (c *Client) IsAgentAlive(session string) bool {
+	resp, err := c.call(request{Op: opAlive, Session: session})
+	return err == nil && resp.Bool
+}
+
+// WaitForRuntimeReady waits for the runtime's prompt, or its fixed delay
+// when it has no prompt to detect.
+func (c *Client) WaitForRuntimeReady(session string, rc *config.RuntimeConfig, timeout time.Duration) error {
+	if rc == nil || rc.Tmux == nil {
+		return nil
+	}
+	if rc.Tmux.ReadyPromptPrefix == "" {
+		if rc.Tmux.ReadyDelayMs <= 0 {
+			return nil
+		}
+		delay := time.Duration(rc.Tmux.ReadyDelayMs) * time.Millisecond
+		if delay > timeout {
+			delay = timeout
+		}
+		time.Sleep(delay)
+		return nil
+	}
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if c.atPrompt(session, rc.Tmux.ReadyPromptPrefix) {
+			return nil
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return fmt.Errorf("timeout waiting for runtime prompt")
+}
+
+// IsAtPrompt reports whether the agent's prompt is among the last lines.
+func (c *Client) IsAtPrompt(session string, rc *config.RuntimeConfig) bool {
+	prefix := defaultReadyPromptPrefix
+	if rc != nil && rc.Tmux != nil && rc.Tmux.ReadyPromptPrefix != "" {
+		prefix = rc.Tmux.ReadyPromptPrefix
+	}
+	return c.atPrompt(session, prefix)
+}
+
+func (c *Client) atPrompt(session, prefix string) bool {
+	out, err := c.CapturePane(session, 10)
+	if err != nil {
+		return false
+	}
+	prefix = strings.ReplaceAll(prefix, "\u00a0", " ")
+	bare := strings.TrimSpace(prefix)
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.ReplaceAll(strings.TrimSpace(line), "\u00a0", " ")
+		if strings.HasPrefix(line, prefix) || (bare != "" && line == bare) {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptStartupDialogs dismisses Claude Code's workspace trust dialog and
+// bypass permissions warning if they appear.
+func (c *Client) AcceptStartupDialogs(session string) error {
+	trustDone := false
+	deadline := time.Now().Add(constants.DialogPollTimeout)
+	for time.Now().Before(deadline) {
+		content, err := c.CapturePane(session, 30)
+		if err != nil {
+			time.Sleep(constants.DialogPollInterval)
+			continue
+		}
+		switch {
+		case !trustDone && (strings.Contains(content, "trust this folder") || strings.Contains(content, "Quick safety check")):
+			if err := c.write(session, []byte{'\r'}); err != nil {
+				return err
+			}
+			trustDone = true
+			time.Sleep(500 * time.Millisecond)
+		case strings.Contains(content, "Bypass Permissions mode"):
+			if err := c.write(session, keyBytes("Down")); err != nil {
+				return err
+			}
+			time.Sleep(200 * time.Millisecond)
+			return c.write(session, []byte{'\r'})
+		case c.atPrompt(session, defaultReadyPromptPrefix):
+			return nil
+		default:
+			time.Sleep(constants.DialogPollInterval)
+		}
+	}
+	return nil
+}
+
+// AttachSession connects the terminal to the session until it ends or the
+// user presses Ctrl-]. The session keeps its own terminal size.
+func (c *Client) AttachSession(session string) error {
+	conn, r, err := c.dial(request{Op: opAttach, Session: session})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("setting raw mode: %w", err)
+		}
+		defer func() { _ = term.Restore(fd, state) }()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(os.Stdout, r)
+		close(done)
+	}()
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if n > 0 {
+				if i := strings.IndexByte(string(buf[:n]), DetachKey); i >= 0 {
+					_, _ = conn.Write(buf[:i])
+					_ = conn.Close()
+					return
+				}
+				if _, werr := conn.Write(buf[:n]); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	<-done
+	fmt.Fprintf(os.Stderr, "\r\n[detached from %s]\r\n", session)
+	return nil
+}
@@ -0,0 +1,74 @@
+//go:build linux
+
+package ptyd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// startProcess runs command with sh in a new session whose controlling
+// terminal is a fresh PTY, and returns the PTY master.
+func startProcess(dir, command string, env []string) (*exec.Cmd, *os.File, error) {
+	master, slaveName, err := openPTY()
+	if err != nil {
+		return nil, nil, err
+	}
+	slave, err := os.OpenFile(slaveName, os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, fmt.Errorf("opening %s: %w", slaveName, err)
+	}
+	defer func() { _ = slave.Close() }()
+
+	ws := &unix.Winsize{Row: ptyRows, Col: ptyCols}
+	if err := unix.IoctlSetWinsize(int(master.Fd()), unix.TIOCSWINSZ, ws); err != nil {
+		_ = master.Close()
+		return nil, nil, fmt.Errorf("setting terminal size: %w", err)
+	}
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+	if err := cmd.Start(); err != nil {
+		_ = master.Close()
+		return nil, nil, err
+	}
+	return cmd, master, nil
+}
+
+// openPTY opens a new PTY master and returns it with the slave's path.
+func openPTY() (*os.File, string, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, "", fmt.Errorf("opening /dev/ptmx: %w", err)
+	}
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		_ = master.Close()
+		return nil, "", fmt.Errorf("unlocking pty: %w", err)
+	}
+	n, err := unix.IoctlGetUint32(fd, unix.TIOCGPTN)
+	if err != nil {
+		_ = master.Close()
+		return nil, "", fmt.Errorf("getting pty number: %w", err)
+	}
+	return master, "/dev/pts/" + strconv.FormatUint(uint64(n), 10), nil
+}
+
+// terminate signals the process group led by pid: SIGTERM, or SIGKILL when
+// force is set.
+func terminate(pid int, force bool) error {
+	sig := unix.SIGTERM
+	if force {
+		sig = unix.SIGKILL
+	}
+	return unix.Kill(-pid, sig)
+}
@@ -0,0 +1,23 @@
+//go:build !linux
+
+package ptyd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+var errUnsupported = errors.New("the pty session backend is only supported on Linux")
+
+func startProcess(dir, command string, env []string) (*exec.Cmd, *os.File, error) {
+	return nil, nil, errUnsupported
+}
+
+func terminate(pid int, force bool) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
@@ -0,0 +1,125 @@
+// Package ptyd is a headless session backend: a supervisor, hosted by the
+// daemon, gives each agent a PTY and keeps its output in a ring-buffered
+// scrollback. Clients (gt commands, the witness) reach it through a unix
+// socket under the town's .runtime directory.
+//
+// Output is also fed to a fixed-size screen model (see screen), so captures
+// show what the agent has on screen and do not depend on the terminal
+// geometry of whoever is attached.
+package ptyd
+
+import (
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// SocketName is the supervisor socket file in <town>/.runtime/.
+const SocketName = "ptyd.sock"
+
+// DefaultScrollback is the scrollback kept per session, in bytes.
+const DefaultScrollback = 1 << 20
+
+// DetachKey detaches an attached terminal (Ctrl-]).
+const DetachKey = 0x1d
+
+// Terminal size given to every session. Agents render for this size; it
+// never changes with attached terminals.
+const (
+	ptyRows = 50
+	ptyCols = 200
+)
+
+// SocketPath returns the supervisor socket for a town.
+func SocketPath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), SocketName)
+}
+
+// Ops of the supervisor protocol: one JSON request per line, answered by one
+// JSON response. An attach request turns the connection into a raw stream.
+const (
+	opNew     = "new"
+	opHas     = "has"
+	opKill    = "kill"
+	opList    = "list"
+	opWrite   = "write"
+	opCapture = "capture"
+	opSetEnv  = "setenv"
+	opGetEnv  = "getenv"
+	opPID     = "pid"
+	opRespawn = "respawn"
+	opAlive   = "alive"
+	opAttach  = "attach"
+)
+
+type request struct {
+	Op      string `json:"op"`
+	Session string `json:"session,omitempty"`
+	Dir     string `json:"dir,omitempty"`
+	Command string `json:"command,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Data    []byte `json:"data,omitempty"`
+	Lines   int    `json:"lines,omitempty"`
+}
+
+type response struct {
+	OK     bool     `json:"ok"`
+	Error  string   `json:"error,omitempty"`
+	Value  string   `json:"value,omitempty"`
+	Values []string `json:"values,omitempty"`
+	Bool   bool     `json:"bool,omitempty"`
+}
+
+// ring keeps the last max bytes written to it.
+type ring struct {
+	max  int
+	data []byte
+}
+
+func (r *ring) Write(p []byte) {
+	r.data = append(r.data, p...)
+	// Trim lazily so steady output doesn't copy the buffer on every write.
+	if len(r.data) > 2*r.max {
+		r.data = append([]byte(nil), r.data[len(r.data)-r.max:]...)
+	}
+}
+
+// Bytes returns a copy of the retained output.
+func (r *ring) Bytes() []byte {
+	b := r.data
+	if len(b) > r.max {
+		b = b[len(b)-r.max:]
+	}
+	return append([]byte(nil), b...)
+}
+
+// keyBytes translates a tmux key name (as used with SendKeysRaw) into the
+// bytes a terminal would send. Anything else is sent literally.
+func keyBytes(key string) []byte {
+	switch key {
+	case "Enter", "C-m":
+		return []byte{'\r'}
+	case "Escape":
+		return []byte{0x1b}
+	case "Tab":
+		return []byte{'\t'}
+	case "BSpace":
+		return []byte{0x7f}
+	case "Up":
+		return []byte("\x1b[A")
+	case "Down":
+		return []byte("\x1b[B")
+	case "Right":
+		return []byte("\x1b[C")
+	case "Left":
+		return []byte("\x1b[D")
+	}
+	if len(key) == 3 && key[0] == 'C' && key[1] == '-' {
+		c := key[2] | 0x20
+		if c >= 'a' && c <= 'z' {
+			return []byte{c - 'a' + 1}
+		}
+	}
+	return []byte(key)
+}
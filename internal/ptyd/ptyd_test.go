@@ -0,0 +1,56 @@
+package ptyd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRing(t *testing.T) {
+	r := ring{max: 4}
+	r.Write([]byte("ab"))
+	if got := string(r.Bytes()); got != "ab" {
+		t.Errorf("Bytes() = %q, want %q", got, "ab")
+	}
+	r.Write([]byte("cdef"))
+	if got := string(r.Bytes()); got != "cdef" {
+		t.Errorf("Bytes() = %q, want %q", got, "cdef")
+	}
+	r.Write([]byte("ghijk"))
+	if got := string(r.Bytes()); got != "hijk" {
+		t.Errorf("Bytes() = %q, want %q", got, "hijk")
+	}
+	if len(r.data) > 2*r.max {
+		t.Errorf("ring retained %d bytes, want at most %d", len(r.data), 2*r.max)
+	}
+}
+
+func TestKeyBytes(t *testing.T) {
+	tests := []struct {
+		key  string
+		want []byte
+	}{
+		{"Enter", []byte{'\r'}},
+		{"C-c", []byte{0x03}},
+		{"C-D", []byte{0x04}},
+		{"Escape", []byte{0x1b}},
+		{"Down", []byte("\x1b[B")},
+		{"hello", []byte("hello")},
+		{"C-", []byte("C-")},
+	}
+	for _, tt := range tests {
+		if got := keyBytes(tt.key); !bytes.Equal(got, tt.want) {
+			t.Errorf("keyBytes(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeMessage(t *testing.T) {
+	got := sanitizeMessage("a\tb\x1b[31mc\nd\x7f")
+	if want := "a b[31mc\nd"; got != want {
+		t.Errorf("sanitizeMessage() = %q, want %q", got, want)
+	}
+	if strings.ContainsRune(got, 0x1b) {
+		t.Error("sanitizeMessage() kept an escape character")
+	}
+}
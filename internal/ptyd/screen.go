@@ -0,0 +1,310 @@
+package ptyd
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Parser states of a screen.
+const (
+	stGround    = iota
+	stEscape    // after ESC
+	stCSI       // control sequence: parameters until a final byte in @..~
+	stString    // OSC/DCS/APC/PM body, until BEL or ST
+	stStringEsc // ESC inside a string: ST if followed by '\'
+	stCharset   // charset designation: one more byte
+)
+
+// screen is a small VT100 model of a session's terminal: a grid of
+// rows x cols cells plus the lines scrolled off its top. It follows the
+// cursor movement and erase sequences agents use to redraw status lines and
+// prompts, so a capture shows what an attached terminal would display
+// rather than every frame ever drawn. Colors and modes are ignored, and
+// every character takes one cell.
+type screen struct {
+	rows, cols int
+	grid       [][]rune
+	row, col   int
+	// wrap is set once the last column is written: the next character
+	// goes to the start of the following line.
+	wrap               bool
+	savedRow, savedCol int
+
+	history      []string
+	historyBytes int
+	maxHistory   int
+
+	// Parser state, kept across writes since the PTY splits its output
+	// anywhere.
+	state  int
+	params []byte
+	utf8   []byte
+}
+
+// newScreen creates a blank screen keeping up to maxHistory bytes of lines
+// scrolled off the top.
+func newScreen(rows, cols, maxHistory int) *screen {
+	s := &screen{rows: rows, cols: cols, maxHistory: maxHistory}
+	s.grid = make([][]rune, rows)
+	for i := range s.grid {
+		s.grid[i] = make([]rune, cols)
+		blank(s.grid[i])
+	}
+	return s
+}
+
+func blank(cells []rune) {
+	for i := range cells {
+		cells[i] = ' '
+	}
+}
+
+// Write feeds raw terminal output to the screen.
+func (s *screen) Write(p []byte) {
+	for _, b := range p {
+		s.feed(b)
+	}
+}
+
+func (s *screen) feed(b byte) {
+	switch s.state {
+	case stEscape:
+		s.escape(b)
+		return
+	case stCSI:
+		if b >= 0x40 && b <= 0x7e {
+			s.state = stGround
+			s.csi(b)
+		} else if len(s.params) < 64 {
+			s.params = append(s.params, b)
+		}
+		return
+	case stString:
+		if b == 0x07 {
+			s.state = stGround
+		} else if b == 0x1b {
+			s.state = stStringEsc
+		}
+		return
+	case stStringEsc:
+		if b == '\\' {
+			s.state = stGround
+		} else {
+			s.escape(b)
+		}
+		return
+	case stCharset:
+		s.state = stGround
+		return
+	}
+
+	if b >= 0x80 {
+		s.utf8 = append(s.utf8, b)
+		if utf8.FullRune(s.utf8) {
+			r, _ := utf8.DecodeRune(s.utf8)
+			s.utf8 = s.utf8[:0]
+			s.put(r)
+		}
+		return
+	}
+	s.utf8 = s.utf8[:0]
+	switch b {
+	case 0x1b:
+		s.state = stEscape
+	case '\n', '\v', '\f':
+		s.lineFeed()
+	case '\r':
+		s.col, s.wrap = 0, false
+	case '\b':
+		s.col, s.wrap = max(s.col-1, 0), false
+	case '\t':
+		s.col, s.wrap = min((s.col/8+1)*8, s.cols-1), false
+	default:
+		if b >= 0x20 && b != 0x7f {
+			s.put(rune(b))
+		}
+	}
+}
+
+// put writes a character at the cursor and advances it.
+func (s *screen) put(r rune) {
+	if s.wrap {
+		s.lineFeed()
+		s.col = 0
+	}
+	s.grid[s.row][s.col] = r
+	if s.col == s.cols-1 {
+		s.wrap = true
+	} else {
+		s.col++
+	}
+}
+
+// lineFeed moves the cursor down, scrolling the screen at the bottom.
+func (s *screen) lineFeed() {
+	s.wrap = false
+	if s.row < s.rows-1 {
+		s.row++
+		return
+	}
+	top := s.grid[0]
+	s.pushHistory(top)
+	copy(s.grid, s.grid[1:])
+	blank(top)
+	s.grid[s.rows-1] = top
+}
+
+func (s *screen) pushHistory(cells []rune) {
+	line := strings.TrimRight(string(cells), " ")
+	s.history = append(s.history, line)
+	s.historyBytes += len(line) + 1
+	for s.historyBytes > s.maxHistory && len(s.history) > 0 {
+		s.historyBytes -= len(s.history[0]) + 1
+		s.history = s.history[1:]
+	}
+}
+
+func (s *screen) escape(b byte) {
+	s.state = stGround
+	switch b {
+	case '[':
+		s.state, s.params = stCSI, s.params[:0]
+	case ']', 'P', '_', '^':
+		s.state = stString
+	case '(', ')', '*', '+', '#':
+		s.state = stCharset
+	case '7':
+		s.savedRow, s.savedCol = s.row, s.col
+	case '8':
+		s.row, s.col, s.wrap = s.savedRow, s.savedCol, false
+	case 'D':
+		s.lineFeed()
+	case 'E':
+		s.lineFeed()
+		s.col = 0
+	case 'M':
+		s.row, s.wrap = max(s.row-1, 0), false
+	}
+}
+
+// csi runs a control sequence. Private sequences (CSI ? ...) and those with
+// intermediate bytes only set modes, so they are ignored, as is SGR.
+func (s *screen) csi(final byte) {
+	for _, c := range s.params {
+		if (c < '0' || c > '9') && c != ';' {
+			return
+		}
+	}
+	args := strings.Split(string(s.params), ";")
+	arg := func(i, def int) int {
+		if i < len(args) {
+			if v, err := strconv.Atoi(args[i]); err == nil && v > 0 {
+				return v
+			}
+		}
+		return def
+	}
+
+	n := arg(0, 1)
+	line := s.grid[s.row]
+	switch final {
+	case 'A': // CUU
+		s.row = max(s.row-n, 0)
+	case 'B', 'e': // CUD, VPR
+		s.row = min(s.row+n, s.rows-1)
+	case 'C', 'a': // CUF, HPR
+		s.col = min(s.col+n, s.cols-1)
+	case 'D': // CUB
+		s.col = max(s.col-n, 0)
+	case 'E': // CNL
+		s.row, s.col = min(s.row+n, s.rows-1), 0
+	case 'F': // CPL
+		s.row, s.col = max(s.row-n, 0), 0
+	case 'G', '`': // CHA, HPA
+		s.col = min(n, s.cols) - 1
+	case 'd': // VPA
+		s.row = min(n, s.rows) - 1
+	case 'H', 'f': // CUP
+		s.row, s.col = min(n, s.rows)-1, min(arg(1, 1), s.cols)-1
+	case 'J': // ED
+		s.eraseDisplay(arg(0, 0))
+	case 'K': // EL
+		switch arg(0, 0) {
+		case 0:
+			blank(line[s.col:])
+		case 1:
+			blank(line[:s.col+1])
+		case 2:
+			blank(line)
+		}
+	case 'X': // ECH
+		blank(line[s.col:min(s.col+n, s.cols)])
+	case 'P': // DCH
+		n = min(n, s.cols-s.col)
+		copy(line[s.col:], line[s.col+n:])
+		blank(line[s.cols-n:])
+	case 's':
+		s.savedRow, s.savedCol = s.row, s.col
+	case 'u':
+		s.row, s.col = s.savedRow, s.savedCol
+	default:
+		return
+	}
+	s.wrap = false
+}
+
+func (s *screen) eraseDisplay(mode int) {
+	switch mode {
+	case 0:
+		blank(s.grid[s.row][s.col:])
+		for _, line := range s.grid[s.row+1:] {
+			blank(line)
+		}
+	case 1:
+		for _, line := range s.grid[:s.row] {
+			blank(line)
+		}
+		blank(s.grid[s.row][:s.col+1])
+	case 2:
+		// Like tmux, keep what the screen showed in the history.
+		last := s.rows - 1
+		for last >= 0 && strings.TrimRight(string(s.grid[last]), " ") == "" {
+			last--
+		}
+		for _, line := range s.grid[:last+1] {
+			s.pushHistory(line)
+		}
+		for _, line := range s.grid {
+			blank(line)
+		}
+	case 3:
+		s.history, s.historyBytes = nil, 0
+	}
+}
+
+// Capture returns the last n lines of history and screen (all when n <= 0),
+// without trailing blank lines or trailing spaces.
+func (s *screen) Capture(n int) string {
+	total := len(s.history) + s.rows
+	line := func(i int) string {
+		if i < len(s.history) {
+			return s.history[i]
+		}
+		return strings.TrimRight(string(s.grid[i-len(s.history)]), " ")
+	}
+
+	end := total
+	for end > 0 && line(end-1) == "" {
+		end--
+	}
+	start := 0
+	if n > 0 && end > n {
+		start = end - n
+	}
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		lines = append(lines, line(i))
+	}
+	return strings.Join(lines, "\n")
+}
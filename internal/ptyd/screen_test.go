@@ -0,0 +1,77 @@
+package ptyd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScreenCapture(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		n    int
+		want string
+	}{
+		{"plain", "one\r\ntwo\r\nthree\r\n", 0, "one\ntwo\nthree"},
+		{"last lines", "one\r\ntwo\r\nthree\r\n", 2, "two\nthree"},
+		{"colors", "\x1b[1;32mok\x1b[0m done\r\n", 0, "ok done"},
+		{"carriage return", "50%\r100%\r\n", 0, "100%"},
+		{"backspace", "abd\bc\r\n", 0, "abc"},
+		{"osc title", "\x1b]0;title\x07prompt\r\n", 0, "prompt"},
+		{"osc st", "\x1b]8;;http://x\x1b\\link\r\n", 0, "link"},
+		{"clear and home", "\x1b[2J\x1b[H❯ \x1b[?25h", 0, "❯"},
+		{"trailing blank lines", "text\r\n\r\n  \r\n", 0, "text"},
+		{"charset", "\x1b(Bline\r\n", 0, "line"},
+		{"tab", "a\tb\r\n", 0, "a       b"},
+		{"status line redrawn", "work\r\n⠋ Thinking 1s\r\n\x1b[1A\x1b[2K⠙ Thinking 2s\r\n", 0, "work\n⠙ Thinking 2s"},
+		{"erase to end of line", "hello world\r\x1b[5C\x1b[K!\r\n", 0, "hello!"},
+		{"cursor position", "\x1b[3;4Hx", 0, "\n\n   x"},
+		{"cursor up and overwrite", "a\r\nb\r\nc\x1b[2A\rA", 0, "A\nb\nc"},
+		{"column", "abcdef\x1b[3GX", 0, "abXdef"},
+		{"erase below", "one\r\ntwo\r\nthree\x1b[2;1H\x1b[J", 0, "one"},
+		{"delete chars", "abcdef\x1b[1G\x1b[2P", 0, "cdef"},
+		{"save and restore", "\x1b7top\x1b[5;1Hbottom\x1b8T", 0, "Top\n\n\n\nbottom"},
+		{"clear keeps history", "old\r\n\x1b[2J\x1b[Hnew", 0, "old\nnew"},
+		{"clear scrollback", "old\r\n\x1b[2J\x1b[3J\x1b[Hnew", 0, "new"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newScreen(ptyRows, ptyCols, DefaultScrollback)
+			s.Write([]byte(tt.out))
+			if got := s.Capture(tt.n); got != tt.want {
+				t.Errorf("Capture(%d) after %q = %q, want %q", tt.n, tt.out, got, tt.want)
+			}
+
+			// The PTY may split sequences and characters between reads.
+			split := newScreen(ptyRows, ptyCols, DefaultScrollback)
+			for _, b := range []byte(tt.out) {
+				split.Write([]byte{b})
+			}
+			if got := split.Capture(tt.n); got != tt.want {
+				t.Errorf("Capture(%d) after %q byte by byte = %q, want %q", tt.n, tt.out, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScreenScroll(t *testing.T) {
+	s := newScreen(3, 5, 4)
+	s.Write([]byte("1\r\n2\r\n3\r\n4\r\n5\r\n6"))
+	if got, want := s.Capture(0), "2\n3\n4\n5\n6"; got != want {
+		t.Errorf("Capture(0) = %q, want %q (history capped at 4 bytes)", got, want)
+	}
+	if got, want := s.Capture(2), "5\n6"; got != want {
+		t.Errorf("Capture(2) = %q, want %q", got, want)
+	}
+
+	// Long lines wrap at the last column.
+	w := newScreen(3, 5, 8)
+	w.Write([]byte("abcdefg"))
+	if got, want := w.Capture(0), "abcde\nfg"; got != want {
+		t.Errorf("Capture(0) = %q, want %q", got, want)
+	}
+	w.Write([]byte(strings.Repeat("x", 3) + "\r\n"))
+	if got, want := w.Capture(0), "abcde\nfgxxx"; got != want {
+		t.Errorf("Capture(0) = %q, want %q", got, want)
+	}
+}
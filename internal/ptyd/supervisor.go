@@ -0,0 +1,433 @@
+package ptyd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// respawnDelay debounces crash loops of auto-respawned sessions, like the
+// tmux pane-died hook does.
+const respawnDelay = 3 * time.Second
+
+// killTimeout is how long a session gets to exit after SIGTERM.
+const killTimeout = 2 * time.Second
+
+// Supervisor owns the PTY sessions. Sessions live as long as the
+// supervisor: when Serve returns, they are killed.
+type Supervisor struct {
+	// Scrollback is the output kept per session, in bytes.
+	Scrollback int
+
+	mu       sync.Mutex
+	sessions map[string]*ptySession
+	closed   bool
+}
+
+type ptySession struct {
+	name    string
+	dir     string
+	command string
+	created time.Time
+
+	mu       sync.Mutex
+	env      map[string]string
+	respawn  bool
+	killed   bool
+	cmd      *exec.Cmd
+	master   *os.File
+	exited   chan struct{}
+	out      ring    // raw output, replayed to attaching clients
+	screen   *screen // rendered output, for captures
+	attached map[net.Conn]struct{}
+}
+
+// NewSupervisor creates a supervisor with the default scrollback.
+func NewSupervisor() *Supervisor {
+	return &Supervisor{
+		Scrollback: DefaultScrollback,
+		sessions:   make(map[string]*ptySession),
+	}
+}
+
+// Serve accepts clients on socketPath until ctx is done, then kills all
+// sessions. It refuses to replace a socket another supervisor is serving.
+func (s *Supervisor) Serve(ctx context.Context, socketPath string) error {
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0755); err != nil {
+		return fmt.Errorf("creating socket directory: %w", err)
+	}
+	if conn, err := net.Dial("unix", socketPath); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("pty supervisor already running at %s", socketPath)
+	}
+	_ = os.Remove(socketPath)
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", socketPath, err)
+	}
+	_ = os.Chmod(socketPath, 0600)
+
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+	defer func() {
+		_ = os.Remove(socketPath)
+		s.shutdown()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+// shutdown kills every session and stops accepting new ones.
+func (s *Supervisor) shutdown() {
+	s.mu.Lock()
+	s.closed = true
+	sessions := make([]*ptySession, 0, len(s.sessions))
+	for _, sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	s.mu.Unlock()
+	for _, sess := range sessions {
+		s.kill(sess)
+	}
+}
+
+func (s *Supervisor) handle(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		reply(conn, response{Error: "invalid request"})
+		_ = conn.Close()
+		return
+	}
+	if req.Op == opAttach {
+		s.attach(conn, r, req.Session)
+		return
+	}
+	reply(conn, s.do(req))
+	_ = conn.Close()
+}
+
+func reply(conn net.Conn, resp response) {
+	if resp.Error == "" {
+		resp.OK = true
+	}
+	data, _ := json.Marshal(resp)
+	_, _ = conn.Write(append(data, '\n'))
+}
+
+// do runs a request and returns its response.
+func (s *Supervisor) do(req request) response {
+	if req.Op == opList {
+		s.mu.Lock()
+		names := make([]string, 0, len(s.sessions))
+		for name := range s.sessions {
+			names = append(names, name)
+		}
+		s.mu.Unlock()
+		sort.Strings(names)
+		return response{Values: names}
+	}
+	if req.Op == opNew {
+		if err := s.create(req.Session, req.Dir, req.Command); err != nil {
+			return response{Error: err.Error()}
+		}
+		return response{}
+	}
+
+	s.mu.Lock()
+	sess := s.sessions[req.Session]
+	s.mu.Unlock()
+	if req.Op == opHas {
+		return response{Bool: sess != nil}
+	}
+	if sess == nil {
+		return response{Error: fmt.Sprintf("can't find session: %s", req.Session)}
+	}
+
+	switch req.Op {
+	case opKill:
+		s.kill(sess)
+		return response{}
+	case opWrite:
+		sess.mu.Lock()
+		master := sess.master
+		sess.mu.Unlock()
+		if master == nil {
+			return response{Error: fmt.Sprintf("session %s is not running", sess.name)}
+		}
+		if _, err := master.Write(req.Data); err != nil {
+			return response{Error: err.Error()}
+		}
+		return response{}
+	case opCapture:
+		sess.mu.Lock()
+		text := sess.screen.Capture(req.Lines)
+		sess.mu.Unlock()
+		return response{Value: text}
+	case opSetEnv:
+		sess.mu.Lock()
+		sess.env[req.Key] = req.Value
+		sess.mu.Unlock()
+		return response{}
+	case opGetEnv:
+		sess.mu.Lock()
+		v, ok := sess.env[req.Key]
+		sess.mu.Unlock()
+		if !ok {
+			return response{Error: fmt.Sprintf("unknown variable: %s", req.Key)}
+		}
+		return response{Value: v}
+	case opPID:
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		if sess.cmd == nil || sess.cmd.Process == nil {
+			return response{Error: fmt.Sprintf("session %s is not running", sess.name)}
+		}
+		return response{Value: fmt.Sprint(sess.cmd.Process.Pid)}
+	case opRespawn:
+		sess.mu.Lock()
+		sess.respawn = true
+		sess.mu.Unlock()
+		return response{}
+	case opAlive:
+		sess.mu.Lock()
+		exited := sess.exited
+		sess.mu.Unlock()
+		select {
+		case <-exited:
+			return response{Bool: false}
+		default:
+			return response{Bool: true}
+		}
+	}
+	return response{Error: fmt.Sprintf("unknown op %q", req.Op)}
+}
+
+// create starts a new session running command in dir.
+func (s *Supervisor) create(name, dir, command string) error {
+	if name == "" || command == "" {
+		return fmt.Errorf("session name and command are required")
+	}
+	sess := &ptySession{
+		name:     name,
+		dir:      dir,
+		command:  command,
+		created:  time.Now(),
+		env:      make(map[string]string),
+		out:      ring{max: s.Scrollback},
+		screen:   newScreen(ptyRows, ptyCols, s.Scrollback),
+		attached: make(map[net.Conn]struct{}),
+	}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return fmt.Errorf("pty supervisor is shutting down")
+	}
+	if _, ok := s.sessions[name]; ok {
+		s.mu.Unlock()
+		return fmt.Errorf("duplicate session: %s", name)
+	}
+	s.sessions[name] = sess
+	s.mu.Unlock()
+
+	if err := s.start(sess); err != nil {
+		s.mu.Lock()
+		delete(s.sessions, name)
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// start runs the session's command on a fresh PTY and supervises it.
+func (s *Supervisor) start(sess *ptySession) error {
+	sess.mu.Lock()
+	env := os.Environ()
+	env = append(env, "TERM=xterm-256color")
+	for k, v := range sess.env {
+		env = append(env, k+"="+v)
+	}
+	sess.mu.Unlock()
+
+	cmd, master, err := startProcess(sess.dir, sess.command, env)
+	if err != nil {
+		return fmt.Errorf("starting session %s: %w", sess.name, err)
+	}
+	exited := make(chan struct{})
+	sess.mu.Lock()
+	sess.cmd, sess.master, sess.exited = cmd, master, exited
+	sess.mu.Unlock()
+
+	copied := make(chan struct{})
+	go func() {
+		defer close(copied)
+		s.pump(sess, master)
+	}()
+	go func() {
+		_ = cmd.Wait()
+		// The PTY reports EOF once the last process holding it exits; don't
+		// let a leftover background process keep the session alive.
+		select {
+		case <-copied:
+		case <-time.After(time.Second):
+		}
+		_ = master.Close()
+		<-copied
+		close(exited)
+		s.finished(sess)
+	}()
+	return nil
+}
+
+// pump copies PTY output into the scrollback and to attached clients.
+func (s *Supervisor) pump(sess *ptySession, master *os.File) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := master.Read(buf)
+		if n > 0 {
+			sess.mu.Lock()
+			sess.out.Write(buf[:n])
+			sess.screen.Write(buf[:n])
+			for conn := range sess.attached {
+				_ = conn.SetWriteDeadline(time.Now().Add(time.Second))
+				if _, werr := conn.Write(buf[:n]); werr != nil {
+					delete(sess.attached, conn)
+					_ = conn.Close()
+				}
+			}
+			sess.mu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// finished handles the end of a session's process: respawn it, or remove
+// the session and disconnect its clients.
+func (s *Supervisor) finished(sess *ptySession) {
+	sess.mu.Lock()
+	respawn := sess.respawn && !sess.killed
+	sess.mu.Unlock()
+
+	if respawn {
+		time.Sleep(respawnDelay)
+		sess.mu.Lock()
+		killed := sess.killed
+		sess.mu.Unlock()
+		if !killed {
+			if err := s.start(sess); err == nil {
+				return
+			}
+		}
+	}
+
+	s.mu.Lock()
+	if s.sessions[sess.name] == sess {
+		delete(s.sessions, sess.name)
+	}
+	s.mu.Unlock()
+
+	sess.mu.Lock()
+	for conn := range sess.attached {
+		_ = conn.Close()
+	}
+	sess.attached = nil
+	sess.mu.Unlock()
+}
+
+// kill terminates the session's process group and removes the session.
+func (s *Supervisor) kill(sess *ptySession) {
+	sess.mu.Lock()
+	sess.killed = true
+	cmd, exited := sess.cmd, sess.exited
+	sess.mu.Unlock()
+
+	if cmd != nil && cmd.Process != nil {
+		select {
+		case <-exited:
+		default:
+			_ = terminate(cmd.Process.Pid, false)
+			select {
+			case <-exited:
+			case <-time.After(killTimeout):
+				_ = terminate(cmd.Process.Pid, true)
+				<-exited
+			}
+		}
+	}
+
+	s.mu.Lock()
+	if s.sessions[sess.name] == sess {
+		delete(s.sessions, sess.name)
+	}
+	s.mu.Unlock()
+}
+
+// attach streams the session to conn: the scrollback first, then live
+// output, while everything read from the client goes to the PTY.
+func (s *Supervisor) attach(conn net.Conn, r *bufio.Reader, name string) {
+	defer func() { _ = conn.Close() }()
+	s.mu.Lock()
+	sess := s.sessions[name]
+	s.mu.Unlock()
+	if sess == nil {
+		reply(conn, response{Error: fmt.Sprintf("can't find session: %s", name)})
+		return
+	}
+
+	sess.mu.Lock()
+	if sess.attached == nil {
+		sess.mu.Unlock()
+		reply(conn, response{Error: fmt.Sprintf("can't find session: %s", name)})
+		return
+	}
+	reply(conn, response{})
+	_, _ = conn.Write(sess.out.Bytes())
+	sess.attached[conn] = struct{}{}
+	sess.mu.Unlock()
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			sess.mu.Lock()
+			master := sess.master
+			sess.mu.Unlock()
+			if master != nil {
+				_, _ = master.Write(buf[:n])
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	sess.mu.Lock()
+	delete(sess.attached, conn)
+	sess.mu.Unlock()
+}
@@ -0,0 +1,143 @@
+//go:build linux
+
+package ptyd
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startSupervisor serves a supervisor on a socket in a temp dir and returns
+// a client for it.
+func startSupervisor(t *testing.T) *Client {
+	t.Helper()
+	dir := t.TempDir()
+	socket := filepath.Join(dir, SocketName)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewSupervisor().Serve(ctx, socket) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	})
+
+	c := &Client{socket: socket}
+	deadline := time.Now().Add(5 * time.Second)
+	for !c.Running() {
+		if time.Now().After(deadline) {
+			t.Fatal("supervisor did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return c
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestSupervisorSession(t *testing.T) {
+	c := startSupervisor(t)
+	dir := t.TempDir()
+
+	if err := c.NewSessionWithCommand("gt-test-cat", dir, "printf 'ready\\n'; cat"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	if err := c.NewSessionWithCommand("gt-test-cat", dir, "cat"); err == nil {
+		t.Error("duplicate session was created")
+	}
+	if ok, err := c.HasSession("gt-test-cat"); err != nil || !ok {
+		t.Fatalf("HasSession = %v, %v; want true", ok, err)
+	}
+	if names, err := c.ListSessions(); err != nil || len(names) != 1 || names[0] != "gt-test-cat" {
+		t.Errorf("ListSessions = %v, %v", names, err)
+	}
+	if pid, err := c.GetPanePID("gt-test-cat"); err != nil || pid == "" {
+		t.Errorf("GetPanePID = %q, %v", pid, err)
+	}
+
+	waitFor(t, "startup output", func() bool {
+		out, _ := c.CapturePane("gt-test-cat", 10)
+		return strings.Contains(out, "ready")
+	})
+
+	// The PTY echoes input and cat repeats it.
+	if err := c.SendKeysRaw("gt-test-cat", "hello"); err != nil {
+		t.Fatalf("SendKeysRaw: %v", err)
+	}
+	if err := c.SendKeysRaw("gt-test-cat", "Enter"); err != nil {
+		t.Fatalf("SendKeysRaw Enter: %v", err)
+	}
+	waitFor(t, "echoed input", func() bool {
+		out, _ := c.CapturePane("gt-test-cat", 10)
+		return strings.Count(out, "hello") == 2
+	})
+
+	if err := c.SetEnvironment("gt-test-cat", "GT_AGENT", "claude"); err != nil {
+		t.Fatalf("SetEnvironment: %v", err)
+	}
+	if v, err := c.GetEnvironment("gt-test-cat", "GT_AGENT"); err != nil || v != "claude" {
+		t.Errorf("GetEnvironment = %q, %v", v, err)
+	}
+	if !c.IsAgentAlive("gt-test-cat") {
+		t.Error("IsAgentAlive = false for a running session")
+	}
+
+	if err := c.KillSessionWithProcesses("gt-test-cat"); err != nil {
+		t.Fatalf("KillSessionWithProcesses: %v", err)
+	}
+	if ok, _ := c.HasSession("gt-test-cat"); ok {
+		t.Error("session still exists after kill")
+	}
+}
+
+func TestSupervisorSessionExit(t *testing.T) {
+	c := startSupervisor(t)
+	if err := c.NewSessionWithCommand("gt-test-exit", t.TempDir(), "echo bye"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	waitFor(t, "session to end", func() bool {
+		ok, _ := c.HasSession("gt-test-exit")
+		return !ok
+	})
+}
+
+func TestSupervisorRespawn(t *testing.T) {
+	c := startSupervisor(t)
+	if err := c.NewSessionWithCommand("gt-test-respawn", t.TempDir(), "sleep 0.1"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	if err := c.SetAutoRespawnHook("gt-test-respawn"); err != nil {
+		t.Fatalf("SetAutoRespawnHook: %v", err)
+	}
+	waitFor(t, "agent to exit", func() bool { return !c.IsAgentAlive("gt-test-respawn") })
+	// The session survives the exit while it waits to respawn.
+	if ok, _ := c.HasSession("gt-test-respawn"); !ok {
+		t.Error("auto-respawned session was removed when its command exited")
+	}
+}
+
+func TestClientNotRunning(t *testing.T) {
+	c := &Client{socket: filepath.Join(t.TempDir(), SocketName)}
+	if c.Running() {
+		t.Error("Running() = true without a supervisor")
+	}
+	if ok, err := c.HasSession("gt-x"); ok || err != nil {
+		t.Errorf("HasSession = %v, %v; want false, nil", ok, err)
+	}
+	if err := c.NewSessionWithCommand("gt-x", "", "true"); err != ErrNotRunning {
+		t.Errorf("NewSessionWithCommand error = %v, want ErrNotRunning", err)
+	}
+}
@@ -0,0 +1,105 @@
+package quota
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSwapOAuthAccount(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	source := filepath.Join(dir, "source")
+	for _, d := range []string{target, source} {
+		if err := os.MkdirAll(d, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(d, content string) {
+		if err := os.WriteFile(filepath.Join(d, ".claude.json"), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(target, `{"oauthAccount":{"accountUuid":"old"},"theme":"dark"}`)
+	write(source, `{"oauthAccount":{"accountUuid":"new"}}`)
+
+	backup, err := SwapOAuthAccount(target, source)
+	if err != nil {
+		t.Fatalf("SwapOAuthAccount: %v", err)
+	}
+	if string(backup) != `{"accountUuid":"old"}` {
+		t.Errorf("backup = %s", backup)
+	}
+
+	account := func(raw json.RawMessage) string {
+		var a struct {
+			AccountUUID string `json:"accountUuid"`
+		}
+		_ = json.Unmarshal(raw, &a)
+		return a.AccountUUID
+	}
+	read := func() map[string]json.RawMessage {
+		data, err := os.ReadFile(filepath.Join(target, ".claude.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc map[string]json.RawMessage
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		return doc
+	}
+	doc := read()
+	if account(doc["oauthAccount"]) != "new" {
+		t.Errorf("oauthAccount after swap = %s", doc["oauthAccount"])
+	}
+	if string(doc["theme"]) != `"dark"` {
+		t.Errorf("other fields not preserved: %v", doc)
+	}
+
+	if err := RestoreOAuthAccount(target, backup); err != nil {
+		t.Fatalf("RestoreOAuthAccount: %v", err)
+	}
+	if doc := read(); account(doc["oauthAccount"]) != "old" {
+		t.Errorf("oauthAccount after restore = %s", doc["oauthAccount"])
+	}
+}
+
+func TestSwapOAuthAccount_MissingFile(t *testing.T) {
+	backup, err := SwapOAuthAccount(t.TempDir(), t.TempDir())
+	if err != nil || backup != nil {
+		t.Errorf("SwapOAuthAccount without .claude.json = %s, %v; want nil, nil", backup, err)
+	}
+}
+
+func TestCheckTokenExpiry(t *testing.T) {
+	past := time.Now().Add(-time.Hour).Unix()
+	future := time.Now().Add(time.Hour).Unix()
+	jwt := func(exp int64) string {
+		payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp)))
+		return "e30." + payload + ".sig"
+	}
+
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr bool
+	}{
+		{"expired json", fmt.Sprintf(`{"expires_at":%d}`, past), true},
+		{"valid json", fmt.Sprintf(`{"expires_at":%d}`, future), false},
+		{"expired jwt", jwt(past), true},
+		{"valid jwt", jwt(future), false},
+		{"opaque", "sk-ant-oat01-abc", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkTokenExpiry(tt.raw); (err != nil) != tt.wantErr {
+				t.Errorf("checkTokenExpiry(%q) = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+		})
+	}
+}
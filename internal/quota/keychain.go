@@ -4,13 +4,10 @@ package quota
 
 import (
 	"crypto/sha256"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 	"time"
 )
@@ -118,88 +115,6 @@ func RestoreKeychainToken(backup *KeychainCredential) error {
 	return WriteKeychainToken(backup.ServiceName, "claude-code", backup.Token)
 }
 
-// SwapOAuthAccount copies the oauthAccount field from the source config dir's
-// .claude.json into the target's. This ensures Claude Code identifies as the
-// new account (correct accountUuid/organizationUuid) after a keychain swap.
-// Returns the target's original oauthAccount value for rollback.
-func SwapOAuthAccount(targetConfigDir, sourceConfigDir string) (json.RawMessage, error) {
-	targetPath := filepath.Join(expandTilde(targetConfigDir), ".claude.json")
-	sourcePath := filepath.Join(expandTilde(sourceConfigDir), ".claude.json")
-
-	// Skip if either file doesn't exist — the keychain token is what
-	// authenticates; oauthAccount is only cached identity metadata.
-	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
-		return nil, nil
-	}
-	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
-		return nil, nil
-	}
-
-	// Read source's oauthAccount
-	sourceData, err := os.ReadFile(sourcePath)
-	if err != nil {
-		return nil, fmt.Errorf("reading source .claude.json: %w", err)
-	}
-	var sourceDoc map[string]json.RawMessage
-	if err := json.Unmarshal(sourceData, &sourceDoc); err != nil {
-		return nil, fmt.Errorf("parsing source .claude.json: %w", err)
-	}
-	sourceOAuth, ok := sourceDoc["oauthAccount"]
-	if !ok {
-		return nil, fmt.Errorf("source .claude.json has no oauthAccount")
-	}
-
-	// Read target's .claude.json (preserve all other fields)
-	targetData, err := os.ReadFile(targetPath)
-	if err != nil {
-		return nil, fmt.Errorf("reading target .claude.json: %w", err)
-	}
-	var targetDoc map[string]json.RawMessage
-	if err := json.Unmarshal(targetData, &targetDoc); err != nil {
-		return nil, fmt.Errorf("parsing target .claude.json: %w", err)
-	}
-
-	// Back up target's oauthAccount
-	backup := targetDoc["oauthAccount"]
-
-	// Swap
-	targetDoc["oauthAccount"] = sourceOAuth
-
-	// Write back
-	out, err := json.MarshalIndent(targetDoc, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("marshaling target .claude.json: %w", err)
-	}
-	if err := os.WriteFile(targetPath, out, 0600); err != nil {
-		return nil, fmt.Errorf("writing target .claude.json: %w", err)
-	}
-
-	return backup, nil
-}
-
-// RestoreOAuthAccount writes the backup oauthAccount back to the target .claude.json.
-func RestoreOAuthAccount(targetConfigDir string, backup json.RawMessage) error {
-	if backup == nil {
-		return nil
-	}
-	targetPath := filepath.Join(expandTilde(targetConfigDir), ".claude.json")
-
-	data, err := os.ReadFile(targetPath)
-	if err != nil {
-		return fmt.Errorf("reading target .claude.json: %w", err)
-	}
-	var doc map[string]json.RawMessage
-	if err := json.Unmarshal(data, &doc); err != nil {
-		return fmt.Errorf("parsing target .claude.json: %w", err)
-	}
-	doc["oauthAccount"] = backup
-	out, err := json.MarshalIndent(doc, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshaling target .claude.json: %w", err)
-	}
-	return os.WriteFile(targetPath, out, 0600)
-}
-
 // ValidateKeychainToken checks if the OAuth token for a config dir is still usable.
 // It attempts local validation first (JSON credential expiry, JWT expiry), then
 // falls back to a lightweight API call. Returns nil if the token appears valid
@@ -216,40 +131,7 @@ func ValidateKeychainToken(configDir string) error {
 		return nil
 	}
 
-	// Strategy 1: Parse as JSON credential with expires_at field.
-	// Claude Code may store the full OAuth response including expiry.
-	var cred struct {
-		ExpiresAt int64 `json:"expires_at"`
-	}
-	if json.Unmarshal([]byte(raw), &cred) == nil && cred.ExpiresAt > 0 {
-		if time.Now().Unix() >= cred.ExpiresAt {
-			return fmt.Errorf("token expired at %s", time.Unix(cred.ExpiresAt, 0).Format(time.RFC3339))
-		}
-		return nil
-	}
-
-	// Strategy 2: Parse as JWT — decode payload, check exp claim.
-	parts := strings.Split(raw, ".")
-	if len(parts) == 3 {
-		payload, decErr := base64.RawURLEncoding.DecodeString(parts[1])
-		if decErr == nil {
-			var claims struct {
-				Exp int64 `json:"exp"`
-			}
-			if json.Unmarshal(payload, &claims) == nil && claims.Exp > 0 {
-				if time.Now().Unix() >= claims.Exp {
-					return fmt.Errorf("JWT expired at %s", time.Unix(claims.Exp, 0).Format(time.RFC3339))
-				}
-				return nil
-			}
-		}
-	}
-
-	// Token is present but format is opaque (not JSON with expires_at, not JWT).
-	// Claude Code uses OAuth tokens that authenticate through a different flow
-	// than Bearer tokens against the Anthropic API, so HTTP validation would
-	// always return 401 for valid OAuth tokens. Assume valid if present.
-	return nil
+	return checkTokenExpiry(raw)
 }
 
 // validateTokenHTTP sends a minimal request to the Anthropic API to check if a
@@ -276,14 +158,3 @@ func validateTokenHTTP(token string) error {
 	}
 	return nil
 }
-
-// expandTilde expands a leading ~/ to the user's home directory.
-func expandTilde(path string) string {
-	if strings.HasPrefix(path, "~/") {
-		home, err := os.UserHomeDir()
-		if err == nil {
-			return home + path[1:]
-		}
-	}
-	return path
-}
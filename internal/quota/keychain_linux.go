@@ -0,0 +1,114 @@
+//go:build linux
+
+package quota
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// credentialsFileName is where Claude Code keeps its OAuth credentials on
+// Linux, inside the config dir. There is no keychain: the file is the store.
+//
+// The token is swapped in this plaintext file rather than kept in the Secret
+// Service or an encrypted store: Claude Code only reads it from here, so any
+// other store would still have to write it back in plain text before the
+// session restarts. The file's mode (0600) is its only protection.
+const credentialsFileName = ".credentials.json"
+
+// KeychainCredential holds a backup of a credential for rollback.
+type KeychainCredential struct {
+	ServiceName string // credentials file path
+	Token       string // backed-up file content
+}
+
+// KeychainServiceName returns the credentials file of a config dir. On Linux
+// it stands in for the macOS Keychain service name.
+func KeychainServiceName(configDirPath string) string {
+	return filepath.Join(expandTilde(configDirPath), credentialsFileName)
+}
+
+// ReadKeychainToken reads the credential stored in a credentials file.
+func ReadKeychainToken(serviceName string) (string, error) {
+	data, err := os.ReadFile(serviceName) //nolint:gosec // G304: path derived from account config dir
+	if err != nil {
+		return "", fmt.Errorf("reading credentials %q: %w", serviceName, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// WriteKeychainToken replaces the content of a credentials file. The file is
+// written (mode 0600) next to the original and renamed over it, so a running
+// session never reads a partial credential. accountLabel is unused on Linux.
+func WriteKeychainToken(serviceName, _, token string) error {
+	dir := filepath.Dir(serviceName)
+	tmp, err := os.CreateTemp(dir, credentialsFileName+".*")
+	if err != nil {
+		return fmt.Errorf("writing credentials %q: %w", serviceName, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.WriteString(token); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing credentials %q: %w", serviceName, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing credentials %q: %w", serviceName, err)
+	}
+	if err := os.Rename(tmp.Name(), serviceName); err != nil {
+		return fmt.Errorf("writing credentials %q: %w", serviceName, err)
+	}
+	return nil
+}
+
+// SwapKeychainCredential backs up the target's credentials file, then
+// overwrites it with the source's. Returns the backup for rollback via
+// RestoreKeychainToken.
+//
+// As on macOS, the target config dir is kept: the respawned session reads
+// the fresh credential while /resume still finds the previous transcript.
+func SwapKeychainCredential(targetConfigDir, sourceConfigDir string) (*KeychainCredential, error) {
+	targetFile := KeychainServiceName(targetConfigDir)
+	sourceFile := KeychainServiceName(sourceConfigDir)
+
+	backupToken, err := ReadKeychainToken(targetFile)
+	if err != nil {
+		return nil, fmt.Errorf("backing up target token: %w", err)
+	}
+
+	sourceToken, err := ReadKeychainToken(sourceFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading source token: %w", err)
+	}
+
+	if err := WriteKeychainToken(targetFile, "claude-code", sourceToken); err != nil {
+		return nil, fmt.Errorf("writing source token to target credentials: %w", err)
+	}
+
+	return &KeychainCredential{
+		ServiceName: targetFile,
+		Token:       backupToken,
+	}, nil
+}
+
+// RestoreKeychainToken writes the backup back to the credentials file,
+// undoing a previous SwapKeychainCredential.
+func RestoreKeychainToken(backup *KeychainCredential) error {
+	if backup == nil {
+		return nil
+	}
+	return WriteKeychainToken(backup.ServiceName, "claude-code", backup.Token)
+}
+
+// ValidateKeychainToken checks if the credential of a config dir has expired.
+// Returns nil if it appears valid or can't be read (the swap itself will fail
+// clearly in that case).
+func ValidateKeychainToken(configDir string) error {
+	raw, err := ReadKeychainToken(KeychainServiceName(configDir))
+	if err != nil || raw == "" {
+		return nil
+	}
+	return checkTokenExpiry(raw)
+}
@@ -0,0 +1,105 @@
+//go:build linux
+
+package quota
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeCredentials(t *testing.T, configDir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, credentialsFileName), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKeychainServiceName_Linux(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if got, want := KeychainServiceName("~/.claude-accounts/work"), filepath.Join(home, ".claude-accounts/work", credentialsFileName); got != want {
+		t.Errorf("KeychainServiceName(~/...) = %q, want %q", got, want)
+	}
+	if got, want := KeychainServiceName("/srv/claude"), "/srv/claude/"+credentialsFileName; got != want {
+		t.Errorf("KeychainServiceName(/srv/claude) = %q, want %q", got, want)
+	}
+}
+
+func TestSwapKeychainCredential_Linux(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	source := filepath.Join(dir, "source")
+	writeCredentials(t, target, `{"claudeAiOauth":{"accessToken":"old"}}`)
+	writeCredentials(t, source, `{"claudeAiOauth":{"accessToken":"fresh"}}`+"\n")
+
+	backup, err := SwapKeychainCredential(target, source)
+	if err != nil {
+		t.Fatalf("SwapKeychainCredential: %v", err)
+	}
+	if backup.Token != `{"claudeAiOauth":{"accessToken":"old"}}` {
+		t.Errorf("backup token = %q", backup.Token)
+	}
+
+	targetFile := KeychainServiceName(target)
+	got, err := ReadKeychainToken(targetFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != `{"claudeAiOauth":{"accessToken":"fresh"}}` {
+		t.Errorf("target after swap = %q", got)
+	}
+	info, err := os.Stat(targetFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("target credentials mode = %o, want 600", mode)
+	}
+	if src, _ := ReadKeychainToken(KeychainServiceName(source)); src != `{"claudeAiOauth":{"accessToken":"fresh"}}` {
+		t.Errorf("source changed by swap: %q", src)
+	}
+
+	if err := RestoreKeychainToken(backup); err != nil {
+		t.Fatalf("RestoreKeychainToken: %v", err)
+	}
+	if got, _ := ReadKeychainToken(targetFile); got != `{"claudeAiOauth":{"accessToken":"old"}}` {
+		t.Errorf("target after restore = %q", got)
+	}
+
+	entries, err := os.ReadDir(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("config dir has %d entries after swap and restore, want only the credentials file", len(entries))
+	}
+}
+
+func TestSwapKeychainCredential_LinuxMissing(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	source := filepath.Join(dir, "source")
+	writeCredentials(t, target, "old")
+
+	if _, err := SwapKeychainCredential(target, source); err == nil {
+		t.Fatal("expected error for missing source credentials")
+	}
+	if got, _ := ReadKeychainToken(KeychainServiceName(target)); got != "old" {
+		t.Errorf("target modified by failed swap: %q", got)
+	}
+
+	if _, err := SwapKeychainCredential(filepath.Join(dir, "none"), target); err == nil {
+		t.Fatal("expected error for missing target credentials")
+	}
+}
+
+func TestRestoreKeychainToken_Nil(t *testing.T) {
+	if err := RestoreKeychainToken(nil); err != nil {
+		t.Errorf("RestoreKeychainToken(nil) = %v", err)
+	}
+}
@@ -1,13 +1,12 @@
-//go:build !darwin
+//go:build !darwin && !linux
 
 package quota
 
 import (
-	"encoding/json"
 	"errors"
 )
 
-var errNotDarwin = errors.New("keychain operations are only supported on macOS")
+var errNoCredentialStore = errors.New("credential swaps are only supported on macOS and Linux")
 
 // KeychainCredential holds a backup of a keychain credential for rollback.
 type KeychainCredential struct {
@@ -15,11 +14,9 @@ type KeychainCredential struct {
 	Token       string
 }
 
-func KeychainServiceName(_ string) string                                          { return "" }
-func ReadKeychainToken(_ string) (string, error)                                   { return "", errNotDarwin }
-func WriteKeychainToken(_, _, _ string) error                                      { return errNotDarwin }
-func SwapKeychainCredential(_, _ string) (*KeychainCredential, error)              { return nil, errNotDarwin }
-func RestoreKeychainToken(_ *KeychainCredential) error                             { return errNotDarwin }
-func SwapOAuthAccount(_, _ string) (json.RawMessage, error)                        { return nil, errNotDarwin }
-func RestoreOAuthAccount(_ string, _ json.RawMessage) error                        { return errNotDarwin }
-func ValidateKeychainToken(_ string) error                                         { return nil }
+func KeychainServiceName(_ string) string                             { return "" }
+func ReadKeychainToken(_ string) (string, error)                      { return "", errNoCredentialStore }
+func WriteKeychainToken(_, _, _ string) error                         { return errNoCredentialStore }
+func SwapKeychainCredential(_, _ string) (*KeychainCredential, error) { return nil, errNoCredentialStore }
+func RestoreKeychainToken(_ *KeychainCredential) error                { return errNoCredentialStore }
+func ValidateKeychainToken(_ string) error                            { return nil }
@@ -149,31 +149,37 @@ func (s *Scanner) scanSession(session string) ScanResult {
 }
 
 // resolveAccountHandle maps a session's active account back to a handle.
+func (s *Scanner) resolveAccountHandle(session string) string {
+	return SessionAccount(s.tmux, s.accounts, session)
+}
+
+// SessionAccount maps a session's active account back to a handle.
 // Checks GT_QUOTA_ACCOUNT first (set by keychain swap rotation), then
 // falls back to matching CLAUDE_CONFIG_DIR against registered accounts.
-func (s *Scanner) resolveAccountHandle(session string) string {
-	if s.accounts == nil {
+// Returns "" when the account can't be resolved.
+func SessionAccount(tmux TmuxClient, accounts *config.AccountsConfig, session string) string {
+	if accounts == nil {
 		return ""
 	}
 
 	// After keychain swap, the config dir still maps to the old account.
 	// GT_QUOTA_ACCOUNT records which account's token is actually active.
-	if override, err := s.tmux.GetEnvironment(session, "GT_QUOTA_ACCOUNT"); err == nil {
+	if override, err := tmux.GetEnvironment(session, "GT_QUOTA_ACCOUNT"); err == nil {
 		override = strings.TrimSpace(override)
 		if override != "" {
-			if _, ok := s.accounts.Accounts[override]; ok {
+			if _, ok := accounts.Accounts[override]; ok {
 				return override
 			}
 		}
 	}
 
-	configDir, err := s.tmux.GetEnvironment(session, "CLAUDE_CONFIG_DIR")
+	configDir, err := tmux.GetEnvironment(session, "CLAUDE_CONFIG_DIR")
 	if err != nil {
 		return "" // No CLAUDE_CONFIG_DIR = using default config
 	}
 
 	configDir = strings.TrimSpace(configDir)
-	for handle, acct := range s.accounts.Accounts {
+	for handle, acct := range accounts.Accounts {
 		// Compare normalized paths (accounts may use ~/... while tmux has expanded)
 		if acct.ConfigDir == configDir || util.ExpandHome(acct.ConfigDir) == configDir {
 			return handle
@@ -181,6 +181,29 @@ func (m *Manager) LimitedAccounts(state *config.QuotaState) []string {
 	return limited
 }
 
+// RateLimited returns the accounts that are rate-limited right now, mapped to
+// their reported reset time. Limits whose reset time has passed are treated as
+// cleared (state on disk is not modified).
+func (m *Manager) RateLimited() (map[string]string, error) {
+	state, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+	return rateLimitedAt(state, time.Now()), nil
+}
+
+// rateLimitedAt is the testable core of RateLimited, accepting a reference time.
+func rateLimitedAt(state *config.QuotaState, now time.Time) map[string]string {
+	clearExpiredAt(nil, state, now)
+	limited := make(map[string]string)
+	for handle, acctState := range state.Accounts {
+		if acctState.Status == config.QuotaStatusLimited {
+			limited[handle] = acctState.ResetsAt
+		}
+	}
+	return limited
+}
+
 // sortByLastUsed sorts handles by their LastUsed timestamp ascending.
 func sortByLastUsed(handles []string, state *config.QuotaState) {
 	// Simple insertion sort — handles list is small (3-5 accounts)
@@ -510,3 +510,24 @@ func TestClearExpired_NoResetsAt(t *testing.T) {
 		t.Errorf("expected no_reset to remain limited")
 	}
 }
+
+func TestRateLimited_SkipsExpired(t *testing.T) {
+	la, _ := time.LoadLocation("America/Los_Angeles")
+	now := time.Date(2026, 2, 18, 15, 0, 0, 0, la)
+
+	state := &config.QuotaState{
+		Accounts: map[string]config.AccountQuotaState{
+			"expired":       {Status: config.QuotaStatusLimited, ResetsAt: "11am (America/Los_Angeles)"},
+			"still_limited": {Status: config.QuotaStatusLimited, ResetsAt: "7pm (America/Los_Angeles)"},
+			"available":     {Status: config.QuotaStatusAvailable},
+		},
+	}
+
+	limited := rateLimitedAt(state, now)
+	if len(limited) != 1 {
+		t.Fatalf("expected 1 limited account, got %v", limited)
+	}
+	if limited["still_limited"] != "7pm (America/Los_Angeles)" {
+		t.Errorf("expected still_limited with reset time, got %q", limited["still_limited"])
+	}
+}
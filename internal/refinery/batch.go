@@ -48,6 +48,14 @@ type BatchResult struct {
 	// MergeCommit is the final SHA pushed to the target branch (empty if nothing merged).
 	MergeCommit string
 
+	// PullRequest is the forge pull request URL when the batch was landed
+	// through a pull request.
+	PullRequest string
+
+	// SkippedGates lists quality gates not run on the merged stack because
+	// it didn't touch any of their paths.
+	SkippedGates []string
+
 	// Error is set if the batch processing encountered an infrastructure error.
 	Error error
 }
@@ -87,13 +95,14 @@ func (e *Engineer) AssembleBatch(readyMRs []*MRInfo, config *BatchConfig) []*MRI
 	return batch
 }
 
-// BuildRebaseStack constructs a squash-merge stack on the target branch.
-// Each MR is squash-merged sequentially: target ← MR1 ← MR2 ← MR3.
+// BuildRebaseStack constructs a merge stack on the target branch.
+// Each MR is applied sequentially with the configured merge strategy:
+// target ← MR1 ← MR2 ← MR3.
 // Returns the list of MRs that were successfully stacked, and any that
 // conflicted (which are removed from the stack and the stack is rebuilt).
 //
 // On return, the git working directory is on the target branch with all
-// successful MR squash-merges applied (but not pushed).
+// successful MRs applied (but not pushed).
 func (e *Engineer) BuildRebaseStack(ctx context.Context, batch []*MRInfo, target string) (stacked []*MRInfo, conflicts []*MRInfo, err error) {
 	if len(batch) == 0 {
 		return nil, nil, nil
@@ -113,7 +122,7 @@ func (e *Engineer) BuildRebaseStack(ctx context.Context, batch []*MRInfo, target
 		return nil, nil, fmt.Errorf("get base SHA: %w", err)
 	}
 
-	// Try to stack each MR via squash-merge
+	// Try to stack each MR
 	for _, mr := range batch {
 		_, _ = fmt.Fprintf(e.output, "[Batch] Stacking MR %s (branch %s)...\n", mr.ID, mr.Branch)
 
@@ -137,17 +146,15 @@ func (e *Engineer) BuildRebaseStack(ctx context.Context, batch []*MRInfo, target
 			}
 			// Rebuild the stack with MRs stacked so far (minus the conflicting one)
 			for _, prev := range stacked {
-				msg := e.getMergeMessage(prev)
-				if mergeErr := e.git.MergeSquash(prev.Branch, msg); mergeErr != nil {
+				if mergeErr := e.applyMR(prev); mergeErr != nil {
 					return nil, nil, fmt.Errorf("rebuild stack for %s: %w", prev.ID, mergeErr)
 				}
 			}
 			continue
 		}
 
-		// Squash-merge this MR onto the stack
-		msg := e.getMergeMessage(mr)
-		if mergeErr := e.git.MergeSquash(mr.Branch, msg); mergeErr != nil {
+		// Apply this MR onto the stack
+		if mergeErr := e.applyMR(mr); mergeErr != nil {
 			_, _ = fmt.Fprintf(e.output, "[Batch] MR %s: merge failed: %v, removing from batch\n", mr.ID, mergeErr)
 			conflicts = append(conflicts, mr)
 
@@ -156,8 +163,7 @@ func (e *Engineer) BuildRebaseStack(ctx context.Context, batch []*MRInfo, target
 				return nil, nil, fmt.Errorf("reset after merge failure: %w", resetErr)
 			}
 			for _, prev := range stacked {
-				prevMsg := e.getMergeMessage(prev)
-				if rebuildErr := e.git.MergeSquash(prev.Branch, prevMsg); rebuildErr != nil {
+				if rebuildErr := e.applyMR(prev); rebuildErr != nil {
 					return nil, nil, fmt.Errorf("rebuild stack for %s: %w", prev.ID, rebuildErr)
 				}
 			}
@@ -191,15 +197,15 @@ func (e *Engineer) getMergeMessage(mr *MRInfo) string {
 //  1. Build the rebase stack (target ← MR1 ← MR2 ← ... ← MRn)
 //  2. Run gates once on the stack tip
 //  3. If green: push (fast-forward all MRs to target)
-//  4. If red and RetryBatchOnFlaky: retry the full batch once
+//  4. If red and RetryBatchOnFlaky, or only known-flaky tests failed: retry the full batch once
 //  5. If still red: bisect to isolate the culprit
 //  6. Re-batch good MRs for the next cycle
-func (e *Engineer) ProcessBatch(ctx context.Context, batch []*MRInfo, target string, batchCfg *BatchConfig) *BatchResult {
+func (e *Engineer) ProcessBatch(ctx context.Context, batch []*MRInfo, target string, batchCfg *BatchConfig) (result *BatchResult) {
 	if batchCfg == nil {
 		batchCfg = DefaultBatchConfig()
 	}
 
-	result := &BatchResult{}
+	result = &BatchResult{}
 
 	if len(batch) == 0 {
 		return result
@@ -210,6 +216,9 @@ func (e *Engineer) ProcessBatch(ctx context.Context, batch []*MRInfo, target str
 		return e.processSingleMR(ctx, batch[0], target)
 	}
 
+	ctx, span := startBatchSpan(ctx, batch, target)
+	defer func() { endBatchSpan(span, result) }()
+
 	_, _ = fmt.Fprintf(e.output, "[Batch] Processing batch of %d MRs targeting %s\n", len(batch), target)
 
 	// Step 1: Build the stack
@@ -228,22 +237,28 @@ func (e *Engineer) ProcessBatch(ctx context.Context, batch []*MRInfo, target str
 	// If only one MR survived after conflict removal, just process it directly
 	if len(stacked) == 1 {
 		_, _ = fmt.Fprintln(e.output, "[Batch] Only 1 MR survived stack construction, processing directly")
-		// We already have the squash merge on the target branch, run gates and push
+		// We already have the MR applied on the target branch, run gates and push
 		return e.verifyAndPush(ctx, stacked, target)
 	}
 
 	// Step 2: Run gates on the stack tip
 	_, _ = fmt.Fprintf(e.output, "[Batch] Running gates on stack tip (%d MRs)...\n", len(stacked))
-	gateResult := e.runBatchGates(ctx)
+	gateResult := e.runBatchGates(ctx, target, stacked)
 
 	// Step 3: Happy path — all green
 	if gateResult.Success {
+		result.SkippedGates = gateResult.SkippedGates
 		return e.fastForwardBatch(ctx, stacked, target, result)
 	}
 
-	// Step 4: Retry if flaky test handling is enabled
-	if batchCfg.RetryBatchOnFlaky {
-		_, _ = fmt.Fprintln(e.output, "[Batch] Gates failed, retrying full batch (flaky test check)...")
+	// Step 4: Retry if flaky test handling is enabled, or if only tests
+	// with a flake history failed
+	if batchCfg.RetryBatchOnFlaky || gateResult.FlakyFailure {
+		if gateResult.FlakyFailure {
+			_, _ = fmt.Fprintln(e.output, "[Batch] Only known-flaky tests failed, retrying full batch...")
+		} else {
+			_, _ = fmt.Fprintln(e.output, "[Batch] Gates failed, retrying full batch (flaky test check)...")
+		}
 
 		// Rebuild the stack from scratch for a clean retry
 		if resetErr := e.resetAndRebuildStack(stacked, target); resetErr != nil {
@@ -251,9 +266,10 @@ func (e *Engineer) ProcessBatch(ctx context.Context, batch []*MRInfo, target str
 			return result
 		}
 
-		retryResult := e.runBatchGates(ctx)
+		retryResult := e.runBatchGates(ctx, target, stacked)
 		if retryResult.Success {
 			_, _ = fmt.Fprintln(e.output, "[Batch] Retry succeeded (was flaky)")
+			result.SkippedGates = retryResult.SkippedGates
 			return e.fastForwardBatch(ctx, stacked, target, result)
 		}
 		_, _ = fmt.Fprintln(e.output, "[Batch] Retry also failed, proceeding to bisection")
@@ -273,8 +289,9 @@ func (e *Engineer) ProcessBatch(ctx context.Context, batch []*MRInfo, target str
 			return result
 		}
 		// Verify the good subset actually passes
-		verifyResult := e.runBatchGates(ctx)
+		verifyResult := e.runBatchGates(ctx, target, good)
 		if verifyResult.Success {
+			result.SkippedGates = verifyResult.SkippedGates
 			return e.fastForwardBatch(ctx, good, target, result)
 		}
 		// If the good subset also fails, something is wrong — don't merge anything
@@ -288,13 +305,15 @@ func (e *Engineer) ProcessBatch(ctx context.Context, batch []*MRInfo, target str
 // processSingleMR handles the degenerate case of a batch with one MR.
 func (e *Engineer) processSingleMR(ctx context.Context, mr *MRInfo, target string) *BatchResult {
 	result := &BatchResult{}
-	processResult := e.doMerge(ctx, mr.Branch, target, mr.SourceIssue)
+	processResult := e.doMerge(ctx, mr, target)
+	result.PullRequest = processResult.PullRequest
+	result.SkippedGates = processResult.SkippedGates
 	if processResult.Success {
 		result.Merged = []*MRInfo{mr}
 		result.MergeCommit = processResult.MergeCommit
 	} else if processResult.Conflict {
 		result.Conflicts = []*MRInfo{mr}
-	} else if processResult.TestsFailed {
+	} else if processResult.TestsFailed || processResult.PullRequestFailed {
 		result.Culprits = []*MRInfo{mr}
 	} else {
 		result.Error = fmt.Errorf("merge failed: %s", processResult.Error)
@@ -302,10 +321,16 @@ func (e *Engineer) processSingleMR(ctx context.Context, mr *MRInfo, target strin
 	return result
 }
 
-// runBatchGates runs quality gates (or legacy tests) on the current working tree.
-func (e *Engineer) runBatchGates(ctx context.Context) ProcessResult {
+// runBatchGates runs quality gates (or legacy tests) on the current working tree,
+// which holds mrs stacked on target. Gates are selected by what the stack
+// changes relative to origin/target.
+func (e *Engineer) runBatchGates(ctx context.Context, target string, mrs []*MRInfo) ProcessResult {
 	if len(e.config.Gates) > 0 {
-		return e.runGates(ctx)
+		return e.runGates(ctx, &gateScope{
+			Target:  target,
+			MRs:     gateRunMRs(mrs...),
+			Changed: e.changedFiles("origin/"+target, "HEAD"),
+		})
 	}
 	if e.config.RunTests && e.config.TestCommand != "" {
 		result := e.runTests(ctx)
@@ -322,11 +347,23 @@ func (e *Engineer) runBatchGates(ctx context.Context) ProcessResult {
 	return ProcessResult{Success: true}
 }
 
+// runBisectGates runs gates on one bisection step. A failure confined to
+// tests that have flaked before is re-run once before it counts against
+// the MRs on the stack; deterministic failures are taken at face value.
+func (e *Engineer) runBisectGates(ctx context.Context, target string, mrs []*MRInfo) ProcessResult {
+	result := e.runBatchGates(ctx, target, mrs)
+	if result.Success || !result.FlakyFailure {
+		return result
+	}
+	_, _ = fmt.Fprintf(e.output, "[Bisect] Only known-flaky tests failed for %v, re-running before blaming\n", mrIDs(mrs))
+	return e.runBatchGates(ctx, target, mrs)
+}
+
 // verifyAndPush runs gates and pushes the current state for a set of stacked MRs.
 func (e *Engineer) verifyAndPush(ctx context.Context, stacked []*MRInfo, target string) *BatchResult {
 	result := &BatchResult{}
 
-	gateResult := e.runBatchGates(ctx)
+	gateResult := e.runBatchGates(ctx, target, stacked)
 	if !gateResult.Success {
 		if gateResult.TestsFailed {
 			result.Culprits = stacked
@@ -336,11 +373,13 @@ func (e *Engineer) verifyAndPush(ctx context.Context, stacked []*MRInfo, target
 		return result
 	}
 
+	result.SkippedGates = gateResult.SkippedGates
 	return e.fastForwardBatch(ctx, stacked, target, result)
 }
 
-// fastForwardBatch pushes the current state to the target branch.
-// The working tree must already be on the target branch with all squash-merges applied.
+// fastForwardBatch pushes the current state to the target branch, or lands it
+// through a pull request when the target is protected.
+// The working tree must already be on the target branch with all MRs applied.
 func (e *Engineer) fastForwardBatch(ctx context.Context, stacked []*MRInfo, target string, result *BatchResult) *BatchResult {
 	// Get the tip SHA
 	tipSHA, err := e.git.Rev("HEAD")
@@ -370,6 +409,28 @@ func (e *Engineer) fastForwardBatch(ctx context.Context, stacked []*MRInfo, targ
 		}()
 	}
 
+	ids := make([]string, len(stacked))
+	for i, mr := range stacked {
+		ids[i] = mr.ID
+	}
+
+	// Protected targets land through one pull request for the whole stack
+	if e.usePullRequest(target) {
+		land := e.landViaPullRequest(ctx, target, pullRequestBranchPrefix+"batch/"+target, stacked)
+		result.PullRequest = land.URL
+		if land.Err != nil {
+			if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+				_, _ = fmt.Fprintf(e.output, "[Batch] Warning: failed to reset %s after pull request failure: %v\n", target, resetErr)
+			}
+			result.Error = fmt.Errorf("land via pull request: %w", land.Err)
+			return result
+		}
+		_, _ = fmt.Fprintf(e.output, "[Batch] Successfully merged batch via %s: %s\n", land.URL, strings.Join(ids, ", "))
+		result.Merged = stacked
+		result.MergeCommit = land.MergeCommit
+		return result
+	}
+
 	// Push to origin
 	_, _ = fmt.Fprintf(e.output, "[Batch] Pushing %d merged MRs to origin/%s...\n", len(stacked), target)
 	if pushErr := e.git.Push("origin", target, false); pushErr != nil {
@@ -380,10 +441,6 @@ func (e *Engineer) fastForwardBatch(ctx context.Context, stacked []*MRInfo, targ
 		return result
 	}
 
-	ids := make([]string, len(stacked))
-	for i, mr := range stacked {
-		ids[i] = mr.ID
-	}
 	_, _ = fmt.Fprintf(e.output, "[Batch] Successfully merged batch: %s (commit %s)\n", strings.Join(ids, ", "), tipSHA[:8])
 
 	result.Merged = stacked
@@ -412,7 +469,7 @@ func (e *Engineer) bisectBatch(ctx context.Context, batch []*MRInfo, target stri
 		return nil, batch
 	}
 
-	leftResult := e.runBatchGates(ctx)
+	leftResult := e.runBisectGates(ctx, target, left)
 
 	if leftResult.Success {
 		// Left half is green — culprit is in right half
@@ -441,7 +498,7 @@ func (e *Engineer) bisectBatch(ctx context.Context, batch []*MRInfo, target stri
 			_, _ = fmt.Fprintf(e.output, "[Bisect] Error testing right with good left: %v\n", resetErr)
 			return leftGood, append(leftCulprits, right...)
 		}
-		combinedResult := e.runBatchGates(ctx)
+		combinedResult := e.runBisectGates(ctx, target, combined)
 		if combinedResult.Success {
 			return append(leftGood, right...), leftCulprits
 		}
@@ -454,7 +511,7 @@ func (e *Engineer) bisectBatch(ctx context.Context, batch []*MRInfo, target stri
 	if resetErr := e.resetAndRebuildStack(right, target); resetErr != nil {
 		return nil, batch
 	}
-	rightResult := e.runBatchGates(ctx)
+	rightResult := e.runBisectGates(ctx, target, right)
 	if rightResult.Success {
 		return right, leftCulprits
 	}
@@ -481,7 +538,7 @@ func (e *Engineer) bisectRight(ctx context.Context, knownGood []*MRInfo, right [
 		return nil, right
 	}
 
-	result := e.runBatchGates(ctx)
+	result := e.runBisectGates(ctx, target, testBatch)
 	if result.Success {
 		// rLeft is fine in context of knownGood — culprit is in rRight
 		_, _ = fmt.Fprintf(e.output, "[Bisect-R] knownGood+rLeft passed → culprit in rRight=%v\n", mrIDs(rRight))
@@ -501,7 +558,7 @@ func (e *Engineer) bisectRight(ctx context.Context, knownGood []*MRInfo, right [
 	if resetErr := e.resetAndRebuildStack(testBatch2, target); resetErr != nil {
 		return rLeftGood, append(rLeftCulprits, rRight...)
 	}
-	result2 := e.runBatchGates(ctx)
+	result2 := e.runBisectGates(ctx, target, testBatch2)
 	if result2.Success {
 		_, _ = fmt.Fprintf(e.output, "[Bisect-R] rRight passed → good=%v, culprits=%v\n", mrIDs(append(rLeftGood, rRight...)), mrIDs(rLeftCulprits))
 		return append(rLeftGood, rRight...), rLeftCulprits
@@ -519,7 +576,7 @@ func mrIDs(mrs []*MRInfo) []string {
 	return ids
 }
 
-// resetAndRebuildStack resets the target branch and rebuilds the squash-merge stack.
+// resetAndRebuildStack resets the target branch and rebuilds the merge stack.
 func (e *Engineer) resetAndRebuildStack(mrs []*MRInfo, target string) error {
 	// Reset target to origin
 	if err := e.git.Checkout(target); err != nil {
@@ -531,9 +588,8 @@ func (e *Engineer) resetAndRebuildStack(mrs []*MRInfo, target string) error {
 
 	// Rebuild the stack
 	for _, mr := range mrs {
-		msg := e.getMergeMessage(mr)
-		if err := e.git.MergeSquash(mr.Branch, msg); err != nil {
-			return fmt.Errorf("squash merge %s: %w", mr.ID, err)
+		if err := e.applyMR(mr); err != nil {
+			return fmt.Errorf("apply %s: %w", mr.ID, err)
 		}
 	}
 	return nil
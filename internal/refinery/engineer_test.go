@@ -439,7 +439,7 @@ func TestRunGates_Sequential_AllPass(t *testing.T) {
 	}
 	e.config.GatesParallel = false
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), nil)
 	if !result.Success {
 		t.Errorf("expected success, got error: %s", result.Error)
 	}
@@ -463,7 +463,7 @@ func TestRunGates_Sequential_StopsOnFirstFailure(t *testing.T) {
 	}
 	e.config.GatesParallel = false
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), nil)
 	if result.Success {
 		t.Error("expected failure")
 	}
@@ -490,7 +490,7 @@ func TestRunGates_Parallel_AllPass(t *testing.T) {
 	}
 	e.config.GatesParallel = true
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), nil)
 	if !result.Success {
 		t.Errorf("expected success, got error: %s", result.Error)
 	}
@@ -508,7 +508,7 @@ func TestRunGates_Parallel_AnyFailure(t *testing.T) {
 	}
 	e.config.GatesParallel = true
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), nil)
 	if result.Success {
 		t.Error("expected failure when any gate fails")
 	}
@@ -527,7 +527,7 @@ func TestRunGates_Empty(t *testing.T) {
 	e.output = io.Discard
 	e.config.Gates = nil
 
-	result := e.runGates(context.Background())
+	result := e.runGates(context.Background(), nil)
 	if !result.Success {
 		t.Error("expected success with no gates configured")
 	}
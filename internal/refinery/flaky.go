@@ -0,0 +1,372 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Outcomes recorded in FlakyTest.History, one per gate run.
+const (
+	testPassed  = 'P'
+	testFailed  = 'F' // Failed on every attempt
+	testFlaked  = 'X' // Failed, then passed when the gate was re-run on the same tree
+	testMissing = 0
+)
+
+// FlakyConfig enables flaky-test tracking for gates that declare a
+// TestReport. A failed gate is re-run on the same tree so that tests which
+// fail and then pass are told apart from deterministic failures; each
+// test's outcomes are kept per rig, and tests that flake too often are
+// quarantined: their failures stop failing MRs and a bead is filed.
+type FlakyConfig struct {
+	// Threshold is the flake rate (flakes / recorded runs) at or above which
+	// a test is quarantined.
+	Threshold float64 `json:"threshold"`
+
+	// MinRuns is how many runs of a test must be recorded before it can be
+	// quarantined.
+	MinRuns int `json:"min_runs"`
+
+	// Window is how many recent runs are kept per test.
+	Window int `json:"window"`
+
+	// Retries is how many times a gate with failing tests is re-run.
+	Retries int `json:"retries"`
+}
+
+// DefaultFlakyConfig returns the flaky-test settings used when the flaky
+// section is present but leaves fields unset.
+func DefaultFlakyConfig() *FlakyConfig {
+	return &FlakyConfig{
+		Threshold: 0.1,
+		MinRuns:   10,
+		Window:    100,
+		Retries:   1,
+	}
+}
+
+// flakyConfigRaw is the JSON representation of a flaky config; pointers
+// tell unset fields from zero values.
+type flakyConfigRaw struct {
+	Threshold *float64 `json:"threshold"`
+	MinRuns   *int     `json:"min_runs"`
+	Window    *int     `json:"window"`
+	Retries   *int     `json:"retries"`
+}
+
+// parseFlakyConfig validates raw and applies defaults.
+func parseFlakyConfig(raw *flakyConfigRaw) (*FlakyConfig, error) {
+	cfg := DefaultFlakyConfig()
+	if raw.Threshold != nil {
+		cfg.Threshold = *raw.Threshold
+	}
+	if raw.MinRuns != nil {
+		cfg.MinRuns = *raw.MinRuns
+	}
+	if raw.Window != nil {
+		cfg.Window = *raw.Window
+	}
+	if raw.Retries != nil {
+		cfg.Retries = *raw.Retries
+	}
+	if cfg.Threshold <= 0 || cfg.Threshold > 1 {
+		return nil, fmt.Errorf("threshold must be in (0, 1], got %v", cfg.Threshold)
+	}
+	if cfg.MinRuns < 1 {
+		return nil, fmt.Errorf("min_runs must be at least 1, got %d", cfg.MinRuns)
+	}
+	if cfg.Window < cfg.MinRuns {
+		return nil, fmt.Errorf("window (%d) must be at least min_runs (%d)", cfg.Window, cfg.MinRuns)
+	}
+	if cfg.Retries < 0 {
+		return nil, fmt.Errorf("retries must not be negative, got %d", cfg.Retries)
+	}
+	return cfg, nil
+}
+
+// FlakyTest is the recorded history of one test.
+type FlakyTest struct {
+	Gate string `json:"gate"`
+
+	// History holds one outcome per gate run, oldest first: P passed,
+	// F failed, X flaked.
+	History string `json:"history"`
+
+	Quarantined   bool       `json:"quarantined,omitempty"`
+	QuarantinedAt *time.Time `json:"quarantined_at,omitempty"`
+	Bead          string     `json:"bead,omitempty"` // Bead filed when quarantined
+}
+
+// Flakes returns how many recorded runs flaked.
+func (t *FlakyTest) Flakes() int {
+	return strings.Count(t.History, string(rune(testFlaked)))
+}
+
+// FlakeRate returns the fraction of recorded runs that flaked.
+func (t *FlakyTest) FlakeRate() float64 {
+	if len(t.History) == 0 {
+		return 0
+	}
+	return float64(t.Flakes()) / float64(len(t.History))
+}
+
+// FlakyHistory is a rig's per-test flakiness record, kept in
+// FlakyHistoryPath.
+type FlakyHistory struct {
+	Tests map[string]*FlakyTest `json:"tests"`
+}
+
+// Quarantined returns the IDs of quarantined tests, sorted.
+func (h *FlakyHistory) Quarantined() []string {
+	var ids []string
+	for id, t := range h.Tests {
+		if t.Quarantined {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// FlakyHistoryPath returns where a rig's flaky-test history is stored.
+func FlakyHistoryPath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "flaky-tests.json")
+}
+
+// LoadFlakyHistory reads a rig's flaky-test history. A missing file yields
+// an empty history.
+func LoadFlakyHistory(rigPath string) (*FlakyHistory, error) {
+	h := &FlakyHistory{Tests: make(map[string]*FlakyTest)}
+	data, err := os.ReadFile(FlakyHistoryPath(rigPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("parsing flaky-test history: %w", err)
+	}
+	if h.Tests == nil {
+		h.Tests = make(map[string]*FlakyTest)
+	}
+	return h, nil
+}
+
+// flakyHistoryMu serializes read-modify-write cycles on history files.
+var flakyHistoryMu sync.Mutex
+
+// classifyAttempts turns the test outcomes of each attempt of one gate run
+// into one outcome per test.
+func classifyAttempts(attempts []testOutcomes) map[string]byte {
+	obs := make(map[string]byte)
+	for _, attempt := range attempts {
+		for id, failed := range attempt {
+			prev := obs[id]
+			switch {
+			case prev == testMissing && failed:
+				obs[id] = testFailed
+			case prev == testMissing:
+				obs[id] = testPassed
+			case prev != testFlaked && (prev == testFailed) != failed:
+				obs[id] = testFlaked
+			}
+		}
+	}
+	return obs
+}
+
+// runTestGate runs a gate and, when flaky tracking applies to it, re-runs it
+// while tests keep failing so flakes can be told from real failures. The
+// result carries per-test outcomes for applyFlakyHistory.
+func (e *Engineer) runTestGate(ctx context.Context, name string, gate *GateConfig) GateResult {
+	started := time.Now()
+	res := e.runGate(ctx, name, gate)
+	cfg := e.config.Flaky
+	if cfg == nil || gate.TestReport == "" {
+		return res
+	}
+
+	attempts := []testOutcomes{e.parseTestReport(gate, res, started)}
+	for retry := 1; retry <= cfg.Retries && !res.Success && ctx.Err() == nil; retry++ {
+		if len(attempts[len(attempts)-1].failed()) == 0 {
+			break // Failed for a reason other than tests, e.g. a build error
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: re-running to check for flaky tests (%d/%d)\n", name, retry, cfg.Retries)
+		started = time.Now()
+		next := e.runGate(ctx, name, gate)
+		attempts = append(attempts, e.parseTestReport(gate, next, started))
+
+		marker := fmt.Sprintf("\n=== gt: re-run %d of gate %s ===\n", retry, name)
+		next.Stdout = append(append(res.Stdout, marker...), next.Stdout...)
+		next.Stderr = append(append(res.Stderr, marker...), next.Stderr...)
+		next.Elapsed += res.Elapsed
+		res = next
+	}
+
+	res.tests = classifyAttempts(attempts)
+	if len(res.tests) == 0 {
+		res.tests = nil // No report to learn from
+		return res
+	}
+	res.FailedTests = attempts[len(attempts)-1].failed()
+	sort.Strings(res.FailedTests)
+	for id, o := range res.tests {
+		if o == testFlaked {
+			res.FlakyTests = append(res.FlakyTests, id)
+		}
+	}
+	sort.Strings(res.FlakyTests)
+	return res
+}
+
+// applyFlakyHistory records the test outcomes of results in the rig's
+// history, quarantines tests that crossed the flake threshold and then
+// re-judges failed gates: a gate whose only failing tests are quarantined
+// passes, and one whose failing tests have all flaked before is marked
+// KnownFlaky.
+func (e *Engineer) applyFlakyHistory(results []GateResult) {
+	cfg := e.config.Flaky
+	if cfg == nil {
+		return
+	}
+	observed := false
+	for _, r := range results {
+		if r.tests != nil {
+			observed = true
+		}
+	}
+	if !observed {
+		return
+	}
+
+	flakyHistoryMu.Lock()
+	defer flakyHistoryMu.Unlock()
+
+	history, err := LoadFlakyHistory(e.rig.Path)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: flaky-test history unreadable, starting fresh: %v\n", err)
+		history = &FlakyHistory{Tests: make(map[string]*FlakyTest)}
+	}
+
+	for i := range results {
+		r := &results[i]
+		for _, id := range sortedTestIDs(r.tests) {
+			t := history.Tests[id]
+			if t == nil {
+				t = &FlakyTest{}
+				history.Tests[id] = t
+			}
+			t.Gate = r.Name
+			if t.Quarantined && r.tests[id] != testPassed && t.Bead != "" && e.quarantineBeadClosed != nil && e.quarantineBeadClosed(t.Bead) {
+				// Closing the bead releases the test; start its record over.
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Test %s released from quarantine (%s closed)\n", id, t.Bead)
+				*t = FlakyTest{Gate: r.Name}
+			}
+			t.History += string(rune(r.tests[id]))
+			if len(t.History) > cfg.Window {
+				t.History = t.History[len(t.History)-cfg.Window:]
+			}
+			if !t.Quarantined && len(t.History) >= cfg.MinRuns && t.FlakeRate() >= cfg.Threshold {
+				e.quarantineTest(id, t)
+			}
+		}
+
+		if r.Success || len(r.FailedTests) == 0 {
+			continue
+		}
+		quarantined, knownFlaky := true, true
+		for _, id := range r.FailedTests {
+			t := history.Tests[id]
+			quarantined = quarantined && t.Quarantined
+			knownFlaky = knownFlaky && t.Flakes() > 0
+		}
+		if quarantined {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: only quarantined tests failed (%s), treating as passed\n", r.Name, strings.Join(r.FailedTests, ", "))
+			r.Success = true
+			r.Error = ""
+			r.QuarantinedTests = r.FailedTests
+		} else {
+			r.KnownFlaky = knownFlaky
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(FlakyHistoryPath(e.rig.Path)), 0755); err == nil {
+		err = util.AtomicWriteJSON(FlakyHistoryPath(e.rig.Path), history)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to save flaky-test history: %v\n", err)
+	}
+}
+
+// quarantineTest marks t quarantined and files a bead for it.
+func (e *Engineer) quarantineTest(id string, t *FlakyTest) {
+	now := time.Now()
+	t.Quarantined = true
+	t.QuarantinedAt = &now
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Quarantining flaky test %s (flaked %d of %d runs)\n", id, t.Flakes(), len(t.History))
+	if e.fileQuarantineBead == nil {
+		return
+	}
+	beadID, err := e.fileQuarantineBead(id, t)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to file bead for quarantined test %s: %v\n", id, err)
+		return
+	}
+	t.Bead = beadID
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Filed %s for quarantined test %s\n", beadID, id)
+}
+
+// createQuarantineBead files the bug bead that tracks a quarantined test.
+func (e *Engineer) createQuarantineBead(id string, t *FlakyTest) (string, error) {
+	description := fmt.Sprintf(`The %s refinery quarantined a flaky test: its failures no longer fail merge requests.
+
+## Metadata
+- Test: %s
+- Gate: %s
+- Flaked: %d of the last %d runs (%.0f%%)
+- History (oldest first; P passed, F failed, X flaked): %s
+
+## Instructions
+1. Reproduce with the gate's command, e.g. run the test repeatedly with -count
+2. Fix the source of nondeterminism
+3. Close this bead: the test leaves quarantine on its next failure check`,
+		e.rig.Name, id, t.Gate, t.Flakes(), len(t.History), 100*t.FlakeRate(), t.History)
+
+	issue, err := e.beads.Create(beads.CreateOptions{
+		Title:       "Flaky test quarantined: " + id,
+		Type:        "bug",
+		Priority:    2,
+		Description: description,
+		Actor:       e.rig.Name + "/refinery",
+	})
+	if err != nil {
+		return "", err
+	}
+	return issue.ID, nil
+}
+
+// beadClosed reports whether a bead exists and is closed.
+func (e *Engineer) beadClosed(id string) bool {
+	issue, err := e.beads.Show(id)
+	return err == nil && issue.Status == "closed"
+}
+
+func sortedTestIDs(obs map[string]byte) []string {
+	ids := make([]string, 0, len(obs))
+	for id := range obs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
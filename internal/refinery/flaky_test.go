@@ -0,0 +1,211 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// flakyGate returns a go-json gate whose test p.TestA fails on the
+// invocations for which fail(n) is true, n counting from 1.
+func flakyGate(t *testing.T, failOn string) *GateConfig {
+	t.Helper()
+	counter := filepath.Join(t.TempDir(), "count")
+	cmd := fmt.Sprintf(`n=$(cat %[1]s 2>/dev/null || echo 0); n=$((n+1)); echo $n > %[1]s
+if %[2]s; then echo '{"Action":"fail","Package":"p","Test":"TestA"}'; exit 1; fi
+echo '{"Action":"pass","Package":"p","Test":"TestA"}'`, counter, failOn)
+	return &GateConfig{Cmd: cmd, TestReport: TestReportGoJSON}
+}
+
+// setupFlakyTest returns an engineer with flaky tracking enabled whose
+// quarantine beads are recorded instead of filed.
+func setupFlakyTest(t *testing.T) (*Engineer, *[]string) {
+	t.Helper()
+	workDir, g, _ := testGitRepo(t)
+	e := newTestEngineer(t, workDir, g)
+	e.config.GatesParallel = false
+	e.config.Flaky = &FlakyConfig{Threshold: 0.5, MinRuns: 2, Window: 10, Retries: 1}
+	var filed []string
+	e.fileQuarantineBead = func(id string, _ *FlakyTest) (string, error) {
+		filed = append(filed, id)
+		return fmt.Sprintf("gt-flaky-%d", len(filed)), nil
+	}
+	e.quarantineBeadClosed = func(string) bool { return false }
+	return e, &filed
+}
+
+func TestClassifyAttempts(t *testing.T) {
+	got := classifyAttempts([]testOutcomes{
+		{"a": true, "b": true, "c": false, "d": false},
+		{"a": false, "b": true, "c": false, "d": true},
+		{"a": true},
+	})
+	want := map[string]byte{"a": testFlaked, "b": testFailed, "c": testPassed, "d": testFlaked}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("classifyAttempts = %q, want %q", got, want)
+	}
+}
+
+func TestRunGates_QuarantinesFlakyTest(t *testing.T) {
+	e, filed := setupFlakyTest(t)
+	e.config.Gates = map[string]*GateConfig{"test": flakyGate(t, `[ $((n % 2)) -eq 1 ]`)}
+
+	// First run: fails, passes on the re-run. Below MinRuns, so not quarantined.
+	result := e.runGates(context.Background(), nil)
+	if !result.Success {
+		t.Fatalf("flaky gate should pass on re-run: %s", result.Error)
+	}
+	gr, err := LoadGateRun(e.rig.Path, result.GateRun)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := gr.Gate("test"); rec == nil || !reflect.DeepEqual(rec.FlakyTests, []string{"p.TestA"}) {
+		t.Errorf("gate record = %+v", rec)
+	}
+	if len(*filed) != 0 {
+		t.Fatalf("quarantined too early: %v", *filed)
+	}
+
+	// Second flake crosses the threshold.
+	e.runGates(context.Background(), nil)
+	history, err := LoadFlakyHistory(e.rig.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tt := history.Tests["p.TestA"]
+	if tt == nil || !tt.Quarantined || tt.Bead != "gt-flaky-1" || tt.History != "XX" {
+		t.Fatalf("history = %+v", tt)
+	}
+	if !reflect.DeepEqual(*filed, []string{"p.TestA"}) {
+		t.Errorf("filed beads for %v", *filed)
+	}
+
+	// Without re-runs the failure is final, but the test is quarantined.
+	e.config.Flaky.Retries = 0
+	result = e.runGates(context.Background(), nil)
+	if !result.Success || !reflect.DeepEqual(result.QuarantinedTests, []string{"p.TestA"}) {
+		t.Errorf("quarantined failure should pass: %+v", result)
+	}
+	if len(*filed) != 1 {
+		t.Errorf("bead filed again: %v", *filed)
+	}
+}
+
+func TestRunGates_DeterministicAndKnownFlakyFailures(t *testing.T) {
+	e, _ := setupFlakyTest(t)
+	e.config.Gates = map[string]*GateConfig{"test": flakyGate(t, "true")}
+
+	result := e.runGates(context.Background(), nil)
+	if result.Success || result.FlakyFailure {
+		t.Fatalf("deterministic failure = %+v", result)
+	}
+	history, _ := LoadFlakyHistory(e.rig.Path)
+	if got := history.Tests["p.TestA"].History; got != "F" {
+		t.Errorf("history = %q, want F", got)
+	}
+
+	// A test that has flaked before, but not enough to be quarantined.
+	history.Tests["p.TestA"].History = "PPPPXPPPP"
+	writeFlakyHistory(t, e.rig.Path, history)
+	e.config.Flaky.Retries = 0
+	result = e.runGates(context.Background(), nil)
+	if result.Success || !result.FlakyFailure {
+		t.Errorf("known-flaky failure = %+v", result)
+	}
+}
+
+func TestRunGates_ClosedBeadReleasesQuarantine(t *testing.T) {
+	e, _ := setupFlakyTest(t)
+	e.config.Gates = map[string]*GateConfig{"test": flakyGate(t, "true")}
+	writeFlakyHistory(t, e.rig.Path, &FlakyHistory{Tests: map[string]*FlakyTest{
+		"p.TestA": {Gate: "test", History: "XXP", Quarantined: true, Bead: "gt-flaky-1"},
+	}})
+	e.quarantineBeadClosed = func(id string) bool { return id == "gt-flaky-1" }
+
+	result := e.runGates(context.Background(), nil)
+	if result.Success {
+		t.Fatal("released test should fail the gate again")
+	}
+	history, _ := LoadFlakyHistory(e.rig.Path)
+	if tt := history.Tests["p.TestA"]; tt.Quarantined || tt.Bead != "" || tt.History != "F" {
+		t.Errorf("history after release = %+v", tt)
+	}
+}
+
+func TestProcessBatch_KnownFlakyFailureRetriedBeforeBisect(t *testing.T) {
+	e, _ := setupFlakyTest(t)
+	workDir := e.workDir
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "a\n")
+	createFeatureBranch(t, workDir, "feature-b", "b.txt", "b\n")
+	e.config.Flaky.Retries = 0
+	e.config.Gates = map[string]*GateConfig{"test": flakyGate(t, `[ $n -eq 1 ]`)}
+	writeFlakyHistory(t, e.rig.Path, &FlakyHistory{Tests: map[string]*FlakyTest{
+		"p.TestA": {Gate: "test", History: "PPPPXPPPP"},
+	}})
+
+	batch := []*MRInfo{makeMR("mr-a", "feature-a", "main"), makeMR("mr-b", "feature-b", "main")}
+	cfg := DefaultBatchConfig()
+	cfg.RetryBatchOnFlaky = false
+	result := e.ProcessBatch(context.Background(), batch, "main", cfg)
+	if result.Error != nil || len(result.Merged) != 2 || len(result.Culprits) != 0 {
+		t.Errorf("merged %d, culprits %d, err %v", len(result.Merged), len(result.Culprits), result.Error)
+	}
+}
+
+func TestEngineer_LoadConfig_Flaky(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(mq map[string]interface{}) {
+		data, _ := json.Marshal(map[string]interface{}{"merge_queue": mq})
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(map[string]interface{}{
+		"flaky": map[string]interface{}{"threshold": 0.25, "retries": 2},
+		"gates": map[string]interface{}{
+			"test": map[string]interface{}{"cmd": "go test -json ./...", "test_report": "go-json"},
+		},
+	})
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	want := &FlakyConfig{Threshold: 0.25, MinRuns: 10, Window: 100, Retries: 2}
+	if !reflect.DeepEqual(e.config.Flaky, want) {
+		t.Errorf("Flaky = %+v, want %+v", e.config.Flaky, want)
+	}
+	if e.config.Gates["test"].TestReport != TestReportGoJSON {
+		t.Errorf("test_report = %q", e.config.Gates["test"].TestReport)
+	}
+
+	for _, bad := range []map[string]interface{}{
+		{"flaky": map[string]interface{}{"threshold": 0}},
+		{"flaky": map[string]interface{}{"min_runs": 20, "window": 10}},
+		{"flaky": map[string]interface{}{"retries": -1}},
+		{"gates": map[string]interface{}{"test": map[string]interface{}{"cmd": "make test", "test_report": "tap"}}},
+		{"gates": map[string]interface{}{"test": map[string]interface{}{"cmd": "make test", "test_report": "junit"}}},
+	} {
+		write(bad)
+		if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+			t.Errorf("expected error for %v", bad)
+		}
+	}
+}
+
+func writeFlakyHistory(t *testing.T, rigPath string, h *FlakyHistory) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(FlakyHistoryPath(rigPath)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(h)
+	if err := os.WriteFile(FlakyHistoryPath(rigPath), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
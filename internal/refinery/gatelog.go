@@ -0,0 +1,351 @@
+package refinery
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// maxGateRuns is how many gate runs are kept per rig before the oldest are pruned.
+const maxGateRuns = 200
+
+// gateRunSeq disambiguates gate run IDs created in the same instant.
+var gateRunSeq uint64
+
+// GateRun is the persisted record of one quality gate run on an MR or a
+// batch stack. It lives in GateLogDir(rigPath)/<ID>/run.json next to each
+// gate's full output and collected artifacts.
+type GateRun struct {
+	ID        string       `json:"id"`
+	MRs       []string     `json:"mrs"`
+	Target    string       `json:"target,omitempty"`
+	HeadSHA   string       `json:"head_sha,omitempty"`
+	StartedAt time.Time    `json:"started_at"`
+	Success   bool         `json:"success"`
+	Gates     []GateRecord `json:"gates"`
+}
+
+// GateRecord is one gate's outcome within a GateRun. Stdout, Stderr and
+// Artifacts are paths relative to the run directory.
+type GateRecord struct {
+	Name       string   `json:"name"`
+	Cmd        string   `json:"cmd,omitempty"`
+	Success    bool     `json:"success"`
+	Skipped    bool     `json:"skipped,omitempty"`
+	ExitCode   int      `json:"exit_code"`
+	DurationMs int64    `json:"duration_ms"`
+	Error      string   `json:"error,omitempty"`
+	Stdout     string   `json:"stdout,omitempty"`
+	Stderr     string   `json:"stderr,omitempty"`
+	Artifacts  []string `json:"artifacts,omitempty"`
+
+	FailedTests      []string `json:"failed_tests,omitempty"`
+	FlakyTests       []string `json:"flaky_tests,omitempty"`
+	QuarantinedTests []string `json:"quarantined_tests,omitempty"`
+}
+
+// Gate returns the record for the named gate, or nil.
+func (r *GateRun) Gate(name string) *GateRecord {
+	for i := range r.Gates {
+		if r.Gates[i].Name == name {
+			return &r.Gates[i]
+		}
+	}
+	return nil
+}
+
+// HasMR reports whether the run covered the given MR.
+func (r *GateRun) HasMR(mrID string) bool {
+	for _, id := range r.MRs {
+		if id == mrID {
+			return true
+		}
+	}
+	return false
+}
+
+// GateLogDir returns the directory holding persisted gate runs for a rig.
+func GateLogDir(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "gate-logs")
+}
+
+// GateRunDir returns the directory of one gate run.
+func GateRunDir(rigPath, runID string) string {
+	return filepath.Join(GateLogDir(rigPath), runID)
+}
+
+// validRunID matches IDs produced by newGateRunID, so lookups can't escape
+// the gate log directory.
+var validRunID = regexp.MustCompile(`^[0-9]{8}T[0-9.]+-[0-9]+$`)
+
+// LoadGateRun reads a persisted gate run.
+func LoadGateRun(rigPath, runID string) (*GateRun, error) {
+	if !validRunID.MatchString(runID) {
+		return nil, fmt.Errorf("invalid gate run ID %q", runID)
+	}
+	data, err := os.ReadFile(filepath.Join(GateRunDir(rigPath, runID), "run.json"))
+	if err != nil {
+		return nil, err
+	}
+	var run GateRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("parsing gate run %s: %w", runID, err)
+	}
+	return &run, nil
+}
+
+// ListGateRuns returns the persisted gate runs that covered mrID, newest
+// first. An empty mrID returns every run.
+func ListGateRuns(rigPath, mrID string) ([]*GateRun, error) {
+	entries, err := os.ReadDir(GateLogDir(rigPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var runs []*GateRun
+	for i := len(entries) - 1; i >= 0; i-- { // IDs sort chronologically
+		if !entries[i].IsDir() {
+			continue
+		}
+		run, err := LoadGateRun(rigPath, entries[i].Name())
+		if err != nil {
+			continue // Partially written or foreign directory
+		}
+		if mrID == "" || run.HasMR(mrID) {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// ReadGateLog returns a file from a gate run directory, such as a record's
+// Stdout or Stderr path.
+func ReadGateLog(rigPath, runID, relPath string) ([]byte, error) {
+	if !validRunID.MatchString(runID) {
+		return nil, fmt.Errorf("invalid gate run ID %q", runID)
+	}
+	clean := filepath.Clean(filepath.FromSlash(relPath))
+	if clean == "." || filepath.IsAbs(clean) || strings.HasPrefix(clean, "..") {
+		return nil, fmt.Errorf("invalid gate log path %q", relPath)
+	}
+	return os.ReadFile(filepath.Join(GateRunDir(rigPath, runID), clean))
+}
+
+// lastGateRun returns the gate run behind a failed result, falling back to
+// the most recent persisted run that covered mr (e.g. a bisection step).
+func (e *Engineer) lastGateRun(mr *MRInfo, result ProcessResult) string {
+	if result.GateRun != "" {
+		return result.GateRun
+	}
+	if mr.ID == "" {
+		return ""
+	}
+	runs, err := ListGateRuns(e.rig.Path, mr.ID)
+	if err != nil || len(runs) == 0 {
+		return ""
+	}
+	return runs[0].ID
+}
+
+// linkGateRun records gateRun on the MR bead so `gt mq show` can find it.
+func (e *Engineer) linkGateRun(mr *MRInfo, gateRun string) {
+	if mr.ID == "" {
+		return
+	}
+	mrBead, err := e.beads.Show(mr.ID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch MR bead %s: %v\n", mr.ID, err)
+		return
+	}
+	mrFields := beads.ParseMRFields(mrBead)
+	if mrFields == nil {
+		mrFields = &beads.MRFields{}
+	}
+	if mrFields.GateRun == gateRun {
+		return
+	}
+	mrFields.GateRun = gateRun
+	newDesc := beads.SetMRFields(mrBead, mrFields)
+	if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to link gate run to MR %s: %v\n", mr.ID, err)
+	}
+}
+
+// gateScope says what a gate run covers: the target, the MRs on the working
+// tree and the files they change (nil when unknown, so every gate runs).
+type gateScope struct {
+	Target  string
+	MRs     []string
+	Changed []string
+}
+
+// gateRunMRs identifies mrs for a GateRun, by branch when an MR has no ID.
+func gateRunMRs(mrs ...*MRInfo) []string {
+	ids := make([]string, len(mrs))
+	for i, mr := range mrs {
+		ids[i] = mr.ID
+		if ids[i] == "" {
+			ids[i] = mr.Branch
+		}
+	}
+	return ids
+}
+
+func newGateRunID(now time.Time) string {
+	return fmt.Sprintf("%s-%d", now.UTC().Format("20060102T150405.000000000"), atomic.AddUint64(&gateRunSeq, 1))
+}
+
+// gateFileName makes a gate name safe to use as a file name.
+func gateFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == '.' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// saveGateRun persists the results of a gate run under the rig and returns
+// its ID. Persistence failures are reported as warnings and yield "".
+func (e *Engineer) saveGateRun(scope *gateScope, started time.Time, results []GateResult, skipped []string) string {
+	run := &GateRun{
+		ID:        newGateRunID(started),
+		StartedAt: started,
+		Success:   true,
+	}
+	if scope != nil {
+		run.Target = scope.Target
+		run.MRs = scope.MRs
+	}
+	if sha, err := e.git.Rev("HEAD"); err == nil {
+		run.HeadSHA = sha
+	}
+
+	dir := GateRunDir(e.rig.Path, run.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to create gate log dir: %v\n", err)
+		return ""
+	}
+
+	for _, res := range results {
+		rec := GateRecord{
+			Name:       res.Name,
+			Success:    res.Success,
+			ExitCode:   res.ExitCode,
+			DurationMs: res.Elapsed.Milliseconds(),
+			Error:      res.Error,
+
+			FailedTests:      res.FailedTests,
+			FlakyTests:       res.FlakyTests,
+			QuarantinedTests: res.QuarantinedTests,
+		}
+		if gate := e.config.Gates[res.Name]; gate != nil {
+			rec.Cmd = gate.Cmd
+			rec.Artifacts = e.collectArtifacts(dir, res.Name, gate.Artifacts)
+		}
+		base := gateFileName(res.Name)
+		if err := os.WriteFile(filepath.Join(dir, base+".stdout"), res.Stdout, 0644); err == nil {
+			rec.Stdout = base + ".stdout"
+		}
+		if err := os.WriteFile(filepath.Join(dir, base+".stderr"), res.Stderr, 0644); err == nil {
+			rec.Stderr = base + ".stderr"
+		}
+		if !res.Success {
+			run.Success = false
+		}
+		run.Gates = append(run.Gates, rec)
+	}
+	for _, name := range skipped {
+		run.Gates = append(run.Gates, GateRecord{Name: name, Success: true, Skipped: true})
+	}
+
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to encode gate run: %v\n", err)
+		return ""
+	}
+	if err := os.WriteFile(filepath.Join(dir, "run.json"), data, 0644); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to write gate run: %v\n", err)
+		return ""
+	}
+	pruneGateRuns(e.rig.Path, maxGateRuns)
+	return run.ID
+}
+
+// collectArtifacts copies files matching the gate's artifact globs (relative
+// to the worktree) into the run directory and returns their relative paths.
+func (e *Engineer) collectArtifacts(runDir, gateName string, patterns []string) []string {
+	var copied []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(e.workDir, pattern))
+		if err != nil {
+			continue
+		}
+		for _, src := range matches {
+			rel, err := filepath.Rel(e.workDir, src)
+			if err != nil || strings.HasPrefix(rel, "..") {
+				continue
+			}
+			dst := filepath.Join("artifacts", gateFileName(gateName), rel)
+			if err := copyRegularFile(src, filepath.Join(runDir, dst)); err != nil {
+				continue
+			}
+			copied = append(copied, filepath.ToSlash(dst))
+		}
+	}
+	return copied
+}
+
+func copyRegularFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil || !info.Mode().IsRegular() {
+		return fmt.Errorf("not a regular file: %s", src)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// pruneGateRuns removes the oldest gate runs beyond keep.
+func pruneGateRuns(rigPath string, keep int) {
+	entries, err := os.ReadDir(GateLogDir(rigPath))
+	if err != nil {
+		return
+	}
+	var ids []string
+	for _, entry := range entries {
+		if entry.IsDir() && validRunID.MatchString(entry.Name()) {
+			ids = append(ids, entry.Name())
+		}
+	}
+	sort.Strings(ids)
+	for len(ids) > keep {
+		_ = os.RemoveAll(GateRunDir(rigPath, ids[0]))
+		ids = ids[1:]
+	}
+}
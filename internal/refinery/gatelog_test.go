@@ -0,0 +1,148 @@
+package refinery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDoMerge_PersistsGateLogs(t *testing.T) {
+	workDir, g, _ := testGitRepo(t)
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "a\n")
+
+	e := newTestEngineer(t, workDir, g)
+	e.config.GatesParallel = false
+	e.config.Gates = map[string]*GateConfig{
+		"lint": {Cmd: "echo lint ok"},
+		"test": {
+			Cmd:       "echo running; echo FAIL: TestA >&2; mkdir -p reports && echo '<testsuite/>' > reports/junit.xml; exit 3",
+			Artifacts: []string{"reports/*.xml", "missing/*.out"},
+		},
+	}
+
+	result := e.ProcessMRInfo(context.Background(), makeMR("mr-a", "feature-a", "main"))
+	if result.Success || !result.TestsFailed {
+		t.Fatalf("expected gate failure, got %+v", result)
+	}
+	if result.GateRun == "" {
+		t.Fatal("GateRun not set on failed result")
+	}
+
+	gr, err := LoadGateRun(workDir, result.GateRun)
+	if err != nil {
+		t.Fatalf("LoadGateRun: %v", err)
+	}
+	if gr.Success || gr.Target != "main" || !reflect.DeepEqual(gr.MRs, []string{"mr-a"}) || gr.HeadSHA == "" {
+		t.Errorf("run = %+v", gr)
+	}
+
+	rec := gr.Gate("test")
+	if rec == nil {
+		t.Fatal("test gate not recorded")
+	}
+	if rec.Success || rec.ExitCode != 3 || rec.Cmd == "" {
+		t.Errorf("test gate = %+v", rec)
+	}
+	stdout, err := ReadGateLog(workDir, gr.ID, rec.Stdout)
+	if err != nil || string(stdout) != "running\n" {
+		t.Errorf("stdout = %q, %v", stdout, err)
+	}
+	stderr, err := ReadGateLog(workDir, gr.ID, rec.Stderr)
+	if err != nil || string(stderr) != "FAIL: TestA\n" {
+		t.Errorf("stderr = %q, %v", stderr, err)
+	}
+	if !reflect.DeepEqual(rec.Artifacts, []string{"artifacts/test/reports/junit.xml"}) {
+		t.Fatalf("artifacts = %v", rec.Artifacts)
+	}
+	if data, err := ReadGateLog(workDir, gr.ID, rec.Artifacts[0]); err != nil || !strings.Contains(string(data), "testsuite") {
+		t.Errorf("artifact = %q, %v", data, err)
+	}
+
+	if lint := gr.Gate("lint"); lint == nil || !lint.Success || lint.ExitCode != 0 {
+		t.Errorf("lint gate = %+v", lint)
+	}
+
+	runs, err := ListGateRuns(workDir, "mr-a")
+	if err != nil || len(runs) != 1 || runs[0].ID != gr.ID {
+		t.Errorf("ListGateRuns = %v, %v", runs, err)
+	}
+	if runs, _ := ListGateRuns(workDir, "mr-other"); len(runs) != 0 {
+		t.Errorf("ListGateRuns(mr-other) = %v, want none", runs)
+	}
+}
+
+func TestProcessBatch_GateRunsCoverStack(t *testing.T) {
+	workDir, g, _ := testGitRepo(t)
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "a\n")
+	createFeatureBranch(t, workDir, "feature-b", "b.txt", "b\n")
+
+	e := newTestEngineer(t, workDir, g)
+	e.config.Gates = map[string]*GateConfig{"test": {Cmd: "true"}}
+
+	batch := []*MRInfo{makeMR("mr-a", "feature-a", "main"), makeMR("mr-b", "feature-b", "main")}
+	result := e.ProcessBatch(context.Background(), batch, "main", DefaultBatchConfig())
+	if result.Error != nil || len(result.Merged) != 2 {
+		t.Fatalf("merged %d, err %v", len(result.Merged), result.Error)
+	}
+
+	for _, id := range []string{"mr-a", "mr-b"} {
+		runs, err := ListGateRuns(workDir, id)
+		if err != nil || len(runs) != 1 {
+			t.Fatalf("ListGateRuns(%s) = %v, %v", id, runs, err)
+		}
+		if !reflect.DeepEqual(runs[0].MRs, []string{"mr-a", "mr-b"}) || !runs[0].Success {
+			t.Errorf("run = %+v", runs[0])
+		}
+	}
+}
+
+func TestGateLogs_RejectUnsafePaths(t *testing.T) {
+	rigPath := t.TempDir()
+	if _, err := LoadGateRun(rigPath, "../../etc"); err == nil {
+		t.Error("LoadGateRun accepted a path as run ID")
+	}
+	id := newGateRunID(time.Now())
+	if !validRunID.MatchString(id) {
+		t.Fatalf("generated run ID %q is not valid", id)
+	}
+	for _, rel := range []string{"../run.json", "/etc/passwd", "."} {
+		if _, err := ReadGateLog(rigPath, id, rel); err == nil {
+			t.Errorf("ReadGateLog accepted %q", rel)
+		}
+	}
+}
+
+func TestPruneGateRuns(t *testing.T) {
+	rigPath := t.TempDir()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var ids []string
+	for i := 0; i < 5; i++ {
+		id := newGateRunID(start.Add(time.Duration(i) * time.Second))
+		if err := os.MkdirAll(GateRunDir(rigPath, id), 0755); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if err := os.MkdirAll(filepath.Join(GateLogDir(rigPath), "keep-me"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	pruneGateRuns(rigPath, 2)
+
+	entries, err := os.ReadDir(GateLogDir(rigPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var left []string
+	for _, entry := range entries {
+		left = append(left, entry.Name())
+	}
+	want := []string{ids[3], ids[4], "keep-me"}
+	if !reflect.DeepEqual(left, want) {
+		t.Errorf("after prune = %v, want %v", left, want)
+	}
+}
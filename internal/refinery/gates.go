@@ -0,0 +1,128 @@
+package refinery
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// matchPathGlob reports whether a slash-separated file path matches pattern.
+// Segments use path.Match syntax; a "**" segment matches zero or more whole
+// segments, so "docs/**" matches everything under docs/ and "**/*.go"
+// matches Go files at any depth.
+func matchPathGlob(pattern, file string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(file, "/"))
+}
+
+func matchSegments(pattern, file []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(file); i++ {
+				if matchSegments(pattern[1:], file[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(file) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], file[0]); err != nil || !ok {
+			return false
+		}
+		pattern, file = pattern[1:], file[1:]
+	}
+	return len(file) == 0
+}
+
+// validateGateSelectors checks gate path globs and depends_on references.
+func validateGateSelectors(gates map[string]*GateConfig) error {
+	for name, gate := range gates {
+		for _, pattern := range gate.Paths {
+			for _, seg := range strings.Split(pattern, "/") {
+				if _, err := path.Match(seg, ""); err != nil {
+					return fmt.Errorf("gate %q: invalid path pattern %q: %w", name, pattern, err)
+				}
+			}
+		}
+		for _, dep := range gate.DependsOn {
+			if _, ok := gates[dep]; !ok {
+				return fmt.Errorf("gate %q depends on unknown gate %q", name, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// selectGates splits gates into those that apply to the changed files and
+// those that can be skipped, both sorted by name. A gate with neither Paths
+// nor DependsOn always runs; otherwise it runs when a changed file matches
+// one of its Paths or when any gate it depends on runs. A nil changed list
+// means the diff is unknown and every gate runs.
+func selectGates(gates map[string]*GateConfig, changed []string) (run, skipped []string) {
+	names := make([]string, 0, len(gates))
+	for name := range gates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if changed == nil {
+		return names, nil
+	}
+
+	memo := make(map[string]bool, len(gates))
+	visiting := make(map[string]bool)
+	var applies func(name string) bool
+	applies = func(name string) bool {
+		if v, ok := memo[name]; ok {
+			return v
+		}
+		gate := gates[name]
+		if gate == nil || visiting[name] {
+			return false // Unknown gate or dependency cycle
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+
+		result := len(gate.Paths) == 0 && len(gate.DependsOn) == 0
+		for _, file := range changed {
+			if result {
+				break
+			}
+			for _, pattern := range gate.Paths {
+				if matchPathGlob(pattern, file) {
+					result = true
+					break
+				}
+			}
+		}
+		for _, dep := range gate.DependsOn {
+			if result {
+				break
+			}
+			result = applies(dep)
+		}
+		memo[name] = result
+		return result
+	}
+
+	for _, name := range names {
+		if applies(name) {
+			run = append(run, name)
+		} else {
+			skipped = append(skipped, name)
+		}
+	}
+	return run, skipped
+}
+
+// changedFiles returns the files head changes relative to base, or nil when
+// the diff can't be computed so callers fall back to running every gate.
+func (e *Engineer) changedFiles(base, head string) []string {
+	files, err := e.git.ChangedFiles(base, head)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not diff %s...%s, running all gates: %v\n", base, head, err)
+		return nil
+	}
+	return files
+}
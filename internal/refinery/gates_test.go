@@ -0,0 +1,175 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestMatchPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"docs/**", "docs/guide.md", true},
+		{"docs/**", "docs/a/b/c.md", true},
+		{"docs/**", "src/docs/x.md", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "internal/refinery/gates.go", true},
+		{"**/*.go", "internal/refinery/gates.md", false},
+		{"services/*/Dockerfile", "services/api/Dockerfile", true},
+		{"services/*/Dockerfile", "services/api/v2/Dockerfile", false},
+		{"services/**/*_test.go", "services/api/v2/x_test.go", true},
+		{"README.md", "README.md", true},
+		{"README.md", "docs/README.md", false},
+		{"*.md", "docs/README.md", false},
+	}
+	for _, tt := range tests {
+		if got := matchPathGlob(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("matchPathGlob(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestSelectGates(t *testing.T) {
+	gates := map[string]*GateConfig{
+		"lint":        {Cmd: "true"},
+		"api":         {Cmd: "true", Paths: []string{"services/api/**"}},
+		"web":         {Cmd: "true", Paths: []string{"web/**"}},
+		"docs":        {Cmd: "true", Paths: []string{"docs/**", "**/*.md"}},
+		"integration": {Cmd: "true", Paths: []string{"e2e/**"}, DependsOn: []string{"api", "web"}},
+	}
+	tests := []struct {
+		name        string
+		changed     []string
+		wantRun     []string
+		wantSkipped []string
+	}{
+		{"unknown diff runs all", nil, []string{"api", "docs", "integration", "lint", "web"}, nil},
+		{"docs only", []string{"docs/guide.md"}, []string{"docs", "lint"}, []string{"api", "integration", "web"}},
+		{"api pulls in integration", []string{"services/api/main.go"}, []string{"api", "integration", "lint"}, []string{"docs", "web"}},
+		{"own paths", []string{"e2e/smoke_test.go"}, []string{"integration", "lint"}, []string{"api", "docs", "web"}},
+		{"no changes", []string{}, []string{"lint"}, []string{"api", "docs", "integration", "web"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run, skipped := selectGates(gates, tt.changed)
+			if !reflect.DeepEqual(run, tt.wantRun) || !reflect.DeepEqual(skipped, tt.wantSkipped) {
+				t.Errorf("selectGates = run %v, skipped %v; want run %v, skipped %v", run, skipped, tt.wantRun, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestSelectGates_DependencyCycle(t *testing.T) {
+	gates := map[string]*GateConfig{
+		"a": {Cmd: "true", Paths: []string{"a/**"}, DependsOn: []string{"b"}},
+		"b": {Cmd: "true", Paths: []string{"b/**"}, DependsOn: []string{"a"}},
+	}
+	run, _ := selectGates(gates, []string{"b/x"})
+	if !reflect.DeepEqual(run, []string{"a", "b"}) {
+		t.Errorf("run = %v, want [a b]", run)
+	}
+	run, _ = selectGates(gates, []string{"c/x"})
+	if len(run) != 0 {
+		t.Errorf("run = %v, want none", run)
+	}
+}
+
+func TestDoMerge_SkipsUnaffectedGates(t *testing.T) {
+	workDir, g, _ := testGitRepo(t)
+	createFeatureBranch(t, workDir, "docs-change", "guide.md", "# Guide\n")
+
+	e := newTestEngineer(t, workDir, g)
+	e.config.GatesParallel = false
+	e.config.Gates = map[string]*GateConfig{
+		"docs":        {Cmd: "true", Paths: []string{"**/*.md"}},
+		"integration": {Cmd: "exit 1", Paths: []string{"src/**"}},
+	}
+
+	result := e.ProcessMRInfo(context.Background(), makeMR("mr-docs", "docs-change", "main"))
+	if !result.Success {
+		t.Fatalf("docs-only MR should skip the failing integration gate: %s", result.Error)
+	}
+	if !reflect.DeepEqual(result.SkippedGates, []string{"integration"}) {
+		t.Errorf("SkippedGates = %v, want [integration]", result.SkippedGates)
+	}
+
+	// A change under src/ runs the gate and fails.
+	run(t, workDir, "git", "checkout", "-b", "src-change", "main")
+	if err := os.MkdirAll(filepath.Join(workDir, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, workDir, filepath.Join("src", "main.go"), "package main\n")
+	run(t, workDir, "git", "add", ".")
+	run(t, workDir, "git", "commit", "-m", "feat: src")
+	run(t, workDir, "git", "checkout", "main")
+
+	result = e.ProcessMRInfo(context.Background(), makeMR("mr-src", "src-change", "main"))
+	if result.Success || !result.TestsFailed {
+		t.Fatalf("expected integration gate failure, got %+v", result)
+	}
+}
+
+func TestProcessBatch_SkipsUnaffectedGates(t *testing.T) {
+	workDir, g, _ := testGitRepo(t)
+	createFeatureBranch(t, workDir, "docs-a", "a.md", "a\n")
+	createFeatureBranch(t, workDir, "docs-b", "b.md", "b\n")
+
+	e := newTestEngineer(t, workDir, g)
+	e.config.Gates = map[string]*GateConfig{
+		"docs":        {Cmd: "true", Paths: []string{"*.md"}},
+		"integration": {Cmd: "exit 1", Paths: []string{"src/**"}},
+	}
+
+	batch := []*MRInfo{makeMR("mr-a", "docs-a", "main"), makeMR("mr-b", "docs-b", "main")}
+	result := e.ProcessBatch(context.Background(), batch, "main", DefaultBatchConfig())
+	if result.Error != nil || len(result.Merged) != 2 {
+		t.Fatalf("merged %d, culprits %d, err %v", len(result.Merged), len(result.Culprits), result.Error)
+	}
+	if !reflect.DeepEqual(result.SkippedGates, []string{"integration"}) {
+		t.Errorf("SkippedGates = %v, want [integration]", result.SkippedGates)
+	}
+}
+
+func TestEngineer_LoadConfig_GateSelectors(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(gates map[string]interface{}) {
+		data, _ := json.Marshal(map[string]interface{}{
+			"merge_queue": map[string]interface{}{"gates": gates},
+		})
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(map[string]interface{}{
+		"api":         map[string]interface{}{"cmd": "make api", "paths": []string{"services/api/**"}},
+		"integration": map[string]interface{}{"cmd": "make e2e", "depends_on": []string{"api"}},
+	})
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if got := e.config.Gates["api"].Paths; !reflect.DeepEqual(got, []string{"services/api/**"}) {
+		t.Errorf("api paths = %v", got)
+	}
+	if got := e.config.Gates["integration"].DependsOn; !reflect.DeepEqual(got, []string{"api"}) {
+		t.Errorf("integration depends_on = %v", got)
+	}
+
+	for _, bad := range []map[string]interface{}{
+		{"e2e": map[string]interface{}{"cmd": "make e2e", "depends_on": []string{"missing"}}},
+		{"api": map[string]interface{}{"cmd": "make api", "paths": []string{"services/[api/**"}}},
+	} {
+		write(bad)
+		if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+			t.Errorf("expected error for %v", bad)
+		}
+	}
+}
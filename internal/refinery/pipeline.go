@@ -0,0 +1,443 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// speculativeBranchPrefix names the branches speculative stacks are built
+// on. Each stack has its own, since a branch can only be checked out in one
+// worktree at a time.
+const speculativeBranchPrefix = "gt-refinery-spec-"
+
+// PipelineResult holds the outcome of processing a queue with ProcessPipeline.
+type PipelineResult struct {
+	BatchResult
+
+	// Phases is the final phase of every MR the pipeline was given. MRs
+	// it never got to are still ready.
+	Phases map[string]MRPhase
+
+	// Stacks is the number of speculative stacks built and tested.
+	Stacks int
+
+	// Dropped is the number of stacks abandoned because a stack below them
+	// failed; their MRs were re-queued and tested again.
+	Dropped int
+}
+
+// add merges the outcome of one batch into the pipeline result.
+func (r *PipelineResult) add(b *BatchResult) {
+	r.Merged = append(r.Merged, b.Merged...)
+	r.Culprits = append(r.Culprits, b.Culprits...)
+	r.Conflicts = append(r.Conflicts, b.Conflicts...)
+	if b.MergeCommit != "" {
+		r.MergeCommit = b.MergeCommit
+	}
+	if b.PullRequest != "" {
+		r.PullRequest = b.PullRequest
+	}
+	r.SkippedGates = b.SkippedGates
+	if b.Error != nil {
+		r.Error = b.Error
+	}
+}
+
+// specStack is one speculative stack: the stack below it (or origin/target
+// for the first) plus one batch, built on its own branch and worktree.
+type specStack struct {
+	index     int       // 1-based position in the pipeline
+	all       []*MRInfo // The batch the stack was built from
+	batch     []*MRInfo // MRs this stack adds to the one below it
+	conflicts []*MRInfo // MRs of the batch that couldn't be applied
+	stacked   []*MRInfo // MRs of this stack and every stack below it
+	path      string
+	branch    string
+	eng       *Engineer // Engineer bound to the stack's worktree
+	cancel    context.CancelFunc
+	done      chan struct{}
+	gates     ProcessResult
+}
+
+// pipelineDepth returns how many stacks are tested at once. Targets landed
+// through pull requests are processed one stack at a time: the forge
+// rewrites commits when it merges, so later stacks would no longer descend
+// from what landed.
+func (e *Engineer) pipelineDepth(target string) int {
+	if e.usePullRequest(target) || e.config.MaxConcurrent < 1 {
+		return 1
+	}
+	return e.config.MaxConcurrent
+}
+
+// ProcessPipeline processes a queue of MRs through a speculative pipeline
+// (Zuul/bors style). The queue is cut into batches of MaxBatchSize; up to
+// MaxConcurrent stacks are tested at once, each in its own worktree:
+//
+//	stack 1: target ← B1
+//	stack 2: target ← B1 ← B2
+//	stack 3: target ← B1 ← B2 ← B3
+//
+// Passing stacks land in order by fast-forwarding the target to their tip.
+// When a stack fails, only the stacks built on it are dropped and re-queued;
+// the failed batch is retried and bisected by ProcessBatch, and stacks below
+// it have already landed. With MaxConcurrent 1 this is ProcessBatch applied
+// to each batch in turn.
+//
+// Each MR is tracked through the phase machine as it moves: claimed and
+// preparing while its stack is built and tested, back to ready when its
+// stack is dropped, and merged, rejected (a bisection culprit) or failed
+// (conflict or infrastructure error) once it leaves the pipeline.
+func (e *Engineer) ProcessPipeline(ctx context.Context, queue []*MRInfo, target string, batchCfg *BatchConfig) *PipelineResult {
+	if batchCfg == nil {
+		batchCfg = DefaultBatchConfig()
+	}
+	size := batchCfg.MaxBatchSize
+	if size < 1 {
+		size = 1
+	}
+	depth := e.pipelineDepth(target)
+
+	// Stacks are tested concurrently; keep their output lines whole
+	prevOutput := e.output
+	e.output = &syncWriter{w: prevOutput}
+	defer func() { e.output = prevOutput }()
+
+	phases := newPhaseTracker(queue, e.output)
+	result := &PipelineResult{}
+	defer func() { result.Phases = phases.snapshot() }()
+
+	for len(queue) > 0 && result.Error == nil {
+		if err := ctx.Err(); err != nil {
+			result.Error = err
+			break
+		}
+
+		var batches [][]*MRInfo
+		consumed := 0
+		for consumed < len(queue) && len(batches) < depth {
+			n := min(size, len(queue)-consumed)
+			batches = append(batches, queue[consumed:consumed+n])
+			consumed += n
+		}
+		rest := queue[consumed:]
+
+		if len(batches) == 1 {
+			e.processPipelineBatch(ctx, batches[0], target, batchCfg, false, phases, result)
+			queue = rest
+			continue
+		}
+
+		_, _ = fmt.Fprintf(e.output, "[Pipeline] Testing %d speculative stacks (%d MRs) targeting %s\n", len(batches), consumed, target)
+		requeue, failed := e.runSpeculativeRound(ctx, batches, target, phases, result)
+		if failed != nil && result.Error == nil {
+			e.processPipelineBatch(ctx, failed, target, batchCfg, true, phases, result)
+		}
+		queue = append(requeue, rest...)
+	}
+	return result
+}
+
+// processPipelineBatch runs one batch through ProcessBatch on the refinery
+// worktree and records where its MRs ended up. A batch whose speculative
+// stack failed is always tested stacked on the target, even when it holds a
+// single MR, so the failure is confirmed on the tree that failed.
+func (e *Engineer) processPipelineBatch(ctx context.Context, batch []*MRInfo, target string, batchCfg *BatchConfig, failed bool, phases *phaseTracker, result *PipelineResult) {
+	phases.advance(batch, MRPhaseClaimed, MRPhasePreparing)
+	var br *BatchResult
+	if failed && len(batch) == 1 {
+		br = e.retestStack(ctx, batch, target)
+	} else {
+		br = e.ProcessBatch(ctx, batch, target, batchCfg)
+	}
+	phases.advance(br.Merged, MRPhasePrepared, MRPhaseMerging, MRPhaseMerged)
+	phases.advance(br.Culprits, MRPhasePrepared, MRPhaseRejected)
+	phases.advance(br.Conflicts, MRPhaseFailed)
+	// Whatever is left was held back by an infrastructure error
+	phases.advance(phases.in(batch, MRPhasePreparing), MRPhaseFailed)
+	result.add(br)
+}
+
+// retestStack stacks batch on the target and lands it if the gates pass,
+// otherwise it resets the target to origin.
+func (e *Engineer) retestStack(ctx context.Context, batch []*MRInfo, target string) *BatchResult {
+	stacked, conflicts, err := e.BuildRebaseStack(ctx, batch, target)
+	if err != nil {
+		return &BatchResult{Error: fmt.Errorf("build rebase stack: %w", err)}
+	}
+	if len(stacked) == 0 {
+		return &BatchResult{Conflicts: conflicts}
+	}
+	br := e.verifyAndPush(ctx, stacked, target)
+	br.Conflicts = conflicts
+	if len(br.Merged) == 0 {
+		if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+			_, _ = fmt.Fprintf(e.output, "[Pipeline] Warning: failed to reset %s: %v\n", target, resetErr)
+		}
+	}
+	return br
+}
+
+// runSpeculativeRound builds one stack per batch, each on top of the one
+// before, tests them concurrently and lands the passing ones in order. It
+// stops at the first failing stack and returns its batch for bisection,
+// along with the MRs of the stacks built on it, which are re-queued.
+func (e *Engineer) runSpeculativeRound(ctx context.Context, batches [][]*MRInfo, target string, phases *phaseTracker, result *PipelineResult) (requeue, failed []*MRInfo) {
+	if err := e.git.FetchBranch("origin", target); err != nil {
+		result.Error = fmt.Errorf("fetch origin/%s: %w", target, err)
+		return batchMRs(batches), nil
+	}
+
+	var stacks []*specStack
+	defer func() {
+		for _, st := range stacks {
+			st.cancel()
+			<-st.done
+			e.removeSpeculativeStack(st)
+		}
+	}()
+
+	// Build the stacks in order, starting each one's gates as soon as it
+	// exists. Building is cheap next to testing, so the stacks are tested
+	// almost entirely in parallel.
+	base := "origin/" + target
+	var below []*MRInfo
+	for i, batch := range batches {
+		phases.advance(batch, MRPhaseClaimed, MRPhasePreparing)
+		st, err := e.buildSpeculativeStack(i+1, base, batch)
+		if err != nil {
+			result.Error = fmt.Errorf("build stack %d: %w", i+1, err)
+			all := batchMRs(batches)
+			phases.advance(all, MRPhaseReady)
+			return all, nil
+		}
+
+		stackCtx, cancel := context.WithCancel(ctx)
+		st.cancel = cancel
+		st.done = make(chan struct{})
+		stacks = append(stacks, st)
+		if len(st.batch) == 0 {
+			// Every MR conflicted; the next stack builds on the same base
+			st.gates = ProcessResult{Success: true}
+			close(st.done)
+			continue
+		}
+
+		below = append(below, st.batch...)
+		st.stacked = append([]*MRInfo(nil), below...)
+		base = st.branch
+		result.Stacks++
+		go func(st *specStack) {
+			defer close(st.done)
+			st.gates = st.eng.runBatchGates(stackCtx, target, st.stacked)
+		}(st)
+	}
+
+	// Land passing stacks in order until one fails. A stack's conflicts
+	// are only final once everything below it has landed.
+	next := 0
+	for ; next < len(stacks); next++ {
+		st := stacks[next]
+		<-st.done
+		if err := ctx.Err(); err != nil {
+			result.Error = err
+			break
+		}
+		phases.advance(st.conflicts, MRPhaseFailed)
+		result.Conflicts = append(result.Conflicts, st.conflicts...)
+		if len(st.batch) == 0 {
+			continue
+		}
+		phases.advance(st.batch, MRPhasePrepared)
+		if !st.gates.Success {
+			_, _ = fmt.Fprintf(e.output, "[Pipeline] Stack %d failed gates, dropping %d stack(s) built on it\n", st.index, len(stacks)-next-1)
+			phases.advance(st.batch, MRPhaseReady)
+			failed = st.batch
+			next++
+			break
+		}
+		if err := e.landSpeculativeStack(ctx, st, target, phases, result); err != nil {
+			result.Error = err
+			next++
+			break
+		}
+	}
+
+	// Everything above the stop point was built on something that didn't land
+	for _, st := range stacks[next:] {
+		st.cancel()
+		<-st.done
+		phases.advance(st.all, MRPhaseReady)
+		requeue = append(requeue, st.all...)
+		if result.Error == nil && len(st.batch) > 0 {
+			result.Dropped++
+		}
+	}
+	return requeue, failed
+}
+
+// buildSpeculativeStack creates a worktree on a fresh branch from base and
+// applies batch to it with the configured merge strategy. MRs that can't
+// be applied are recorded as conflicts and left out of the stack.
+func (e *Engineer) buildSpeculativeStack(index int, base string, batch []*MRInfo) (*specStack, error) {
+	st := &specStack{
+		index:  index,
+		all:    batch,
+		path:   filepath.Join(e.rig.Path, ".runtime", "speculative", fmt.Sprintf("stack-%d", index)),
+		branch: fmt.Sprintf("%s%d", speculativeBranchPrefix, index),
+	}
+	e.removeSpeculativeStack(st) // Leftover from an interrupted run
+	if err := os.MkdirAll(filepath.Dir(st.path), 0755); err != nil {
+		return nil, err
+	}
+	if err := e.git.WorktreeAddFromRef(st.path, st.branch, base); err != nil {
+		return nil, fmt.Errorf("create worktree: %w", err)
+	}
+
+	eng := *e
+	eng.git = git.NewGit(st.path)
+	eng.workDir = st.path
+	eng.rebaseBranch = st.branch + "-rebase"
+	st.eng = &eng
+
+	for _, mr := range batch {
+		_, _ = fmt.Fprintf(e.output, "[Pipeline] Stack %d: stacking MR %s (branch %s)...\n", index, mr.ID, mr.Branch)
+		if exists, err := e.git.BranchExists(mr.Branch); err != nil || !exists {
+			_, _ = fmt.Fprintf(e.output, "[Pipeline] MR %s: branch %s not found, removing from stack\n", mr.ID, mr.Branch)
+			st.conflicts = append(st.conflicts, mr)
+			continue
+		}
+		if err := eng.applyMR(mr); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Pipeline] MR %s: %v, removing from stack\n", mr.ID, err)
+			st.conflicts = append(st.conflicts, mr)
+			continue
+		}
+		st.batch = append(st.batch, mr)
+	}
+	return st, nil
+}
+
+// landSpeculativeStack fast-forwards the target to a tested stack's tip.
+// Stacks below it have landed, so the push is a fast-forward unless someone
+// else moved the target in the meantime.
+func (e *Engineer) landSpeculativeStack(ctx context.Context, st *specStack, target string, phases *phaseTracker, result *PipelineResult) error {
+	phases.advance(st.batch, MRPhaseMerging)
+	fail := func(err error) error {
+		phases.advance(st.batch, MRPhaseFailed)
+		return err
+	}
+
+	tipSHA, err := st.eng.git.Rev("HEAD")
+	if err != nil {
+		return fail(fmt.Errorf("get stack %d tip: %w", st.index, err))
+	}
+
+	if target == e.rig.DefaultBranch() {
+		holder, slotErr := e.acquireMainPushSlot(ctx)
+		if slotErr != nil {
+			return fail(fmt.Errorf("acquire merge slot: %w", slotErr))
+		}
+		defer func() {
+			if releaseErr := e.mergeSlotRelease(holder); releaseErr != nil {
+				_, _ = fmt.Fprintf(e.output, "[Pipeline] Warning: failed to release merge slot: %v\n", releaseErr)
+			}
+		}()
+	}
+
+	if err := e.git.Push("origin", st.branch+":"+target, false); err != nil {
+		return fail(fmt.Errorf("push stack %d to origin/%s: %w", st.index, target, err))
+	}
+	phases.advance(st.batch, MRPhaseMerged)
+
+	_, _ = fmt.Fprintf(e.output, "[Pipeline] Landed stack %d: %s (commit %s)\n", st.index, strings.Join(mrIDs(st.batch), ", "), tipSHA[:8])
+	result.Merged = append(result.Merged, st.batch...)
+	result.MergeCommit = tipSHA
+	result.SkippedGates = st.gates.SkippedGates
+	return nil
+}
+
+// removeSpeculativeStack removes a stack's worktree and branches.
+func (e *Engineer) removeSpeculativeStack(st *specStack) {
+	_ = e.git.WorktreeRemove(st.path, true)
+	_ = os.RemoveAll(st.path)
+	_ = e.git.WorktreePrune()
+	_ = e.git.DeleteBranch(st.branch, true)
+	_ = e.git.DeleteBranch(st.branch+"-rebase", true)
+}
+
+// batchMRs flattens batches back into queue order.
+func batchMRs(batches [][]*MRInfo) []*MRInfo {
+	var mrs []*MRInfo
+	for _, b := range batches {
+		mrs = append(mrs, b...)
+	}
+	return mrs
+}
+
+// phaseTracker follows each MR in a pipeline through the phase machine,
+// refusing transitions ValidPhaseTransitions doesn't allow.
+type phaseTracker struct {
+	phases map[string]MRPhase
+	output io.Writer
+}
+
+func newPhaseTracker(mrs []*MRInfo, output io.Writer) *phaseTracker {
+	t := &phaseTracker{phases: make(map[string]MRPhase, len(mrs)), output: output}
+	for _, mr := range mrs {
+		t.phases[mr.ID] = MRPhaseReady
+	}
+	return t
+}
+
+// advance moves each MR through the given phases in order. An invalid
+// transition is reported and leaves the MR in its last valid phase.
+func (t *phaseTracker) advance(mrs []*MRInfo, path ...MRPhase) {
+	for _, mr := range mrs {
+		for _, to := range path {
+			if err := ValidatePhaseTransition(t.phases[mr.ID], to); err != nil {
+				_, _ = fmt.Fprintf(t.output, "[Pipeline] Warning: MR %s: %v\n", mr.ID, err)
+				break
+			}
+			t.phases[mr.ID] = to
+		}
+	}
+}
+
+// in returns the MRs of mrs currently in phase.
+func (t *phaseTracker) in(mrs []*MRInfo, phase MRPhase) []*MRInfo {
+	var out []*MRInfo
+	for _, mr := range mrs {
+		if t.phases[mr.ID] == phase {
+			out = append(out, mr)
+		}
+	}
+	return out
+}
+
+// snapshot returns a copy of the current phases.
+func (t *phaseTracker) snapshot() map[string]MRPhase {
+	out := make(map[string]MRPhase, len(t.phases))
+	for id, phase := range t.phases {
+		out[id] = phase
+	}
+	return out
+}
+
+// syncWriter serializes writes from stacks tested concurrently.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
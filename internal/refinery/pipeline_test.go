@@ -0,0 +1,170 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// setupPipelineTest returns an engineer testing up to depth stacks at once
+// on three feature branches; feature-b adds bad.txt.
+func setupPipelineTest(t *testing.T, depth int) (*Engineer, string, []*MRInfo) {
+	t.Helper()
+	workDir, g, _ := testGitRepo(t)
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "a\n")
+	createFeatureBranch(t, workDir, "feature-b", "bad.txt", "b\n")
+	createFeatureBranch(t, workDir, "feature-c", "c.txt", "c\n")
+
+	e := newTestEngineer(t, workDir, g)
+	e.config.MaxConcurrent = depth
+	e.config.Gates = map[string]*GateConfig{"test": {Cmd: "true"}}
+	mrs := []*MRInfo{
+		makeMR("mr-a", "feature-a", "main"),
+		makeMR("mr-b", "feature-b", "main"),
+		makeMR("mr-c", "feature-c", "main"),
+	}
+	return e, workDir, mrs
+}
+
+// singleMRBatches processes each MR as its own batch, one stack per MR.
+func singleMRBatches() *BatchConfig {
+	cfg := DefaultBatchConfig()
+	cfg.MaxBatchSize = 1
+	return cfg
+}
+
+func checkPipelineOutput(t *testing.T, e *Engineer) {
+	t.Helper()
+	if out := e.output.(*bytes.Buffer).String(); strings.Contains(out, "Warning: MR") {
+		t.Errorf("invalid phase transition:\n%s", out)
+	}
+}
+
+func TestProcessPipeline_LandsStacksInOrder(t *testing.T) {
+	for _, strategy := range []string{config.MergeStrategySquash, config.MergeStrategyRebase} {
+		t.Run(strategy, func(t *testing.T) {
+			e, workDir, mrs := setupPipelineTest(t, 3)
+			e.config.MergeStrategy = strategy
+
+			result := e.ProcessPipeline(context.Background(), mrs, "main", singleMRBatches())
+			if result.Error != nil {
+				t.Fatalf("ProcessPipeline: %v", result.Error)
+			}
+			if got := mrIDs(result.Merged); !reflect.DeepEqual(got, []string{"mr-a", "mr-b", "mr-c"}) {
+				t.Errorf("merged = %v", got)
+			}
+			if result.Stacks != 3 || result.Dropped != 0 {
+				t.Errorf("stacks = %d, dropped = %d", result.Stacks, result.Dropped)
+			}
+			for id, phase := range result.Phases {
+				if phase != MRPhaseMerged {
+					t.Errorf("%s phase = %s, want merged", id, phase)
+				}
+			}
+			checkPipelineOutput(t, e)
+
+			if tip := run(t, workDir, "git", "rev-parse", "origin/main"); tip != result.MergeCommit {
+				t.Errorf("origin/main = %s, want %s", tip, result.MergeCommit)
+			}
+			files := run(t, workDir, "git", "ls-tree", "--name-only", "origin/main")
+			for _, f := range []string{"a.txt", "bad.txt", "c.txt"} {
+				if !strings.Contains(files, f) {
+					t.Errorf("origin/main missing %s: %s", f, files)
+				}
+			}
+
+			// Worktrees and branches are cleaned up
+			if out := run(t, workDir, "git", "branch", "--list", speculativeBranchPrefix+"*"); out != "" {
+				t.Errorf("leftover branches: %s", out)
+			}
+			if out := run(t, workDir, "git", "worktree", "list"); strings.Contains(out, "speculative") {
+				t.Errorf("leftover worktrees: %s", out)
+			}
+		})
+	}
+}
+
+func TestProcessPipeline_FailureDropsOnlyDependentStacks(t *testing.T) {
+	e, workDir, mrs := setupPipelineTest(t, 3)
+	e.config.Gates = map[string]*GateConfig{"test": {Cmd: "test ! -f bad.txt"}}
+
+	result := e.ProcessPipeline(context.Background(), mrs, "main", singleMRBatches())
+	if result.Error != nil {
+		t.Fatalf("ProcessPipeline: %v", result.Error)
+	}
+	if got := mrIDs(result.Merged); !reflect.DeepEqual(got, []string{"mr-a", "mr-c"}) {
+		t.Errorf("merged = %v", got)
+	}
+	if got := mrIDs(result.Culprits); !reflect.DeepEqual(got, []string{"mr-b"}) {
+		t.Errorf("culprits = %v", got)
+	}
+	// Stack 1 landed, stack 2 failed, stack 3 (built on it) was dropped
+	if result.Stacks != 3 || result.Dropped != 1 {
+		t.Errorf("stacks = %d, dropped = %d", result.Stacks, result.Dropped)
+	}
+	want := map[string]MRPhase{"mr-a": MRPhaseMerged, "mr-b": MRPhaseRejected, "mr-c": MRPhaseMerged}
+	if !reflect.DeepEqual(result.Phases, want) {
+		t.Errorf("phases = %v, want %v", result.Phases, want)
+	}
+	checkPipelineOutput(t, e)
+
+	files := run(t, workDir, "git", "ls-tree", "--name-only", "origin/main")
+	if strings.Contains(files, "bad.txt") || !strings.Contains(files, "c.txt") {
+		t.Errorf("origin/main files = %s", files)
+	}
+}
+
+func TestProcessPipeline_ConflictsAndSerialFallback(t *testing.T) {
+	e, workDir, mrs := setupPipelineTest(t, 1)
+	createConflictingBranch(t, workDir, "feature-a2", "a.txt", "other\n")
+	mrs = append(mrs[:1], makeMR("mr-a2", "feature-a2", "main"))
+
+	result := e.ProcessPipeline(context.Background(), mrs, "main", singleMRBatches())
+	if result.Error != nil {
+		t.Fatalf("ProcessPipeline: %v", result.Error)
+	}
+	if result.Stacks != 0 {
+		t.Errorf("MaxConcurrent 1 built %d speculative stacks", result.Stacks)
+	}
+	want := map[string]MRPhase{"mr-a": MRPhaseMerged, "mr-a2": MRPhaseFailed}
+	if !reflect.DeepEqual(result.Phases, want) {
+		t.Errorf("phases = %v, want %v", result.Phases, want)
+	}
+
+	// The same conflict found while building a speculative stack
+	e2, workDir2, mrs2 := setupPipelineTest(t, 2)
+	createConflictingBranch(t, workDir2, "feature-a2", "a.txt", "other\n")
+	mrs2 = append(mrs2[:1], makeMR("mr-a2", "feature-a2", "main"))
+	result = e2.ProcessPipeline(context.Background(), mrs2, "main", singleMRBatches())
+	if result.Error != nil || !reflect.DeepEqual(result.Phases, want) {
+		t.Errorf("speculative phases = %v, err %v", result.Phases, result.Error)
+	}
+	if got := mrIDs(result.Conflicts); !reflect.DeepEqual(got, []string{"mr-a2"}) {
+		t.Errorf("conflicts = %v", got)
+	}
+	checkPipelineOutput(t, e2)
+	if _, err := os.Stat(filepath.Join(workDir2, ".runtime", "speculative", "stack-1")); !os.IsNotExist(err) {
+		t.Errorf("stack worktree not removed: %v", err)
+	}
+}
+
+func TestPhaseTracker_RejectsInvalidTransitions(t *testing.T) {
+	var out bytes.Buffer
+	mr := makeMR("mr-a", "feature-a", "main")
+	tr := newPhaseTracker([]*MRInfo{mr}, &out)
+
+	tr.advance([]*MRInfo{mr}, MRPhaseClaimed, MRPhasePreparing, MRPhaseReady)
+	if tr.phases["mr-a"] != MRPhaseReady || out.Len() != 0 {
+		t.Fatalf("dropped speculation: phase %s, output %q", tr.phases["mr-a"], out.String())
+	}
+	tr.advance([]*MRInfo{mr}, MRPhaseMerged)
+	if tr.phases["mr-a"] != MRPhaseReady || !strings.Contains(out.String(), "Warning: MR mr-a") {
+		t.Errorf("ready → merged allowed: phase %s, output %q", tr.phases["mr-a"], out.String())
+	}
+}
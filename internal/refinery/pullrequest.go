@@ -0,0 +1,331 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
+)
+
+// pullRequestBranchPrefix namespaces the head branches the refinery pushes
+// for pull requests, so they never collide with polecat branches.
+const pullRequestBranchPrefix = "gt-refinery/"
+
+// PullRequestConfig makes the refinery land protected targets through forge
+// pull requests instead of pushing to them directly.
+type PullRequestConfig struct {
+	// Forge is the forge API flavour. Only "github" (GitHub, GitHub
+	// Enterprise and compatible APIs) is supported.
+	Forge string `json:"forge"`
+
+	// APIURL is the REST API base URL. Empty means api.github.com.
+	APIURL string `json:"api_url"`
+
+	// Repo is the forge repository as "owner/name".
+	Repo string `json:"repo"`
+
+	// TokenEnv names the environment variable holding the API token.
+	// Empty falls back to GITHUB_TOKEN, then GH_TOKEN.
+	TokenEnv string `json:"token_env"`
+
+	// Branches are target branch patterns (path.Match syntax) that must be
+	// landed through pull requests. Empty means the rig's default branch only.
+	Branches []string `json:"branches"`
+
+	// MergeMethod is the forge merge method. Empty derives it from the merge
+	// strategy: "merge" for the merge strategy, otherwise "rebase", which
+	// lands the tested commits as they are.
+	MergeMethod string `json:"merge_method"`
+
+	// WaitTimeout bounds how long one attempt waits for checks and reviews.
+	// The MR stays queued when it expires and the next attempt reuses the
+	// open pull request.
+	WaitTimeout time.Duration `json:"wait_timeout"`
+
+	// PollInterval is how often the pull request is polled while waiting.
+	PollInterval time.Duration `json:"poll_interval"`
+}
+
+// pullRequestConfigRaw is the JSON-friendly representation of a pull request
+// config with durations as strings.
+type pullRequestConfigRaw struct {
+	Forge        string   `json:"forge"`
+	APIURL       string   `json:"api_url"`
+	Repo         string   `json:"repo"`
+	TokenEnv     string   `json:"token_env"`
+	Branches     []string `json:"branches"`
+	MergeMethod  string   `json:"merge_method"`
+	WaitTimeout  string   `json:"wait_timeout"`
+	PollInterval string   `json:"poll_interval"`
+}
+
+// parsePullRequestConfig validates raw and applies defaults.
+func parsePullRequestConfig(raw *pullRequestConfigRaw) (*PullRequestConfig, error) {
+	cfg := &PullRequestConfig{
+		Forge:        raw.Forge,
+		APIURL:       raw.APIURL,
+		Repo:         raw.Repo,
+		TokenEnv:     raw.TokenEnv,
+		Branches:     raw.Branches,
+		MergeMethod:  raw.MergeMethod,
+		WaitTimeout:  30 * time.Minute,
+		PollInterval: 30 * time.Second,
+	}
+	if cfg.Forge == "" {
+		cfg.Forge = "github"
+	}
+	if cfg.Forge != "github" {
+		return nil, fmt.Errorf("unsupported forge %q (expected github)", cfg.Forge)
+	}
+	if cfg.Repo == "" {
+		return nil, fmt.Errorf("repo is required (owner/name)")
+	}
+	if cfg.MergeMethod != "" && !forge.ValidMergeMethod(cfg.MergeMethod) {
+		return nil, fmt.Errorf("invalid merge_method %q (expected merge, squash or rebase)", cfg.MergeMethod)
+	}
+	for _, pattern := range cfg.Branches {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid branch pattern %q: %w", pattern, err)
+		}
+	}
+	for name, field := range map[string]struct {
+		raw string
+		dst *time.Duration
+	}{
+		"wait_timeout":  {raw.WaitTimeout, &cfg.WaitTimeout},
+		"poll_interval": {raw.PollInterval, &cfg.PollInterval},
+	} {
+		if field.raw == "" {
+			continue
+		}
+		dur, err := time.ParseDuration(field.raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", name, field.raw, err)
+		}
+		if dur <= 0 {
+			return nil, fmt.Errorf("%s must be positive, got %v", name, dur)
+		}
+		*field.dst = dur
+	}
+	return cfg, nil
+}
+
+// usePullRequest reports whether target must be landed through a pull request.
+func (e *Engineer) usePullRequest(target string) bool {
+	cfg := e.config.PullRequest
+	if cfg == nil {
+		return false
+	}
+	if len(cfg.Branches) == 0 {
+		return target == e.rig.DefaultBranch()
+	}
+	for _, pattern := range cfg.Branches {
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// getForge returns the configured forge client, creating it on first use.
+func (e *Engineer) getForge() (forge.Forge, error) {
+	if e.forge != nil {
+		return e.forge, nil
+	}
+	cfg := e.config.PullRequest
+	token := forge.GitHubToken(cfg.TokenEnv)
+	if token == "" {
+		env := cfg.TokenEnv
+		if env == "" {
+			env = "GITHUB_TOKEN or GH_TOKEN"
+		}
+		return nil, fmt.Errorf("no forge token: set %s", env)
+	}
+	gh, err := forge.NewGitHub(cfg.APIURL, cfg.Repo, token)
+	if err != nil {
+		return nil, err
+	}
+	e.forge = gh
+	return gh, nil
+}
+
+// pullRequestMergeMethod returns the forge merge method for landings.
+func (e *Engineer) pullRequestMergeMethod() string {
+	if m := e.config.PullRequest.MergeMethod; m != "" {
+		return m
+	}
+	if e.mergeStrategy() == config.MergeStrategyMerge {
+		return forge.MergeMethodMerge
+	}
+	return forge.MergeMethodRebase
+}
+
+// pullRequestLanding is the outcome of landing through a pull request.
+type pullRequestLanding struct {
+	Phase       MRPhase // merged or failed; merging while in flight
+	URL         string
+	MergeCommit string
+	Conflict    bool // The forge reports the head conflicts with the target
+	Pending     bool // Checks or reviews were still outstanding at WaitTimeout
+	Rejected    bool // A required check failed or a reviewer requested changes
+	Err         error
+}
+
+// advance moves the landing to phase to, refusing invalid transitions.
+func (l *pullRequestLanding) advance(to MRPhase) error {
+	if err := ValidatePhaseTransition(l.Phase, to); err != nil {
+		return err
+	}
+	l.Phase = to
+	return nil
+}
+
+// landViaPullRequest lands the tested HEAD of the checked-out target through
+// a pull request from headBranch: push the head, open or update the pull
+// request, wait until the forge reports it mergeable and merge it at the
+// tested SHA. The local target is left as-is; on success callers can reset
+// it to origin, on failure they must.
+func (e *Engineer) landViaPullRequest(ctx context.Context, target, headBranch string, mrs []*MRInfo) pullRequestLanding {
+	land := pullRequestLanding{Phase: MRPhaseMerging}
+	fail := func(err error) pullRequestLanding {
+		if phaseErr := land.advance(MRPhaseFailed); phaseErr != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", phaseErr)
+		}
+		land.Err = err
+		return land
+	}
+
+	f, err := e.getForge()
+	if err != nil {
+		return fail(err)
+	}
+	cfg := e.config.PullRequest
+
+	headSHA, err := e.pushPullRequestHead(target, headBranch)
+	if err != nil {
+		return fail(err)
+	}
+
+	title, body := e.pullRequestText(mrs, target)
+	pr, err := f.EnsurePullRequest(ctx, forge.PullRequestSpec{
+		Head:  headBranch,
+		Base:  target,
+		Title: title,
+		Body:  body,
+	})
+	if err != nil {
+		return fail(err)
+	}
+	land.URL = pr.URL
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pull request #%d: %s (waiting up to %v)\n", pr.Number, pr.URL, cfg.WaitTimeout)
+	// Waiting can take a while; let gt mq show report the MRs as merging.
+	for _, mr := range mrs {
+		e.recordMRPhase(mr, land.Phase)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, cfg.WaitTimeout)
+	defer cancel()
+	pr, err = forge.WaitMergeable(waitCtx, f, pr.Number, cfg.PollInterval)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			land.Pending = true
+		}
+		return fail(err)
+	}
+
+	switch pr.Status() {
+	case forge.StatusMerged:
+		// Merged by someone else or by forge auto-merge while we waited.
+		land.MergeCommit = pr.MergeCommitSHA
+	case forge.StatusConflict:
+		land.Conflict = true
+		return fail(fmt.Errorf("pull request #%d conflicts with %s", pr.Number, target))
+	case forge.StatusBehind:
+		return fail(fmt.Errorf("pull request #%d is behind %s; will retry on the new target", pr.Number, target))
+	case forge.StatusClosed:
+		return fail(fmt.Errorf("pull request #%d was closed without merging", pr.Number))
+	case forge.StatusChecksFailed:
+		land.Rejected = true
+		return fail(fmt.Errorf("pull request #%d: required checks failed: %s", pr.Number, strings.Join(pr.FailedChecks, ", ")))
+	case forge.StatusChangesRequested:
+		land.Rejected = true
+		return fail(fmt.Errorf("pull request #%d: a reviewer requested changes", pr.Number))
+	default:
+		sha, err := f.MergePullRequest(ctx, pr.Number, forge.MergeOptions{
+			Method: e.pullRequestMergeMethod(),
+			SHA:    headSHA,
+		})
+		if err != nil {
+			return fail(err)
+		}
+		land.MergeCommit = sha
+	}
+
+	if err := land.advance(MRPhaseMerged); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", err)
+	}
+	if err := e.git.DeleteRemoteBranch("origin", headBranch); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to delete pull request branch %s: %v\n", headBranch, err)
+	}
+	if err := e.git.FetchBranch("origin", target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to fetch %s after merge: %v\n", target, err)
+	} else if err := e.git.ResetHard("origin/" + target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to sync %s after merge: %v\n", target, err)
+	}
+	return land
+}
+
+// pushPullRequestHead pushes HEAD to headBranch and returns the head SHA.
+// When the remote head already holds the same tree on top of the current
+// target, it is reused so a retry does not restart the forge's checks.
+func (e *Engineer) pushPullRequestHead(target, headBranch string) (string, error) {
+	tip, err := e.git.Rev("HEAD")
+	if err != nil {
+		return "", fmt.Errorf("get tip SHA: %w", err)
+	}
+	if err := e.git.FetchBranch("origin", headBranch); err == nil {
+		remote, revErr := e.git.Rev("FETCH_HEAD")
+		remoteTree, treeErr := e.git.Rev("FETCH_HEAD^{tree}")
+		tipTree, tipTreeErr := e.git.Rev("HEAD^{tree}")
+		upToDate, ancErr := e.git.IsAncestor("origin/"+target, "FETCH_HEAD")
+		if revErr == nil && treeErr == nil && tipTreeErr == nil && ancErr == nil && remoteTree == tipTree && upToDate {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Reusing unchanged pull request branch %s\n", headBranch)
+			return remote, nil
+		}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing tested changes to origin/%s...\n", headBranch)
+	if err := e.git.Push("origin", "HEAD:refs/heads/"+headBranch, true); err != nil {
+		return "", fmt.Errorf("push %s: %w", headBranch, err)
+	}
+	return tip, nil
+}
+
+// pullRequestText returns the title and body of the pull request for mrs.
+func (e *Engineer) pullRequestText(mrs []*MRInfo, target string) (string, string) {
+	var title string
+	if len(mrs) == 1 {
+		title, _, _ = strings.Cut(strings.TrimSpace(e.getMergeMessage(mrs[0])), "\n")
+	} else {
+		title = fmt.Sprintf("Refinery batch: %d merge requests into %s", len(mrs), target)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Landed by the %s refinery after its quality gates passed.\n\n", e.rig.Name)
+	for _, mr := range mrs {
+		line := "- " + mr.Branch
+		if mr.ID != "" {
+			line = fmt.Sprintf("- %s (%s)", mr.ID, mr.Branch)
+		}
+		if mr.SourceIssue != "" {
+			line += " for " + mr.SourceIssue
+		}
+		body.WriteString(line + "\n")
+	}
+	return title, body.String()
+}
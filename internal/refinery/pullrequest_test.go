@@ -0,0 +1,269 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/forge"
+	"github.com/steveyegge/gastown/internal/forge/forgetest"
+	gitpkg "github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// setupPullRequestTest returns an engineer in pull request mode backed by a
+// fake forge. Merging a pull request fast-forwards the bare origin's base
+// branch to the pull request head, like a no-op rebase merge.
+func setupPullRequestTest(t *testing.T) (string, *gitpkg.Git, *Engineer, *forgetest.Server) {
+	t.Helper()
+	workDir, g, _ := testGitRepo(t)
+	bareDir := filepath.Join(filepath.Dir(workDir), "origin.git")
+
+	srv := forgetest.NewServer(t, "acme", "widgets")
+	srv.ResolveHead = func(branch string) string {
+		out, err := exec.Command("git", "-C", bareDir, "rev-parse", "refs/heads/"+branch).Output()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(out))
+	}
+	srv.OnMerge = func(pr forgetest.Pull, method string) (string, error) {
+		cmd := exec.Command("git", "-C", bareDir, "update-ref", "refs/heads/"+pr.Base, pr.HeadSHA)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("update-ref: %v: %s", err, out)
+		}
+		return pr.HeadSHA, nil
+	}
+
+	e := newTestEngineer(t, workDir, g)
+	e.config.PullRequest = &PullRequestConfig{
+		Forge:        "github",
+		Repo:         srv.Repo(),
+		WaitTimeout:  5 * time.Second,
+		PollInterval: 10 * time.Millisecond,
+	}
+	gh, err := forge.NewGitHub(srv.URL, srv.Repo(), "test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.forge = gh
+	return workDir, g, e, srv
+}
+
+func TestPullRequest_SingleMR(t *testing.T) {
+	workDir, _, e, srv := setupPullRequestTest(t)
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "a\n")
+	base := run(t, workDir, "git", "rev-parse", "origin/main")
+
+	result := e.ProcessMRInfo(context.Background(), makeMR("mr-a", "feature-a", "main"))
+	if !result.Success {
+		t.Fatalf("ProcessMRInfo failed: %s", result.Error)
+	}
+	if result.Phase != MRPhaseMerged {
+		t.Errorf("Phase = %q, want merged", result.Phase)
+	}
+
+	pulls := srv.Pulls()
+	if len(pulls) != 1 {
+		t.Fatalf("got %d pull requests, want 1", len(pulls))
+	}
+	pr := pulls[0]
+	if pr.Head != "gt-refinery/feature-a" || pr.Base != "main" || !pr.Merged {
+		t.Errorf("pull request = %+v", pr)
+	}
+	if pr.MergeMethod != forge.MergeMethodRebase {
+		t.Errorf("merge method = %q, want rebase for squash strategy", pr.MergeMethod)
+	}
+	if !strings.HasSuffix(result.PullRequest, "/pull/1") {
+		t.Errorf("PullRequest = %q", result.PullRequest)
+	}
+
+	run(t, workDir, "git", "fetch", "origin")
+	if tip := run(t, workDir, "git", "rev-parse", "origin/main"); tip != result.MergeCommit || tip == base {
+		t.Errorf("origin/main = %s, want merge commit %s", tip, result.MergeCommit)
+	}
+	if local := run(t, workDir, "git", "rev-parse", "main"); local != result.MergeCommit {
+		t.Errorf("local main = %s, want synced to %s", local, result.MergeCommit)
+	}
+	if out := run(t, workDir, "git", "ls-remote", "--heads", "origin", "gt-refinery/*"); out != "" {
+		t.Errorf("pull request branch left on origin: %q", out)
+	}
+}
+
+func TestPullRequest_PendingThenRetry(t *testing.T) {
+	workDir, _, e, srv := setupPullRequestTest(t)
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "a\n")
+	base := run(t, workDir, "git", "rev-parse", "origin/main")
+	srv.MergeableState = "blocked" // Waiting on a required review
+	e.config.PullRequest.WaitTimeout = 50 * time.Millisecond
+
+	mr := makeMR("mr-a", "feature-a", "main")
+	result := e.ProcessMRInfo(context.Background(), mr)
+	if result.Success || !result.PullRequestPending {
+		t.Fatalf("expected pending pull request, got %+v", result)
+	}
+	if result.Phase != MRPhaseFailed {
+		t.Errorf("Phase = %q, want failed", result.Phase)
+	}
+	if tip := run(t, workDir, "git", "ls-remote", "origin", "refs/heads/main"); !strings.HasPrefix(tip, base) {
+		t.Errorf("origin/main moved while the pull request was pending: %s", tip)
+	}
+	if local := run(t, workDir, "git", "rev-parse", "main"); local != base {
+		t.Errorf("local main = %s, want reset to %s", local, base)
+	}
+
+	// The review lands; the retry reuses the same pull request and head.
+	srv.SetMergeableState(1, "clean")
+	head := run(t, workDir, "git", "ls-remote", "origin", "refs/heads/gt-refinery/feature-a")
+	result = e.ProcessMRInfo(context.Background(), mr)
+	if !result.Success {
+		t.Fatalf("retry failed: %s", result.Error)
+	}
+	if n := len(srv.Pulls()); n != 1 {
+		t.Errorf("retry opened %d pull requests, want 1", n)
+	}
+	if !strings.HasPrefix(head, result.MergeCommit) {
+		t.Errorf("retry pushed a new head: merged %s, pending head was %s", result.MergeCommit, head)
+	}
+}
+
+func TestPullRequest_ForgeConflict(t *testing.T) {
+	workDir, _, e, srv := setupPullRequestTest(t)
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "a\n")
+	srv.MergeableState = "dirty"
+
+	result := e.ProcessMRInfo(context.Background(), makeMR("mr-a", "feature-a", "main"))
+	if result.Success || !result.Conflict {
+		t.Fatalf("expected conflict, got %+v", result)
+	}
+	if result.PullRequestPending {
+		t.Error("conflict should not be reported as pending")
+	}
+}
+
+func TestPullRequest_RequiredCheckFailed(t *testing.T) {
+	workDir, _, e, srv := setupPullRequestTest(t)
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "a\n")
+	base := run(t, workDir, "git", "rev-parse", "origin/main")
+	srv.MergeableState = "blocked"
+	srv.RequiredChecks = map[string][]string{"main": {"ci"}}
+	srv.DefaultChecks = []forgetest.CheckRun{{Name: "ci", Status: "completed", Conclusion: "failure"}}
+
+	result := e.ProcessMRInfo(context.Background(), makeMR("mr-a", "feature-a", "main"))
+	if result.Success || result.PullRequestPending || !result.PullRequestFailed {
+		t.Fatalf("expected failed landing, got %+v", result)
+	}
+	if result.Phase != MRPhaseFailed {
+		t.Errorf("Phase = %q, want failed", result.Phase)
+	}
+	if !strings.Contains(result.Error, "ci") {
+		t.Errorf("Error = %q, want the failed check named", result.Error)
+	}
+	if tip := run(t, workDir, "git", "ls-remote", "origin", "refs/heads/main"); !strings.HasPrefix(tip, base) {
+		t.Errorf("origin/main moved despite failed checks: %s", tip)
+	}
+}
+
+func TestPullRequest_Batch(t *testing.T) {
+	workDir, _, e, srv := setupPullRequestTest(t)
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "a\n")
+	createFeatureBranch(t, workDir, "feature-b", "b.txt", "b\n")
+
+	batch := []*MRInfo{makeMR("mr-a", "feature-a", "main"), makeMR("mr-b", "feature-b", "main")}
+	result := e.ProcessBatch(context.Background(), batch, "main", DefaultBatchConfig())
+	if result.Error != nil || len(result.Merged) != 2 {
+		t.Fatalf("merged %d, err %v", len(result.Merged), result.Error)
+	}
+
+	pulls := srv.Pulls()
+	if len(pulls) != 1 || pulls[0].Head != "gt-refinery/batch/main" {
+		t.Fatalf("pull requests = %+v, want one batch pull request", pulls)
+	}
+	if !strings.Contains(pulls[0].Body, "mr-a (feature-a)") || !strings.Contains(pulls[0].Body, "mr-b (feature-b)") {
+		t.Errorf("batch body does not list MRs: %q", pulls[0].Body)
+	}
+	if result.PullRequest == "" || result.MergeCommit != pulls[0].MergeCommitSHA {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestPullRequest_DirectPushForUnprotectedTarget(t *testing.T) {
+	workDir, _, e, srv := setupPullRequestTest(t)
+	run(t, workDir, "git", "checkout", "-b", "integration/epic")
+	run(t, workDir, "git", "push", "-u", "origin", "integration/epic")
+	run(t, workDir, "git", "checkout", "main")
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "a\n")
+
+	result := e.ProcessMRInfo(context.Background(), makeMR("mr-a", "feature-a", "integration/epic"))
+	if !result.Success {
+		t.Fatalf("ProcessMRInfo failed: %s", result.Error)
+	}
+	if len(srv.Pulls()) != 0 || result.PullRequest != "" {
+		t.Errorf("integration branch should be pushed directly, got pull request %q", result.PullRequest)
+	}
+}
+
+func TestEngineer_UsePullRequest(t *testing.T) {
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	if e.usePullRequest("main") {
+		t.Error("pull requests should be off without config")
+	}
+
+	e.config.PullRequest = &PullRequestConfig{}
+	if !e.usePullRequest("main") || e.usePullRequest("integration/x") {
+		t.Error("empty branches should protect only the default branch")
+	}
+
+	e.config.PullRequest.Branches = []string{"main", "release/*"}
+	for target, want := range map[string]bool{"main": true, "release/1.2": true, "integration/x": false} {
+		if got := e.usePullRequest(target); got != want {
+			t.Errorf("usePullRequest(%q) = %v, want %v", target, got, want)
+		}
+	}
+}
+
+func TestEngineer_LoadConfig_PullRequest(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(pr map[string]interface{}) {
+		data, _ := json.Marshal(map[string]interface{}{
+			"merge_queue": map[string]interface{}{"pull_request": pr},
+		})
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(map[string]interface{}{
+		"repo":          "acme/widgets",
+		"branches":      []string{"main"},
+		"wait_timeout":  "2h",
+		"poll_interval": "1m",
+	})
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	cfg := e.config.PullRequest
+	if cfg == nil || cfg.Forge != "github" || cfg.Repo != "acme/widgets" || cfg.WaitTimeout != 2*time.Hour || cfg.PollInterval != time.Minute {
+		t.Errorf("PullRequest config = %+v", cfg)
+	}
+
+	for _, bad := range []map[string]interface{}{
+		{},
+		{"repo": "acme/widgets", "forge": "gitlab"},
+		{"repo": "acme/widgets", "merge_method": "octopus"},
+		{"repo": "acme/widgets", "wait_timeout": "-1m"},
+		{"repo": "acme/widgets", "branches": []string{"["}},
+	} {
+		write(bad)
+		if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+			t.Errorf("expected error for %v", bad)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package refinery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// rebaseScratchBranch is the temporary branch the rebase strategy replays an
+// MR onto, so the polecat's own branch is never rewritten.
+const rebaseScratchBranch = "gt-refinery-rebase"
+
+// errApplyConflict is returned by applyMR when the MR conflicts with the
+// current state of the target branch.
+var errApplyConflict = errors.New("merge conflict")
+
+// ValidMergeStrategy reports whether s is a known merge strategy.
+func ValidMergeStrategy(s string) bool {
+	switch s {
+	case config.MergeStrategySquash, config.MergeStrategyRebase, config.MergeStrategyMerge:
+		return true
+	}
+	return false
+}
+
+// mergeStrategy returns the configured strategy, defaulting to squash.
+func (e *Engineer) mergeStrategy() string {
+	if e.config.MergeStrategy == "" {
+		return config.MergeStrategySquash
+	}
+	return e.config.MergeStrategy
+}
+
+// applyMR lands mr on the currently checked-out target branch using the
+// configured merge strategy, without pushing. On failure the merge or rebase
+// is aborted and target is left checked out; conflicts wrap errApplyConflict.
+func (e *Engineer) applyMR(mr *MRInfo) error {
+	var err error
+	switch strategy := e.mergeStrategy(); strategy {
+	case config.MergeStrategySquash:
+		err = e.git.MergeSquash(mr.Branch, e.getMergeMessage(mr))
+	case config.MergeStrategyMerge:
+		err = e.git.MergeNoFF(mr.Branch, e.getMergeCommitMessage(mr))
+	case config.MergeStrategyRebase:
+		return e.rebaseAndFastForward(mr)
+	default:
+		return fmt.Errorf("unknown merge strategy %q", strategy)
+	}
+	if err == nil {
+		return nil
+	}
+
+	// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
+	// merge --abort doesn't apply to squash merges, so reset instead.
+	conflicts, conflictErr := e.git.GetConflictingFiles()
+	_ = e.git.ResetHard("HEAD")
+	if conflictErr == nil && len(conflicts) > 0 {
+		return fmt.Errorf("%w in: %v", errApplyConflict, conflicts)
+	}
+	return err
+}
+
+// rebaseAndFastForward replays mr's commits onto the target on a scratch
+// branch and fast-forwards the target to it, keeping history linear.
+func (e *Engineer) rebaseAndFastForward(mr *MRInfo) error {
+	target, err := e.git.CurrentBranch()
+	if err != nil {
+		return fmt.Errorf("get current branch: %w", err)
+	}
+
+	scratch := rebaseScratchBranch
+	if e.rebaseBranch != "" {
+		scratch = e.rebaseBranch
+	}
+	_ = e.git.DeleteBranch(scratch, true) // Leftover from an interrupted run
+	if err := e.git.CheckoutNewBranch(scratch, mr.Branch); err != nil {
+		_ = e.git.Checkout(target)
+		return fmt.Errorf("create rebase branch from %s: %w", mr.Branch, err)
+	}
+	defer func() { _ = e.git.DeleteBranch(scratch, true) }()
+
+	if err := e.git.Rebase(target); err != nil {
+		conflicts, conflictErr := e.git.GetConflictingFiles()
+		_ = e.git.AbortRebase()
+		_ = e.git.Checkout(target)
+		if conflictErr == nil && len(conflicts) > 0 {
+			return fmt.Errorf("%w in: %v", errApplyConflict, conflicts)
+		}
+		return fmt.Errorf("rebase %s onto %s: %w", mr.Branch, target, err)
+	}
+
+	if err := e.git.Checkout(target); err != nil {
+		return fmt.Errorf("checkout %s: %w", target, err)
+	}
+	if err := e.git.MergeFFOnly(scratch); err != nil {
+		return fmt.Errorf("fast-forward %s: %w", target, err)
+	}
+	return nil
+}
+
+// getMergeCommitMessage returns the message for a merge-commit strategy
+// merge: a standard merge subject followed by the branch's own message.
+func (e *Engineer) getMergeCommitMessage(mr *MRInfo) string {
+	subject := fmt.Sprintf("Merge branch '%s' into %s", mr.Branch, mr.Target)
+	if mr.SourceIssue != "" {
+		subject = fmt.Sprintf("%s (%s)", subject, mr.SourceIssue)
+	}
+	body, err := e.git.GetBranchCommitMessage(mr.Branch)
+	if err != nil || strings.TrimSpace(body) == "" {
+		return subject
+	}
+	return subject + "\n\n" + strings.TrimSpace(body)
+}
@@ -0,0 +1,146 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// createTwoCommitBranch creates a branch with two commits so strategies that
+// preserve or collapse history can be told apart.
+func createTwoCommitBranch(t *testing.T, workDir, branchName, prefix string) {
+	t.Helper()
+	run(t, workDir, "git", "checkout", "-b", branchName, "main")
+	for _, n := range []string{"1", "2"} {
+		writeFile(t, workDir, prefix+n+".txt", n+"\n")
+		run(t, workDir, "git", "add", ".")
+		run(t, workDir, "git", "commit", "-m", "feat: "+prefix+" part "+n)
+	}
+	run(t, workDir, "git", "checkout", "main")
+}
+
+func TestMergeStrategies_Batch(t *testing.T) {
+	tests := []struct {
+		strategy    string
+		wantCommits int // New commits on main for two MRs of two commits each
+		wantMerges  int
+	}{
+		{config.MergeStrategySquash, 2, 0},
+		{config.MergeStrategyRebase, 4, 0},
+		{config.MergeStrategyMerge, 6, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			workDir, g, _ := testGitRepo(t)
+			createTwoCommitBranch(t, workDir, "feature-a", "a")
+			createTwoCommitBranch(t, workDir, "feature-b", "b")
+			base := run(t, workDir, "git", "rev-parse", "main")
+
+			e := newTestEngineer(t, workDir, g)
+			e.config.MergeStrategy = tt.strategy
+			batch := []*MRInfo{
+				makeMR("mr-a", "feature-a", "main"),
+				makeMR("mr-b", "feature-b", "main"),
+			}
+			batch[1].SourceIssue = "gt-b"
+
+			result := e.ProcessBatch(context.Background(), batch, "main", DefaultBatchConfig())
+			if result.Error != nil || len(result.Merged) != 2 {
+				t.Fatalf("merged %d, err %v", len(result.Merged), result.Error)
+			}
+
+			commits := run(t, workDir, "git", "rev-list", "--count", base+"..origin/main")
+			if commits != strconv.Itoa(tt.wantCommits) {
+				t.Errorf("new commits on main = %s, want %d", commits, tt.wantCommits)
+			}
+			merges := run(t, workDir, "git", "rev-list", "--count", "--merges", "origin/main")
+			if merges != strconv.Itoa(tt.wantMerges) {
+				t.Errorf("merge commits = %s, want %d", merges, tt.wantMerges)
+			}
+			for _, f := range []string{"a1.txt", "a2.txt", "b1.txt", "b2.txt"} {
+				if _, err := os.Stat(filepath.Join(workDir, f)); err != nil {
+					t.Errorf("expected %s on main: %v", f, err)
+				}
+			}
+
+			if tt.strategy == config.MergeStrategyMerge {
+				msg := run(t, workDir, "git", "log", "-1", "--format=%B", "origin/main")
+				if !strings.HasPrefix(msg, "Merge branch 'feature-b' into main (gt-b)") {
+					t.Errorf("merge commit message = %q", msg)
+				}
+			}
+			if tt.strategy == config.MergeStrategyRebase {
+				// The polecat branch itself is not rewritten.
+				if base := run(t, workDir, "git", "merge-base", "feature-b", "origin/main"); base == run(t, workDir, "git", "rev-parse", "origin/main") {
+					t.Error("feature-b should not have been rebased in place")
+				}
+				if out := run(t, workDir, "git", "branch", "--list", rebaseScratchBranch); out != "" {
+					t.Errorf("scratch branch left behind: %q", out)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeStrategies_SingleMRConflict(t *testing.T) {
+	for _, strategy := range []string{config.MergeStrategySquash, config.MergeStrategyRebase, config.MergeStrategyMerge} {
+		t.Run(strategy, func(t *testing.T) {
+			workDir, g, _ := testGitRepo(t)
+			createConflictingBranch(t, workDir, "feature-x", "README.md", "# X\n")
+			// Land a conflicting change on main after the branch was cut.
+			writeFile(t, workDir, "README.md", "# Main\n")
+			run(t, workDir, "git", "commit", "-am", "main change")
+			run(t, workDir, "git", "push", "origin", "main")
+
+			e := newTestEngineer(t, workDir, g)
+			e.config.MergeStrategy = strategy
+			mr := makeMR("mr-x", "feature-x", "main")
+			if err := e.applyMR(mr); err == nil {
+				t.Fatal("expected conflict")
+			}
+			if branch := run(t, workDir, "git", "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+				t.Errorf("HEAD = %s after failed %s, want main", branch, strategy)
+			}
+			if status := run(t, workDir, "git", "status", "--porcelain"); status != "" {
+				t.Errorf("working tree not clean after failed %s: %q", strategy, status)
+			}
+		})
+	}
+}
+
+func TestEngineer_LoadConfig_MergeStrategy(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(strategy string) {
+		data, _ := json.Marshal(map[string]interface{}{
+			"merge_queue": map[string]interface{}{"merge_strategy": strategy},
+		})
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if e.config.MergeStrategy != config.MergeStrategySquash {
+		t.Errorf("default MergeStrategy = %q, want squash", e.config.MergeStrategy)
+	}
+
+	write("rebase")
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if e.config.MergeStrategy != config.MergeStrategyRebase {
+		t.Errorf("MergeStrategy = %q, want rebase", e.config.MergeStrategy)
+	}
+
+	write("octopus")
+	if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+		t.Error("expected error for unknown merge_strategy")
+	}
+}
@@ -0,0 +1,147 @@
+package refinery
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Test report formats a gate can declare with GateConfig.TestReport.
+const (
+	// TestReportGoJSON parses the gate's stdout as `go test -json` events.
+	TestReportGoJSON = "go-json"
+
+	// TestReportJUnit parses the JUnit XML files matched by the gate's
+	// artifacts globs.
+	TestReportJUnit = "junit"
+)
+
+// testOutcomes maps a test ID to whether it failed in one gate attempt.
+// Skipped tests are not recorded.
+type testOutcomes map[string]bool
+
+// record notes an outcome; a test reported more than once counts as failed
+// if any report failed.
+func (o testOutcomes) record(id string, failed bool) {
+	o[id] = o[id] || failed
+}
+
+// failed returns the IDs of the failed tests.
+func (o testOutcomes) failed() []string {
+	var ids []string
+	for id, failed := range o {
+		if failed {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// parseGoTestJSON reads `go test -json` output. Lines that aren't JSON
+// events (build errors, -v noise from wrappers) are ignored. Test IDs are
+// "<package>.<test>", including subtests ("pkg.TestA/sub").
+func parseGoTestJSON(data []byte) testOutcomes {
+	out := make(testOutcomes)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var ev struct {
+			Action  string
+			Package string
+			Test    string
+		}
+		if err := json.Unmarshal(line, &ev); err != nil || ev.Test == "" {
+			continue
+		}
+		switch ev.Action {
+		case "pass":
+			out.record(ev.Package+"."+ev.Test, false)
+		case "fail":
+			out.record(ev.Package+"."+ev.Test, true)
+		}
+	}
+	return out
+}
+
+// parseJUnit reads a JUnit XML report into out. Test IDs are
+// "<classname>.<name>"; a testcase with a failure or error element failed.
+func parseJUnit(r io.Reader, out testOutcomes) error {
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "testcase" {
+			continue
+		}
+		var tc struct {
+			Classname string    `xml:"classname,attr"`
+			Name      string    `xml:"name,attr"`
+			Failure   *struct{} `xml:"failure"`
+			Error     *struct{} `xml:"error"`
+			Skipped   *struct{} `xml:"skipped"`
+		}
+		if err := dec.DecodeElement(&tc, &start); err != nil {
+			return err
+		}
+		if tc.Skipped != nil && tc.Failure == nil && tc.Error == nil {
+			continue
+		}
+		id := tc.Name
+		if tc.Classname != "" {
+			id = tc.Classname + "." + tc.Name
+		}
+		out.record(id, tc.Failure != nil || tc.Error != nil)
+	}
+}
+
+// parseTestReport extracts per-test outcomes from one gate attempt that
+// started at started. It returns nil when the gate declares no report or
+// none could be read. Stale JUnit files left by earlier runs are ignored.
+func (e *Engineer) parseTestReport(gate *GateConfig, res GateResult, started time.Time) testOutcomes {
+	switch gate.TestReport {
+	case TestReportGoJSON:
+		if out := parseGoTestJSON(res.Stdout); len(out) > 0 {
+			return out
+		}
+	case TestReportJUnit:
+		out := make(testOutcomes)
+		for _, pattern := range gate.Artifacts {
+			matches, _ := filepath.Glob(filepath.Join(e.workDir, pattern))
+			for _, path := range matches {
+				info, err := os.Stat(path)
+				if err != nil || !info.Mode().IsRegular() || info.ModTime().Before(started.Truncate(time.Second)) {
+					continue
+				}
+				f, err := os.Open(path)
+				if err != nil {
+					continue
+				}
+				if err := parseJUnit(f, out); err != nil {
+					_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: parsing JUnit report %s: %v\n", path, err)
+				}
+				_ = f.Close()
+			}
+		}
+		if len(out) > 0 {
+			return out
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package refinery
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseGoTestJSON(t *testing.T) {
+	out := parseGoTestJSON([]byte(`# example.com/p [build noise]
+{"Action":"run","Package":"example.com/p","Test":"TestA"}
+{"Action":"output","Package":"example.com/p","Test":"TestA","Output":"--- FAIL: TestA\n"}
+{"Action":"fail","Package":"example.com/p","Test":"TestA"}
+{"Action":"pass","Package":"example.com/p","Test":"TestB/sub"}
+{"Action":"pass","Package":"example.com/p","Test":"TestB"}
+{"Action":"skip","Package":"example.com/p","Test":"TestC"}
+{"Action":"fail","Package":"example.com/p"}
+not json
+`))
+	want := testOutcomes{
+		"example.com/p.TestA":     true,
+		"example.com/p.TestB/sub": false,
+		"example.com/p.TestB":     false,
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("parseGoTestJSON = %v, want %v", out, want)
+	}
+}
+
+func TestParseJUnit(t *testing.T) {
+	out := make(testOutcomes)
+	err := parseJUnit(strings.NewReader(`<?xml version="1.0"?>
+<testsuites>
+  <testsuite name="api">
+    <testcase classname="api.Users" name="create"/>
+    <testcase classname="api.Users" name="delete"><failure message="boom">trace</failure></testcase>
+    <testcase classname="api.Users" name="list"><error/></testcase>
+    <testcase classname="api.Users" name="slow"><skipped/></testcase>
+  </testsuite>
+</testsuites>`), out)
+	if err != nil {
+		t.Fatalf("parseJUnit: %v", err)
+	}
+	failed := out.failed()
+	sort.Strings(failed)
+	if len(out) != 3 || !reflect.DeepEqual(failed, []string{"api.Users.delete", "api.Users.list"}) {
+		t.Errorf("parseJUnit = %v", out)
+	}
+}
+
+func TestParseTestReport_JUnitIgnoresStaleFiles(t *testing.T) {
+	workDir := t.TempDir()
+	e := newTestEngineer(t, workDir, nil)
+	gate := &GateConfig{TestReport: TestReportJUnit, Artifacts: []string{"*.xml"}}
+
+	report := `<testsuite><testcase classname="c" name="t"><failure/></testcase></testsuite>`
+	path := filepath.Join(workDir, "junit.xml")
+	if err := os.WriteFile(path, []byte(report), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if out := e.parseTestReport(gate, GateResult{}, time.Now()); out != nil {
+		t.Errorf("stale report parsed: %v", out)
+	}
+	if out := e.parseTestReport(gate, GateResult{}, old.Add(-time.Minute)); !reflect.DeepEqual(out, testOutcomes{"c.t": true}) {
+		t.Errorf("parseTestReport = %v", out)
+	}
+}
@@ -0,0 +1,57 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startMergeSpan starts the span for merging one MR. It continues the trace
+// recorded on the MR bead by gt done, so the gates and merge appear in the
+// same trace as the sling that dispatched the work.
+func startMergeSpan(ctx context.Context, mr *MRInfo, target string) (context.Context, trace.Span) {
+	return telemetry.StartSpan(telemetry.WithTraceParent(ctx, mr.TraceParent), "refinery.merge",
+		attribute.String("mr.id", mr.ID),
+		attribute.String("mr.branch", mr.Branch),
+		attribute.String("mr.target", target),
+	)
+}
+
+// startBatchSpan starts the span for a batch of MRs. MRs in a batch come
+// from different traces, so the batch gets its own trace linked to each.
+func startBatchSpan(ctx context.Context, batch []*MRInfo, target string) (context.Context, trace.Span) {
+	parents := make([]string, 0, len(batch))
+	for _, mr := range batch {
+		parents = append(parents, mr.TraceParent)
+	}
+	return telemetry.StartLinkedSpan(ctx, "refinery.batch", parents,
+		attribute.StringSlice("mr.ids", mrIDs(batch)),
+		attribute.String("mr.target", target),
+	)
+}
+
+// endMergeSpan ends a merge span with the outcome of result.
+func endMergeSpan(span trace.Span, result ProcessResult) {
+	var err error
+	if !result.Success {
+		err = errors.New(result.Error)
+	}
+	span.SetAttributes(
+		attribute.Bool("merge.conflict", result.Conflict),
+		attribute.Bool("merge.tests_failed", result.TestsFailed),
+	)
+	telemetry.EndSpan(span, err)
+}
+
+// endBatchSpan ends a batch span with the outcome of result.
+func endBatchSpan(span trace.Span, result *BatchResult) {
+	span.SetAttributes(
+		attribute.StringSlice("batch.merged", mrIDs(result.Merged)),
+		attribute.StringSlice("batch.culprits", mrIDs(result.Culprits)),
+		attribute.StringSlice("batch.conflicts", mrIDs(result.Conflicts)),
+	)
+	telemetry.EndSpan(span, result.Error)
+}
@@ -0,0 +1,74 @@
+package refinery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestDoMerge_ContinuesMRTrace(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	t.Cleanup(telemetry.UseSpanExporter(exp))
+	t.Setenv(telemetry.EnvTraceParent, "")
+
+	workDir, g, _ := testGitRepo(t)
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "a\n")
+	e := newTestEngineer(t, workDir, g)
+	e.config.Gates = map[string]*GateConfig{"test": {Cmd: "true"}}
+
+	mr := makeMR("mr-a", "feature-a", "main")
+	mr.TraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	if result := e.doMerge(context.Background(), mr, "main"); !result.Success {
+		t.Fatalf("doMerge: %s", result.Error)
+	}
+
+	spans := exp.GetSpans()
+	byName := make(map[string]tracetest.SpanStub)
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	merge, gate := byName["refinery.merge"], byName["refinery.gate"]
+	if merge.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("merge span parent = %s, want the MR's traceparent", merge.Parent.SpanID())
+	}
+	if gate.Parent.SpanID() != merge.SpanContext.SpanID() {
+		t.Errorf("gate span not a child of the merge span: %+v", spans)
+	}
+	for _, s := range spans {
+		if s.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("span %q in trace %s", s.Name, s.SpanContext.TraceID())
+		}
+	}
+}
+
+func TestProcessBatch_LinksMRTraces(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	t.Cleanup(telemetry.UseSpanExporter(exp))
+	t.Setenv(telemetry.EnvTraceParent, "")
+
+	workDir, g, _ := testGitRepo(t)
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "a\n")
+	createFeatureBranch(t, workDir, "feature-b", "b.txt", "b\n")
+	e := newTestEngineer(t, workDir, g)
+	e.config.Gates = map[string]*GateConfig{"test": {Cmd: "true"}}
+
+	a, b := makeMR("mr-a", "feature-a", "main"), makeMR("mr-b", "feature-b", "main")
+	a.TraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	b.TraceParent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	if result := e.ProcessBatch(context.Background(), []*MRInfo{a, b}, "main", nil); len(result.Merged) != 2 {
+		t.Fatalf("merged %d, err %v", len(result.Merged), result.Error)
+	}
+
+	for _, s := range exp.GetSpans() {
+		if s.Name != "refinery.batch" {
+			continue
+		}
+		if len(s.Links) != 2 {
+			t.Errorf("batch span links = %+v", s.Links)
+		}
+		return
+	}
+	t.Error("no refinery.batch span")
+}
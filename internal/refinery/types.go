@@ -93,7 +93,7 @@ const (
 var ValidPhaseTransitions = map[MRPhase][]MRPhase{
 	MRPhaseReady:     {MRPhaseClaimed},
 	MRPhaseClaimed:   {MRPhasePreparing, MRPhaseReady},
-	MRPhasePreparing: {MRPhasePrepared, MRPhaseFailed},
+	MRPhasePreparing: {MRPhasePrepared, MRPhaseFailed, MRPhaseReady}, // ready: speculative stack dropped
 	MRPhasePrepared:  {MRPhaseMerging, MRPhaseRejected, MRPhaseReady},
 	MRPhaseMerging:   {MRPhaseMerged, MRPhaseFailed},
 	MRPhaseFailed:    {MRPhaseReady},
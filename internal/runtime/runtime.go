@@ -15,7 +15,6 @@ import (
 	"github.com/steveyegge/gastown/internal/opencode"
 	"github.com/steveyegge/gastown/internal/pi"
 	"github.com/steveyegge/gastown/internal/templates/commands"
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 func init() {
@@ -128,8 +127,13 @@ func StartupFallbackCommands(role string, rc *config.RuntimeConfig) []string {
 	return []string{command}
 }
 
-// RunStartupFallback sends the startup fallback commands via tmux.
-func RunStartupFallback(t *tmux.Tmux, sessionID, role string, rc *config.RuntimeConfig) error {
+// Nudger delivers a message to an agent session; *tmux.Tmux is one.
+type Nudger interface {
+	NudgeSession(session, message string) error
+}
+
+// RunStartupFallback sends the startup fallback commands via t, usually tmux.
+func RunStartupFallback(t Nudger, sessionID, role string, rc *config.RuntimeConfig) error {
 	commands := StartupFallbackCommands(role, rc)
 	for _, cmd := range commands {
 		if err := t.NudgeSession(sessionID, cmd); err != nil {
@@ -0,0 +1,173 @@
+package sandbox
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultHomeReadWrite are the $HOME entries an agent may write by default:
+// Claude Code's state (sessions, todos, credentials refresh).
+var DefaultHomeReadWrite = []string{".claude", ".claude.json"}
+
+// DefaultHomeReadOnly are the $HOME entries exposed read-only by default.
+// They are mounted after the writable entries, so agent settings, hooks and
+// commands under ~/.claude stay read-only.
+//
+// ~/.ssh is deliberately absent: with the default host network, the user's
+// keys would let the agent reach every host they open. Towns whose agents
+// push over SSH opt in by listing it in home_read_only.
+var DefaultHomeReadOnly = []string{
+	".gitconfig",
+	".config/git",
+	".local/bin",
+	"go/bin",
+	".claude/settings.json",
+	".claude/settings.local.json",
+	".claude/hooks",
+	".claude/commands",
+	".claude/agents",
+	".claude/plugins",
+}
+
+// Config is the per-rig sandbox configuration (settings/config.json "sandbox").
+type Config struct {
+	// Enabled turns sandboxing on for the roles below.
+	Enabled bool `json:"enabled"`
+
+	// Roles lists the roles that run sandboxed. Default: ["polecat"].
+	Roles []string `json:"roles,omitempty"`
+
+	// Backend is "bwrap" or "unshare". Empty picks bwrap when installed,
+	// otherwise unshare.
+	Backend string `json:"backend,omitempty"`
+
+	// Network is "host" (default) or "none". With "none" the agent cannot
+	// reach the Dolt server or the model API unless they are reachable
+	// through a unix socket exposed in ReadWrite.
+	Network string `json:"network,omitempty"`
+
+	// ReadWrite lists extra host paths the agent may write, besides its
+	// worktree and the repository's git directory. "~/" expands to $HOME.
+	ReadWrite []string `json:"read_write,omitempty"`
+
+	// HomeReadWrite and HomeReadOnly list paths relative to $HOME exposed
+	// inside the otherwise empty private home. Nil uses the defaults
+	// (DefaultHomeReadWrite, DefaultHomeReadOnly); an empty list exposes
+	// nothing.
+	HomeReadWrite []string `json:"home_read_write,omitempty"`
+	HomeReadOnly  []string `json:"home_read_only,omitempty"`
+}
+
+// AppliesTo reports whether sessions of role run sandboxed.
+func (c *Config) AppliesTo(role string) bool {
+	if c == nil || !c.Enabled {
+		return false
+	}
+	if len(c.Roles) == 0 {
+		return role == "polecat"
+	}
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the backend, network mode and paths.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Backend {
+	case "", BackendBwrap, BackendUnshare:
+	default:
+		return fmt.Errorf("invalid sandbox backend %q (want bwrap or unshare)", c.Backend)
+	}
+	switch c.Network {
+	case "", NetworkHost, NetworkNone:
+	default:
+		return fmt.Errorf("invalid sandbox network %q (want host or none)", c.Network)
+	}
+	for _, p := range c.ReadWrite {
+		if !filepath.IsAbs(p) && !strings.HasPrefix(p, "~/") {
+			return fmt.Errorf("invalid sandbox read_write path %q (want absolute or ~/...)", p)
+		}
+	}
+	for _, list := range [][]string{c.HomeReadWrite, c.HomeReadOnly} {
+		for _, p := range list {
+			if p == "" || filepath.IsAbs(p) || strings.HasPrefix(filepath.Clean(p), "..") {
+				return fmt.Errorf("invalid sandbox home path %q (want a path inside $HOME)", p)
+			}
+		}
+	}
+	return nil
+}
+
+// Spec builds the sandbox for an agent working in workDir. writable lists
+// additional paths the agent must be able to write (such as a runtime
+// config directory); rootDir is the mount point used by the unshare backend.
+//
+// The town stays readable at its real location even when it lives under
+// $HOME. The git directory shared by the worktree is writable so commits
+// work, but its hooks and config stay read-only: other agents run them
+// outside the sandbox.
+func (c *Config) Spec(workDir, townRoot, home, rootDir string, writable ...string) Spec {
+	spec := Spec{
+		Backend: c.Backend,
+		Network: c.Network,
+		WorkDir: workDir,
+		Home:    home,
+		RootDir: rootDir,
+	}
+	if townRoot != "" && isUnder(townRoot, home) {
+		spec.Mounts = append(spec.Mounts, Mount{Path: townRoot})
+	}
+
+	homeRW, homeRO := c.HomeReadWrite, c.HomeReadOnly
+	if homeRW == nil {
+		homeRW = DefaultHomeReadWrite
+	}
+	if homeRO == nil {
+		homeRO = DefaultHomeReadOnly
+	}
+	for _, p := range homeRW {
+		spec.Mounts = append(spec.Mounts, Mount{Path: filepath.Join(home, p), Writable: true})
+	}
+	for _, p := range homeRO {
+		spec.Mounts = append(spec.Mounts, Mount{Path: filepath.Join(home, p)})
+	}
+
+	gitDir := gitCommonDir(workDir)
+	rw := append([]string{workDir, gitDir}, writable...)
+	rw = append(rw, c.ReadWrite...)
+	for _, p := range rw {
+		if strings.HasPrefix(p, "~/") {
+			p = filepath.Join(home, p[2:])
+		}
+		spec.Mounts = append(spec.Mounts, Mount{Path: p, Writable: true})
+	}
+	if gitDir != "" {
+		spec.Mounts = append(spec.Mounts,
+			Mount{Path: filepath.Join(gitDir, "hooks")},
+			Mount{Path: filepath.Join(gitDir, "config")})
+	}
+	return spec
+}
+
+// gitCommonDir returns the absolute git directory shared by the worktree at
+// dir, or "" if dir is not in a git repository.
+func gitCommonDir(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--path-format=absolute", "--git-common-dir").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func isUnder(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
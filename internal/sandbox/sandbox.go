@@ -0,0 +1,243 @@
+// Package sandbox wraps agent commands in Linux namespaces so an agent can
+// only write to its worktree.
+//
+// Inside the sandbox the whole filesystem is read-only except for the
+// configured writable paths, $HOME and /tmp are private tmpfs mounts, and
+// the network is optionally cut off. Two backends are supported: bubblewrap
+// (bwrap), and util-linux unshare with a generated mount script for hosts
+// without bwrap.
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Backends.
+const (
+	BackendBwrap   = "bwrap"
+	BackendUnshare = "unshare"
+)
+
+// Network modes.
+const (
+	NetworkHost = "host" // Share the host network (default)
+	NetworkNone = "none" // Private network namespace with only loopback
+)
+
+// ErrUnavailable is returned when no sandbox backend is installed.
+var ErrUnavailable = errors.New("no sandbox backend available (install bubblewrap or util-linux unshare)")
+
+// Mount exposes a host path inside the sandbox at the same location.
+// Mounts are applied in order, so a read-only mount can protect part of an
+// earlier writable one.
+type Mount struct {
+	Path     string
+	Writable bool
+}
+
+// Spec describes a sandbox.
+type Spec struct {
+	// Backend is BackendBwrap or BackendUnshare; empty picks bwrap when
+	// installed, otherwise unshare.
+	Backend string
+
+	// Network is NetworkHost or NetworkNone (empty means host).
+	Network string
+
+	// WorkDir is the working directory of the command inside the sandbox.
+	WorkDir string
+
+	// Home is the user's home directory, replaced by a private tmpfs.
+	Home string
+
+	// Mounts are the host paths exposed inside the sandbox. Paths that
+	// don't exist are skipped.
+	Mounts []Mount
+
+	// RootDir is an empty directory the unshare backend assembles the
+	// sandbox root on. Unused by bwrap.
+	RootDir string
+}
+
+// Resolve returns the backend to use: the requested one if it is installed,
+// or the first installed backend when backend is empty.
+func Resolve(backend string) (string, error) {
+	switch backend {
+	case "":
+		for _, b := range []string{BackendBwrap, BackendUnshare} {
+			if _, err := exec.LookPath(b); err == nil {
+				return b, nil
+			}
+		}
+		return "", ErrUnavailable
+	case BackendBwrap, BackendUnshare:
+		if _, err := exec.LookPath(backend); err != nil {
+			return "", fmt.Errorf("sandbox backend %s not found in PATH", backend)
+		}
+		return backend, nil
+	default:
+		return "", fmt.Errorf("unknown sandbox backend %q", backend)
+	}
+}
+
+// Wrap returns a shell command that runs command inside the sandbox.
+func Wrap(command string, spec Spec) (string, error) {
+	backend, err := Resolve(spec.Backend)
+	if err != nil {
+		return "", err
+	}
+	if spec.WorkDir == "" || spec.Home == "" {
+		return "", fmt.Errorf("sandbox needs a work directory and home directory")
+	}
+	switch spec.Network {
+	case "", NetworkHost, NetworkNone:
+	default:
+		return "", fmt.Errorf("unknown sandbox network mode %q", spec.Network)
+	}
+
+	mounts := existingMounts(spec.Mounts)
+	if backend == BackendBwrap {
+		return join(bwrapArgs(command, spec, mounts)), nil
+	}
+	if spec.RootDir == "" {
+		return "", fmt.Errorf("unshare sandbox needs a root directory")
+	}
+	if err := os.MkdirAll(spec.RootDir, 0755); err != nil {
+		return "", fmt.Errorf("creating sandbox root: %w", err)
+	}
+	return join(unshareArgs(command, spec, mounts)), nil
+}
+
+// Check verifies that the backend can create a sandbox on this host by
+// running a trivial command in one. Returns the backend checked.
+func Check(backend string) (string, error) {
+	backend, err := Resolve(backend)
+	if err != nil {
+		return "", err
+	}
+	var cmd *exec.Cmd
+	if backend == BackendBwrap {
+		cmd = exec.Command("bwrap", "--ro-bind", "/", "/", "--dev", "/dev", "--proc", "/proc",
+			"--unshare-pid", "--die-with-parent", "true")
+	} else {
+		help, _ := exec.Command("unshare", "--help").CombinedOutput()
+		if !strings.Contains(string(help), "--map-user") {
+			return backend, fmt.Errorf("unshare is too old (needs --map-user, util-linux 2.38+)")
+		}
+		cmd = exec.Command("unshare", "--map-root-user", "--mount", "--pid", "--fork", "true")
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return backend, fmt.Errorf("%s cannot create namespaces: %v %s", backend, err, strings.TrimSpace(string(out)))
+	}
+	return backend, nil
+}
+
+// existingMounts drops mounts whose host path doesn't exist, cleaning paths.
+func existingMounts(mounts []Mount) []Mount {
+	var out []Mount
+	for _, m := range mounts {
+		if m.Path == "" {
+			continue
+		}
+		p := filepath.Clean(m.Path)
+		if _, err := os.Stat(p); err != nil {
+			continue
+		}
+		out = append(out, Mount{Path: p, Writable: m.Writable})
+	}
+	return out
+}
+
+func bwrapArgs(command string, spec Spec, mounts []Mount) []string {
+	args := []string{
+		"bwrap",
+		"--die-with-parent",
+		"--unshare-pid",
+		"--unshare-ipc",
+		"--ro-bind", "/", "/",
+		"--dev", "/dev",
+		"--proc", "/proc",
+		"--tmpfs", "/tmp",
+		"--tmpfs", spec.Home,
+	}
+	if spec.Network == NetworkNone {
+		args = append(args, "--unshare-net")
+	}
+	for _, m := range mounts {
+		if m.Writable {
+			args = append(args, "--bind", m.Path, m.Path)
+		} else {
+			args = append(args, "--ro-bind", m.Path, m.Path)
+		}
+	}
+	return append(args, "--setenv", "HOME", spec.Home, "--chdir", spec.WorkDir, "--", "sh", "-c", command)
+}
+
+// unshareArgs builds the unshare invocation. As root of a new user
+// namespace it bind-mounts / onto RootDir, makes it read-only, lays the
+// private and exposed paths over it and pivots into it, then drops back to the
+// caller's uid in a nested user namespace to run the command.
+func unshareArgs(command string, spec Spec, mounts []Mount) []string {
+	r := spec.RootDir
+	var s strings.Builder
+	s.WriteString("set -e\n")
+	fmt.Fprintf(&s, "R=%s\n", quote(r))
+	s.WriteString("mount --make-rprivate /\n")
+	s.WriteString(`mount --rbind / "$R"` + "\n")
+	// Everything read-only, except the pseudo filesystems.
+	s.WriteString(`for m in $(awk '{print $5}' /proc/self/mountinfo | grep "^$R"); do` + "\n")
+	s.WriteString(`  case "$m" in *\\*|"$R"/dev|"$R"/dev/*|"$R"/proc|"$R"/proc/*|"$R"/sys|"$R"/sys/*) continue ;; esac` + "\n")
+	s.WriteString(`  mount -o remount,bind,ro "$m"` + "\n")
+	s.WriteString("done\n")
+	s.WriteString(`mount -t proc proc "$R/proc"` + "\n")
+	s.WriteString(`mount -t tmpfs tmpfs "$R/tmp"` + "\n")
+	home := `"$R"` + quote(spec.Home)
+	fmt.Fprintf(&s, "[ -d %s ] || mkdir -p %s\n", home, home)
+	fmt.Fprintf(&s, "mount -t tmpfs tmpfs %s\n", home)
+	for _, m := range mounts {
+		target := `"$R"` + quote(m.Path)
+		if fi, err := os.Stat(m.Path); err == nil && !fi.IsDir() {
+			fmt.Fprintf(&s, "[ -e %s ] || { mkdir -p \"$(dirname %s)\" && touch %s; }\n", target, target, target)
+		} else {
+			fmt.Fprintf(&s, "[ -d %s ] || mkdir -p %s\n", target, target)
+		}
+		fmt.Fprintf(&s, "mount --rbind %s %s\n", quote(m.Path), target)
+		if !m.Writable {
+			fmt.Fprintf(&s, "mount -o remount,bind,ro %s\n", target)
+		}
+	}
+	inner := "cd " + quote(spec.WorkDir) + " && " + command
+	// pivot_root rather than chroot: a chrooted process can't create the
+	// nested user namespace.
+	s.WriteString(`cd "$R" && pivot_root . . && umount -l . && cd /` + "\n")
+	fmt.Fprintf(&s, "HOME=%s exec unshare --user --map-user=%d --map-group=%d -- sh -c %s\n",
+		quote(spec.Home), os.Getuid(), os.Getgid(), quote(inner))
+
+	args := []string{"unshare", "--map-root-user", "--mount", "--pid", "--fork", "--kill-child"}
+	if spec.Network == NetworkNone {
+		args = append(args, "--net")
+	}
+	return append(args, "--", "sh", "-c", s.String())
+}
+
+// join quotes args into a shell command line.
+func join(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = quote(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// quote single-quotes s for sh when it contains anything but safe characters.
+func quote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:,+@%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,147 @@
+package sandbox
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBwrapArgs(t *testing.T) {
+	dir := t.TempDir()
+	work := filepath.Join(dir, "work")
+	hooks := filepath.Join(work, ".git", "hooks")
+	if err := os.MkdirAll(hooks, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	mounts := existingMounts([]Mount{
+		{Path: work, Writable: true},
+		{Path: hooks},
+		{Path: filepath.Join(dir, "missing"), Writable: true},
+		{Path: ""},
+	})
+	got := strings.Join(bwrapArgs("claude --x", Spec{Network: NetworkNone, WorkDir: work, Home: "/home/gt"}, mounts), " ")
+
+	for _, want := range []string{
+		"--ro-bind / / ",
+		"--tmpfs /home/gt ",
+		"--unshare-net ",
+		"--bind " + work + " " + work + " --ro-bind " + hooks + " " + hooks + " ",
+		"--chdir " + work + " -- sh -c claude --x",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("bwrap args missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "missing") {
+		t.Errorf("nonexistent mount should be skipped:\n%s", got)
+	}
+}
+
+func TestConfigSpec(t *testing.T) {
+	home := t.TempDir()
+	town := filepath.Join(home, "gt")
+	work := filepath.Join(town, "gastown", "polecats", "toast")
+	if err := os.MkdirAll(work, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{Enabled: true, ReadWrite: []string{"~/.cache/go-build"}, HomeReadOnly: []string{}}
+	spec := cfg.Spec(work, town, home, "/unused")
+
+	want := []Mount{
+		{Path: town},
+		{Path: filepath.Join(home, ".claude"), Writable: true},
+		{Path: filepath.Join(home, ".claude.json"), Writable: true},
+		{Path: work, Writable: true},
+		{Path: "", Writable: true}, // not a git repository
+		{Path: filepath.Join(home, ".cache/go-build"), Writable: true},
+	}
+	if len(spec.Mounts) != len(want) {
+		t.Fatalf("mounts = %+v, want %+v", spec.Mounts, want)
+	}
+	for i := range want {
+		if spec.Mounts[i] != want[i] {
+			t.Errorf("mount %d = %+v, want %+v", i, spec.Mounts[i], want[i])
+		}
+	}
+}
+
+func TestConfigAppliesTo(t *testing.T) {
+	var nilCfg *Config
+	if nilCfg.AppliesTo("polecat") {
+		t.Error("nil config should not apply")
+	}
+	if (&Config{Roles: []string{"polecat"}}).AppliesTo("polecat") {
+		t.Error("disabled config should not apply")
+	}
+	cfg := &Config{Enabled: true}
+	if !cfg.AppliesTo("polecat") || cfg.AppliesTo("crew") {
+		t.Error("default roles should be polecat only")
+	}
+	cfg.Roles = []string{"crew"}
+	if cfg.AppliesTo("polecat") || !cfg.AppliesTo("crew") {
+		t.Error("explicit roles should replace the default")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"empty", Config{}, false},
+		{"valid", Config{Backend: BackendUnshare, Network: NetworkNone, ReadWrite: []string{"/srv/cache", "~/.npm"}}, false},
+		{"bad backend", Config{Backend: "docker"}, true},
+		{"bad network", Config{Network: "lan"}, true},
+		{"relative read_write", Config{ReadWrite: []string{"cache"}}, true},
+		{"home escape", Config{HomeReadOnly: []string{"../other"}}, true},
+		{"absolute home path", Config{HomeReadWrite: []string{"/etc"}}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+// TestWrap_Unshare runs a command in a real unshare sandbox when the host
+// allows user namespaces.
+func TestWrap_Unshare(t *testing.T) {
+	if _, err := Check(BackendUnshare); err != nil {
+		t.Skipf("unshare sandbox unavailable: %v", err)
+	}
+	dir := t.TempDir()
+	home := filepath.Join(dir, "home")
+	work := filepath.Join(home, "work")
+	outside := filepath.Join(home, "outside")
+	for _, d := range []string{work, outside} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	command, err := Wrap(`touch inside && (touch `+outside+`/x 2>/dev/null || echo outside-ro) && ls -A "$HOME"`, Spec{
+		Backend: BackendUnshare,
+		WorkDir: work,
+		Home:    home,
+		Mounts:  []Mount{{Path: work, Writable: true}},
+		RootDir: filepath.Join(dir, "root"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command("sh", "-c", command).CombinedOutput()
+	if err != nil {
+		t.Fatalf("sandboxed command failed: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(work, "inside")); err != nil {
+		t.Error("write in the work dir should reach the host")
+	}
+	if got := strings.TrimSpace(string(out)); got != "outside-ro\nwork" {
+		t.Errorf("output = %q, want outside-ro and a home containing only work", got)
+	}
+}
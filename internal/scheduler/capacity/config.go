@@ -28,6 +28,37 @@ type SchedulerConfig struct {
 	// SpawnDelay is the delay between spawns to prevent Dolt lock contention.
 	// Default: "0s".
 	SpawnDelay string `json:"spawn_delay,omitempty"`
+
+	// RigMaxPolecats caps concurrent polecats per rig, on top of MaxPolecats.
+	// Rigs not listed are limited only by MaxPolecats.
+	RigMaxPolecats map[string]int `json:"rig_max_polecats,omitempty"`
+
+	// AccountMaxPolecats caps concurrent polecats per account handle
+	// (beads without --account count against the default account).
+	AccountMaxPolecats map[string]int `json:"account_max_polecats,omitempty"`
+
+	// AgentMaxPolecats caps concurrent polecats per agent preset,
+	// e.g. {"codex": 3}. Beads without --agent count against the town default.
+	AgentMaxPolecats map[string]int `json:"agent_max_polecats,omitempty"`
+
+	// Policy selects which ready beads get the free slots each cycle:
+	// "fifo" (default, arrival order), "priority" (P0 first), or "fair"
+	// (weighted share per rig, then priority).
+	Policy string `json:"policy,omitempty"`
+
+	// RigWeights sets each rig's share under the "fair" policy.
+	// Rigs not listed have weight 1.
+	RigWeights map[string]int `json:"rig_weights,omitempty"`
+
+	// MaxPerConvoy caps how many polecats may work one convoy at once,
+	// counting running polecats plus beads picked this cycle, so a large
+	// convoy can't take every slot. 0 = unlimited.
+	MaxPerConvoy int `json:"max_per_convoy,omitempty"`
+
+	// AgingInterval raises a waiting bead's effective priority by one level
+	// for each interval it has been queued, so low-priority work is never
+	// starved. Applies to "priority" and "fair". Empty = no aging.
+	AgingInterval string `json:"aging_interval,omitempty"`
 }
 
 // DefaultSchedulerConfig returns a SchedulerConfig with sensible defaults.
@@ -66,6 +97,22 @@ func (c *SchedulerConfig) GetSpawnDelay() time.Duration {
 	return ParseDurationOrDefault(c.SpawnDelay, 0)
 }
 
+// GetPolicy returns Policy or the default ("fifo") if unset.
+func (c *SchedulerConfig) GetPolicy() string {
+	if c == nil || c.Policy == "" {
+		return PolicyFIFO
+	}
+	return c.Policy
+}
+
+// GetAgingInterval returns AgingInterval as a duration, defaulting to 0 (no aging).
+func (c *SchedulerConfig) GetAgingInterval() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return ParseDurationOrDefault(c.AgingInterval, 0)
+}
+
 // IsDeferred returns true when the scheduler is configured for deferred dispatch
 // (max_polecats > 0). Returns false for direct dispatch (-1) and disabled (0).
 func (c *SchedulerConfig) IsDeferred() bool {
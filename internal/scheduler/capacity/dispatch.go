@@ -40,6 +40,14 @@ type DispatchCycle struct {
 
 	// SpawnDelay between dispatches.
 	SpawnDelay time.Duration
+
+	// Policy picks which ready items fill the free slots.
+	// nil = arrival order (same as PolicyFIFO without a convoy cap).
+	Policy DispatchPolicy
+
+	// PolicyEnv returns cycle-wide inputs for Policy (running polecats per
+	// rig, concurrency limits, current time). Optional.
+	PolicyEnv func() PolicyEnv
 }
 
 // DispatchReport summarizes the result of one dispatch cycle.
@@ -47,7 +55,7 @@ type DispatchReport struct {
 	Dispatched int
 	Failed     int
 	Skipped    int
-	Reason     string // "capacity" | "batch" | "ready" | "none"
+	Reason     string // "capacity" | "batch" | "ready" | "policy" | "limits" | "none"
 }
 
 // Plan returns the dispatch plan without executing. Used for dry-run.
@@ -62,7 +70,14 @@ func (c *DispatchCycle) Plan() (DispatchPlan, error) {
 		return DispatchPlan{}, fmt.Errorf("querying pending: %w", err)
 	}
 
-	return PlanDispatch(cap, c.BatchSize, pending), nil
+	if c.Policy == nil {
+		return PlanDispatch(cap, c.BatchSize, pending), nil
+	}
+	var env PolicyEnv
+	if c.PolicyEnv != nil {
+		env = c.PolicyEnv()
+	}
+	return PlanDispatchWithPolicy(cap, c.BatchSize, pending, c.Policy, env), nil
 }
 
 // onSuccessRetries is the number of times to retry OnSuccess before giving up.
@@ -0,0 +1,190 @@
+package capacity
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Limit kinds reported in LimitUsage.
+const (
+	LimitRig     = "rig"
+	LimitAccount = "account"
+	LimitAgent   = "agent"
+)
+
+// ActivePolecat describes a running polecat for limit accounting.
+// Empty Account/Agent mean the town defaults.
+type ActivePolecat struct {
+	Rig     string
+	Account string
+	Agent   string
+}
+
+// LimitUsage reports how much of one concurrency limit is in use.
+type LimitUsage struct {
+	Kind   string `json:"kind"`
+	Key    string `json:"key"`
+	Active int    `json:"active"`
+	Max    int    `json:"max"`
+
+	// ResetsAt is set for accounts that quota tracking reports as rate-limited.
+	ResetsAt    string `json:"resets_at,omitempty"`
+	RateLimited bool   `json:"rate_limited,omitempty"`
+}
+
+// Limiter enforces per-rig, per-account and per-agent concurrency limits
+// alongside the town-wide MaxPolecats. It starts from the running polecats and
+// counts every bead taken during the cycle, so one plan never overshoots.
+// Beads whose account is rate-limited are held back until the limit clears,
+// as are beads an optional hold (e.g. an exceeded spend budget) rejects.
+type Limiter struct {
+	max            map[string]map[string]int // kind → key → max
+	active         map[string]map[string]int // kind → key → running + taken
+	rateLimited    map[string]string         // account → resets_at
+	hold           func(PendingBead) (string, bool)
+	defaultAccount string
+	defaultAgent   string
+}
+
+// NewLimiter builds a Limiter from scheduler config and the current state.
+// rateLimited maps account handles to their reset time (see quota.Manager).
+// defaultAccount and defaultAgent stand in for beads and polecats that don't
+// name one.
+func NewLimiter(cfg *SchedulerConfig, running []ActivePolecat, rateLimited map[string]string, defaultAccount, defaultAgent string) *Limiter {
+	l := &Limiter{
+		max:            make(map[string]map[string]int),
+		active:         make(map[string]map[string]int),
+		rateLimited:    rateLimited,
+		defaultAccount: defaultAccount,
+		defaultAgent:   defaultAgent,
+	}
+	if cfg != nil {
+		l.max[LimitRig] = cfg.RigMaxPolecats
+		l.max[LimitAccount] = cfg.AccountMaxPolecats
+		l.max[LimitAgent] = cfg.AgentMaxPolecats
+	}
+	for _, kind := range []string{LimitRig, LimitAccount, LimitAgent} {
+		l.active[kind] = make(map[string]int)
+	}
+	for _, p := range running {
+		l.take(p.Rig, p.Account, p.Agent)
+	}
+	return l
+}
+
+// SetHold installs a check run before the concurrency limits. When hold
+// returns true, the bead waits with the returned reason.
+func (l *Limiter) SetHold(hold func(PendingBead) (string, bool)) {
+	l.hold = hold
+}
+
+// Admit reports whether b may be dispatched now. When it may not, the
+// returned reason says which limit holds it back.
+func (l *Limiter) Admit(b PendingBead) (string, bool) {
+	if l == nil {
+		return "", true
+	}
+	if l.hold != nil {
+		if reason, held := l.hold(b); held {
+			return reason, false
+		}
+	}
+	rig, account, agent := l.keys(b)
+	if account != "" {
+		if resetsAt, limited := l.rateLimited[account]; limited {
+			if resetsAt == "" {
+				return fmt.Sprintf("account %s rate-limited", account), false
+			}
+			return fmt.Sprintf("account %s rate-limited (resets %s)", account, resetsAt), false
+		}
+	}
+	for _, k := range []struct{ kind, key string }{{LimitRig, rig}, {LimitAccount, account}, {LimitAgent, agent}} {
+		max, ok := l.max[k.kind][k.key]
+		if !ok || max <= 0 || k.key == "" {
+			continue
+		}
+		if l.active[k.kind][k.key] >= max {
+			return fmt.Sprintf("%s %s at limit (%d/%d)", k.kind, k.key, l.active[k.kind][k.key], max), false
+		}
+	}
+	return "", true
+}
+
+// Take records that b is being dispatched this cycle.
+func (l *Limiter) Take(b PendingBead) {
+	if l == nil {
+		return
+	}
+	l.take(l.keys(b))
+}
+
+func (l *Limiter) take(rig, account, agent string) {
+	if account == "" {
+		account = l.defaultAccount
+	}
+	if agent == "" {
+		agent = l.defaultAgent
+	}
+	l.active[LimitRig][rig]++
+	if account != "" {
+		l.active[LimitAccount][account]++
+	}
+	if agent != "" {
+		l.active[LimitAgent][agent]++
+	}
+}
+
+// keys returns the rig, account and agent a bead will run under.
+func (l *Limiter) keys(b PendingBead) (rig, account, agent string) {
+	rig = b.TargetRig
+	if b.Context != nil {
+		account = b.Context.Account
+		agent = b.Context.Agent
+	}
+	if account == "" {
+		account = l.defaultAccount
+	}
+	if agent == "" {
+		agent = l.defaultAgent
+	}
+	return rig, account, agent
+}
+
+// Usage lists every configured limit with its current use, plus any
+// rate-limited accounts, sorted by kind then key.
+func (l *Limiter) Usage() []LimitUsage {
+	if l == nil {
+		return nil
+	}
+	byKey := make(map[[2]string]*LimitUsage)
+	for _, kind := range []string{LimitRig, LimitAccount, LimitAgent} {
+		for key, max := range l.max[kind] {
+			if max <= 0 {
+				continue
+			}
+			byKey[[2]string{kind, key}] = &LimitUsage{Kind: kind, Key: key, Active: l.active[kind][key], Max: max}
+		}
+	}
+	for account, resetsAt := range l.rateLimited {
+		u, ok := byKey[[2]string{LimitAccount, account}]
+		if !ok {
+			u = &LimitUsage{Kind: LimitAccount, Key: account, Active: l.active[LimitAccount][account]}
+			byKey[[2]string{LimitAccount, account}] = u
+		}
+		u.RateLimited = true
+		u.ResetsAt = resetsAt
+	}
+
+	usage := make([]LimitUsage, 0, len(byKey))
+	for _, u := range byKey {
+		usage = append(usage, *u)
+	}
+	order := map[string]int{LimitRig: 0, LimitAccount: 1, LimitAgent: 2}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Kind != usage[j].Kind {
+			return order[usage[i].Kind] < order[usage[j].Kind]
+		}
+		return usage[i].Key < usage[j].Key
+	})
+	return usage
+}
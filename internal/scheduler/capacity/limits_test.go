@@ -0,0 +1,141 @@
+package capacity
+
+import (
+	"strings"
+	"testing"
+)
+
+func withRuntime(b PendingBead, account, agent string) PendingBead {
+	b.Context.Account = account
+	b.Context.Agent = agent
+	return b
+}
+
+func TestLimiterAdmit(t *testing.T) {
+	cfg := &SchedulerConfig{
+		RigMaxPolecats:     map[string]int{"small": 1},
+		AccountMaxPolecats: map[string]int{"work": 2},
+		AgentMaxPolecats:   map[string]int{"codex": 1},
+	}
+	running := []ActivePolecat{
+		{Rig: "small"},
+		{Rig: "big", Account: "work"},
+		{Rig: "big", Agent: "codex"},
+	}
+	l := NewLimiter(cfg, running, map[string]string{"personal": "7pm"}, "work", "claude")
+
+	tests := []struct {
+		name       string
+		bead       PendingBead
+		wantOK     bool
+		wantReason string
+	}{
+		{"rig at limit", pending("a", "small", 2, "", ""), false, "rig small at limit (1/1)"},
+		// Running polecats without an account count against the default ("work").
+		{"default account at limit", pending("b", "big", 2, "", ""), false, "account work at limit (3/2)"},
+		{"agent at limit", withRuntime(pending("c", "big", 2, "", ""), "other", "codex"), false, "agent codex at limit (1/1)"},
+		{"rate-limited account waits", withRuntime(pending("d", "big", 2, "", ""), "personal", ""), false, "account personal rate-limited (resets 7pm)"},
+		{"unlimited account", withRuntime(pending("e", "big", 2, "", ""), "other", ""), true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, ok := l.Admit(tt.bead)
+			if ok != tt.wantOK || reason != tt.wantReason {
+				t.Errorf("Admit() = (%q, %v), want (%q, %v)", reason, ok, tt.wantReason, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLimiterTakeCountsWithinCycle(t *testing.T) {
+	cfg := &SchedulerConfig{AgentMaxPolecats: map[string]int{"codex": 2}}
+	l := NewLimiter(cfg, nil, nil, "", "claude")
+
+	b := withRuntime(pending("a", "r", 2, "", ""), "", "codex")
+	for i := 0; i < 2; i++ {
+		if _, ok := l.Admit(b); !ok {
+			t.Fatalf("take %d: expected admit", i)
+		}
+		l.Take(b)
+	}
+	if _, ok := l.Admit(b); ok {
+		t.Error("expected third codex bead to be held back")
+	}
+	if _, ok := l.Admit(pending("b", "r", 2, "", "")); !ok {
+		t.Error("expected default-agent bead to be admitted")
+	}
+}
+
+func TestLimiterHold(t *testing.T) {
+	l := NewLimiter(nil, nil, nil, "", "claude")
+	l.SetHold(func(b PendingBead) (string, bool) {
+		if b.TargetRig == "spendy" {
+			return "rig spendy budget exceeded", true
+		}
+		return "", false
+	})
+
+	if reason, ok := l.Admit(pending("a", "spendy", 2, "", "")); ok || reason != "rig spendy budget exceeded" {
+		t.Errorf("Admit(spendy) = (%q, %v), want held by budget", reason, ok)
+	}
+	if _, ok := l.Admit(pending("b", "frugal", 2, "", "")); !ok {
+		t.Error("expected bead in another rig to be admitted")
+	}
+}
+
+func TestLimiterUsage(t *testing.T) {
+	cfg := &SchedulerConfig{
+		RigMaxPolecats:   map[string]int{"gastown": 3},
+		AgentMaxPolecats: map[string]int{"codex": 3},
+	}
+	running := []ActivePolecat{{Rig: "gastown", Agent: "codex"}, {Rig: "gastown"}}
+	l := NewLimiter(cfg, running, map[string]string{"work": "7pm"}, "", "claude")
+
+	var got []string
+	for _, u := range l.Usage() {
+		s := u.Kind + ":" + u.Key
+		if u.Max > 0 {
+			s += ":" + strings.Repeat("x", u.Active) + "/" + strings.Repeat("x", u.Max)
+		}
+		if u.RateLimited {
+			s += ":limited@" + u.ResetsAt
+		}
+		got = append(got, s)
+	}
+	want := []string{"rig:gastown:xx/xxx", "account:work:limited@7pm", "agent:codex:x/xxx"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Usage() = %v, want %v", got, want)
+	}
+}
+
+func TestPlanDispatchWithPolicy_Limits(t *testing.T) {
+	ready := []PendingBead{
+		withRuntime(pending("c1", "r", 2, "", ""), "", "codex"),
+		withRuntime(pending("c2", "r", 2, "", ""), "", "codex"),
+		pending("x", "r", 2, "", ""),
+	}
+	cfg := &SchedulerConfig{AgentMaxPolecats: map[string]int{"codex": 1}}
+	policy := mustPolicy(t, cfg)
+
+	// Limit leaves room for other work in the same cycle.
+	env := PolicyEnv{Limits: NewLimiter(cfg, nil, nil, "", "claude")}
+	plan := PlanDispatchWithPolicy(5, 2, ready, policy, env)
+	if got := strings.Join(selectedIDs(plan), ","); got != "c1,x" {
+		t.Errorf("ToDispatch = %s, want c1,x", got)
+	}
+
+	// Limit leaves slots unfilled → reason "limits".
+	env = PolicyEnv{Limits: NewLimiter(cfg, []ActivePolecat{{Rig: "r", Agent: "codex"}}, nil, "", "claude")}
+	plan = PlanDispatchWithPolicy(5, 3, ready, policy, env)
+	if got := strings.Join(selectedIDs(plan), ","); got != "x" {
+		t.Errorf("ToDispatch = %s, want x", got)
+	}
+	if plan.Reason != "limits" {
+		t.Errorf("Reason = %q, want limits", plan.Reason)
+	}
+	for _, d := range plan.Decisions {
+		if !d.Selected && !d.Limited {
+			t.Errorf("decision for %s should be marked Limited", d.Bead.WorkBeadID)
+		}
+	}
+}
@@ -10,6 +10,7 @@ type PendingBead struct {
 	TargetRig   string
 	Description string
 	Labels      []string
+	Priority    int                 // Work bead priority (0 = P0, highest); used by dispatch policies
 	Context     *SlingContextFields // Parsed sling params from context bead
 }
 
@@ -34,6 +35,13 @@ type SlingContextFields struct {
 	Mode             string `json:"mode,omitempty"`
 	DispatchFailures int    `json:"dispatch_failures,omitempty"`
 	LastFailure      string `json:"last_failure,omitempty"`
+	TraceParent      string `json:"trace_parent,omitempty"` // W3C traceparent of the scheduling gt sling
+
+	// Time window and recurrence (all optional).
+	NotBefore         string `json:"not_before,omitempty"`         // RFC3339; held until then
+	Deadline          string `json:"deadline,omitempty"`           // RFC3339; escalate if still queued after
+	Recurrence        string `json:"recurrence,omitempty"`         // Cron rule; re-enqueued after each dispatch
+	DeadlineEscalated bool   `json:"deadline_escalated,omitempty"` // Deadline escalation already sent
 }
 
 // LabelSlingContext is the label used to identify sling context beads.
@@ -43,7 +51,11 @@ const LabelSlingContext = "gt:sling-context"
 type DispatchPlan struct {
 	ToDispatch []PendingBead
 	Skipped    int
-	Reason     string // "capacity" | "batch" | "ready" | "none"
+	Reason     string // "capacity" | "batch" | "ready" | "policy" | "limits" | "none"
+
+	// Decisions explains, per ready bead, why it was or wasn't picked.
+	// Only set by PlanDispatchWithPolicy.
+	Decisions []BeadDecision
 }
 
 // FailureAction indicates what to do after a dispatch failure.
@@ -121,6 +133,45 @@ func PlanDispatch(availableCapacity, batchSize int, ready []PendingBead) Dispatc
 	}
 }
 
+// PlanDispatchWithPolicy is PlanDispatch with the choice of beads delegated to
+// a DispatchPolicy. The number of free slots is computed exactly as in
+// PlanDispatch; the policy decides which beads fill them. Reason is "policy"
+// when the policy left slots unfilled (e.g. the per-convoy cap), or "limits"
+// when per-rig/account/agent limits or rate-limited accounts were involved.
+func PlanDispatchWithPolicy(availableCapacity, batchSize int, ready []PendingBead, policy DispatchPolicy, env PolicyEnv) DispatchPlan {
+	plan := PlanDispatch(availableCapacity, batchSize, ready)
+	if len(ready) == 0 || policy == nil {
+		return plan
+	}
+
+	slots := len(plan.ToDispatch)
+	plan.Decisions = policy.Select(ready, slots, env)
+	plan.ToDispatch = nil
+	for _, d := range plan.Decisions {
+		if d.Selected {
+			plan.ToDispatch = append(plan.ToDispatch, d.Bead)
+		}
+	}
+	plan.Skipped = len(ready) - len(plan.ToDispatch)
+	if len(plan.ToDispatch) < slots {
+		plan.Reason = "policy"
+		if anyLimited(plan.Decisions) {
+			plan.Reason = "limits"
+		}
+	}
+	return plan
+}
+
+// anyLimited reports whether a Limiter held back any bead.
+func anyLimited(decisions []BeadDecision) bool {
+	for _, d := range decisions {
+		if d.Limited {
+			return true
+		}
+	}
+	return false
+}
+
 // NoRetryPolicy returns a FailurePolicy that always quarantines on first failure.
 func NoRetryPolicy() FailurePolicy {
 	return func(failures int) FailureAction {
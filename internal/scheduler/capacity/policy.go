@@ -0,0 +1,317 @@
+package capacity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Dispatch policy names accepted in SchedulerConfig.Policy.
+const (
+	// PolicyFIFO dispatches beads in arrival (enqueue) order. Default.
+	PolicyFIFO = "fifo"
+	// PolicyPriority dispatches the highest-priority beads first (P0 before P4).
+	PolicyPriority = "priority"
+	// PolicyFair splits slots between rigs in proportion to their weights,
+	// counting polecats already running, then orders each rig by priority.
+	PolicyFair = "fair"
+)
+
+// DefaultBeadPriority is assumed when a work bead's priority is unknown (P2).
+const DefaultBeadPriority = 2
+
+// BeadDecision records whether a ready bead was picked this cycle and why.
+type BeadDecision struct {
+	Bead     PendingBead
+	Selected bool
+	Reason   string
+
+	// Limited is true when a per-rig/account/agent limit or a rate-limited
+	// account held the bead back.
+	Limited bool
+}
+
+// PolicyEnv carries cycle-wide inputs a policy may consult.
+type PolicyEnv struct {
+	// Now is the reference time for aging. Zero means time.Now().
+	Now time.Time
+
+	// ActiveByRig counts polecats currently running per rig.
+	ActiveByRig map[string]int
+
+	// ActiveByConvoy counts polecats currently running work from each convoy.
+	// They count toward MaxPerConvoy.
+	ActiveByConvoy map[string]int
+
+	// Limits enforces per-rig/account/agent limits and rate-limited accounts.
+	// nil = no limits beyond the slot count.
+	Limits *Limiter
+}
+
+// DispatchPolicy picks up to slots beads from ready and explains every decision.
+// Decisions must cover every ready bead, selected ones first in dispatch order.
+type DispatchPolicy interface {
+	Name() string
+	Select(ready []PendingBead, slots int, env PolicyEnv) []BeadDecision
+}
+
+// PolicyFromConfig builds the dispatch policy described by cfg.
+func PolicyFromConfig(cfg *SchedulerConfig) (DispatchPolicy, error) {
+	name := cfg.GetPolicy()
+	if !IsValidPolicy(name) {
+		return nil, fmt.Errorf("unknown scheduler policy %q (expected %s, %s or %s)", name, PolicyFIFO, PolicyPriority, PolicyFair)
+	}
+	var weights map[string]int
+	var maxPerConvoy int
+	if cfg != nil {
+		weights = cfg.RigWeights
+		maxPerConvoy = cfg.MaxPerConvoy
+	}
+	return &policy{
+		name:         name,
+		rigWeights:   weights,
+		maxPerConvoy: maxPerConvoy,
+		aging:        cfg.GetAgingInterval(),
+	}, nil
+}
+
+// IsValidPolicy reports whether name is a known dispatch policy.
+func IsValidPolicy(name string) bool {
+	switch name {
+	case PolicyFIFO, PolicyPriority, PolicyFair:
+		return true
+	}
+	return false
+}
+
+// policy implements the built-in dispatch policies. Ordering depends on name;
+// the per-convoy cap, concurrency limits and aging apply to all of them.
+type policy struct {
+	name         string
+	rigWeights   map[string]int
+	maxPerConvoy int
+	aging        time.Duration
+}
+
+func (p *policy) Name() string { return p.name }
+
+// candidate is a ready bead annotated with its ordering inputs.
+type candidate struct {
+	bead      PendingBead
+	arrival   int // position in the ready list (arrival order)
+	enqueued  time.Time
+	effective int // priority after aging
+}
+
+// Select implements DispatchPolicy.
+func (p *policy) Select(ready []PendingBead, slots int, env PolicyEnv) []BeadDecision {
+	now := env.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	cands := make([]*candidate, len(ready))
+	for i, b := range ready {
+		cands[i] = p.annotate(b, i, now)
+	}
+	if p.name != PolicyFIFO {
+		sort.SliceStable(cands, func(i, j int) bool { return p.less(cands[i], cands[j]) })
+	}
+
+	if p.name == PolicyFair {
+		return p.selectFair(cands, slots, env)
+	}
+
+	var selected, skipped []BeadDecision
+	perConvoy := activeByConvoy(env)
+	for _, c := range cands {
+		if len(selected) >= slots {
+			skipped = append(skipped, BeadDecision{Bead: c.bead, Reason: noSlotReason(slots)})
+			continue
+		}
+		if d, ok := p.admit(c.bead, perConvoy, env); !ok {
+			skipped = append(skipped, d)
+			continue
+		}
+		perConvoy[convoyOf(c.bead)]++
+		env.Limits.Take(c.bead)
+		selected = append(selected, BeadDecision{Bead: c.bead, Selected: true, Reason: p.orderReason(c)})
+	}
+	return append(selected, skipped...)
+}
+
+// selectFair hands out slots one at a time to the rig with the lowest
+// weighted load, where load counts running polecats plus beads picked so far.
+func (p *policy) selectFair(cands []*candidate, slots int, env PolicyEnv) []BeadDecision {
+	queues := make(map[string][]*candidate)
+	var rigs []string
+	for _, c := range cands {
+		rig := c.bead.TargetRig
+		if _, ok := queues[rig]; !ok {
+			rigs = append(rigs, rig)
+		}
+		queues[rig] = append(queues[rig], c)
+	}
+	sort.Strings(rigs)
+
+	load := make(map[string]int)
+	for _, rig := range rigs {
+		load[rig] = env.ActiveByRig[rig]
+	}
+
+	var selected, skipped []BeadDecision
+	perConvoy := activeByConvoy(env)
+	for len(selected) < slots {
+		best := ""
+		for _, rig := range rigs {
+			// Drop heads blocked by a cap or limit so the rig's next bead can compete.
+			for len(queues[rig]) > 0 {
+				d, ok := p.admit(queues[rig][0].bead, perConvoy, env)
+				if ok {
+					break
+				}
+				skipped = append(skipped, d)
+				queues[rig] = queues[rig][1:]
+			}
+			if len(queues[rig]) == 0 {
+				continue
+			}
+			if best == "" || p.weightedLoad(rig, load) < p.weightedLoad(best, load) {
+				best = rig
+			}
+		}
+		if best == "" {
+			break
+		}
+
+		c := queues[best][0]
+		queues[best] = queues[best][1:]
+		perConvoy[convoyOf(c.bead)]++
+		env.Limits.Take(c.bead)
+		selected = append(selected, BeadDecision{
+			Bead:     c.bead,
+			Selected: true,
+			Reason: fmt.Sprintf("fair share: %s had %d running/picked (weight %d); %s",
+				displayRig(best), load[best], p.weight(best), p.orderReason(c)),
+		})
+		load[best]++
+	}
+
+	for _, rig := range rigs {
+		for _, c := range queues[rig] {
+			reason := noSlotReason(slots)
+			if slots > 0 {
+				reason += fmt.Sprintf("; %s has %d running/picked (weight %d)", displayRig(rig), load[rig], p.weight(rig))
+			}
+			skipped = append(skipped, BeadDecision{Bead: c.bead, Reason: reason})
+		}
+	}
+	return append(selected, skipped...)
+}
+
+// weightedLoad is the rig's load after taking one more slot, divided by its
+// weight. The rig with the lowest value has the strongest claim on the slot.
+func (p *policy) weightedLoad(rig string, load map[string]int) float64 {
+	return float64(load[rig]+1) / float64(p.weight(rig))
+}
+
+func (p *policy) weight(rig string) int {
+	if w, ok := p.rigWeights[rig]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
+// annotate computes a candidate's arrival, enqueue time and aged priority.
+func (p *policy) annotate(b PendingBead, arrival int, now time.Time) *candidate {
+	c := &candidate{bead: b, arrival: arrival, effective: b.Priority}
+	if b.Context != nil && b.Context.EnqueuedAt != "" {
+		if t, err := time.Parse(time.RFC3339, b.Context.EnqueuedAt); err == nil {
+			c.enqueued = t
+		}
+	}
+	if p.aging > 0 && !c.enqueued.IsZero() && now.After(c.enqueued) {
+		c.effective = b.Priority - int(now.Sub(c.enqueued)/p.aging)
+		if c.effective < 0 {
+			c.effective = 0
+		}
+	}
+	return c
+}
+
+// less orders candidates by aged priority, then oldest first.
+func (p *policy) less(a, b *candidate) bool {
+	if a.effective != b.effective {
+		return a.effective < b.effective
+	}
+	if !a.enqueued.Equal(b.enqueued) {
+		if a.enqueued.IsZero() || b.enqueued.IsZero() {
+			return !a.enqueued.IsZero()
+		}
+		return a.enqueued.Before(b.enqueued)
+	}
+	return a.arrival < b.arrival
+}
+
+// orderReason describes where a candidate sits in the policy's ordering.
+func (p *policy) orderReason(c *candidate) string {
+	if p.name == PolicyFIFO {
+		return fmt.Sprintf("arrival order #%d", c.arrival+1)
+	}
+	if c.effective != c.bead.Priority {
+		return fmt.Sprintf("P%d (aged from P%d)", c.effective, c.bead.Priority)
+	}
+	return fmt.Sprintf("P%d", c.effective)
+}
+
+// admit reports whether b may take a slot given the per-convoy cap and limits.
+// When it may not, the returned decision explains why.
+func (p *policy) admit(b PendingBead, perConvoy map[string]int, env PolicyEnv) (BeadDecision, bool) {
+	if reason, capped := p.convoyCapped(b, perConvoy); capped {
+		return BeadDecision{Bead: b, Reason: reason}, false
+	}
+	if reason, ok := env.Limits.Admit(b); !ok {
+		return BeadDecision{Bead: b, Reason: reason, Limited: true}, false
+	}
+	return BeadDecision{}, true
+}
+
+// convoyCapped reports whether dispatching b would exceed the per-convoy cap.
+// perConvoy counts running polecats plus beads picked this cycle.
+func (p *policy) convoyCapped(b PendingBead, perConvoy map[string]int) (string, bool) {
+	convoy := convoyOf(b)
+	if p.maxPerConvoy <= 0 || convoy == "" || perConvoy[convoy] < p.maxPerConvoy {
+		return "", false
+	}
+	return fmt.Sprintf("convoy %s at cap (%d running/picked, max %d)", convoy, perConvoy[convoy], p.maxPerConvoy), true
+}
+
+// activeByConvoy returns a copy of env.ActiveByConvoy to count picks into.
+func activeByConvoy(env PolicyEnv) map[string]int {
+	counts := make(map[string]int, len(env.ActiveByConvoy))
+	for convoy, n := range env.ActiveByConvoy {
+		counts[convoy] = n
+	}
+	return counts
+}
+
+func convoyOf(b PendingBead) string {
+	if b.Context == nil {
+		return ""
+	}
+	return b.Context.Convoy
+}
+
+func displayRig(rig string) string {
+	if rig == "" {
+		return "(no rig)"
+	}
+	return rig
+}
+
+func noSlotReason(slots int) string {
+	if slots <= 0 {
+		return "no free capacity"
+	}
+	return fmt.Sprintf("all %d slot(s) this cycle taken", slots)
+}
@@ -0,0 +1,275 @@
+package capacity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func pending(id, rig string, priority int, enqueuedAt, convoy string) PendingBead {
+	return PendingBead{
+		ID:         "ctx-" + id,
+		WorkBeadID: id,
+		TargetRig:  rig,
+		Priority:   priority,
+		Context:    &SlingContextFields{WorkBeadID: id, TargetRig: rig, EnqueuedAt: enqueuedAt, Convoy: convoy},
+	}
+}
+
+func selectedIDs(plan DispatchPlan) []string {
+	var ids []string
+	for _, b := range plan.ToDispatch {
+		ids = append(ids, b.WorkBeadID)
+	}
+	return ids
+}
+
+func mustPolicy(t *testing.T, cfg *SchedulerConfig) DispatchPolicy {
+	t.Helper()
+	p, err := PolicyFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("PolicyFromConfig: %v", err)
+	}
+	return p
+}
+
+func TestPlanDispatchWithPolicy(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(ago time.Duration) string { return now.Add(-ago).Format(time.RFC3339) }
+
+	tests := []struct {
+		name       string
+		cfg        *SchedulerConfig
+		capacity   int
+		batch      int
+		active     map[string]int
+		convoys    map[string]int
+		ready      []PendingBead
+		want       []string
+		wantReason string
+	}{
+		{
+			name:     "fifo keeps arrival order",
+			cfg:      nil,
+			capacity: 5, batch: 2,
+			ready: []PendingBead{
+				pending("a", "busy", 3, at(3*time.Hour), ""),
+				pending("b", "busy", 0, at(2*time.Hour), ""),
+				pending("c", "quiet", 1, at(time.Hour), ""),
+			},
+			want:       []string{"a", "b"},
+			wantReason: "batch",
+		},
+		{
+			name:     "priority picks P0 first",
+			cfg:      &SchedulerConfig{Policy: PolicyPriority},
+			capacity: 5, batch: 2,
+			ready: []PendingBead{
+				pending("a", "busy", 3, at(3*time.Hour), ""),
+				pending("b", "busy", 0, at(2*time.Hour), ""),
+				pending("c", "quiet", 1, at(time.Hour), ""),
+			},
+			want:       []string{"b", "c"},
+			wantReason: "batch",
+		},
+		{
+			name:     "priority ties broken by age",
+			cfg:      &SchedulerConfig{Policy: PolicyPriority},
+			capacity: 5, batch: 1,
+			ready: []PendingBead{
+				pending("new", "r", 1, at(time.Minute), ""),
+				pending("old", "r", 1, at(time.Hour), ""),
+			},
+			want:       []string{"old"},
+			wantReason: "batch",
+		},
+		{
+			name:     "aging lifts starved bead",
+			cfg:      &SchedulerConfig{Policy: PolicyPriority, AgingInterval: "1h"},
+			capacity: 5, batch: 1,
+			ready: []PendingBead{
+				pending("fresh-p1", "r", 1, at(time.Minute), ""),
+				pending("stale-p4", "r", 4, at(5*time.Hour), ""),
+			},
+			want:       []string{"stale-p4"},
+			wantReason: "batch",
+		},
+		{
+			name:     "fair shares slots between busy and quiet rig",
+			cfg:      &SchedulerConfig{Policy: PolicyFair},
+			capacity: 4, batch: 4,
+			ready: []PendingBead{
+				pending("b1", "busy", 2, at(4*time.Hour), ""),
+				pending("b2", "busy", 2, at(3*time.Hour), ""),
+				pending("b3", "busy", 2, at(2*time.Hour), ""),
+				pending("b4", "busy", 2, at(90*time.Minute), ""),
+				pending("q1", "quiet", 2, at(time.Hour), ""),
+				pending("q2", "quiet", 2, at(time.Minute), ""),
+			},
+			want:       []string{"b1", "q1", "b2", "q2"},
+			wantReason: "batch",
+		},
+		{
+			name:     "fair counts running polecats",
+			cfg:      &SchedulerConfig{Policy: PolicyFair},
+			capacity: 2, batch: 2,
+			active: map[string]int{"busy": 3},
+			ready: []PendingBead{
+				pending("b1", "busy", 0, at(4*time.Hour), ""),
+				pending("q1", "quiet", 3, at(time.Hour), ""),
+				pending("q2", "quiet", 3, at(time.Minute), ""),
+			},
+			want:       []string{"q1", "q2"},
+			wantReason: "batch",
+		},
+		{
+			name:     "fair honors rig weights",
+			cfg:      &SchedulerConfig{Policy: PolicyFair, RigWeights: map[string]int{"big": 3}},
+			capacity: 4, batch: 4,
+			ready: []PendingBead{
+				pending("b1", "big", 2, at(6*time.Hour), ""),
+				pending("b2", "big", 2, at(5*time.Hour), ""),
+				pending("b3", "big", 2, at(4*time.Hour), ""),
+				pending("b4", "big", 2, at(3*time.Hour), ""),
+				pending("s1", "small", 2, at(2*time.Hour), ""),
+				pending("s2", "small", 2, at(time.Hour), ""),
+			},
+			want:       []string{"b1", "b2", "b3", "s1"},
+			wantReason: "batch",
+		},
+		{
+			name:     "convoy cap leaves room for other work",
+			cfg:      &SchedulerConfig{MaxPerConvoy: 1},
+			capacity: 5, batch: 2,
+			ready: []PendingBead{
+				pending("c1", "r", 2, at(3*time.Hour), "hq-cv-1"),
+				pending("c2", "r", 2, at(2*time.Hour), "hq-cv-1"),
+				pending("x", "r", 2, at(time.Hour), ""),
+			},
+			want:       []string{"c1", "x"},
+			wantReason: "batch",
+		},
+		{
+			name:     "convoy cap leaving slots unfilled reports policy",
+			cfg:      &SchedulerConfig{Policy: PolicyFair, MaxPerConvoy: 1},
+			capacity: 5, batch: 3,
+			ready: []PendingBead{
+				pending("c1", "r", 2, at(3*time.Hour), "hq-cv-1"),
+				pending("c2", "r", 2, at(2*time.Hour), "hq-cv-1"),
+				pending("c3", "s", 2, at(time.Hour), "hq-cv-1"),
+			},
+			want:       []string{"c1"},
+			wantReason: "policy",
+		},
+		{
+			name:     "convoy cap counts running polecats",
+			cfg:      &SchedulerConfig{MaxPerConvoy: 2},
+			capacity: 5, batch: 3,
+			convoys: map[string]int{"hq-cv-1": 1, "hq-cv-2": 2},
+			ready: []PendingBead{
+				pending("c1", "r", 2, at(4*time.Hour), "hq-cv-1"),
+				pending("c2", "r", 2, at(3*time.Hour), "hq-cv-1"),
+				pending("d1", "r", 2, at(2*time.Hour), "hq-cv-2"),
+				pending("x", "r", 2, at(time.Hour), ""),
+			},
+			want:       []string{"c1", "x"},
+			wantReason: "policy",
+		},
+		{
+			name:     "fair convoy cap counts running polecats",
+			cfg:      &SchedulerConfig{Policy: PolicyFair, MaxPerConvoy: 1},
+			capacity: 5, batch: 2,
+			convoys: map[string]int{"hq-cv-1": 1},
+			ready: []PendingBead{
+				pending("c1", "r", 2, at(2*time.Hour), "hq-cv-1"),
+				pending("x", "s", 2, at(time.Hour), ""),
+			},
+			want:       []string{"x"},
+			wantReason: "policy",
+		},
+		{
+			name:     "no capacity",
+			cfg:      &SchedulerConfig{Policy: PolicyPriority},
+			capacity: 0, batch: 3,
+			ready: []PendingBead{
+				pending("a", "r", 0, at(time.Hour), ""),
+			},
+			want:       nil,
+			wantReason: "capacity",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := PolicyEnv{Now: now, ActiveByRig: tt.active, ActiveByConvoy: tt.convoys}
+			plan := PlanDispatchWithPolicy(tt.capacity, tt.batch, tt.ready, mustPolicy(t, tt.cfg), env)
+
+			got := selectedIDs(plan)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ToDispatch: got %v, want %v", got, tt.want)
+			}
+			if plan.Reason != tt.wantReason {
+				t.Errorf("Reason: got %q, want %q", plan.Reason, tt.wantReason)
+			}
+			if plan.Skipped != len(tt.ready)-len(tt.want) {
+				t.Errorf("Skipped: got %d, want %d", plan.Skipped, len(tt.ready)-len(tt.want))
+			}
+			if len(plan.Decisions) != len(tt.ready) {
+				t.Fatalf("Decisions: got %d, want one per ready bead (%d)", len(plan.Decisions), len(tt.ready))
+			}
+			for _, d := range plan.Decisions {
+				if d.Reason == "" {
+					t.Errorf("decision for %s has no reason", d.Bead.WorkBeadID)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanDispatchWithPolicy_FIFOMatchesPlanDispatch(t *testing.T) {
+	ready := []PendingBead{
+		pending("a", "r", 4, "", ""),
+		pending("b", "r", 0, "", ""),
+		pending("c", "r", 1, "", ""),
+	}
+	policy := mustPolicy(t, &SchedulerConfig{})
+	for _, tc := range []struct{ capacity, batch int }{{0, 3}, {1, 3}, {5, 2}, {5, 5}} {
+		want := PlanDispatch(tc.capacity, tc.batch, ready)
+		got := PlanDispatchWithPolicy(tc.capacity, tc.batch, ready, policy, PolicyEnv{})
+		if strings.Join(selectedIDs(got), ",") != strings.Join(selectedIDs(want), ",") ||
+			got.Skipped != want.Skipped || got.Reason != want.Reason {
+			t.Errorf("cap=%d batch=%d: got %v/%d/%s, want %v/%d/%s", tc.capacity, tc.batch,
+				selectedIDs(got), got.Skipped, got.Reason, selectedIDs(want), want.Skipped, want.Reason)
+		}
+	}
+}
+
+func TestPolicyDecisionReasons(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	ready := []PendingBead{
+		pending("aged", "r", 3, now.Add(-2*time.Hour).Format(time.RFC3339), ""),
+		pending("capped", "r", 0, now.Format(time.RFC3339), "hq-cv-1"),
+		pending("first", "r", 0, now.Add(-time.Hour).Format(time.RFC3339), "hq-cv-1"),
+	}
+	policy := mustPolicy(t, &SchedulerConfig{Policy: PolicyPriority, AgingInterval: "1h", MaxPerConvoy: 1})
+	decisions := policy.Select(ready, 2, PolicyEnv{Now: now})
+
+	reasons := make(map[string]string)
+	for _, d := range decisions {
+		reasons[d.Bead.WorkBeadID] = d.Reason
+	}
+	if want := "P1 (aged from P3)"; reasons["aged"] != want {
+		t.Errorf("aged reason = %q, want %q", reasons["aged"], want)
+	}
+	if !strings.Contains(reasons["capped"], "convoy hq-cv-1 at cap") {
+		t.Errorf("capped reason = %q, want convoy cap", reasons["capped"])
+	}
+	if reasons["first"] != "P0" {
+		t.Errorf("first reason = %q, want P0", reasons["first"])
+	}
+}
+
+func TestPolicyFromConfig_Invalid(t *testing.T) {
+	if _, err := PolicyFromConfig(&SchedulerConfig{Policy: "random"}); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
@@ -0,0 +1,251 @@
+package capacity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NotBeforeTime returns the parsed not_before time, or zero if unset or invalid.
+func (f *SlingContextFields) NotBeforeTime() time.Time {
+	return parseRFC3339(f.NotBefore)
+}
+
+// DeadlineTime returns the parsed deadline, or zero if unset or invalid.
+func (f *SlingContextFields) DeadlineTime() time.Time {
+	return parseRFC3339(f.Deadline)
+}
+
+// IsHeld reports whether the context must wait because not_before is in the future.
+func (f *SlingContextFields) IsHeld(now time.Time) bool {
+	nb := f.NotBeforeTime()
+	return !nb.IsZero() && now.Before(nb)
+}
+
+// IsOverdue reports whether the deadline has passed.
+func (f *SlingContextFields) IsOverdue(now time.Time) bool {
+	d := f.DeadlineTime()
+	return !d.IsZero() && now.After(d)
+}
+
+func parseRFC3339(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// TimeWindow returns a ReadinessFilter that drops beads whose not_before
+// time hasn't arrived yet.
+func TimeWindow(now time.Time) ReadinessFilter {
+	return func(pending []PendingBead) []PendingBead {
+		var result []PendingBead
+		for _, b := range pending {
+			if b.Context != nil && b.Context.IsHeld(now) {
+				continue
+			}
+			result = append(result, b)
+		}
+		return result
+	}
+}
+
+// ParseScheduleTime parses a user-supplied scheduling time relative to now.
+// Accepted forms:
+//
+//	2026-03-01T02:00:00Z   RFC3339
+//	2026-03-01 02:00       local date and time
+//	02:00                  next occurrence of that local time of day
+//	+3h, 90m               duration from now
+func ParseScheduleTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next, nil
+	}
+	if d, err := time.ParseDuration(strings.TrimPrefix(s, "+")); err == nil && d >= 0 {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use RFC3339, \"YYYY-MM-DD HH:MM\", \"HH:MM\", or a duration like +2h)", s)
+}
+
+// Recurrence is a parsed recurrence rule: a standard 5-field cron expression
+// (minute hour day-of-month month day-of-week), a descriptor such as @daily,
+// or "@every <duration>".
+type Recurrence struct {
+	rule  string
+	every time.Duration
+
+	minute, hour, dom, month, dow []bool
+	domStar, dowStar              bool
+}
+
+// cronDescriptors maps @-descriptors to their cron expressions.
+var cronDescriptors = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@nightly":  "0 2 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// ParseRecurrence parses a recurrence rule.
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	rule = strings.TrimSpace(rule)
+	r := &Recurrence{rule: rule}
+
+	if rest, ok := strings.CutPrefix(rule, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || d < time.Minute {
+			return nil, fmt.Errorf("invalid recurrence %q: @every needs a duration of at least 1m", rule)
+		}
+		r.every = d
+		return r, nil
+	}
+
+	expr := rule
+	if strings.HasPrefix(rule, "@") {
+		var ok bool
+		if expr, ok = cronDescriptors[rule]; !ok {
+			return nil, fmt.Errorf("unknown recurrence descriptor %q", rule)
+		}
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid recurrence %q: expected 5 cron fields (min hour dom month dow)", rule)
+	}
+	specs := []struct {
+		dst      *[]bool
+		min, max int
+	}{
+		{&r.minute, 0, 59},
+		{&r.hour, 0, 23},
+		{&r.dom, 1, 31},
+		{&r.month, 1, 12},
+		{&r.dow, 0, 7}, // 0 and 7 are both Sunday
+	}
+	for i, spec := range specs {
+		set, err := parseCronField(fields[i], spec.min, spec.max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrence %q: field %d: %w", rule, i+1, err)
+		}
+		*spec.dst = set
+	}
+	if r.dow[7] {
+		r.dow[0] = true
+	}
+	r.domStar = fields[2] == "*"
+	r.dowStar = fields[4] == "*"
+	return r, nil
+}
+
+// String returns the rule as given.
+func (r *Recurrence) String() string { return r.rule }
+
+// Next returns the first occurrence strictly after t, in t's location.
+// Returns the zero time if none exists within the next five years.
+func (r *Recurrence) Next(t time.Time) time.Time {
+	if r.every > 0 {
+		return t.Add(r.every)
+	}
+
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for next.Before(limit) {
+		if !r.month[int(next.Month())] {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if !r.dayMatches(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if !r.hour[next.Hour()] {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+			continue
+		}
+		if !r.minute[next.Minute()] {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next
+	}
+	return time.Time{}
+}
+
+// dayMatches applies cron's day rule: when both day-of-month and day-of-week
+// are restricted, either may match.
+func (r *Recurrence) dayMatches(t time.Time) bool {
+	dom := r.dom[t.Day()]
+	dow := r.dow[int(t.Weekday())]
+	switch {
+	case r.domStar && r.dowStar:
+		return true
+	case r.domStar:
+		return dow
+	case r.dowStar:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// parseCronField parses one cron field ("*", "5", "1-5", "*/15", "1,15,30")
+// into a membership table indexed by value.
+func parseCronField(field string, min, max int) ([]bool, error) {
+	set := make([]bool, max+1)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			loStr, hiStr, isRange := strings.Cut(rangePart, "-")
+			n, err := strconv.Atoi(loStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q", rangePart)
+			}
+			lo, hi = n, n
+			if isRange {
+				if hi, err = strconv.Atoi(hiStr); err != nil {
+					return nil, fmt.Errorf("invalid value %q", rangePart)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("value %q out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
@@ -0,0 +1,120 @@
+package capacity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseScheduleTime(t *testing.T) {
+	now := time.Date(2026, 3, 1, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-03-02T02:00:00Z", time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC)},
+		{"2026-03-05 09:15", time.Date(2026, 3, 5, 9, 15, 0, 0, time.UTC)},
+		{"22:00", time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC)},
+		{"02:00", time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC)}, // already past today
+		{"+2h", now.Add(2 * time.Hour)},
+		{"90m", now.Add(90 * time.Minute)},
+	}
+	for _, tt := range tests {
+		got, err := ParseScheduleTime(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseScheduleTime(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseScheduleTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "tomorrow", "-1h", "25:00"} {
+		if _, err := ParseScheduleTime(bad, now); err == nil {
+			t.Errorf("ParseScheduleTime(%q): expected error", bad)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	// Sunday 2026-03-01 14:30 UTC.
+	from := time.Date(2026, 3, 1, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule string
+		want time.Time
+	}{
+		{"@nightly", time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 6h", from.Add(6 * time.Hour)},
+		{"*/15 * * * *", time.Date(2026, 3, 1, 14, 45, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)},
+		{"30 14 * * *", time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC)}, // strictly after
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches (15th or a Friday).
+		{"0 12 15 * 5", time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)}, // 7 = Sunday
+	}
+	for _, tt := range tests {
+		r, err := ParseRecurrence(tt.rule)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q): %v", tt.rule, err)
+			continue
+		}
+		if got := r.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q.Next = %v, want %v", tt.rule, got, tt.want)
+		}
+		if r.String() != tt.rule {
+			t.Errorf("String() = %q, want %q", r.String(), tt.rule)
+		}
+	}
+}
+
+func TestParseRecurrence_Invalid(t *testing.T) {
+	for _, rule := range []string{
+		"", "@yearly", "@every 10s", "@every soon",
+		"* * * *", "60 * * * *", "0 24 * * *", "0 0 0 * *",
+		"*/0 * * * *", "5-1 * * * *", "a * * * *",
+	} {
+		if _, err := ParseRecurrence(rule); err == nil {
+			t.Errorf("ParseRecurrence(%q): expected error", rule)
+		}
+	}
+}
+
+func TestTimeWindow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	held := pending("held", "r", 2, "", "")
+	held.Context.NotBefore = now.Add(time.Hour).Format(time.RFC3339)
+	due := pending("due", "r", 2, "", "")
+	due.Context.NotBefore = now.Add(-time.Minute).Format(time.RFC3339)
+	plain := pending("plain", "r", 2, "", "")
+
+	got := TimeWindow(now)([]PendingBead{held, due, plain})
+	var ids []string
+	for _, b := range got {
+		ids = append(ids, b.WorkBeadID)
+	}
+	if strings.Join(ids, ",") != "due,plain" {
+		t.Errorf("TimeWindow kept %v, want [due plain]", ids)
+	}
+}
+
+func TestSlingContextFieldsIsOverdue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	f := &SlingContextFields{Deadline: now.Add(-time.Second).Format(time.RFC3339)}
+	if !f.IsOverdue(now) {
+		t.Error("expected past deadline to be overdue")
+	}
+	f.Deadline = now.Add(time.Hour).Format(time.RFC3339)
+	if f.IsOverdue(now) {
+		t.Error("expected future deadline not to be overdue")
+	}
+	if (&SlingContextFields{}).IsOverdue(now) {
+		t.Error("expected no deadline never to be overdue")
+	}
+}
@@ -0,0 +1,65 @@
+package session
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/ptyd"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// SessionBackend creates and drives agent sessions. tmux is the default
+// implementation; the PTY supervisor (ptyd) runs sessions headless, owned
+// by the daemon. Methods carry the tmux names so *tmux.Tmux satisfies the
+// interface as is.
+type SessionBackend interface {
+	NewSessionWithCommand(name, workDir, command string) error
+	HasSession(name string) (bool, error)
+	KillSessionWithProcesses(name string) error
+	ListSessions() ([]string, error)
+
+	// SendKeysRaw sends a tmux key name ("Enter", "C-c") or literal text.
+	SendKeysRaw(session, keys string) error
+	// NudgeSession types a message into the agent and submits it.
+	NudgeSession(session, message string) error
+	// CapturePane returns the last lines of the session's output.
+	CapturePane(session string, lines int) (string, error)
+
+	SetEnvironment(session, key, value string) error
+	GetEnvironment(session, key string) (string, error)
+	GetPanePID(session string) (string, error)
+
+	// SetAutoRespawnHook restarts the agent command whenever it exits.
+	SetAutoRespawnHook(session string) error
+	IsAgentAlive(session string) bool
+
+	WaitForRuntimeReady(session string, rc *config.RuntimeConfig, timeout time.Duration) error
+	IsAtPrompt(session string, rc *config.RuntimeConfig) bool
+	AcceptStartupDialogs(session string) error
+	AttachSession(session string) error
+}
+
+var (
+	_ SessionBackend = (*tmux.Tmux)(nil)
+	_ SessionBackend = (*ptyd.Client)(nil)
+)
+
+// PolecatBackend returns the backend polecat sessions of the town run in:
+// t, unless town settings select the PTY supervisor.
+func PolecatBackend(townRoot string, t *tmux.Tmux) SessionBackend {
+	if townRoot == "" {
+		return t
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.SessionBackend != config.SessionBackendPTY {
+		return t
+	}
+	return ptyd.NewClient(townRoot)
+}
+
+// AsTmux returns the tmux backend, or nil when b isn't tmux. Callers use it
+// for tmux-only features such as themes and pane hooks.
+func AsTmux(b SessionBackend) *tmux.Tmux {
+	t, _ := b.(*tmux.Tmux)
+	return t
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/sandbox"
 	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -82,6 +83,11 @@ type SessionConfig struct {
 	// Theme is the tmux theme to apply. Nil means no theme is applied.
 	Theme *tmux.Theme
 
+	// Sandbox overrides the rig's sandbox settings. Nil uses the settings
+	// of the rig at RigPath. The command runs sandboxed when the settings
+	// enable sandboxing for Role.
+	Sandbox *sandbox.Config
+
 	// Post-start behavior options.
 
 	// WaitForAgent waits for the agent command to appear in the pane.
@@ -118,7 +124,9 @@ type StartResult struct {
 	RuntimeConfig *config.RuntimeConfig
 }
 
-// StartSession creates a tmux session following the standard Gas Town lifecycle.
+// StartSession creates a session following the standard Gas Town lifecycle.
+// t is usually *tmux.Tmux; theme, remain-on-exit and the wait for the agent
+// command are tmux-only and skipped on other backends.
 //
 // The lifecycle handles:
 //  1. Resolve runtime config for the role
@@ -133,7 +141,7 @@ type StartResult struct {
 // Role-specific concerns (issue validation, fallback nudges, pane-died hooks,
 // crew cycle bindings, etc.) should be handled by the caller before/after
 // calling StartSession.
-func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error) {
+func StartSession(t SessionBackend, cfg SessionConfig) (_ *StartResult, retErr error) {
 	defer func() { telemetry.RecordSessionStart(context.Background(), cfg.SessionID, cfg.Role, retErr) }()
 	if cfg.SessionID == "" {
 		return nil, fmt.Errorf("SessionID is required")
@@ -180,14 +188,26 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 		command = config.PrependEnv(command, cfg.ExtraEnv)
 	}
 
+	// Wrap in the rig's sandbox last, so the env exports run inside it.
+	sandboxCfg := cfg.Sandbox
+	if sandboxCfg == nil {
+		sandboxCfg = LoadSandbox(cfg.RigPath)
+	}
+	command, err := SandboxCommand(command, sandboxCfg, cfg.Role, cfg.WorkDir, cfg.TownRoot, cfg.RuntimeConfigDir)
+	if err != nil {
+		return nil, err
+	}
+
 	// 4. Create tmux session with command.
 	if err := t.NewSessionWithCommand(cfg.SessionID, cfg.WorkDir, command); err != nil {
 		return nil, fmt.Errorf("creating session: %w", err)
 	}
 
+	tm := AsTmux(t)
+
 	// 5. Set remain-on-exit immediately if requested (before anything else can fail).
-	if cfg.RemainOnExit {
-		_ = t.SetRemainOnExit(cfg.SessionID, true)
+	if cfg.RemainOnExit && tm != nil {
+		_ = tm.SetRemainOnExit(cfg.SessionID, true)
 	}
 
 	// 6. Set environment variables.
@@ -208,13 +228,13 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 	}
 
 	// 7. Apply theme.
-	if cfg.Theme != nil {
-		_ = t.ConfigureGasTownSession(cfg.SessionID, *cfg.Theme, cfg.RigName, cfg.AgentName, cfg.Role)
+	if cfg.Theme != nil && tm != nil {
+		_ = tm.ConfigureGasTownSession(cfg.SessionID, *cfg.Theme, cfg.RigName, cfg.AgentName, cfg.Role)
 	}
 
 	// 8. Wait for agent to start.
-	if cfg.WaitForAgent {
-		if err := t.WaitForCommand(cfg.SessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+	if cfg.WaitForAgent && tm != nil {
+		if err := tm.WaitForCommand(cfg.SessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 			if cfg.WaitFatal {
 				_ = t.KillSessionWithProcesses(cfg.SessionID)
 				return nil, fmt.Errorf("waiting for %s to start: %w", cfg.Role, err)
@@ -268,7 +288,7 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 //
 // If graceful is true, sends Ctrl-C first and waits for the session to exit
 // before force-killing. This allows the agent to clean up.
-func StopSession(t *tmux.Tmux, sessionID string, graceful bool) error {
+func StopSession(t SessionBackend, sessionID string, graceful bool) error {
 	running, err := t.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
@@ -347,7 +367,7 @@ func MergeRuntimeLivenessEnv(envVars map[string]string, runtimeConfig *config.Ru
 // If checkAlive is true, only kills zombie sessions (tmux alive but agent dead).
 // If the session exists and the agent is alive, returns ErrAlreadyRunning.
 // If checkAlive is false, kills any existing session unconditionally.
-func KillExistingSession(t *tmux.Tmux, sessionID string, checkAlive bool) (bool, error) {
+func KillExistingSession(t SessionBackend, sessionID string, checkAlive bool) (bool, error) {
 	running, err := t.HasSession(sessionID)
 	if err != nil {
 		return false, fmt.Errorf("checking session: %w", err)
@@ -0,0 +1,45 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/sandbox"
+)
+
+// LoadSandbox returns the sandbox settings of the rig at rigPath, or nil
+// when the rig has none.
+func LoadSandbox(rigPath string) *sandbox.Config {
+	if rigPath == "" {
+		return nil
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		return nil
+	}
+	return settings.Sandbox
+}
+
+// SandboxCommand wraps an agent startup command in the sandbox when cfg
+// enables sandboxing for role, and returns command unchanged otherwise.
+// A sandbox that is enabled but can't be built is an error: sessions never
+// silently fall back to running unsandboxed.
+func SandboxCommand(command string, cfg *sandbox.Config, role, workDir, townRoot, runtimeConfigDir string) (string, error) {
+	if !cfg.AppliesTo(role) {
+		return command, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("sandbox: %w", err)
+	}
+	rootDir := filepath.Join(constants.TownRuntimePath(townRoot), "sandbox")
+	spec := cfg.Spec(workDir, townRoot, home, rootDir, runtimeConfigDir)
+	wrapped, err := sandbox.Wrap(command, spec)
+	if err != nil {
+		return "", fmt.Errorf("sandbox: %w (see gt doctor)", err)
+	}
+	return wrapped, nil
+}
@@ -0,0 +1,149 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// DeadLetter is an event a sink failed to receive after every retry.
+type DeadLetter struct {
+	Sink     string    `json:"sink"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+	Message  Message   `json:"message"`
+}
+
+// DeadLetterPath returns the path of the dead-letter file.
+func DeadLetterPath(townRoot string) string {
+	return filepath.Join(StateDir(townRoot), "deadletter.jsonl")
+}
+
+// lockDeadLetters takes the cross-process lock guarding the dead-letter file.
+func lockDeadLetters(townRoot string) (*flock.Flock, error) {
+	if err := os.MkdirAll(StateDir(townRoot), 0755); err != nil {
+		return nil, err
+	}
+	fl := flock.New(DeadLetterPath(townRoot) + ".lock")
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("acquiring dead-letter lock: %w", err)
+	}
+	return fl, nil
+}
+
+// AppendDeadLetter records a failed delivery.
+func AppendDeadLetter(townRoot string, dl DeadLetter) error {
+	fl, err := lockDeadLetters(townRoot)
+	if err != nil {
+		return err
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	data, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(DeadLetterPath(townRoot), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadDeadLetters returns every recorded failed delivery, oldest first.
+func ReadDeadLetters(townRoot string) ([]DeadLetter, error) {
+	f, err := os.Open(DeadLetterPath(townRoot))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var result []DeadLetter
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var dl DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &dl); err != nil {
+			continue
+		}
+		result = append(result, dl)
+	}
+	return result, scanner.Err()
+}
+
+// ReplayResult summarizes a Replay run.
+type ReplayResult struct {
+	Delivered int
+	Failed    int
+	Skipped   int // Sink no longer configured or filtered out
+}
+
+// Replay retries dead letters once each using the sinks' current config.
+// Delivered letters are removed; the rest stay with their error updated.
+// If only is non-empty, just that sink's letters are retried.
+func Replay(ctx context.Context, townRoot string, cfg *config.SinksConfig, only string) (ReplayResult, error) {
+	var result ReplayResult
+	fl, err := lockDeadLetters(townRoot)
+	if err != nil {
+		return result, err
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	letters, err := ReadDeadLetters(townRoot)
+	if err != nil {
+		return result, err
+	}
+	sinks := make(map[string]*config.SinkConfig)
+	for i := range cfg.Sinks {
+		sinks[cfg.Sinks[i].Name] = &cfg.Sinks[i]
+	}
+
+	var remaining []DeadLetter
+	for _, dl := range letters {
+		sc, ok := sinks[dl.Sink]
+		if !ok || (only != "" && dl.Sink != only) {
+			result.Skipped++
+			remaining = append(remaining, dl)
+			continue
+		}
+		if err := Deliver(ctx, sc, dl.Message); err != nil {
+			result.Failed++
+			dl.Attempts++
+			dl.Error = err.Error()
+			dl.FailedAt = time.Now().UTC()
+			remaining = append(remaining, dl)
+			continue
+		}
+		result.Delivered++
+	}
+
+	var buf []byte
+	for _, dl := range remaining {
+		data, err := json.Marshal(dl)
+		if err != nil {
+			return result, err
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+	path := DeadLetterPath(townRoot)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf, 0600); err != nil {
+		return result, err
+	}
+	return result, os.Rename(tmp, path)
+}
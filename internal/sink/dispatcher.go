@@ -0,0 +1,266 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+const (
+	// defaultPollInterval is how often workers check the events log.
+	defaultPollInterval = 2 * time.Second
+
+	// maxBackoff caps the retry delay between attempts.
+	maxBackoff = 5 * time.Minute
+)
+
+// StateDir returns the directory holding sink cursors and dead letters.
+func StateDir(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "sinks")
+}
+
+// cursor is a sink's durable position in the events log.
+type cursor struct {
+	Offset    int64     `json:"offset"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func cursorPath(townRoot, name string) string {
+	return filepath.Join(StateDir(townRoot), name+".cursor.json")
+}
+
+func loadCursor(townRoot, name string) (*cursor, error) {
+	data, err := os.ReadFile(cursorPath(townRoot, name))
+	if err != nil {
+		return nil, err
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func saveCursor(townRoot, name string, c *cursor) error {
+	if err := os.MkdirAll(StateDir(townRoot), 0755); err != nil {
+		return err
+	}
+	c.UpdatedAt = time.Now().UTC()
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	path := cursorPath(townRoot, name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: cursor is non-sensitive state
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Dispatcher delivers events from the events log to the configured sinks.
+// Each enabled sink gets its own worker and cursor, so a slow or failing
+// endpoint only delays itself. Delivery is at-least-once: the cursor
+// advances after an event is delivered or dead-lettered, and a restart
+// mid-batch redelivers with the same message ID.
+//
+// The config file is re-read when it changes; workers restart on reload.
+type Dispatcher struct {
+	townRoot string
+	town     string
+	logger   func(format string, args ...interface{})
+	store    *events.Store
+
+	pollInterval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher for the town at townRoot.
+func NewDispatcher(townRoot string, logger func(format string, args ...interface{})) *Dispatcher {
+	town, _ := workspace.GetTownName(townRoot)
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		townRoot:     townRoot,
+		town:         town,
+		logger:       logger,
+		store:        events.NewStore(townRoot),
+		pollInterval: defaultPollInterval,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// Start begins watching the sinks config and delivering events.
+func (d *Dispatcher) Start() error {
+	d.wg.Add(1)
+	go d.run()
+	return nil
+}
+
+// Stop stops all workers. Events not yet delivered are retried on next start.
+func (d *Dispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+// run reloads the config when it changes and (re)starts the workers.
+func (d *Dispatcher) run() {
+	defer d.wg.Done()
+
+	path := config.SinksConfigPath(d.townRoot)
+	var loadedMod time.Time
+	var stopWorkers func()
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+	for {
+		var mod time.Time
+		if info, err := os.Stat(path); err == nil {
+			mod = info.ModTime()
+		}
+		if !mod.Equal(loadedMod) {
+			loadedMod = mod
+			if stopWorkers != nil {
+				stopWorkers()
+				stopWorkers = nil
+			}
+			cfg, err := config.LoadOrCreateSinksConfig(path)
+			if err != nil {
+				d.logger("Sinks: invalid config, no events delivered until fixed: %v", err)
+			} else {
+				stopWorkers = d.startWorkers(cfg)
+			}
+		}
+
+		select {
+		case <-d.ctx.Done():
+			if stopWorkers != nil {
+				stopWorkers()
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// startWorkers starts a worker per enabled sink and returns a func that
+// stops them and waits for them to exit.
+func (d *Dispatcher) startWorkers(cfg *config.SinksConfig) func() {
+	ctx, cancel := context.WithCancel(d.ctx)
+	var wg sync.WaitGroup
+	for i := range cfg.Sinks {
+		sc := cfg.Sinks[i]
+		if !sc.IsEnabled() {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.worker(ctx, &sc)
+		}()
+		d.logger("Sinks: delivering %v to %s (%s)", eventsLabel(sc.Events), sc.Name, sc.Kind)
+	}
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+func eventsLabel(types []string) string {
+	if len(types) == 0 {
+		return "all events"
+	}
+	return fmt.Sprintf("%v", types)
+}
+
+// worker tails the events log from the sink's cursor. A new sink starts at
+// the end of the log rather than replaying history.
+func (d *Dispatcher) worker(ctx context.Context, sc *config.SinkConfig) {
+	cur, err := loadCursor(d.townRoot, sc.Name)
+	if err != nil {
+		cur = &cursor{Offset: d.store.Size()}
+		if err := saveCursor(d.townRoot, sc.Name, cur); err != nil {
+			d.logger("Sinks: %s: saving cursor: %v", sc.Name, err)
+		}
+	}
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+	for {
+		evts, next, err := d.store.ReadFrom(cur.Offset)
+		if err != nil {
+			d.logger("Sinks: %s: reading events: %v", sc.Name, err)
+		}
+		for _, e := range evts {
+			if !Matches(sc, e) {
+				continue
+			}
+			if err := d.deliverWithRetry(ctx, sc, NewMessage(sc.Name, d.town, e)); err != nil {
+				return // Stopping; cursor stays put so the batch is redelivered.
+			}
+		}
+		if next != cur.Offset {
+			cur.Offset = next
+			if err := saveCursor(d.townRoot, sc.Name, cur); err != nil {
+				d.logger("Sinks: %s: saving cursor: %v", sc.Name, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverWithRetry delivers msg, retrying with exponential backoff. After
+// the last attempt fails the message is dead-lettered and nil is returned;
+// a non-nil error means ctx was cancelled before the outcome was known.
+func (d *Dispatcher) deliverWithRetry(ctx context.Context, sc *config.SinkConfig, msg Message) error {
+	attempts := sc.GetMaxAttempts()
+	delay := sc.GetBackoff()
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = Deliver(ctx, sc, msg); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempt == attempts {
+			break
+		}
+		d.logger("Sinks: %s: %s delivery attempt %d/%d failed, retrying in %v: %v",
+			sc.Name, msg.Event.Type, attempt, attempts, delay, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxBackoff)
+	}
+
+	d.logger("Sinks: %s: giving up on %s %s after %d attempts: %v", sc.Name, msg.Event.Type, msg.ID, attempts, err)
+	if dlErr := AppendDeadLetter(d.townRoot, DeadLetter{
+		Sink:     sc.Name,
+		Error:    err.Error(),
+		Attempts: attempts,
+		FailedAt: time.Now().UTC(),
+		Message:  msg,
+	}); dlErr != nil {
+		d.logger("Sinks: %s: writing dead letter: %v", sc.Name, dlErr)
+	}
+	return nil
+}
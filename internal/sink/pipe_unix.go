@@ -0,0 +1,15 @@
+//go:build unix
+
+package sink
+
+import (
+	"os"
+	"syscall"
+)
+
+// openPipe opens path for appending without blocking. For a named pipe with
+// no reader attached this fails (ENXIO) instead of hanging, so the delivery
+// is retried later. Regular files are created if missing.
+func openPipe(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE|syscall.O_NONBLOCK, 0600)
+}
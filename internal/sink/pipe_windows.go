@@ -0,0 +1,11 @@
+//go:build windows
+
+package sink
+
+import "os"
+
+// openPipe opens path for appending. Windows has no FIFOs on the
+// filesystem, so pipe sinks behave as append-only files.
+func openPipe(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+}
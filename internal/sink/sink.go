@@ -0,0 +1,156 @@
+// Package sink delivers town events to outbound notification sinks:
+// signed webhooks, local commands and named pipes.
+//
+// Sinks are configured in settings/sinks.json (see config.SinksConfig). The
+// daemon runs a Dispatcher that tails the events log with a durable cursor
+// per sink, retries failed deliveries with exponential backoff, and writes
+// events that still fail to a dead-letter file for later replay.
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Webhook request headers.
+const (
+	HeaderEvent     = "X-Gastown-Event"
+	HeaderDelivery  = "X-Gastown-Delivery"
+	HeaderTimestamp = "X-Gastown-Timestamp"
+	HeaderSignature = "X-Gastown-Signature"
+)
+
+// TypeTest is the event type sent by `gt sink test`.
+const TypeTest = "sink_test"
+
+// Message is the JSON body delivered to every sink.
+type Message struct {
+	// ID identifies the event, not the attempt: retries and replays of the
+	// same event carry the same ID so receivers can deduplicate.
+	ID    string       `json:"id"`
+	Sink  string       `json:"sink"`
+	Town  string       `json:"town,omitempty"`
+	Event events.Event `json:"event"`
+}
+
+// NewMessage builds the message for e.
+func NewMessage(sinkName, town string, e events.Event) Message {
+	return Message{ID: EventID(e), Sink: sinkName, Town: town, Event: e}
+}
+
+// EventID returns a stable ID for an event (hash of its JSON encoding).
+func EventID(e events.Event) string {
+	data, _ := json.Marshal(e)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Sign returns the webhook signature header value for body sent at ts:
+// "sha256=" + hex(HMAC-SHA256(secret, ts + "." + body)). Including the
+// timestamp lets receivers reject replayed requests.
+func Sign(secret string, ts int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(ts, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Matches reports whether the sink subscribes to e.
+func Matches(cfg *config.SinkConfig, e events.Event) bool {
+	q := events.Query{Types: cfg.Events, Rig: cfg.Rig}
+	return q.Match(e)
+}
+
+// Deliver makes one delivery attempt of msg to the sink, bounded by the
+// sink's timeout.
+func Deliver(ctx context.Context, cfg *config.SinkConfig, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encoding message: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.GetTimeout())
+	defer cancel()
+
+	switch cfg.Kind {
+	case config.SinkKindWebhook:
+		return deliverWebhook(ctx, cfg, msg, body)
+	case config.SinkKindCommand:
+		return deliverCommand(ctx, cfg, msg, body)
+	case config.SinkKindPipe:
+		return deliverPipe(cfg, body)
+	}
+	return fmt.Errorf("unknown sink kind %q", cfg.Kind)
+}
+
+func deliverWebhook(ctx context.Context, cfg *config.SinkConfig, msg Message, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gastown-sink")
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	ts := time.Now().Unix()
+	req.Header.Set(HeaderEvent, msg.Event.Type)
+	req.Header.Set(HeaderDelivery, msg.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	if secret := cfg.GetSecret(); secret != "" {
+		req.Header.Set(HeaderSignature, Sign(secret, ts, body))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting to %s: %w", cfg.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, bytes.TrimSpace(snippet))
+	}
+	return nil
+}
+
+func deliverCommand(ctx context.Context, cfg *config.SinkConfig, msg Message, body []byte) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", cfg.Command) //nolint:gosec // G204: command is from trusted town config
+	cmd.Stdin = bytes.NewReader(append(body, '\n'))
+	cmd.Env = append(os.Environ(),
+		"GT_SINK="+cfg.Name,
+		"GT_EVENT_TYPE="+msg.Event.Type,
+		"GT_EVENT_ID="+msg.ID,
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("command failed: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+func deliverPipe(cfg *config.SinkConfig, body []byte) error {
+	f, err := openPipe(cfg.Path)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", cfg.Path, err)
+	}
+	if _, err := f.Write(append(body, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing %s: %w", cfg.Path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,236 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func testEvent(typ string) events.Event {
+	return events.Event{
+		Timestamp:  "2026-03-01T03:00:00Z",
+		Source:     "gt",
+		Type:       typ,
+		Actor:      "gastown/refinery",
+		Payload:    events.MergePayload("gt-mr1", "Toast", "polecat/Toast", "tests failed"),
+		Visibility: events.VisibilityFeed,
+	}
+}
+
+func TestDeliverWebhookSigned(t *testing.T) {
+	var got struct {
+		headers http.Header
+		body    []byte
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.headers = r.Header.Clone()
+		got.body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	t.Setenv("GT_TEST_SINK_SECRET", "s3cret")
+	sc := &config.SinkConfig{Name: "hook", Kind: config.SinkKindWebhook, URL: srv.URL, SecretEnv: "GT_TEST_SINK_SECRET"}
+	msg := NewMessage("hook", "town", testEvent(events.TypeMergeFailed))
+	if err := Deliver(context.Background(), sc, msg); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+
+	if got.headers.Get(HeaderEvent) != events.TypeMergeFailed {
+		t.Errorf("%s = %q", HeaderEvent, got.headers.Get(HeaderEvent))
+	}
+	if got.headers.Get(HeaderDelivery) != msg.ID {
+		t.Errorf("%s = %q, want %q", HeaderDelivery, got.headers.Get(HeaderDelivery), msg.ID)
+	}
+	ts, _ := strconv.ParseInt(got.headers.Get(HeaderTimestamp), 10, 64)
+	if want := Sign("s3cret", ts, got.body); got.headers.Get(HeaderSignature) != want {
+		t.Errorf("signature = %q, want %q", got.headers.Get(HeaderSignature), want)
+	}
+	var decoded Message
+	if err := json.Unmarshal(got.body, &decoded); err != nil || decoded.Event.Type != events.TypeMergeFailed {
+		t.Errorf("body = %s (err %v)", got.body, err)
+	}
+}
+
+func TestDeliverWebhookErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	sc := &config.SinkConfig{Name: "hook", Kind: config.SinkKindWebhook, URL: srv.URL}
+	if err := Deliver(context.Background(), sc, NewMessage("hook", "", testEvent("x"))); err == nil {
+		t.Error("expected error for 502 response")
+	}
+}
+
+func TestDeliverCommandAndPipe(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("command sinks use sh")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.json")
+
+	sc := &config.SinkConfig{Name: "cmd", Kind: config.SinkKindCommand, Command: "cat > " + out}
+	if err := Deliver(context.Background(), sc, NewMessage("cmd", "", testEvent(events.TypeMassDeath))); err != nil {
+		t.Fatalf("command Deliver: %v", err)
+	}
+	data, _ := os.ReadFile(out)
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil || msg.Event.Type != events.TypeMassDeath {
+		t.Errorf("command got %s (err %v)", data, err)
+	}
+
+	fail := &config.SinkConfig{Name: "bad", Kind: config.SinkKindCommand, Command: "exit 3"}
+	if err := Deliver(context.Background(), fail, msg); err == nil {
+		t.Error("expected error for failing command")
+	}
+
+	pipe := &config.SinkConfig{Name: "pipe", Kind: config.SinkKindPipe, Path: filepath.Join(dir, "events.log")}
+	for i := 0; i < 2; i++ {
+		if err := Deliver(context.Background(), pipe, msg); err != nil {
+			t.Fatalf("pipe Deliver: %v", err)
+		}
+	}
+	data, _ = os.ReadFile(pipe.Path)
+	if n := len(splitLines(data)); n != 2 {
+		t.Errorf("pipe file has %d lines, want 2", n)
+	}
+}
+
+func splitLines(data []byte) []string {
+	var lines []string
+	start := 0
+	for i, b := range data {
+		if b == '\n' {
+			lines = append(lines, string(data[start:i]))
+			start = i + 1
+		}
+	}
+	return lines
+}
+
+func TestMatches(t *testing.T) {
+	sc := &config.SinkConfig{Events: []string{"merge_*", events.TypeMassDeath}}
+	if !Matches(sc, testEvent(events.TypeMergeFailed)) || !Matches(sc, testEvent(events.TypeMassDeath)) {
+		t.Error("expected subscribed types to match")
+	}
+	if Matches(sc, testEvent(events.TypeNudge)) {
+		t.Error("expected nudge not to match")
+	}
+	if !Matches(&config.SinkConfig{}, testEvent(events.TypeNudge)) {
+		t.Error("expected empty event list to match everything")
+	}
+}
+
+func TestEventIDStable(t *testing.T) {
+	if EventID(testEvent("a")) != EventID(testEvent("a")) {
+		t.Error("EventID should be stable for the same event")
+	}
+	if EventID(testEvent("a")) == EventID(testEvent("b")) {
+		t.Error("EventID should differ for different events")
+	}
+}
+
+func appendEvent(t *testing.T, townRoot string, e events.Event) {
+	t.Helper()
+	f, err := os.OpenFile(filepath.Join(townRoot, events.EventsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(e)
+	_, _ = f.Write(append(data, '\n'))
+	_ = f.Close()
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestDispatcherDeliversRetriesAndDeadLetters(t *testing.T) {
+	townRoot := t.TempDir()
+	appendEvent(t, townRoot, testEvent(events.TypeMergeFailed)) // before the sink existed: not replayed
+
+	var mu sync.Mutex
+	var received []string
+	failing := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failing && r.Header.Get(HeaderEvent) == events.TypeMassDeath {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		received = append(received, r.Header.Get(HeaderEvent))
+	}))
+	defer srv.Close()
+
+	cfg := config.NewSinksConfig()
+	cfg.Sinks = []config.SinkConfig{{
+		Name: "hook", Kind: config.SinkKindWebhook, URL: srv.URL,
+		Events: []string{events.TypeMergeFailed, events.TypeMassDeath}, MaxAttempts: 2, Backoff: "1ms",
+	}}
+	if err := config.SaveSinksConfig(config.SinksConfigPath(townRoot), cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDispatcher(townRoot, t.Logf)
+	d.pollInterval = 10 * time.Millisecond
+	_ = d.Start()
+	waitFor(t, "cursor", func() bool {
+		_, err := loadCursor(townRoot, "hook")
+		return err == nil
+	})
+
+	appendEvent(t, townRoot, testEvent(events.TypeNudge)) // not subscribed
+	appendEvent(t, townRoot, testEvent(events.TypeMassDeath))
+	appendEvent(t, townRoot, testEvent(events.TypeMergeFailed))
+	waitFor(t, "delivery", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 1
+	})
+	d.Stop()
+
+	if received[0] != events.TypeMergeFailed {
+		t.Errorf("received %v, want only the new merge_failed", received)
+	}
+	letters, err := ReadDeadLetters(townRoot)
+	if err != nil || len(letters) != 1 {
+		t.Fatalf("dead letters = %v (err %v), want 1", letters, err)
+	}
+	if letters[0].Message.Event.Type != events.TypeMassDeath || letters[0].Attempts != 2 {
+		t.Errorf("dead letter = %+v", letters[0])
+	}
+
+	// Endpoint recovers: replay delivers and clears the dead letter.
+	mu.Lock()
+	failing = false
+	mu.Unlock()
+	result, err := Replay(context.Background(), townRoot, cfg, "")
+	if err != nil || result.Delivered != 1 {
+		t.Fatalf("Replay = %+v, %v", result, err)
+	}
+	if letters, _ := ReadDeadLetters(townRoot); len(letters) != 0 {
+		t.Errorf("dead letters after replay = %d, want 0", len(letters))
+	}
+}
@@ -0,0 +1,290 @@
+// Package telemetry — prometheus.go
+// Prometheus scrape endpoint for GT metrics.
+//
+// OTLP push covers most deployments; for Prometheus-only monitoring stacks
+// the daemon and `gt dashboard` can also serve /metrics in the Prometheus
+// text format. The handler exposes every OTel instrument registered in this
+// process plus point-in-time gauges supplied by the caller (queue depth,
+// polecat counts, ...) that are too expensive to keep as live instruments.
+package telemetry
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// EnvPrometheusAddr is the env var holding the listen address (e.g.
+// "127.0.0.1:9464") of the daemon's Prometheus /metrics endpoint. Unset
+// means no endpoint.
+const EnvPrometheusAddr = "GT_PROMETHEUS_ADDR"
+
+// prometheusContentType is the Prometheus text exposition format.
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	scrapeMu     sync.Mutex
+	scrapeReader *sdkmetric.ManualReader
+)
+
+// newScrapeReader returns the reader the scrape handler collects from,
+// attached to the meter provider by Init or EnablePrometheus.
+func newScrapeReader() sdkmetric.Reader {
+	scrapeMu.Lock()
+	defer scrapeMu.Unlock()
+	scrapeReader = sdkmetric.NewManualReader()
+	return scrapeReader
+}
+
+// EnablePrometheus makes this process's OTel instruments available to
+// PrometheusHandler. When Init already set up metrics this is a no-op;
+// otherwise it installs a meter provider that only feeds the scrape
+// handler, so /metrics works without any OTLP endpoint configured.
+func EnablePrometheus(ctx context.Context, serviceName, serviceVersion string) error {
+	initMu.Lock()
+	defer initMu.Unlock()
+	scrapeMu.Lock()
+	enabled := scrapeReader != nil
+	scrapeMu.Unlock()
+	if enabled {
+		return nil
+	}
+
+	res, err := newResource(ctx, serviceName, serviceVersion)
+	if err != nil {
+		return err
+	}
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(newScrapeReader()),
+	))
+	initInstruments()
+	return nil
+}
+
+// Sample is a gauge value computed at scrape time. Name is used as-is and
+// must be a valid Prometheus metric name; samples sharing a name form one
+// metric family and should share Help.
+type Sample struct {
+	Name   string
+	Help   string
+	Labels map[string]string
+	Value  float64
+}
+
+// PrometheusHandler serves the process's OTel metrics, followed by the
+// samples returned by gauges (which may be nil), in the Prometheus text
+// format. OTel instruments are only included once EnablePrometheus or Init
+// has run.
+func PrometheusHandler(gauges func(context.Context) []Sample) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var families []*promFamily
+		scrapeMu.Lock()
+		reader := scrapeReader
+		scrapeMu.Unlock()
+		if reader != nil {
+			var rm metricdata.ResourceMetrics
+			if err := reader.Collect(r.Context(), &rm); err != nil {
+				http.Error(w, fmt.Sprintf("collecting metrics: %v", err), http.StatusInternalServerError)
+				return
+			}
+			families = append(families, otelFamilies(&rm)...)
+		}
+		if gauges != nil {
+			families = append(families, sampleFamilies(gauges(r.Context()))...)
+		}
+
+		w.Header().Set("Content-Type", prometheusContentType)
+		bw := bufio.NewWriter(w)
+		writeFamilies(bw, families)
+		_ = bw.Flush()
+	})
+}
+
+// promFamily is one Prometheus metric family ready to be written.
+type promFamily struct {
+	name, help, typ string
+	lines           []promLine
+}
+
+type promLine struct {
+	suffix string // "", "_bucket", "_sum" or "_count"
+	labels []promLabel
+	value  float64
+}
+
+type promLabel struct{ name, value string }
+
+// otelFamilies converts collected OTel metrics to Prometheus families.
+// Monotonic sums become counters (with a _total suffix), other sums and
+// gauges become gauges, and explicit-bucket histograms become histograms.
+func otelFamilies(rm *metricdata.ResourceMetrics) []*promFamily {
+	var families []*promFamily
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			name := promName(m.Name)
+			var f *promFamily
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				f = sumFamily(name, m.Description, data.IsMonotonic, data.DataPoints)
+			case metricdata.Sum[float64]:
+				f = sumFamily(name, m.Description, data.IsMonotonic, data.DataPoints)
+			case metricdata.Gauge[int64]:
+				f = gaugeFamily(name, m.Description, data.DataPoints)
+			case metricdata.Gauge[float64]:
+				f = gaugeFamily(name, m.Description, data.DataPoints)
+			case metricdata.Histogram[int64]:
+				f = histogramFamily(name, m.Description, data.DataPoints)
+			case metricdata.Histogram[float64]:
+				f = histogramFamily(name, m.Description, data.DataPoints)
+			}
+			if f != nil && len(f.lines) > 0 {
+				families = append(families, f)
+			}
+		}
+	}
+	return families
+}
+
+func sumFamily[N int64 | float64](name, help string, monotonic bool, points []metricdata.DataPoint[N]) *promFamily {
+	if !monotonic {
+		return gaugeFamily(name, help, points)
+	}
+	f := &promFamily{name: strings.TrimSuffix(name, "_total") + "_total", help: help, typ: "counter"}
+	for _, p := range points {
+		f.lines = append(f.lines, promLine{labels: promLabels(p.Attributes), value: float64(p.Value)})
+	}
+	return f
+}
+
+func gaugeFamily[N int64 | float64](name, help string, points []metricdata.DataPoint[N]) *promFamily {
+	f := &promFamily{name: name, help: help, typ: "gauge"}
+	for _, p := range points {
+		f.lines = append(f.lines, promLine{labels: promLabels(p.Attributes), value: float64(p.Value)})
+	}
+	return f
+}
+
+func histogramFamily[N int64 | float64](name, help string, points []metricdata.HistogramDataPoint[N]) *promFamily {
+	f := &promFamily{name: name, help: help, typ: "histogram"}
+	for _, p := range points {
+		labels := promLabels(p.Attributes)
+		var cumulative uint64
+		for i, count := range p.BucketCounts {
+			cumulative += count
+			le := math.Inf(1)
+			if i < len(p.Bounds) {
+				le = p.Bounds[i]
+			}
+			bucket := append(append([]promLabel{}, labels...), promLabel{"le", formatFloat(le)})
+			f.lines = append(f.lines, promLine{suffix: "_bucket", labels: bucket, value: float64(cumulative)})
+		}
+		f.lines = append(f.lines,
+			promLine{suffix: "_sum", labels: labels, value: float64(p.Sum)},
+			promLine{suffix: "_count", labels: labels, value: float64(p.Count)},
+		)
+	}
+	return f
+}
+
+// sampleFamilies groups samples into gauge families, in first-seen order.
+func sampleFamilies(samples []Sample) []*promFamily {
+	var families []*promFamily
+	byName := make(map[string]*promFamily)
+	for _, s := range samples {
+		f := byName[s.Name]
+		if f == nil {
+			f = &promFamily{name: s.Name, help: s.Help, typ: "gauge"}
+			byName[s.Name] = f
+			families = append(families, f)
+		}
+		labels := make([]promLabel, 0, len(s.Labels))
+		for k, v := range s.Labels {
+			labels = append(labels, promLabel{k, v})
+		}
+		sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+		f.lines = append(f.lines, promLine{labels: labels, value: s.Value})
+	}
+	return families
+}
+
+func writeFamilies(w *bufio.Writer, families []*promFamily) {
+	for _, f := range families {
+		if f.help != "" {
+			fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		}
+		fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.typ)
+		for _, l := range f.lines {
+			w.WriteString(f.name + l.suffix)
+			if len(l.labels) > 0 {
+				w.WriteByte('{')
+				for i, lb := range l.labels {
+					if i > 0 {
+						w.WriteByte(',')
+					}
+					fmt.Fprintf(w, "%s=\"%s\"", lb.name, escapeLabelValue(lb.value))
+				}
+				w.WriteByte('}')
+			}
+			w.WriteString(" " + formatFloat(l.value) + "\n")
+		}
+	}
+}
+
+// promLabels converts OTel attributes to sorted Prometheus labels.
+func promLabels(set attribute.Set) []promLabel {
+	labels := make([]promLabel, 0, set.Len())
+	for _, kv := range set.ToSlice() {
+		labels = append(labels, promLabel{promName(string(kv.Key)), kv.Value.Emit()})
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+	return labels
+}
+
+// promName maps an OTel name ("gastown.bd.duration_ms") to a Prometheus
+// one ("gastown_bd_duration_ms").
+func promName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9' && i > 0:
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
@@ -0,0 +1,117 @@
+package telemetry
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// useScrapeReader points the scrape handler at a fresh meter provider.
+func useScrapeReader(t *testing.T) metric.Meter {
+	t.Helper()
+	scrapeMu.Lock()
+	prev := scrapeReader
+	scrapeReader = sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(scrapeReader))
+	scrapeMu.Unlock()
+	t.Cleanup(func() {
+		scrapeMu.Lock()
+		scrapeReader = prev
+		scrapeMu.Unlock()
+	})
+	return mp.Meter("test")
+}
+
+func scrape(t *testing.T, gauges func(context.Context) []Sample) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	PrometheusHandler(gauges).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Code != 200 {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != prometheusContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	return rec.Body.String()
+}
+
+func TestPrometheusHandler_OTelInstruments(t *testing.T) {
+	meter := useScrapeReader(t)
+	ctx := context.Background()
+
+	restarts, _ := meter.Int64Counter("gastown.daemon.restart.total", metric.WithDescription("Total restarts"))
+	restarts.Add(ctx, 2, metric.WithAttributes(attribute.String("agent.type", "witness")))
+	hist, _ := meter.Float64Histogram("gastown.bd.duration_ms",
+		metric.WithExplicitBucketBoundaries(10, 100))
+	hist.Record(ctx, 5)
+	hist.Record(ctx, 50)
+	hist.Record(ctx, 500)
+	healthy, _ := meter.Int64ObservableGauge("gastown.dolt.healthy")
+	_, _ = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(healthy, 1)
+		return nil
+	}, healthy)
+
+	out := scrape(t, nil)
+	for _, want := range []string{
+		"# HELP gastown_daemon_restart_total Total restarts\n",
+		"# TYPE gastown_daemon_restart_total counter\n",
+		`gastown_daemon_restart_total{agent_type="witness"} 2` + "\n",
+		"# TYPE gastown_bd_duration_ms histogram\n",
+		`gastown_bd_duration_ms_bucket{le="10"} 1` + "\n",
+		`gastown_bd_duration_ms_bucket{le="100"} 2` + "\n",
+		`gastown_bd_duration_ms_bucket{le="+Inf"} 3` + "\n",
+		"gastown_bd_duration_ms_sum 555\n",
+		"gastown_bd_duration_ms_count 3\n",
+		"# TYPE gastown_dolt_healthy gauge\n",
+		"gastown_dolt_healthy 1\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrometheusHandler_Samples(t *testing.T) {
+	scrapeMu.Lock()
+	prev := scrapeReader
+	scrapeReader = nil
+	scrapeMu.Unlock()
+	t.Cleanup(func() { scrapeMu.Lock(); scrapeReader = prev; scrapeMu.Unlock() })
+
+	out := scrape(t, func(context.Context) []Sample {
+		return []Sample{
+			{Name: "gastown_rig_polecats", Help: "Polecats per rig", Labels: map[string]string{"rig": "gastown"}, Value: 3},
+			{Name: "gastown_rig_polecats", Help: "Polecats per rig", Labels: map[string]string{"rig": `we"ird`}, Value: 1},
+			{Name: "gastown_scheduler_paused", Value: 0},
+		}
+	})
+	want := `# HELP gastown_rig_polecats Polecats per rig
+# TYPE gastown_rig_polecats gauge
+gastown_rig_polecats{rig="gastown"} 3
+gastown_rig_polecats{rig="we\"ird"} 1
+# TYPE gastown_scheduler_paused gauge
+gastown_scheduler_paused 0
+`
+	if out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestPromName(t *testing.T) {
+	for in, want := range map[string]string{
+		"gastown.bd.duration_ms": "gastown_bd_duration_ms",
+		"agent.type":             "agent_type",
+		"9lives":                 "_lives",
+		"http-status":            "http_status",
+	} {
+		if got := promName(in); got != want {
+			t.Errorf("promName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
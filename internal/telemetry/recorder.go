@@ -1,7 +1,8 @@
 // Package telemetry — recorder.go
 // Recording helper functions for all GT telemetry events.
 // Each function emits both an OTel log event (→ VictoriaLogs) and increments
-// a metric counter (→ VictoriaMetrics).
+// a metric counter (→ VictoriaMetrics). Log events are attached to the
+// current trace (see trace.go).
 package telemetry
 
 import (
@@ -9,6 +10,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 	"unicode/utf8"
 
 	"go.opentelemetry.io/otel"
@@ -130,13 +132,15 @@ func statusStr(err error) string {
 }
 
 // emit sends an OTel log event with the given body and key-value attributes.
+// Events are attached to the span in ctx, or to the process trace context
+// when ctx has none, so they carry trace and span IDs.
 func emit(ctx context.Context, body string, sev otellog.Severity, attrs ...otellog.KeyValue) {
 	logger := global.GetLoggerProvider().Logger(loggerName)
 	var r otellog.Record
 	r.SetBody(otellog.StringValue(body))
 	r.SetSeverity(sev)
 	r.AddAttributes(attrs...)
-	logger.Emit(ctx, r)
+	logger.Emit(processContext(ctx), r)
 }
 
 // errKV returns a log KeyValue with the error message, or empty string if nil.
@@ -176,7 +180,7 @@ func truncateOutput(s string, max int) string {
 	return truncated + "…"
 }
 
-// RecordBDCall records a bd CLI invocation with duration (metrics + log event).
+// RecordBDCall records a bd CLI invocation with duration (metrics + log event + span).
 // args is the full argument list; args[0] is used as the subcommand label.
 // durationMs is the wall-clock time of the subprocess in milliseconds.
 // stdout and stderr are the raw process outputs; both are truncated before logging.
@@ -197,6 +201,9 @@ func RecordBDCall(ctx context.Context, args []string, durationMs float64, err er
 	)
 	inst.bdTotal.Add(ctx, 1, attrs)
 	inst.bdDurationHist.Record(ctx, durationMs, attrs)
+	recordSpan(ctx, "bd "+subcommand, time.Duration(durationMs*float64(time.Millisecond)), err,
+		attribute.String("bd.subcommand", subcommand),
+	)
 	kvs := []otellog.KeyValue{
 		otellog.String("subcommand", subcommand),
 		otellog.String("args", strings.Join(args, " ")),
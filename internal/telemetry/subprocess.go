@@ -1,6 +1,7 @@
 package telemetry
 
 import (
+	"context"
 	"os"
 	"strings"
 )
@@ -62,13 +63,16 @@ func SetProcessOTELAttrs() {
 // (beads.go run, mail/bd.go runBdCommand) so the vars aren't lost when the
 // explicit env slice is built from scratch instead of os.Environ().
 //
-// Returns nil when GT telemetry is not active (GT_OTEL_METRICS_URL not set).
+// The trace context (TRACEPARENT) is included whenever this process has
+// one, even with export disabled, so a trace stays connected through
+// untraced processes. Returns nil when GT telemetry is not active
+// (GT_OTEL_METRICS_URL not set) and there is no trace context.
 func OTELEnvForSubprocess() []string {
+	env := traceEnv(context.Background())
 	metricsURL := os.Getenv(EnvMetricsURL)
 	if metricsURL == "" {
-		return nil
+		return env
 	}
-	var env []string
 	if attrs := buildGTResourceAttrs(); attrs != "" {
 		env = append(env, "OTEL_RESOURCE_ATTRIBUTES="+attrs)
 	}
@@ -1,12 +1,15 @@
-// Package telemetry initializes OpenTelemetry providers for metric and log export.
+// Package telemetry initializes OpenTelemetry providers for metric, log and
+// trace export.
 //
 // Metrics → VictoriaMetrics via OTLP HTTP
 // Logs    → VictoriaLogs via OTLP HTTP
+// Traces  → VictoriaTraces (or any OTLP trace backend) via OTLP HTTP
 //
 // Enabled by setting at least one of:
 //
 //	GT_OTEL_METRICS_URL  (default: http://localhost:8428/opentelemetry/api/v1/push)
 //	GT_OTEL_LOGS_URL     (default: http://localhost:9428/insert/opentelemetry/v1/logs)
+//	GT_OTEL_TRACES_URL   (default: http://localhost:10428/insert/opentelemetry/v1/traces)
 //
 // Telemetry is best-effort: initialization errors are returned but do not
 // affect normal gt operation — callers should log and continue.
@@ -24,10 +27,12 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/log/global"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
@@ -38,12 +43,18 @@ const (
 	// EnvLogsURL is the env var for the VictoriaLogs OTLP endpoint.
 	EnvLogsURL = "GT_OTEL_LOGS_URL"
 
+	// EnvTracesURL is the env var for the OTLP trace endpoint.
+	EnvTracesURL = "GT_OTEL_TRACES_URL"
+
 	// DefaultMetricsURL is VictoriaMetrics' OTLP push endpoint.
 	DefaultMetricsURL = "http://localhost:8428/opentelemetry/api/v1/push"
 
 	// DefaultLogsURL is VictoriaLogs' OTLP insert endpoint.
 	DefaultLogsURL = "http://localhost:9428/insert/opentelemetry/v1/logs"
 
+	// DefaultTracesURL is VictoriaTraces' OTLP insert endpoint.
+	DefaultTracesURL = "http://localhost:10428/insert/opentelemetry/v1/traces"
+
 	// ExportInterval is how often metrics are pushed to VictoriaMetrics.
 	ExportInterval = 30 * time.Second
 )
@@ -85,7 +96,23 @@ func (p *Provider) Shutdown(ctx context.Context) error {
 	return nil
 }
 
-// Init initializes OTel metric and log providers.
+// newResource describes this process to every OTel provider.
+func newResource(ctx context.Context, serviceName, serviceVersion string) (*resource.Resource, error) {
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(serviceVersion),
+		),
+		resource.WithHost(),
+		resource.WithOS(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating OTel resource: %w", err)
+	}
+	return res, nil
+}
+
+// Init initializes OTel metric, log and trace providers.
 //
 // Idempotent: subsequent calls (same or different arguments) return the
 // provider created on the first call. The serviceName and serviceVersion
@@ -94,13 +121,15 @@ func (p *Provider) Shutdown(ctx context.Context) error {
 // issue. If multiple packages call Init, ensure the entry-point (main or
 // cobra root) calls it first with the correct service name.
 //
-// Returns (nil, nil) if neither GT_OTEL_METRICS_URL nor GT_OTEL_LOGS_URL is set,
-// so that telemetry is strictly opt-in. Set either variable to activate.
+// Returns (nil, nil) if none of GT_OTEL_METRICS_URL, GT_OTEL_LOGS_URL and
+// GT_OTEL_TRACES_URL is set, so that telemetry is strictly opt-in. Set any
+// of them to activate.
 //
 // When active, defaults are used for any unset endpoint:
 //
 //	metrics → http://localhost:8428/opentelemetry/api/v1/push
 //	logs    → http://localhost:9428/insert/opentelemetry/v1/logs
+//	traces  → http://localhost:10428/insert/opentelemetry/v1/traces
 func Init(ctx context.Context, serviceName, serviceVersion string) (*Provider, error) {
 	initMu.Lock()
 	defer initMu.Unlock()
@@ -110,9 +139,10 @@ func Init(ctx context.Context, serviceName, serviceVersion string) (*Provider, e
 
 	metricsURL := os.Getenv(EnvMetricsURL)
 	logsURL := os.Getenv(EnvLogsURL)
+	tracesURL := os.Getenv(EnvTracesURL)
 
-	// Both unset → telemetry disabled, not an error.
-	if metricsURL == "" && logsURL == "" {
+	// All unset → telemetry disabled, not an error.
+	if metricsURL == "" && logsURL == "" && tracesURL == "" {
 		initDone = true
 		globalProvider = nil
 		return nil, nil
@@ -123,22 +153,18 @@ func Init(ctx context.Context, serviceName, serviceVersion string) (*Provider, e
 	if logsURL == "" {
 		logsURL = DefaultLogsURL
 	}
+	if tracesURL == "" {
+		tracesURL = DefaultTracesURL
+	}
 
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceName(serviceName),
-			semconv.ServiceVersion(serviceVersion),
-		),
-		resource.WithHost(),
-		resource.WithOS(),
-	)
+	res, err := newResource(ctx, serviceName, serviceVersion)
 	if err != nil {
-		return nil, fmt.Errorf("creating OTel resource: %w", err)
+		return nil, err
 	}
 
 	p := &Provider{}
 
-	// Metrics → VictoriaMetrics
+	// Metrics → VictoriaMetrics, and to the Prometheus scrape handler
 	metricExp, err := otlpmetrichttp.New(ctx,
 		otlpmetrichttp.WithEndpointURL(metricsURL),
 	)
@@ -152,6 +178,7 @@ func Init(ctx context.Context, serviceName, serviceVersion string) (*Provider, e
 				sdkmetric.WithInterval(ExportInterval),
 			),
 		),
+		sdkmetric.WithReader(newScrapeReader()),
 	)
 	otel.SetMeterProvider(mp)
 	p.shutdowns = append(p.shutdowns, mp.Shutdown)
@@ -171,6 +198,21 @@ func Init(ctx context.Context, serviceName, serviceVersion string) (*Provider, e
 	global.SetLoggerProvider(lp)
 	p.shutdowns = append(p.shutdowns, lp.Shutdown)
 
+	// Traces → VictoriaTraces
+	traceExp, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(tracesURL),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP trace exporter: %w", err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(traceExp),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
+	p.shutdowns = append(p.shutdowns, tp.Shutdown)
+
 	initDone = true
 	globalProvider = p
 	return p, nil
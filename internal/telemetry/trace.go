@@ -0,0 +1,231 @@
+// Package telemetry — trace.go
+// Trace spans and W3C trace context propagation.
+//
+// A trace follows one bead from `gt sling` through the polecat spawn, bd
+// calls, `gt done` and the Refinery's gates and merge. The context crosses
+// process boundaries in two ways:
+//
+//   - TRACEPARENT in the environment, for subprocesses and agent sessions
+//     (see SetProcessTraceContext and OTELEnvForSubprocess);
+//   - a trace_parent field in bead metadata, for hand-offs between agents
+//     that aren't parent and child processes (sling → done → Refinery).
+package telemetry
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "github.com/steveyegge/gastown"
+
+	// EnvTraceParent carries the W3C traceparent of the span that started a
+	// process, per the OTel environment-variable carrier convention.
+	EnvTraceParent = "TRACEPARENT"
+
+	// EnvTraceState carries the accompanying W3C tracestate, if any.
+	EnvTraceState = "TRACESTATE"
+)
+
+// propagator is the W3C trace context format used for env vars and beads.
+var propagator = propagation.TraceContext{}
+
+// envCarrier adapts trace context env vars to a TextMapCarrier.
+type envCarrier map[string]string
+
+func (c envCarrier) Get(key string) string { return c[key] }
+func (c envCarrier) Set(key, value string) { c[key] = value }
+func (c envCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// processContext returns ctx if it carries a span, otherwise ctx with the
+// trace context this process was started with (TRACEPARENT), if any.
+func processContext(ctx context.Context) context.Context {
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	return ContextFromEnv(ctx)
+}
+
+// ContextFromEnv returns ctx with the remote span context from TRACEPARENT
+// and TRACESTATE in the process environment. Returns ctx unchanged when
+// they are unset or malformed.
+func ContextFromEnv(ctx context.Context) context.Context {
+	return extract(ctx, os.Getenv(EnvTraceParent), os.Getenv(EnvTraceState))
+}
+
+// WithTraceParent returns ctx with the remote span context described by a
+// W3C traceparent value, as stored in bead metadata. Returns ctx unchanged
+// when traceParent is empty or malformed.
+func WithTraceParent(ctx context.Context, traceParent string) context.Context {
+	return extract(ctx, traceParent, "")
+}
+
+func extract(ctx context.Context, traceParent, traceState string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return propagator.Extract(ctx, envCarrier{"traceparent": traceParent, "tracestate": traceState})
+}
+
+// TraceParent returns the W3C traceparent of the span in ctx, falling back
+// to this process's trace context. Returns "" when there is no valid span,
+// which is the case whenever tracing is disabled and no parent was inherited.
+func TraceParent(ctx context.Context) string {
+	carrier := envCarrier{}
+	propagator.Inject(processContext(ctx), carrier)
+	return carrier["traceparent"]
+}
+
+// traceEnv returns TRACEPARENT (and TRACESTATE) entries for ctx's span.
+func traceEnv(ctx context.Context) []string {
+	carrier := envCarrier{}
+	propagator.Inject(processContext(ctx), carrier)
+	if carrier["traceparent"] == "" {
+		return nil
+	}
+	env := []string{EnvTraceParent + "=" + carrier["traceparent"]}
+	if ts := carrier["tracestate"]; ts != "" {
+		env = append(env, EnvTraceState+"="+ts)
+	}
+	return env
+}
+
+// SetProcessTraceContext makes ctx's span the parent of everything this
+// process spawns: TRACEPARENT is set in the process environment, so every
+// exec.Command subprocess inherits it, and Record* events with no span of
+// their own are attached to it. Called by Execute once the command span is
+// started. No-op when ctx has no valid span.
+func SetProcessTraceContext(ctx context.Context) {
+	carrier := envCarrier{}
+	propagator.Inject(ctx, carrier)
+	if carrier["traceparent"] == "" {
+		return
+	}
+	_ = os.Setenv(EnvTraceParent, carrier["traceparent"])
+	if ts := carrier["tracestate"]; ts != "" {
+		_ = os.Setenv(EnvTraceState, ts)
+	} else {
+		_ = os.Unsetenv(EnvTraceState)
+	}
+}
+
+// SwapProcessTraceParent sets the process trace context to traceParent, as
+// SetProcessTraceContext does for a span, and returns a function restoring
+// the previous one. Lets a command act on behalf of another trace for a
+// while, such as the scheduler dispatching a bead for the sling that queued
+// it. No-op when traceParent is empty or malformed.
+func SwapProcessTraceParent(traceParent string) (restore func()) {
+	oldParent, hadParent := os.LookupEnv(EnvTraceParent)
+	oldState, hadState := os.LookupEnv(EnvTraceState)
+	SetProcessTraceContext(WithTraceParent(context.Background(), traceParent))
+	return func() {
+		restoreEnv(EnvTraceParent, oldParent, hadParent)
+		restoreEnv(EnvTraceState, oldState, hadState)
+	}
+}
+
+func restoreEnv(key, value string, set bool) {
+	if set {
+		_ = os.Setenv(key, value)
+	} else {
+		_ = os.Unsetenv(key)
+	}
+}
+
+// HasTraceContext reports whether ctx carries a span or this process was
+// started under one (TRACEPARENT).
+func HasTraceContext(ctx context.Context) bool {
+	return trace.SpanContextFromContext(processContext(ctx)).IsValid()
+}
+
+// EnvWithoutTraceContext returns the process environment minus TRACEPARENT
+// and TRACESTATE, for commands whose children outlive this process (such as
+// a tmux server) and must not adopt its trace. Returns nil, meaning "inherit
+// unchanged", when no trace context is set.
+func EnvWithoutTraceContext() []string {
+	if os.Getenv(EnvTraceParent) == "" && os.Getenv(EnvTraceState) == "" {
+		return nil
+	}
+	var env []string
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, EnvTraceParent+"=") || strings.HasPrefix(kv, EnvTraceState+"=") {
+			continue
+		}
+		env = append(env, kv)
+	}
+	return env
+}
+
+// StartSpan starts a span named name as a child of the span in ctx, or of
+// the process trace context when ctx has none. Use EndSpan to finish it.
+// With tracing disabled the span is a no-op that still carries an
+// inherited trace context, so propagation works through untraced processes.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(processContext(ctx), name, trace.WithAttributes(attrs...))
+}
+
+// StartLinkedSpan starts a span like StartSpan, linked to the spans named
+// by traceParents. Used where one operation serves several traces, such as
+// a Refinery batch merging MRs from different slings.
+func StartLinkedSpan(ctx context.Context, name string, traceParents []string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	var links []trace.Link
+	for _, tp := range traceParents {
+		if sc := trace.SpanContextFromContext(WithTraceParent(context.Background(), tp)); sc.IsValid() {
+			links = append(links, trace.Link{SpanContext: sc})
+		}
+	}
+	return otel.Tracer(tracerName).Start(processContext(ctx), name,
+		trace.WithAttributes(attrs...), trace.WithLinks(links...))
+}
+
+// EndSpan records err on span, if any, and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// recordSpan records an operation that has already finished as a span
+// ending now, for helpers that only learn about an operation afterwards.
+func recordSpan(ctx context.Context, name string, duration time.Duration, err error, attrs ...attribute.KeyValue) {
+	end := time.Now()
+	_, span := otel.Tracer(tracerName).Start(processContext(ctx), name,
+		trace.WithTimestamp(end.Add(-duration)),
+		trace.WithAttributes(attrs...),
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(end))
+}
+
+// UseSpanExporter installs a tracer provider that hands every finished span
+// to exp synchronously, and returns a function restoring the previous
+// provider. Intended for tests, with tracetest.NewInMemoryExporter.
+func UseSpanExporter(exp sdktrace.SpanExporter) (restore func()) {
+	prev := otel.GetTracerProvider()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	otel.SetTracerProvider(tp)
+	return func() {
+		_ = tp.Shutdown(context.Background())
+		otel.SetTracerProvider(prev)
+	}
+}
@@ -0,0 +1,145 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func useInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exp := tracetest.NewInMemoryExporter()
+	t.Cleanup(UseSpanExporter(exp))
+	return exp
+}
+
+func TestStartSpan_ParentsOnInheritedTraceParent(t *testing.T) {
+	exp := useInMemoryExporter(t)
+	t.Setenv(EnvTraceParent, testTraceParent)
+
+	_, span := StartSpan(context.Background(), "gt sling")
+	EndSpan(span, errors.New("boom"))
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	got := spans[0]
+	if got.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s", got.SpanContext.TraceID())
+	}
+	if got.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("parent span ID = %s", got.Parent.SpanID())
+	}
+	if got.Status.Description != "boom" {
+		t.Errorf("status = %+v", got.Status)
+	}
+}
+
+func TestSetProcessTraceContext_ExportsToSubprocesses(t *testing.T) {
+	useInMemoryExporter(t)
+	t.Setenv(EnvTraceParent, "")
+	t.Setenv(EnvTraceState, "")
+	t.Setenv(EnvMetricsURL, "")
+
+	ctx, span := StartSpan(context.Background(), "gt done")
+	defer span.End()
+	SetProcessTraceContext(ctx)
+
+	want := TraceParent(ctx)
+	if !strings.HasPrefix(want, "00-"+span.SpanContext().TraceID().String()) {
+		t.Fatalf("TraceParent = %q", want)
+	}
+	if got := os.Getenv(EnvTraceParent); got != want {
+		t.Errorf("TRACEPARENT = %q, want %q", got, want)
+	}
+	// Propagated even when metrics export is off
+	env := OTELEnvForSubprocess()
+	if len(env) != 1 || env[0] != EnvTraceParent+"="+want {
+		t.Errorf("OTELEnvForSubprocess = %v", env)
+	}
+	for _, kv := range EnvWithoutTraceContext() {
+		if strings.HasPrefix(kv, EnvTraceParent+"=") {
+			t.Errorf("EnvWithoutTraceContext kept %s", kv)
+		}
+	}
+}
+
+func TestWithTraceParent(t *testing.T) {
+	ctx := WithTraceParent(context.Background(), testTraceParent)
+	if got := TraceParent(ctx); got != testTraceParent {
+		t.Errorf("TraceParent = %q, want %q", got, testTraceParent)
+	}
+
+	t.Setenv(EnvTraceParent, "")
+	for _, tp := range []string{"", "not-a-traceparent"} {
+		if sc := trace.SpanContextFromContext(WithTraceParent(context.Background(), tp)); sc.IsValid() {
+			t.Errorf("WithTraceParent(%q) produced a valid span context", tp)
+		}
+	}
+}
+
+func TestSwapProcessTraceParent(t *testing.T) {
+	t.Setenv(EnvTraceParent, "")
+	os.Unsetenv(EnvTraceParent)
+	t.Setenv(EnvTraceState, "")
+	os.Unsetenv(EnvTraceState)
+
+	if HasTraceContext(context.Background()) {
+		t.Fatal("HasTraceContext with no TRACEPARENT")
+	}
+	restore := SwapProcessTraceParent(testTraceParent)
+	if got := os.Getenv(EnvTraceParent); got != testTraceParent {
+		t.Errorf("TRACEPARENT = %q, want %q", got, testTraceParent)
+	}
+	if !HasTraceContext(context.Background()) {
+		t.Error("HasTraceContext after swap = false")
+	}
+	restore()
+	if _, ok := os.LookupEnv(EnvTraceParent); ok {
+		t.Errorf("TRACEPARENT not unset on restore: %q", os.Getenv(EnvTraceParent))
+	}
+}
+
+func TestStartLinkedSpan(t *testing.T) {
+	exp := useInMemoryExporter(t)
+	t.Setenv(EnvTraceParent, "")
+
+	_, span := StartLinkedSpan(context.Background(), "refinery.batch", []string{testTraceParent, ""})
+	span.End()
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 || len(spans[0].Links) != 1 {
+		t.Fatalf("spans = %+v", spans)
+	}
+	if got := spans[0].Links[0].SpanContext.TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("link trace ID = %s", got)
+	}
+}
+
+func TestRecordBDCall_RecordsSpan(t *testing.T) {
+	exp := useInMemoryExporter(t)
+	t.Setenv(EnvTraceParent, testTraceParent)
+
+	RecordBDCall(context.Background(), []string{"show", "gt-abc"}, 250, nil, nil, "")
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	got := spans[0]
+	if got.Name != "bd show" || got.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("span %q parent %s", got.Name, got.Parent.SpanID())
+	}
+	if d := got.EndTime.Sub(got.StartTime); d != 250*time.Millisecond {
+		t.Errorf("span duration = %v, want 250ms", d)
+	}
+}
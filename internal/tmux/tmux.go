@@ -166,6 +166,9 @@ func (t *Tmux) run(args ...string) (string, error) {
 	}
 	allArgs = append(allArgs, args...)
 	cmd := exec.Command("tmux", allArgs...)
+	// A tmux server started here would keep this command's trace context
+	// in its global environment; sessions get their own TRACEPARENT instead.
+	cmd.Env = telemetry.EnvWithoutTraceContext()
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -0,0 +1,84 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// claudeExtractor reads Claude Code transcripts from
+// ~/.claude/projects/<workdir-with-dashes>/<session>.jsonl.
+type claudeExtractor struct{}
+
+// claudeMessage is the subset of a transcript line carrying usage.
+type claudeMessage struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int `json:"input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+		} `json:"usage,omitempty"`
+	} `json:"message,omitempty"`
+}
+
+func (claudeExtractor) Extract(workDir string) ([]Usage, error) {
+	configDir, err := homeDir("CLAUDE_CONFIG_DIR", ".claude")
+	if err != nil {
+		return nil, err
+	}
+	// Claude's directory naming replaces / with -, keeping the leading
+	// slash as a leading dash.
+	projectDir := filepath.Join(configDir, "projects", strings.ReplaceAll(workDir, "/", "-"))
+	transcripts, err := filesByModTime(projectDir, "*.jsonl", false)
+	if err != nil {
+		return nil, fmt.Errorf("finding transcript: %w", err)
+	}
+	if len(transcripts) == 0 {
+		return nil, fmt.Errorf("no transcript files found in %s", projectDir)
+	}
+	usages, err := parseClaudeTranscript(transcripts[0])
+	return inSession(transcripts[0], usages), err
+}
+
+// parseClaudeTranscript sums token usage from assistant messages.
+func parseClaudeTranscript(path string) ([]Usage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var acc byModel
+	scanner := newLineScanner(file)
+	for scanner.Scan() {
+		var msg claudeMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue // Skip malformed lines
+		}
+		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
+			continue
+		}
+		u := acc.get(msg.Message.Model)
+		u.InputTokens += msg.Message.Usage.InputTokens
+		u.CacheWriteTokens += msg.Message.Usage.CacheCreationInputTokens
+		u.CacheReadTokens += msg.Message.Usage.CacheReadInputTokens
+		u.OutputTokens += msg.Message.Usage.OutputTokens
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return acc.result(), nil
+}
+
+// newLineScanner returns a scanner for JSONL files with long lines.
+func newLineScanner(f *os.File) *bufio.Scanner {
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 256*1024), 16*1024*1024)
+	return scanner
+}
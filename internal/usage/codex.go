@@ -0,0 +1,118 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// codexExtractor reads Codex CLI rollouts from
+// $CODEX_HOME/sessions/YYYY/MM/DD/rollout-*.jsonl (default ~/.codex).
+// The first line of a rollout is its session_meta, which records the
+// working directory.
+type codexExtractor struct{}
+
+// codexLine is the subset of a rollout line needed for usage.
+type codexLine struct {
+	Type    string `json:"type"`
+	Payload struct {
+		// session_meta and turn_context
+		CWD   string `json:"cwd"`
+		Model string `json:"model"`
+		// event_msg
+		Type string `json:"type"`
+		Info *struct {
+			Total codexTokenUsage `json:"total_token_usage"`
+		} `json:"info"`
+	} `json:"payload"`
+}
+
+type codexTokenUsage struct {
+	InputTokens       int `json:"input_tokens"`
+	CachedInputTokens int `json:"cached_input_tokens"`
+	OutputTokens      int `json:"output_tokens"`
+}
+
+func (codexExtractor) Extract(workDir string) ([]Usage, error) {
+	home, err := homeDir("CODEX_HOME", ".codex")
+	if err != nil {
+		return nil, err
+	}
+	sessionsDir := filepath.Join(home, "sessions")
+	rollouts, err := filesByModTime(sessionsDir, "rollout-*.jsonl", true)
+	if err != nil {
+		return nil, fmt.Errorf("finding rollout: %w", err)
+	}
+	for _, path := range rollouts {
+		cwd, err := codexRolloutCWD(path)
+		if err != nil || !sameDir(cwd, workDir) {
+			continue
+		}
+		usages, err := parseCodexRollout(path)
+		return inSession(path, usages), err
+	}
+	return nil, fmt.Errorf("no Codex rollout for %s in %s", workDir, sessionsDir)
+}
+
+// codexRolloutCWD returns the working directory from a rollout's session_meta.
+func codexRolloutCWD(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	scanner := newLineScanner(f)
+	if !scanner.Scan() {
+		return "", scanner.Err()
+	}
+	var line codexLine
+	if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+		return "", err
+	}
+	if line.Type != "session_meta" {
+		return "", fmt.Errorf("rollout %s does not start with session_meta", path)
+	}
+	return line.Payload.CWD, nil
+}
+
+// parseCodexRollout reads token usage from a rollout. token_count events
+// carry the session's cumulative usage, so each event's increase is
+// attributed to the model of the turn it belongs to.
+func parseCodexRollout(path string) ([]Usage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var acc byModel
+	var model string
+	var prev codexTokenUsage
+	scanner := newLineScanner(f)
+	for scanner.Scan() {
+		var line codexLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue // Skip malformed lines
+		}
+		switch {
+		case line.Type == "turn_context" && line.Payload.Model != "":
+			model = line.Payload.Model
+		case line.Type == "event_msg" && line.Payload.Type == "token_count" && line.Payload.Info != nil:
+			total := line.Payload.Info.Total
+			if total.InputTokens < prev.InputTokens || total.OutputTokens < prev.OutputTokens {
+				prev = codexTokenUsage{} // Counter reset
+			}
+			cached := total.CachedInputTokens - prev.CachedInputTokens
+			u := acc.get(model)
+			u.InputTokens += total.InputTokens - prev.InputTokens - cached
+			u.CacheReadTokens += cached
+			u.OutputTokens += total.OutputTokens - prev.OutputTokens
+			prev = total
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return acc.result(), nil
+}
@@ -0,0 +1,70 @@
+package usage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// geminiExtractor reads Gemini CLI chat recordings from
+// ~/.gemini/tmp/<sha256(workdir)>/chats/session-*.json.
+type geminiExtractor struct{}
+
+// geminiConversation is the subset of a chat recording needed for usage.
+type geminiConversation struct {
+	Messages []struct {
+		Type   string `json:"type"`
+		Model  string `json:"model"`
+		Tokens *struct {
+			Input    int `json:"input"`
+			Output   int `json:"output"`
+			Cached   int `json:"cached"`
+			Thoughts int `json:"thoughts"`
+		} `json:"tokens"`
+	} `json:"messages"`
+}
+
+func (geminiExtractor) Extract(workDir string) ([]Usage, error) {
+	home, err := homeDir("", ".gemini")
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256([]byte(workDir))
+	chatsDir := filepath.Join(home, "tmp", hex.EncodeToString(hash[:]), "chats")
+	sessions, err := filesByModTime(chatsDir, "session-*.json", false)
+	if err != nil {
+		return nil, fmt.Errorf("finding chat recording: %w", err)
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("no chat recordings found in %s", chatsDir)
+	}
+	usages, err := parseGeminiChat(sessions[0])
+	return inSession(sessions[0], usages), err
+}
+
+// parseGeminiChat sums token usage from model responses. Gemini counts
+// cached tokens inside input and bills thoughts as output.
+func parseGeminiChat(path string) ([]Usage, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the Gemini chats dir
+	if err != nil {
+		return nil, err
+	}
+	var conv geminiConversation
+	if err := json.Unmarshal(data, &conv); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	var acc byModel
+	for _, m := range conv.Messages {
+		if m.Type != "gemini" || m.Tokens == nil {
+			continue
+		}
+		u := acc.get(m.Model)
+		u.InputTokens += m.Tokens.Input - m.Tokens.Cached
+		u.CacheReadTokens += m.Tokens.Cached
+		u.OutputTokens += m.Tokens.Output + m.Tokens.Thoughts
+	}
+	return acc.result(), nil
+}
@@ -0,0 +1,90 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// openCodeExtractor reads OpenCode's storage under
+// $XDG_DATA_HOME/opencode/storage (default ~/.local/share/opencode):
+// session/<project>/<session>.json records each session's directory, and
+// message/<session>/*.json holds its messages with token counts and cost.
+type openCodeExtractor struct{}
+
+type openCodeSession struct {
+	ID        string `json:"id"`
+	Directory string `json:"directory"`
+	Time      struct {
+		Updated int64 `json:"updated"`
+	} `json:"time"`
+}
+
+type openCodeMessage struct {
+	Role    string  `json:"role"`
+	ModelID string  `json:"modelID"`
+	Cost    float64 `json:"cost"`
+	Tokens  *struct {
+		Input     int `json:"input"`
+		Output    int `json:"output"`
+		Reasoning int `json:"reasoning"`
+		Cache     struct {
+			Read  int `json:"read"`
+			Write int `json:"write"`
+		} `json:"cache"`
+	} `json:"tokens"`
+}
+
+func (openCodeExtractor) Extract(workDir string) ([]Usage, error) {
+	dataDir, err := homeDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+	if err != nil {
+		return nil, err
+	}
+	storage := filepath.Join(dataDir, "opencode", "storage")
+
+	sessionFiles, err := filesByModTime(filepath.Join(storage, "session"), "*.json", true)
+	if err != nil {
+		return nil, fmt.Errorf("finding session: %w", err)
+	}
+	var latest *openCodeSession
+	for _, path := range sessionFiles {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the OpenCode storage dir
+		if err != nil {
+			continue
+		}
+		var s openCodeSession
+		if json.Unmarshal(data, &s) != nil || s.ID == "" || !sameDir(s.Directory, workDir) {
+			continue
+		}
+		if latest == nil || s.Time.Updated > latest.Time.Updated {
+			latest = &s
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no OpenCode session for %s in %s", workDir, storage)
+	}
+
+	messages, err := filesByModTime(filepath.Join(storage, "message", latest.ID), "*.json", false)
+	if err != nil {
+		return nil, fmt.Errorf("reading messages of %s: %w", latest.ID, err)
+	}
+	var acc byModel
+	for _, path := range messages {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the OpenCode storage dir
+		if err != nil {
+			continue
+		}
+		var m openCodeMessage
+		if json.Unmarshal(data, &m) != nil || m.Role != "assistant" || m.Tokens == nil {
+			continue
+		}
+		u := acc.get(m.ModelID)
+		u.InputTokens += m.Tokens.Input
+		u.CacheReadTokens += m.Tokens.Cache.Read
+		u.CacheWriteTokens += m.Tokens.Cache.Write
+		u.OutputTokens += m.Tokens.Output + m.Tokens.Reasoning
+		u.ReportedCostUSD += m.Cost
+	}
+	return inSession(latest.ID, acc.result()), nil
+}
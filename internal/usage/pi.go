@@ -0,0 +1,92 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// piExtractor reads Pi coding agent sessions from
+// <agent dir>/sessions/--<workdir-with-dashes>--/*.jsonl. Oh My Pi keeps
+// the same format under its own agent dir.
+type piExtractor struct {
+	agentDirEnv string // env var overriding the agent dir
+	defaultDir  string // dir under $HOME holding agent/
+}
+
+// piLine is the subset of a session line needed for usage.
+type piLine struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Role  string `json:"role"`
+		Model string `json:"model"`
+		Usage *struct {
+			Input      int `json:"input"`
+			Output     int `json:"output"`
+			CacheRead  int `json:"cacheRead"`
+			CacheWrite int `json:"cacheWrite"`
+			Cost       *struct {
+				Total float64 `json:"total"`
+			} `json:"cost"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+func (e piExtractor) Extract(workDir string) ([]Usage, error) {
+	agentDir, err := homeDir(e.agentDirEnv, filepath.Join(e.defaultDir, "agent"))
+	if err != nil {
+		return nil, err
+	}
+	sessionDir := filepath.Join(agentDir, "sessions", piSessionDirName(workDir))
+	sessions, err := filesByModTime(sessionDir, "*.jsonl", false)
+	if err != nil {
+		return nil, fmt.Errorf("finding session: %w", err)
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("no session files found in %s", sessionDir)
+	}
+	usages, err := parsePiSession(sessions[0])
+	return inSession(sessions[0], usages), err
+}
+
+// piSessionDirName encodes a working directory the way Pi names its
+// per-project session directories: "/home/u/repo" -> "--home-u-repo--".
+func piSessionDirName(workDir string) string {
+	trimmed := strings.TrimLeft(workDir, `/\`)
+	return "--" + strings.NewReplacer("/", "-", `\`, "-", ":", "-").Replace(trimmed) + "--"
+}
+
+func parsePiSession(path string) ([]Usage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var acc byModel
+	scanner := newLineScanner(f)
+	for scanner.Scan() {
+		var line piLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue // Skip malformed lines
+		}
+		if line.Type != "message" || line.Message == nil || line.Message.Role != "assistant" || line.Message.Usage == nil {
+			continue
+		}
+		pu := line.Message.Usage
+		u := acc.get(line.Message.Model)
+		u.InputTokens += pu.Input
+		u.CacheReadTokens += pu.CacheRead
+		u.CacheWriteTokens += pu.CacheWrite
+		u.OutputTokens += pu.Output
+		if pu.Cost != nil {
+			u.ReportedCostUSD += pu.Cost.Total
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return acc.result(), nil
+}
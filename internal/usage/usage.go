@@ -0,0 +1,222 @@
+// Package usage reads token usage from agent session logs so Gas Town can
+// account spend across every agent runtime.
+//
+// Each runtime keeps its own session log (Claude Code transcripts, Codex
+// rollouts, Gemini chat recordings, ...). An Extractor knows one format and
+// finds the most recent session for a working directory. Agent presets name
+// their format via AgentPresetInfo.UsageProvider; Cost applies the pricing
+// table from config.
+package usage
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Usage is the token usage of one model within an agent session.
+type Usage struct {
+	Model string
+
+	// InputTokens excludes cached input, which is counted in CacheReadTokens.
+	InputTokens      int
+	CacheReadTokens  int
+	CacheWriteTokens int
+	// OutputTokens includes reasoning/thinking tokens, which are billed as output.
+	OutputTokens int
+
+	// ReportedCostUSD is the spend the agent computed itself, for runtimes
+	// whose logs record it (OpenCode, Pi). Zero when not reported.
+	ReportedCostUSD float64
+
+	// Session identifies the session the usage was read from: the log path,
+	// or the runtime's session ID. Every entry of one Extract shares it, and
+	// unlike the tmux session name it changes each time the agent restarts.
+	Session string
+}
+
+// Extractor reads token usage for a runtime's session log format.
+type Extractor interface {
+	// Extract returns the usage of the most recent session run in workDir,
+	// one entry per model used.
+	Extract(workDir string) ([]Usage, error)
+}
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   = map[string]Extractor{
+		"claude":   claudeExtractor{},
+		"codex":    codexExtractor{},
+		"gemini":   geminiExtractor{},
+		"opencode": openCodeExtractor{},
+		"pi":       piExtractor{agentDirEnv: "PI_CODING_AGENT_DIR", defaultDir: ".pi"},
+		"omp":      piExtractor{agentDirEnv: "OMP_CODING_AGENT_DIR", defaultDir: ".omp"},
+	}
+)
+
+// Register adds or replaces the extractor for a usage provider.
+func Register(provider string, e Extractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors[provider] = e
+}
+
+// For returns the extractor for a usage provider, or nil if there is none.
+func For(provider string) Extractor {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+	return extractors[provider]
+}
+
+// Extract reads the usage of the latest session in workDir using the
+// provider's extractor.
+func Extract(provider, workDir string) ([]Usage, error) {
+	e := For(provider)
+	if e == nil {
+		if provider == "" {
+			return nil, fmt.Errorf("agent has no usage provider")
+		}
+		return nil, fmt.Errorf("no usage extractor for provider %q", provider)
+	}
+	return e.Extract(workDir)
+}
+
+// SessionOf returns the session usages were read from, or "" if none.
+func SessionOf(usages []Usage) string {
+	if len(usages) == 0 {
+		return ""
+	}
+	return usages[0].Session
+}
+
+// inSession tags usages read from session.
+func inSession(session string, usages []Usage) []Usage {
+	for i := range usages {
+		usages[i].Session = session
+	}
+	return usages
+}
+
+// Cost converts usage to USD. Models priced by the table (built-in or
+// pricing overrides) are priced per token; for models the table does not
+// know, the agent's own reported cost is used when available, and default
+// pricing otherwise.
+func Cost(usages []Usage, pricing map[string]*config.ModelPricing) float64 {
+	var total float64
+	for _, u := range usages {
+		p, known := config.LookupModelPricing(u.Model, pricing)
+		if !known && u.ReportedCostUSD > 0 {
+			total += u.ReportedCostUSD
+			continue
+		}
+		total += float64(u.InputTokens)/1_000_000*p.InputPerMillion +
+			float64(u.CacheReadTokens)/1_000_000*p.CacheReadPerMillion +
+			float64(u.CacheWriteTokens)/1_000_000*p.CacheWritePerMillion +
+			float64(u.OutputTokens)/1_000_000*p.OutputPerMillion
+	}
+	return total
+}
+
+// Models returns the distinct models in usages, in order of first use.
+func Models(usages []Usage) []string {
+	var models []string
+	seen := make(map[string]bool)
+	for _, u := range usages {
+		if u.Model != "" && !seen[u.Model] {
+			seen[u.Model] = true
+			models = append(models, u.Model)
+		}
+	}
+	return models
+}
+
+// byModel accumulates usage per model, preserving first-use order.
+type byModel struct {
+	order []string
+	usage map[string]*Usage
+}
+
+func (b *byModel) get(model string) *Usage {
+	if b.usage == nil {
+		b.usage = make(map[string]*Usage)
+	}
+	u, ok := b.usage[model]
+	if !ok {
+		u = &Usage{Model: model}
+		b.usage[model] = u
+		b.order = append(b.order, model)
+	}
+	return u
+}
+
+func (b *byModel) result() []Usage {
+	out := make([]Usage, 0, len(b.order))
+	for _, m := range b.order {
+		out = append(out, *b.usage[m])
+	}
+	return out
+}
+
+// homeDir returns the directory named by env, or ~/<rel> when env is unset.
+func homeDir(env, rel string) (string, error) {
+	if env != "" {
+		if dir := os.Getenv(env); dir != "" {
+			return dir, nil
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, rel), nil
+}
+
+// filesByModTime returns the files under root whose names match pattern,
+// newest first. When recursive is false only root itself is listed.
+func filesByModTime(root, pattern string, recursive bool) ([]string, error) {
+	type file struct {
+		path string
+		mod  time.Time
+	}
+	var files []file
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && !recursive {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if ok, _ := filepath.Match(pattern, d.Name()); !ok {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil // Skip files we can't stat
+		}
+		files = append(files, file{path, info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].mod.After(files[j].mod) })
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths, nil
+}
+
+// sameDir reports whether two paths name the same directory.
+func sameDir(a, b string) bool {
+	return a != "" && b != "" && filepath.Clean(a) == filepath.Clean(b)
+}
@@ -0,0 +1,198 @@
+package usage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeFile(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, env := range []string{"CLAUDE_CONFIG_DIR", "CODEX_HOME", "XDG_DATA_HOME", "PI_CODING_AGENT_DIR", "OMP_CODING_AGENT_DIR"} {
+		t.Setenv(env, "")
+	}
+	return home
+}
+
+func TestClaudeExtractor(t *testing.T) {
+	home := setHome(t)
+	workDir := "/town/gastown/polecats/toast"
+	projectDir := filepath.Join(home, ".claude", "projects", "-town-gastown-polecats-toast")
+	old := filepath.Join(projectDir, "old.jsonl")
+	writeFile(t, old, `{"type":"assistant","message":{"model":"claude-opus-4-1","usage":{"input_tokens":999}}}`)
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(old, past, past); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(projectDir, "latest.jsonl"),
+		`{"type":"user","message":{"role":"user"}}`,
+		`{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"cache_creation_input_tokens":10,"cache_read_input_tokens":1000,"output_tokens":50}}}`,
+		`not json`,
+		`{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":20,"output_tokens":5}}}`,
+	)
+
+	got, err := Extract("claude", workDir)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	want := []Usage{{Model: "claude-sonnet-4-20250514", InputTokens: 120, CacheWriteTokens: 10, CacheReadTokens: 1000, OutputTokens: 55}}
+	assertUsage(t, got, want)
+	if session := SessionOf(got); session != filepath.Join(projectDir, "latest.jsonl") {
+		t.Errorf("SessionOf = %q, want the latest transcript", session)
+	}
+}
+
+func TestCodexExtractor(t *testing.T) {
+	home := setHome(t)
+	workDir := "/town/gastown/polecats/nux"
+	dayDir := filepath.Join(home, ".codex", "sessions", "2026", "10", "16")
+
+	other := filepath.Join(dayDir, "rollout-2026-10-16T10-00-00-other.jsonl")
+	writeFile(t, other, `{"type":"session_meta","payload":{"cwd":"/elsewhere"}}`)
+	writeFile(t, filepath.Join(dayDir, "rollout-2026-10-16T09-00-00-nux.jsonl"),
+		`{"type":"session_meta","payload":{"id":"abc","cwd":"/town/gastown/polecats/nux/"}}`,
+		`{"type":"turn_context","payload":{"cwd":"/town/gastown/polecats/nux","model":"gpt-5-codex"}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":null}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1000,"cached_input_tokens":400,"output_tokens":100,"reasoning_output_tokens":30}}}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1000,"cached_input_tokens":400,"output_tokens":100}}}}`,
+		`{"type":"turn_context","payload":{"model":"gpt-5-mini"}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1500,"cached_input_tokens":600,"output_tokens":160}}}}`,
+	)
+
+	got, err := Extract("codex", workDir)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	want := []Usage{
+		{Model: "gpt-5-codex", InputTokens: 600, CacheReadTokens: 400, OutputTokens: 100},
+		{Model: "gpt-5-mini", InputTokens: 300, CacheReadTokens: 200, OutputTokens: 60},
+	}
+	assertUsage(t, got, want)
+}
+
+func TestGeminiExtractor(t *testing.T) {
+	home := setHome(t)
+	workDir := "/town/gastown/crew/max"
+	hash := sha256.Sum256([]byte(workDir))
+	writeFile(t, filepath.Join(home, ".gemini", "tmp", hex.EncodeToString(hash[:]), "chats", "session-2026-10-16T10-00-abc.json"),
+		`{"sessionId":"abc","messages":[`+
+			`{"type":"user","content":"hi"},`+
+			`{"type":"gemini","model":"gemini-2.5-pro","tokens":{"input":500,"output":40,"cached":200,"thoughts":10,"total":550}},`+
+			`{"type":"gemini","model":"gemini-2.5-pro","tokens":{"input":100,"output":5,"cached":0,"total":105}}`+
+			`]}`)
+
+	got, err := Extract("gemini", workDir)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	want := []Usage{{Model: "gemini-2.5-pro", InputTokens: 400, CacheReadTokens: 200, OutputTokens: 55}}
+	assertUsage(t, got, want)
+}
+
+func TestOpenCodeExtractor(t *testing.T) {
+	home := setHome(t)
+	workDir := "/town/gastown/polecats/slit"
+	storage := filepath.Join(home, ".local", "share", "opencode", "storage")
+	writeFile(t, filepath.Join(storage, "session", "proj1", "ses_old.json"),
+		`{"id":"ses_old","directory":"/town/gastown/polecats/slit","time":{"created":1,"updated":2}}`)
+	writeFile(t, filepath.Join(storage, "session", "proj1", "ses_new.json"),
+		`{"id":"ses_new","directory":"/town/gastown/polecats/slit","time":{"created":3,"updated":4}}`)
+	writeFile(t, filepath.Join(storage, "session", "proj2", "ses_other.json"),
+		`{"id":"ses_other","directory":"/elsewhere","time":{"created":5,"updated":6}}`)
+	writeFile(t, filepath.Join(storage, "message", "ses_old", "msg_1.json"),
+		`{"role":"assistant","modelID":"gpt-5","tokens":{"input":999,"output":999,"cache":{}}}`)
+	writeFile(t, filepath.Join(storage, "message", "ses_new", "msg_1.json"),
+		`{"role":"user"}`)
+	writeFile(t, filepath.Join(storage, "message", "ses_new", "msg_2.json"),
+		`{"role":"assistant","modelID":"kimi-k2","cost":0.25,"tokens":{"input":100,"output":20,"reasoning":5,"cache":{"read":300,"write":10}}}`)
+
+	got, err := Extract("opencode", workDir)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	want := []Usage{{Model: "kimi-k2", InputTokens: 100, CacheReadTokens: 300, CacheWriteTokens: 10, OutputTokens: 25, ReportedCostUSD: 0.25}}
+	assertUsage(t, got, want)
+}
+
+func TestPiExtractor(t *testing.T) {
+	home := setHome(t)
+	workDir := "/town/gastown/polecats/furiosa"
+	writeFile(t, filepath.Join(home, ".omp", "agent", "sessions", "--town-gastown-polecats-furiosa--", "2026-10-16_abc.jsonl"),
+		`{"type":"session","cwd":"/town/gastown/polecats/furiosa"}`,
+		`{"type":"message","message":{"role":"user","content":"hi"}}`,
+		`{"type":"message","message":{"role":"assistant","model":"claude-sonnet-4-5","usage":{"input":10,"output":20,"cacheRead":30,"cacheWrite":40,"cost":{"total":0.01}}}}`,
+	)
+
+	got, err := Extract("omp", workDir)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	want := []Usage{{Model: "claude-sonnet-4-5", InputTokens: 10, OutputTokens: 20, CacheReadTokens: 30, CacheWriteTokens: 40, ReportedCostUSD: 0.01}}
+	assertUsage(t, got, want)
+
+	if _, err := Extract("pi", workDir); err == nil {
+		t.Error("Extract(pi) should not read the omp agent dir")
+	}
+}
+
+func TestExtract_UnknownProvider(t *testing.T) {
+	if _, err := Extract("", "/tmp"); err == nil {
+		t.Error("expected error for empty provider")
+	}
+	if _, err := Extract("amp", "/tmp"); err == nil {
+		t.Error("expected error for provider without extractor")
+	}
+}
+
+func TestCost(t *testing.T) {
+	usages := []Usage{
+		// Priced by the table: 1M input at $3 + 1M output at $15
+		{Model: "claude-sonnet-4-20250514", InputTokens: 1_000_000, OutputTokens: 1_000_000},
+		// Unknown model with a reported cost: reported cost wins
+		{Model: "kimi-k2", InputTokens: 1_000_000, ReportedCostUSD: 0.5},
+	}
+	if got := Cost(usages, nil); math.Abs(got-18.5) > 1e-9 {
+		t.Errorf("Cost = %v, want 18.5", got)
+	}
+
+	// Configured pricing takes precedence over the reported cost
+	pricing := map[string]*config.ModelPricing{"kimi-k2": {InputPerMillion: 0.6}}
+	if got := Cost(usages, pricing); math.Abs(got-18.6) > 1e-9 {
+		t.Errorf("Cost with pricing = %v, want 18.6", got)
+	}
+}
+
+func assertUsage(t *testing.T, got, want []Usage) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d usages %+v, want %d %+v", len(got), got, len(want), want)
+	}
+	for i := range want {
+		g := got[i]
+		if want[i].Session == "" {
+			g.Session = ""
+		}
+		if g != want[i] {
+			t.Errorf("usage[%d] = %+v, want %+v", i, g, want[i])
+		}
+	}
+}
@@ -138,6 +138,22 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleSSE(w, r)
 	case path == "/session/preview" && r.Method == http.MethodGet:
 		h.handleSessionPreview(w, r)
+	case path == "/openapi.json" && r.Method == http.MethodGet:
+		h.handleOpenAPI(w, r)
+	case path == "/convoys" && r.Method == http.MethodGet:
+		h.handleConvoys(w, r)
+	case path == "/merge-queue" && r.Method == http.MethodGet:
+		h.handleMergeQueue(w, r)
+	case path == "/merge-queue/gates" && r.Method == http.MethodGet:
+		h.handleMergeQueueGates(w, r)
+	case path == "/scheduler" && r.Method == http.MethodGet:
+		h.handleScheduler(w, r)
+	case path == "/polecats" && r.Method == http.MethodGet:
+		h.handlePolecats(w, r)
+	case path == "/quota" && r.Method == http.MethodGet:
+		h.handleQuota(w, r)
+	case path == "/costs" && r.Method == http.MethodGet:
+		h.handleCosts(w, r)
 	default:
 		http.Error(w, "Not found", http.StatusNotFound)
 	}
@@ -223,6 +239,21 @@ func (h *APIHandler) handleCommands(w http.ResponseWriter, _ *http.Request) {
 
 // runGtCommand executes a gt command with the given args.
 func (h *APIHandler) runGtCommand(ctx context.Context, timeout time.Duration, args []string) (string, error) {
+	stdout, stderr, err := h.execGt(ctx, timeout, args)
+
+	// Combine stdout and stderr for output
+	output := stdout
+	if stderr != "" {
+		if output != "" {
+			output += "\n"
+		}
+		output += stderr
+	}
+	return output, err
+}
+
+// execGt runs a gt command and returns its stdout and stderr separately.
+func (h *APIHandler) execGt(ctx context.Context, timeout time.Duration, args []string) (string, string, error) {
 	// Apply timeout first so it bounds both semaphore wait and command execution.
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
@@ -232,7 +263,7 @@ func (h *APIHandler) runGtCommand(ctx context.Context, timeout time.Duration, ar
 	case h.cmdSem <- struct{}{}:
 		defer func() { <-h.cmdSem }()
 	case <-ctx.Done():
-		return "", fmt.Errorf("command slot unavailable: %w", ctx.Err())
+		return "", "", fmt.Errorf("command slot unavailable: %w", ctx.Err())
 	}
 
 	cmd := exec.CommandContext(ctx, h.gtPath, args...)
@@ -248,24 +279,15 @@ func (h *APIHandler) runGtCommand(ctx context.Context, timeout time.Duration, ar
 
 	err := cmd.Run()
 
-	// Combine stdout and stderr for output
-	output := stdout.String()
-	if stderr.Len() > 0 {
-		if output != "" {
-			output += "\n"
-		}
-		output += stderr.String()
-	}
-
 	if ctx.Err() == context.DeadlineExceeded {
-		return output, fmt.Errorf("command timed out after %v", timeout)
+		return stdout.String(), stderr.String(), fmt.Errorf("command timed out after %v", timeout)
 	}
 
 	if err != nil {
-		return output, fmt.Errorf("command failed: %v", err)
+		return stdout.String(), stderr.String(), fmt.Errorf("command failed: %v", err)
 	}
 
-	return output, nil
+	return stdout.String(), stderr.String(), nil
 }
 
 // sendError sends a JSON error response.
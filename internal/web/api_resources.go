@@ -0,0 +1,551 @@
+package web
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// The resource endpoints below return town state as typed JSON. The response
+// types are the API contract described in openapi.json: CLI output is decoded
+// into them rather than passed through, so a change to a command's --json
+// output surfaces as an error here instead of silently changing the API.
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// resourceTimeout bounds the gt subprocess behind each resource endpoint.
+const resourceTimeout = 20 * time.Second
+
+// handleOpenAPI serves the OpenAPI document describing the dashboard API.
+func (h *APIHandler) handleOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPISpec)
+}
+
+// runGtJSON runs a gt command that prints JSON and decodes stdout into v.
+// Stderr is ignored so warnings printed alongside the JSON don't corrupt it.
+func (h *APIHandler) runGtJSON(ctx context.Context, args []string, v interface{}) error {
+	stdout, stderr, err := h.execGt(ctx, resourceTimeout, args)
+	if err != nil {
+		log.Printf("api: gt %v: %v: %s", args, err, stderr)
+		return err
+	}
+	if err := json.Unmarshal([]byte(stdout), v); err != nil {
+		log.Printf("api: gt %v: parsing output: %v", args, err)
+		return fmt.Errorf("unexpected output from gt %s", args[0])
+	}
+	return nil
+}
+
+// sendJSON writes v as a JSON response.
+func (h *APIHandler) sendJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// ConvoyIssue is an issue tracked by a convoy.
+type ConvoyIssue struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	IssueType string `json:"issue_type,omitempty"`
+	Blocked   bool   `json:"blocked,omitempty"`
+	Assignee  string `json:"assignee,omitempty"`
+	Worker    string `json:"worker,omitempty"`
+}
+
+// Convoy is a convoy with its tracked issues and progress.
+type Convoy struct {
+	ID        string        `json:"id"`
+	Title     string        `json:"title"`
+	Status    string        `json:"status"`
+	CreatedAt string        `json:"created_at,omitempty"`
+	Completed int           `json:"completed"`
+	Total     int           `json:"total"`
+	Tracked   []ConvoyIssue `json:"tracked"`
+}
+
+// ConvoysResponse is the response for /api/convoys.
+type ConvoysResponse struct {
+	Convoys []Convoy `json:"convoys"`
+	Total   int      `json:"total"`
+}
+
+// handleConvoys returns convoys and their progress.
+// Query: status=open|closed|all (default open).
+func (h *APIHandler) handleConvoys(w http.ResponseWriter, r *http.Request) {
+	args := []string{"convoy", "list", "--json"}
+	switch status := r.URL.Query().Get("status"); status {
+	case "", "open":
+	case "closed":
+		args = append(args, "--status", "closed")
+	case "all":
+		args = append(args, "--all")
+	default:
+		h.sendError(w, "Invalid status (expected open, closed or all)", http.StatusBadRequest)
+		return
+	}
+
+	resp := ConvoysResponse{Convoys: make([]Convoy, 0)}
+	if err := h.runGtJSON(r.Context(), args, &resp.Convoys); err != nil {
+		h.sendError(w, "Failed to list convoys", http.StatusBadGateway)
+		return
+	}
+	for i := range resp.Convoys {
+		if resp.Convoys[i].Tracked == nil {
+			resp.Convoys[i].Tracked = []ConvoyIssue{}
+		}
+	}
+	resp.Total = len(resp.Convoys)
+	h.sendJSON(w, resp)
+}
+
+// MergeQueueItem is a merge request waiting in a rig's refinery queue.
+type MergeQueueItem struct {
+	Position     int       `json:"position"`
+	ID           string    `json:"id"`
+	Branch       string    `json:"branch"`
+	Worker       string    `json:"worker,omitempty"`
+	IssueID      string    `json:"issue_id,omitempty"`
+	TargetBranch string    `json:"target_branch,omitempty"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	Age          string    `json:"age"`
+}
+
+// QueueAnomaly is a merge queue problem needing operator attention, such as
+// a stale claim or an orphaned branch.
+type QueueAnomaly struct {
+	ID         string `json:"id"`
+	Branch     string `json:"branch"`
+	Type       string `json:"type"`
+	Assignee   string `json:"assignee,omitempty"`
+	AgeSeconds int64  `json:"age_seconds,omitempty"`
+	Detail     string `json:"detail"`
+}
+
+// RigMergeQueue is the merge queue of one rig. Error is set when the rig's
+// queue could not be read; the other rigs are still reported.
+type RigMergeQueue struct {
+	Rig       string           `json:"rig"`
+	Items     []MergeQueueItem `json:"items"`
+	Anomalies []QueueAnomaly   `json:"anomalies"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// MergeQueueResponse is the response for /api/merge-queue.
+type MergeQueueResponse struct {
+	Rigs  []RigMergeQueue `json:"rigs"`
+	Total int             `json:"total"`
+}
+
+// handleMergeQueue returns the refinery queue and queue anomalies per rig.
+// Query: rig=<name> limits the response to one rig.
+func (h *APIHandler) handleMergeQueue(w http.ResponseWriter, r *http.Request) {
+	rigName := r.URL.Query().Get("rig")
+	if rigName != "" && !isValidRigName(rigName) {
+		h.sendError(w, "Invalid rig name", http.StatusBadRequest)
+		return
+	}
+
+	townRoot, err := workspace.FindOrError(h.workDir)
+	if err != nil {
+		h.sendError(w, "Not in a Gas Town workspace", http.StatusServiceUnavailable)
+		return
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	rigMgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+
+	var rigs []*rig.Rig
+	if rigName != "" {
+		rg, err := rigMgr.GetRig(rigName)
+		if err != nil {
+			h.sendError(w, "Rig not found", http.StatusNotFound)
+			return
+		}
+		rigs = []*rig.Rig{rg}
+	} else {
+		rigs, _ = rigMgr.DiscoverRigs()
+		sort.Slice(rigs, func(i, j int) bool { return rigs[i].Name < rigs[j].Name })
+	}
+
+	resp := MergeQueueResponse{Rigs: make([]RigMergeQueue, 0, len(rigs))}
+	now := time.Now()
+	for _, rg := range rigs {
+		rq := fetchRigMergeQueue(rg, now)
+		resp.Total += len(rq.Items)
+		resp.Rigs = append(resp.Rigs, rq)
+	}
+	h.sendJSON(w, resp)
+}
+
+// fetchRigMergeQueue reads one rig's queue and anomalies. Failures are logged
+// and reported on the entry rather than failing the whole response.
+func fetchRigMergeQueue(rg *rig.Rig, now time.Time) RigMergeQueue {
+	rq := RigMergeQueue{
+		Rig:       rg.Name,
+		Items:     make([]MergeQueueItem, 0),
+		Anomalies: make([]QueueAnomaly, 0),
+	}
+
+	queue, err := refinery.NewManager(rg).Queue()
+	if err != nil {
+		log.Printf("api: merge queue for %s: %v", rg.Name, err)
+		rq.Error = "failed to read merge queue"
+		return rq
+	}
+	for _, item := range queue {
+		if item.MR == nil {
+			continue
+		}
+		rq.Items = append(rq.Items, MergeQueueItem{
+			Position:     item.Position,
+			ID:           item.MR.ID,
+			Branch:       item.MR.Branch,
+			Worker:       item.MR.Worker,
+			IssueID:      item.MR.IssueID,
+			TargetBranch: item.MR.TargetBranch,
+			Status:       string(item.MR.Status),
+			Error:        item.MR.Error,
+			CreatedAt:    item.MR.CreatedAt,
+			Age:          item.Age,
+		})
+	}
+
+	anomalies, err := refinery.NewEngineer(rg).ListQueueAnomalies(now)
+	if err != nil {
+		log.Printf("api: queue anomalies for %s: %v", rg.Name, err)
+		rq.Error = "failed to check queue anomalies"
+		return rq
+	}
+	for _, a := range anomalies {
+		rq.Anomalies = append(rq.Anomalies, QueueAnomaly{
+			ID:         a.ID,
+			Branch:     a.Branch,
+			Type:       a.Type,
+			Assignee:   a.Assignee,
+			AgeSeconds: int64(a.Age.Seconds()),
+			Detail:     a.Detail,
+		})
+	}
+	return rq
+}
+
+// maxGateLogBytes caps each stream returned by /api/merge-queue/gates; the
+// tail is kept since failures are usually reported last.
+const maxGateLogBytes = 1 << 20
+
+// maxGateRunsListed caps how many runs /api/merge-queue/gates lists.
+const maxGateRunsListed = 20
+
+// GateLog is the full output of one gate in a persisted gate run.
+type GateLog struct {
+	Run       string `json:"run"`
+	Gate      string `json:"gate"`
+	Stdout    string `json:"stdout"`
+	Stderr    string `json:"stderr"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// MergeQueueGatesResponse is the response for /api/merge-queue/gates.
+type MergeQueueGatesResponse struct {
+	Rig  string             `json:"rig"`
+	MR   string             `json:"mr"`
+	Runs []refinery.GateRun `json:"runs"`
+	Log  *GateLog           `json:"log,omitempty"`
+}
+
+// handleMergeQueueGates returns the persisted quality gate runs of an MR,
+// newest first. Query: rig=<name>&mr=<id> (required); gate=<name> adds that
+// gate's full output from run=<id>, or from the newest run.
+func (h *APIHandler) handleMergeQueueGates(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	rigName, mrID, gate, runID := q.Get("rig"), q.Get("mr"), q.Get("gate"), q.Get("run")
+	if !isValidRigName(rigName) || !isValidID(mrID) {
+		h.sendError(w, "rig and mr are required", http.StatusBadRequest)
+		return
+	}
+	if (gate != "" && !isValidID(gate)) || (runID != "" && gate == "") {
+		h.sendError(w, "Invalid gate or run", http.StatusBadRequest)
+		return
+	}
+
+	townRoot, err := workspace.FindOrError(h.workDir)
+	if err != nil {
+		h.sendError(w, "Not in a Gas Town workspace", http.StatusServiceUnavailable)
+		return
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	rg, err := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot)).GetRig(rigName)
+	if err != nil {
+		h.sendError(w, "Rig not found", http.StatusNotFound)
+		return
+	}
+
+	runs, err := refinery.ListGateRuns(rg.Path, mrID)
+	if err != nil {
+		log.Printf("api: gate runs for %s: %v", rigName, err)
+		h.sendError(w, "Failed to read gate logs", http.StatusInternalServerError)
+		return
+	}
+	resp := MergeQueueGatesResponse{Rig: rigName, MR: mrID, Runs: make([]refinery.GateRun, 0, len(runs))}
+	for i, run := range runs {
+		if i == maxGateRunsListed {
+			break
+		}
+		resp.Runs = append(resp.Runs, *run)
+	}
+
+	if gate != "" {
+		var run *refinery.GateRun
+		for _, candidate := range runs {
+			if runID == "" || candidate.ID == runID {
+				run = candidate
+				break
+			}
+		}
+		var rec *refinery.GateRecord
+		if run != nil {
+			rec = run.Gate(gate)
+		}
+		if rec == nil {
+			h.sendError(w, "Gate run not found", http.StatusNotFound)
+			return
+		}
+		gl := &GateLog{Run: run.ID, Gate: rec.Name}
+		for _, stream := range []struct {
+			rel string
+			dst *string
+		}{{rec.Stdout, &gl.Stdout}, {rec.Stderr, &gl.Stderr}} {
+			if stream.rel == "" {
+				continue
+			}
+			data, err := refinery.ReadGateLog(rg.Path, run.ID, stream.rel)
+			if err != nil {
+				log.Printf("api: gate log %s/%s: %v", run.ID, stream.rel, err)
+				continue
+			}
+			if len(data) > maxGateLogBytes {
+				data = data[len(data)-maxGateLogBytes:]
+				gl.Truncated = true
+			}
+			*stream.dst = string(data)
+		}
+		resp.Log = gl
+	}
+	h.sendJSON(w, resp)
+}
+
+// ScheduledBead is a bead waiting in the scheduler for dispatch capacity.
+type ScheduledBead struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Status     string `json:"status"`
+	TargetRig  string `json:"target_rig"`
+	Blocked    bool   `json:"blocked,omitempty"`
+	NotBefore  string `json:"not_before,omitempty"`
+	Deadline   string `json:"deadline,omitempty"`
+	Recurrence string `json:"recurrence,omitempty"`
+	Held       bool   `json:"held,omitempty"`
+	Overdue    bool   `json:"overdue,omitempty"`
+}
+
+// SchedulerResponse is the response for /api/scheduler.
+type SchedulerResponse struct {
+	Paused         bool                  `json:"paused"`
+	PausedBy       string                `json:"paused_by,omitempty"`
+	QueuedTotal    int                   `json:"queued_total"`
+	QueuedReady    int                   `json:"queued_ready"`
+	ActivePolecats int                   `json:"active_polecats"`
+	MaxPolecats    int                   `json:"max_polecats"`
+	Limits         []capacity.LimitUsage `json:"limits"`
+	LastDispatchAt string                `json:"last_dispatch_at,omitempty"`
+	Beads          []ScheduledBead       `json:"beads"`
+}
+
+// handleScheduler returns scheduler state, capacity limits and queued beads.
+func (h *APIHandler) handleScheduler(w http.ResponseWriter, r *http.Request) {
+	var resp SchedulerResponse
+	if err := h.runGtJSON(r.Context(), []string{"scheduler", "status", "--json"}, &resp); err != nil {
+		h.sendError(w, "Failed to read scheduler state", http.StatusBadGateway)
+		return
+	}
+	if resp.Limits == nil {
+		resp.Limits = []capacity.LimitUsage{}
+	}
+	if resp.Beads == nil {
+		resp.Beads = []ScheduledBead{}
+	}
+	h.sendJSON(w, resp)
+}
+
+// PolecatStatus is the state of one polecat.
+type PolecatStatus struct {
+	Rig            string `json:"rig"`
+	Name           string `json:"name"`
+	State          string `json:"state"`
+	Issue          string `json:"issue,omitempty"`
+	SessionRunning bool   `json:"session_running"`
+	Zombie         bool   `json:"zombie,omitempty"`
+	SessionName    string `json:"session_name,omitempty"`
+}
+
+// PolecatsResponse is the response for /api/polecats.
+type PolecatsResponse struct {
+	Polecats []PolecatStatus `json:"polecats"`
+	Total    int             `json:"total"`
+	ByState  map[string]int  `json:"by_state"`
+}
+
+// handlePolecats returns polecat status across all rigs.
+// Query: rig=<name> limits the response to one rig.
+func (h *APIHandler) handlePolecats(w http.ResponseWriter, r *http.Request) {
+	args := []string{"polecat", "list", "--all", "--json"}
+	if rigName := r.URL.Query().Get("rig"); rigName != "" {
+		if !isValidRigName(rigName) {
+			h.sendError(w, "Invalid rig name", http.StatusBadRequest)
+			return
+		}
+		args = []string{"polecat", "list", rigName, "--json"}
+	}
+
+	resp := PolecatsResponse{
+		Polecats: make([]PolecatStatus, 0),
+		ByState:  make(map[string]int),
+	}
+	if err := h.runGtJSON(r.Context(), args, &resp.Polecats); err != nil {
+		h.sendError(w, "Failed to list polecats", http.StatusBadGateway)
+		return
+	}
+	for _, p := range resp.Polecats {
+		resp.ByState[p.State]++
+	}
+	resp.Total = len(resp.Polecats)
+	h.sendJSON(w, resp)
+}
+
+// QuotaAccount is the rate-limit state of one registered account.
+type QuotaAccount struct {
+	Handle    string `json:"handle"`
+	Email     string `json:"email"`
+	Status    string `json:"status"`
+	LimitedAt string `json:"limited_at,omitempty"`
+	ResetsAt  string `json:"resets_at,omitempty"`
+	LastUsed  string `json:"last_used,omitempty"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// QuotaResponse is the response for /api/quota.
+type QuotaResponse struct {
+	Accounts  []QuotaAccount `json:"accounts"`
+	Available int            `json:"available"`
+	Limited   int            `json:"limited"`
+}
+
+// handleQuota returns the quota state of every registered account.
+func (h *APIHandler) handleQuota(w http.ResponseWriter, r *http.Request) {
+	resp := QuotaResponse{Accounts: make([]QuotaAccount, 0)}
+	if err := h.runGtJSON(r.Context(), []string{"quota", "status", "--json"}, &resp.Accounts); err != nil {
+		h.sendError(w, "Failed to read quota state", http.StatusBadGateway)
+		return
+	}
+	if resp.Accounts == nil { // gt prints null when no accounts are registered
+		resp.Accounts = []QuotaAccount{}
+	}
+	for _, a := range resp.Accounts {
+		if a.Status == string(config.QuotaStatusAvailable) {
+			resp.Available++
+		} else {
+			resp.Limited++
+		}
+	}
+	h.sendJSON(w, resp)
+}
+
+// SessionCostItem is the cost of one agent session.
+type SessionCostItem struct {
+	Session string  `json:"session"`
+	Role    string  `json:"role"`
+	Rig     string  `json:"rig,omitempty"`
+	Worker  string  `json:"worker,omitempty"`
+	CostUSD float64 `json:"cost_usd"`
+	Running bool    `json:"running"`
+}
+
+// CostsResponse is the response for /api/costs.
+type CostsResponse struct {
+	Period   string             `json:"period"`
+	TotalUSD float64            `json:"total_usd"`
+	ByRole   map[string]float64 `json:"by_role"`
+	ByRig    map[string]float64 `json:"by_rig"`
+	Sessions []SessionCostItem  `json:"sessions"`
+}
+
+// handleCosts returns agent spend.
+// Query: period=live|today|week (default live). live reports running
+// sessions; today and week report the cost ledger with role and rig totals.
+func (h *APIHandler) handleCosts(w http.ResponseWriter, r *http.Request) {
+	period := r.URL.Query().Get("period")
+	args := []string{"costs", "--json"}
+	switch period {
+	case "", "live":
+		period = "live"
+	case "today":
+		args = append(args, "--today", "--by-role", "--by-rig")
+	case "week":
+		args = append(args, "--week", "--by-role", "--by-rig")
+	default:
+		h.sendError(w, "Invalid period (expected live, today or week)", http.StatusBadRequest)
+		return
+	}
+
+	var out struct {
+		Sessions []SessionCostItem  `json:"sessions"`
+		Total    float64            `json:"total_usd"`
+		ByRole   map[string]float64 `json:"by_role"`
+		ByRig    map[string]float64 `json:"by_rig"`
+	}
+	if err := h.runGtJSON(r.Context(), args, &out); err != nil {
+		h.sendError(w, "Failed to read costs", http.StatusBadGateway)
+		return
+	}
+
+	resp := CostsResponse{
+		Period:   period,
+		TotalUSD: out.Total,
+		ByRole:   out.ByRole,
+		ByRig:    out.ByRig,
+		Sessions: out.Sessions,
+	}
+	if resp.ByRole == nil {
+		resp.ByRole = map[string]float64{}
+	}
+	if resp.ByRig == nil {
+		resp.ByRig = map[string]float64{}
+	}
+	if resp.Sessions == nil {
+		resp.Sessions = []SessionCostItem{}
+	}
+	h.sendJSON(w, resp)
+}
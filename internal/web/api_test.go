@@ -6,6 +6,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
@@ -1158,3 +1161,152 @@ func TestHandleSessionPreviewPrefixValidation(t *testing.T) {
 		})
 	}
 }
+
+// fakeGt writes a stub gt that prints canned --json output for the resource
+// endpoints, with a warning on stderr that must not corrupt the JSON.
+func fakeGt(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("stub gt is a shell script")
+	}
+	script := `#!/bin/sh
+echo "warning: noise" >&2
+case "$1 $2" in
+"convoy list") echo '[{"id":"hq-cv1","title":"Auth","status":"open","completed":1,"total":2,"tracked":[{"id":"gt-1","title":"Login","status":"closed"}]}]' ;;
+"scheduler status") echo '{"paused":true,"paused_by":"mayor","queued_total":2,"queued_ready":1,"active_polecats":3,"max_polecats":5,"limits":[{"kind":"rig","key":"gastown","active":3,"max":4}],"beads":[{"id":"gt-2","title":"Fix","status":"open","target_rig":"gastown","deadline":"2026-03-01T00:00:00Z","overdue":true}]}' ;;
+"polecat list") echo '[{"rig":"gastown","name":"Toast","state":"working","session_running":true},{"rig":"gastown","name":"Nux","state":"idle","session_running":false}]' ;;
+"quota status") echo '[{"handle":"work","email":"a@b.c","status":"available","is_default":true},{"handle":"home","email":"d@e.f","status":"limited"}]' ;;
+"costs --json") echo '{"sessions":[{"session":"gt-gastown-Toast","role":"polecat","cost_usd":1.5,"running":true}],"total_usd":1.5}' ;;
+*) exit 1 ;;
+esac
+`
+	path := filepath.Join(t.TempDir(), "gt")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func resourceHandler(t *testing.T, gtPath string) *APIHandler {
+	return &APIHandler{
+		gtPath:            gtPath,
+		workDir:           t.TempDir(),
+		defaultRunTimeout: 5 * time.Second,
+		maxRunTimeout:     10 * time.Second,
+		cmdSem:            make(chan struct{}, maxConcurrentCommands),
+		csrfToken:         "test-token",
+	}
+}
+
+func getJSON(t *testing.T, h *APIHandler, target string, wantStatus int, v interface{}) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	if w.Code != wantStatus {
+		t.Fatalf("GET %s status = %d, want %d (body %s)", target, w.Code, wantStatus, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("GET %s Content-Type = %q", target, ct)
+	}
+	if v != nil {
+		if err := json.NewDecoder(w.Body).Decode(v); err != nil {
+			t.Fatalf("GET %s: decoding response: %v", target, err)
+		}
+	}
+}
+
+func TestAPIHandler_Resources(t *testing.T) {
+	h := resourceHandler(t, fakeGt(t))
+
+	var convoys ConvoysResponse
+	getJSON(t, h, "/api/convoys", http.StatusOK, &convoys)
+	if convoys.Total != 1 || convoys.Convoys[0].Completed != 1 || len(convoys.Convoys[0].Tracked) != 1 {
+		t.Errorf("convoys = %+v", convoys)
+	}
+
+	var sched SchedulerResponse
+	getJSON(t, h, "/api/scheduler", http.StatusOK, &sched)
+	if !sched.Paused || sched.MaxPolecats != 5 || len(sched.Limits) != 1 || !sched.Beads[0].Overdue {
+		t.Errorf("scheduler = %+v", sched)
+	}
+
+	var polecats PolecatsResponse
+	getJSON(t, h, "/api/polecats?rig=gastown", http.StatusOK, &polecats)
+	if polecats.Total != 2 || polecats.ByState["working"] != 1 || polecats.ByState["idle"] != 1 {
+		t.Errorf("polecats = %+v", polecats)
+	}
+
+	var quota QuotaResponse
+	getJSON(t, h, "/api/quota", http.StatusOK, &quota)
+	if len(quota.Accounts) != 2 || quota.Available != 1 || quota.Limited != 1 {
+		t.Errorf("quota = %+v", quota)
+	}
+
+	var costs CostsResponse
+	getJSON(t, h, "/api/costs", http.StatusOK, &costs)
+	if costs.Period != "live" || costs.TotalUSD != 1.5 || len(costs.Sessions) != 1 || costs.ByRig == nil {
+		t.Errorf("costs = %+v", costs)
+	}
+}
+
+func TestAPIHandler_ResourceErrors(t *testing.T) {
+	h := resourceHandler(t, "false")
+
+	for _, target := range []string{
+		"/api/convoys?status=bogus",
+		"/api/polecats?rig=../etc",
+		"/api/merge-queue?rig=a-b",
+		"/api/merge-queue/gates?rig=gastown",
+		"/api/merge-queue/gates?rig=gastown&mr=gt-mr-1&run=20261016T120000.0-1",
+		"/api/costs?period=year",
+	} {
+		getJSON(t, h, target, http.StatusBadRequest, nil)
+	}
+	for _, target := range []string{"/api/convoys", "/api/scheduler", "/api/polecats", "/api/quota", "/api/costs"} {
+		var resp CommandResponse
+		getJSON(t, h, target, http.StatusBadGateway, &resp)
+		if resp.Error == "" {
+			t.Errorf("GET %s: expected error message", target)
+		}
+	}
+	// workDir is not inside a town.
+	getJSON(t, h, "/api/merge-queue", http.StatusServiceUnavailable, nil)
+	getJSON(t, h, "/api/merge-queue/gates?rig=gastown&mr=gt-mr-1", http.StatusServiceUnavailable, nil)
+}
+
+// TestOpenAPIDocument verifies the served document is valid JSON and that
+// every path it describes is routed by the handler.
+func TestOpenAPIDocument(t *testing.T) {
+	h := resourceHandler(t, "false")
+
+	var doc struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	getJSON(t, h, "/api/openapi.json", http.StatusOK, &doc)
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("openapi = %q", doc.OpenAPI)
+	}
+	for _, p := range []string{"/api/convoys", "/api/merge-queue", "/api/merge-queue/gates", "/api/scheduler", "/api/polecats", "/api/quota", "/api/costs"} {
+		if _, ok := doc.Paths[p]; !ok {
+			t.Errorf("OpenAPI document missing %s", p)
+		}
+	}
+
+	for p, ops := range doc.Paths {
+		if p == "/api/events" {
+			continue // SSE stream doesn't return
+		}
+		for method := range ops {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			req := httptest.NewRequest(strings.ToUpper(method), p, strings.NewReader("{}")).WithContext(ctx)
+			req.Header.Set("X-Dashboard-Token", "test-token")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			cancel()
+			if w.Code == http.StatusNotFound && w.Header().Get("Content-Type") != "application/json" {
+				t.Errorf("%s %s is documented but not routed", strings.ToUpper(method), p)
+			}
+		}
+	}
+}
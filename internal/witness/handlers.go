@@ -741,7 +741,7 @@ func NukePolecat(workDir, rigName, polecatName string) error {
 	// session due to rig loading issues or race conditions with IsRunning checks.
 	// See: gt-g9ft5 - sessions were piling up because nuke wasn't killing them.
 	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
-	t := tmux.NewTmux()
+	t := session.PolecatBackend(workDirToTownRoot(workDir), tmux.NewTmux())
 
 	// Check if session exists and kill it
 	if running, _ := t.HasSession(sessionName); running {
@@ -750,7 +750,7 @@ func NukePolecat(workDir, rigName, polecatName string) error {
 		// Brief delay for graceful handling
 		time.Sleep(100 * time.Millisecond)
 		// Force kill the session
-		if err := t.KillSession(sessionName); err != nil {
+		if err := t.KillSessionWithProcesses(sessionName); err != nil {
 			// Log but continue - session might already be dead
 			// The important thing is we tried
 		}
@@ -956,7 +956,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 		return result
 	}
 
-	t := tmux.NewTmux()
+	t := session.PolecatBackend(townRoot, tmux.NewTmux())
 
 	for _, entry := range entries {
 		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -1025,7 +1025,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 //
 // gt-dsgp: Uses restart-first policy. Instead of nuking polecats, restarts their
 // sessions to preserve worktrees and branches.
-func detectZombieLiveSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t *tmux.Tmux, doneIntent *DoneIntent) (ZombieResult, bool) {
+func detectZombieLiveSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t session.SessionBackend, doneIntent *DoneIntent) (ZombieResult, bool) {
 	// Check for done-intent stuck too long (polecat hung in gt done).
 	// gt-dsgp: Restart instead of nuke — the session is stuck trying to exit,
 	// a fresh start will let it retry or pick up its hook cleanly.
@@ -1093,7 +1093,7 @@ func detectZombieLiveSession(workDir, rigName, polecatName, agentBeadID, session
 //
 // gt-dsgp: Uses restart-first policy. Instead of nuking polecats with dead sessions,
 // restarts them to preserve worktrees and branches.
-func detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t *tmux.Tmux, doneIntent *DoneIntent, detectedAt time.Time) (ZombieResult, bool) {
+func detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t session.SessionBackend, doneIntent *DoneIntent, detectedAt time.Time) (ZombieResult, bool) {
 	// Done-intent: polecat was trying to exit.
 	if doneIntent != nil {
 		age := time.Since(doneIntent.Timestamp)
@@ -1276,7 +1276,12 @@ func DetectStalledPolecats(workDir, rigName string) *DetectStalledPolecatsResult
 		return result // No polecats directory
 	}
 
-	t := tmux.NewTmux()
+	// Stall detection reads tmux session timestamps; polecats running in the
+	// PTY supervisor are left to zombie detection.
+	t := session.AsTmux(session.PolecatBackend(townRoot, tmux.NewTmux()))
+	if t == nil {
+		return result
+	}
 	now := time.Now()
 
 	for _, entry := range entries {
@@ -1734,7 +1739,7 @@ func DetectOrphanedBeads(workDir, rigName string, router *mail.Router) *DetectOr
 		beadList = append(beadList, batch...)
 	}
 
-	t := tmux.NewTmux()
+	t := session.PolecatBackend(townRoot, tmux.NewTmux())
 
 	for _, bead := range beadList {
 		if bead.Assignee == "" {
@@ -1876,7 +1881,7 @@ func DetectOrphanedMolecules(workDir, rigName string, router *mail.Router) *Dete
 
 	// Step 2: Check each polecat-assigned bead
 	polecatPrefix := rigName + "/polecats/"
-	t := tmux.NewTmux()
+	t := session.PolecatBackend(townRoot, tmux.NewTmux())
 	polecatsDir := filepath.Join(townRoot, rigName, "polecats")
 
 	for _, b := range allBeads {
@@ -2110,7 +2115,7 @@ func getAgentBeadLabels(workDir, agentBeadID string) []string {
 // sessionRecreated checks whether a tmux session was (re)created after the
 // given timestamp. Returns true if the session exists and was created after
 // detectedAt, indicating a new session replaced the dead one (TOCTOU guard).
-func sessionRecreated(t *tmux.Tmux, sessionName string, detectedAt time.Time) bool {
+func sessionRecreated(t session.SessionBackend, sessionName string, detectedAt time.Time) bool {
 	alive, err := t.HasSession(sessionName)
 	if err != nil || !alive {
 		return false // Still dead — not recreated